@@ -0,0 +1,64 @@
+// Command replay-verify re-simulates archived games and checks that their
+// recorded final board and winner match what the engine produces.
+//
+// Usage:
+//
+//	replay-verify game.json [more.json ...]
+//
+// Each file must contain a JSON-encoded models.Game including move_history.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: replay-verify game.json [more.json ...]")
+		os.Exit(2)
+	}
+
+	failed := false
+	for _, path := range os.Args[1:] {
+		result, err := verifyFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			failed = true
+			continue
+		}
+
+		if result.Valid {
+			fmt.Printf("%s: OK (%d moves, winner %q)\n", path, result.MovesReplayed, result.Winner)
+			continue
+		}
+
+		failed = true
+		fmt.Printf("%s: MISMATCH after %d moves\n", path, result.MovesReplayed)
+		for _, mismatch := range result.Mismatches {
+			fmt.Printf("  - %s\n", mismatch)
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// verifyFile loads a game dump and replays it through the engine
+func verifyFile(path string) (*models.ReplayResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	game := &models.Game{}
+	if err := json.Unmarshal(data, game); err != nil {
+		return nil, fmt.Errorf("invalid game JSON: %w", err)
+	}
+
+	return game.VerifyReplay(), nil
+}
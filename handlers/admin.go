@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// Announcement levels, used by clients to style the banner
+const (
+	AnnouncementInfo     = "info"
+	AnnouncementWarning  = "warning"
+	AnnouncementCritical = "critical"
+)
+
+// DefaultAnnouncementDuration is how long an announcement stays up when no duration is given
+const DefaultAnnouncementDuration = 10 * time.Minute
+
+// MaxAnnouncementDelay is how far ahead an announcement can be scheduled
+const MaxAnnouncementDelay = 7 * 24 * time.Hour
+
+// Announcement is a server-wide notice shown to every player as a banner
+type Announcement struct {
+	Type      string           `json:"type"` // Always "announcement"
+	ID        string           `json:"id"`
+	Message   string           `json:"message"`
+	Level     string           `json:"level"`
+	Timestamp models.Timestamp `json:"timestamp"`
+	ExpiresAt models.Timestamp `json:"expires_at"`
+}
+
+// AnnounceRequest represents the request to broadcast a server-wide announcement
+type AnnounceRequest struct {
+	Message         string `json:"message"`
+	Level           string `json:"level"`            // info (default), warning or critical
+	DurationSeconds int    `json:"duration_seconds"` // How long the banner stays up
+
+	DeliverAt models.Timestamp `json:"deliver_at"` // Hold the announcement until then; missing or past sends it now
+}
+
+// CancelAnnouncementRequest represents the request to drop a scheduled announcement
+type CancelAnnouncementRequest struct {
+	ID string `json:"id"`
+}
+
+// announcementBoard keeps the announcements that haven't expired yet, so clients
+// that connect later (or sit on the home screen) still see them, and those
+// scheduled for later. Scheduled announcements don't survive a restart.
+type announcementBoard struct {
+	items     []Announcement
+	scheduled map[string]*scheduledAnnouncement
+	seq       int
+	mu        sync.Mutex
+}
+
+// scheduledAnnouncement is an announcement waiting for its delivery time
+type scheduledAnnouncement struct {
+	Announcement
+	timer *time.Timer
+}
+
+// newLocked builds an announcement shown from start for duration (caller must hold lock)
+func (b *announcementBoard) newLocked(message, level string, start time.Time, duration time.Duration) Announcement {
+	b.seq++
+	return Announcement{
+		Type:      "announcement",
+		ID:        fmt.Sprintf("ann_%d_%d", start.Unix(), b.seq),
+		Message:   message,
+		Level:     level,
+		Timestamp: models.At(start),
+		ExpiresAt: models.At(start.Add(duration)),
+	}
+}
+
+// post adds an announcement and drops expired ones
+func (b *announcementBoard) post(message, level string, duration time.Duration) Announcement {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	a := b.newLocked(message, level, now, duration)
+	b.items = append(b.activeLocked(now), a)
+	return a
+}
+
+// schedule holds an announcement until deliverAt, then posts it and hands it to deliver
+func (b *announcementBoard) schedule(message, level string, duration time.Duration, deliverAt time.Time, deliver func(Announcement)) Announcement {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	a := b.newLocked(message, level, deliverAt, duration)
+	if b.scheduled == nil {
+		b.scheduled = make(map[string]*scheduledAnnouncement)
+	}
+	b.scheduled[a.ID] = &scheduledAnnouncement{
+		Announcement: a,
+		timer: time.AfterFunc(time.Until(deliverAt), func() {
+			b.mu.Lock()
+			if _, pending := b.scheduled[a.ID]; !pending {
+				b.mu.Unlock()
+				return // Cancelled
+			}
+			delete(b.scheduled, a.ID)
+			b.items = append(b.activeLocked(time.Now()), a)
+			b.mu.Unlock()
+			deliver(a)
+		}),
+	}
+	return a
+}
+
+// cancel drops a scheduled announcement. Reports false if there is none by that ID.
+func (b *announcementBoard) cancel(id string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	pending, exists := b.scheduled[id]
+	if !exists {
+		return false
+	}
+	pending.timer.Stop()
+	delete(b.scheduled, id)
+	return true
+}
+
+// upcoming returns the scheduled announcements, soonest first
+func (b *announcementBoard) upcoming() []Announcement {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	upcoming := make([]Announcement, 0, len(b.scheduled))
+	for _, pending := range b.scheduled {
+		upcoming = append(upcoming, pending.Announcement)
+	}
+	sort.Slice(upcoming, func(i, j int) bool {
+		return upcoming[i].Timestamp.Before(upcoming[j].Timestamp.Time)
+	})
+	return upcoming
+}
+
+// active returns the announcements that haven't expired
+func (b *announcementBoard) active() []Announcement {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.items = b.activeLocked(time.Now())
+	return append([]Announcement{}, b.items...)
+}
+
+// activeLocked filters out expired announcements (caller must hold lock)
+func (b *announcementBoard) activeLocked(now time.Time) []Announcement {
+	kept := b.items[:0]
+	for _, a := range b.items {
+		if a.ExpiresAt.After(now) {
+			kept = append(kept, a)
+		}
+	}
+	return kept
+}
+
+// SetAdminToken sets the bearer token required by admin routes
+func (h *Handler) SetAdminToken(token string) {
+	h.adminToken = token
+}
+
+// Announce broadcasts a system announcement to every active game and lobby, now or
+// at deliver_at
+func (h *Handler) Announce(w http.ResponseWriter, r *http.Request) {
+	var req AnnounceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	message := strings.TrimSpace(req.Message)
+	if message == "" || len(message) > models.MaxChatMessageLen {
+		respondWithError(w, fmt.Sprintf("message must be 1-%d characters", models.MaxChatMessageLen), http.StatusBadRequest)
+		return
+	}
+
+	level := req.Level
+	switch level {
+	case "":
+		level = AnnouncementInfo
+	case AnnouncementInfo, AnnouncementWarning, AnnouncementCritical:
+	default:
+		respondWithError(w, "level must be info, warning or critical", http.StatusBadRequest)
+		return
+	}
+
+	duration := DefaultAnnouncementDuration
+	if req.DurationSeconds < 0 {
+		respondWithError(w, "duration_seconds must not be negative", http.StatusBadRequest)
+		return
+	} else if req.DurationSeconds > 0 {
+		duration = time.Duration(req.DurationSeconds) * time.Second
+	}
+
+	broadcast := func(announcement Announcement) {
+		if h.hub != nil {
+			h.hub.BroadcastToAll(announcement)
+		}
+	}
+
+	if deliverAt := req.DeliverAt.Time; deliverAt.After(time.Now()) {
+		if time.Until(deliverAt) > MaxAnnouncementDelay {
+			respondWithError(w, "deliver_at must be within 7 days", http.StatusBadRequest)
+			return
+		}
+		announcement := h.announcements.schedule(message, level, duration, deliverAt, broadcast)
+		respondWithJSON(w, announcement, http.StatusAccepted)
+		return
+	}
+
+	announcement := h.announcements.post(message, level, duration)
+	broadcast(announcement)
+
+	respondWithJSON(w, announcement, http.StatusOK)
+}
+
+// GetScheduledAnnouncements lists the announcements waiting to be sent (moderator)
+func (h *Handler) GetScheduledAnnouncements(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, map[string]interface{}{
+		"announcements": h.announcements.upcoming(),
+	}, http.StatusOK)
+}
+
+// CancelAnnouncement drops a scheduled announcement before it is sent (moderator)
+func (h *Handler) CancelAnnouncement(w http.ResponseWriter, r *http.Request) {
+	var req CancelAnnouncementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	if !h.announcements.cancel(req.ID) {
+		respondWithError(w, "No scheduled announcement with that id", http.StatusNotFound)
+		return
+	}
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Announcement cancelled",
+		"id":      req.ID,
+	}, http.StatusOK)
+}
+
+// GetAnnouncements returns the announcements that are still showing
+func (h *Handler) GetAnnouncements(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, map[string]interface{}{
+		"announcements": h.announcements.active(),
+	}, http.StatusOK)
+}
@@ -0,0 +1,17 @@
+package handlers
+
+import "github.com/aminearbi/ludo-nadwa-server/models"
+
+// AFKRemovedEvent tells a game's clients that a player was taken out of the game
+// for letting too many turns in a row time out
+type AFKRemovedEvent struct {
+	Type string `json:"type"` // Always "afk_removed"
+	models.AFKRemoval
+}
+
+// BroadcastAFKRemoved tells clients and integrations that a player was removed for being AFK
+func (h *Hub) BroadcastAFKRemoved(gameCode string, removal models.AFKRemoval) {
+	event := AFKRemovedEvent{Type: "afk_removed", AFKRemoval: removal}
+	h.publish(gameCode, event.Type, event)
+	h.BroadcastEvent(gameCode, event)
+}
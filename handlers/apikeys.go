@@ -0,0 +1,460 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// API key scopes granted to trusted integrations
+const (
+	ScopeGamesCreate   = "games:create"   // Create games in batches
+	ScopeGamesRead     = "games:read"     // Read the state of any game
+	ScopeWebhooksWrite = "webhooks:write" // Register webhooks on any game without hosting it
+)
+
+// APIKeyHeader carries an integration's API key
+const APIKeyHeader = "X-API-Key"
+
+// MaxIntegrationGames caps how many games one batch create request may make
+const MaxIntegrationGames = 50
+
+var (
+	ErrAPIKeyMissing  = errors.New("API key required")
+	ErrAPIKeyInvalid  = errors.New("invalid or revoked API key")
+	ErrAPIKeyScope    = errors.New("API key lacks the required scope")
+	ErrUnknownScope   = errors.New("unknown scope")
+	ErrAPIKeyNotFound = errors.New("API key not found")
+)
+
+// validScopes lists the scopes an API key may be issued with
+var validScopes = map[string]bool{
+	ScopeGamesCreate:   true,
+	ScopeGamesRead:     true,
+	ScopeWebhooksWrite: true,
+}
+
+// APIKey describes an issued key. The key itself is only shown once at issue
+// time; the store keeps its SHA-256 hash.
+type APIKey struct {
+	ID         string           `json:"id"`
+	Name       string           `json:"name"`
+	Scopes     []string         `json:"scopes"`
+	Role       Role             `json:"role,omitempty"` // Admin role granted to the key, if any
+	CreatedAt  models.Timestamp `json:"created_at"`
+	LastUsedAt models.Timestamp `json:"last_used_at,omitempty"`
+	RevokedAt  models.Timestamp `json:"revoked_at,omitempty"`
+	hash       string
+}
+
+// storedAPIKey is how a key is written to the key file
+type storedAPIKey struct {
+	APIKey
+	Hash string `json:"hash"`
+}
+
+// hasScope reports whether the key was granted a scope
+func (k *APIKey) hasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyStore issues, revokes and checks integration API keys. When a file is
+// set, keys are saved there so they survive restarts.
+type APIKeyStore struct {
+	keys []*APIKey
+	path string
+	mu   sync.Mutex
+}
+
+// NewAPIKeyStore creates a store, loading keys from path if it is set and exists
+func NewAPIKeyStore(path string) (*APIKeyStore, error) {
+	s := &APIKeyStore{path: path}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var stored []storedAPIKey
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, sk := range stored {
+		key := sk.APIKey
+		key.hash = sk.Hash
+		s.keys = append(s.keys, &key)
+	}
+	return s, nil
+}
+
+// Issue creates a key with the given scopes and admin role and returns it with its secret value
+func (s *APIKeyStore) Issue(name string, scopes []string, role Role) (APIKey, string, error) {
+	if len(scopes) == 0 && role == "" {
+		return APIKey{}, "", fmt.Errorf("at least one scope or a role is required")
+	}
+	if role != "" && !role.valid() {
+		return APIKey{}, "", fmt.Errorf("%w: %s", ErrUnknownRole, role)
+	}
+	for _, scope := range scopes {
+		if !validScopes[scope] {
+			return APIKey{}, "", fmt.Errorf("%w: %s", ErrUnknownScope, scope)
+		}
+	}
+
+	token := "ludo_" + models.GenerateSessionSecret()
+	key := &APIKey{
+		ID:        "key_" + models.GenerateSessionSecret()[:12],
+		Name:      name,
+		Scopes:    scopes,
+		Role:      role,
+		CreatedAt: models.Now(),
+		hash:      hashAPIKey(token),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = append(s.keys, key)
+	if err := s.saveLocked(); err != nil {
+		s.keys = s.keys[:len(s.keys)-1]
+		return APIKey{}, "", err
+	}
+	return *key, token, nil
+}
+
+// Revoke disables a key. Revoked keys stay listed for auditing.
+func (s *APIKeyStore) Revoke(id string) (APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range s.keys {
+		if key.ID != id {
+			continue
+		}
+		if key.RevokedAt.IsZero() {
+			key.RevokedAt = models.Now()
+			if err := s.saveLocked(); err != nil {
+				key.RevokedAt = models.Timestamp{}
+				return APIKey{}, err
+			}
+		}
+		return *key, nil
+	}
+	return APIKey{}, ErrAPIKeyNotFound
+}
+
+// List returns every issued key, newest first
+func (s *APIKeyStore) List() []APIKey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]APIKey, 0, len(s.keys))
+	for i := len(s.keys) - 1; i >= 0; i-- {
+		keys = append(keys, *s.keys[i])
+	}
+	return keys
+}
+
+// Authorize checks a presented key against a scope and records its use
+func (s *APIKeyStore) Authorize(token, scope string) (APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, err := s.lookupLocked(token)
+	if err != nil {
+		return APIKey{}, err
+	}
+	if !key.hasScope(scope) {
+		return APIKey{}, ErrAPIKeyScope
+	}
+	key.LastUsedAt = models.Now()
+	return *key, nil
+}
+
+// Lookup returns the live key matching a presented token and records its use
+func (s *APIKeyStore) Lookup(token string) (APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, err := s.lookupLocked(token)
+	if err != nil {
+		return APIKey{}, err
+	}
+	key.LastUsedAt = models.Now()
+	return *key, nil
+}
+
+// HasRoles reports whether any live key carries an admin role
+func (s *APIKeyStore) HasRoles() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range s.keys {
+		if key.Role != "" && key.RevokedAt.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupLocked finds the live key for a token (caller must hold lock)
+func (s *APIKeyStore) lookupLocked(token string) (*APIKey, error) {
+	if token == "" {
+		return nil, ErrAPIKeyMissing
+	}
+	hash := hashAPIKey(token)
+
+	for _, key := range s.keys {
+		if subtle.ConstantTimeCompare([]byte(key.hash), []byte(hash)) != 1 {
+			continue
+		}
+		if !key.RevokedAt.IsZero() {
+			return nil, ErrAPIKeyInvalid
+		}
+		return key, nil
+	}
+	return nil, ErrAPIKeyInvalid
+}
+
+// saveLocked writes the keys to the store's file, if any (caller must hold lock)
+func (s *APIKeyStore) saveLocked() error {
+	if s.path == "" {
+		return nil
+	}
+	stored := make([]storedAPIKey, len(s.keys))
+	for i, key := range s.keys {
+		stored[i] = storedAPIKey{APIKey: *key, Hash: key.hash}
+	}
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// hashAPIKey returns the hex SHA-256 of a key
+func hashAPIKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IssueAPIKeyRequest represents the request to issue an integration API key
+type IssueAPIKeyRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"` // games:create, games:read, webhooks:write
+	Role   Role     `json:"role"`   // Optional admin role: viewer, moderator or operator
+}
+
+// RevokeAPIKeyRequest represents the request to revoke an API key
+type RevokeAPIKeyRequest struct {
+	ID string `json:"id"`
+}
+
+// IntegrationCreateGamesRequest represents the request to create a batch of games
+type IntegrationCreateGamesRequest struct {
+	Games []CreateGameRequest `json:"games"`
+}
+
+// IntegrationWebhookRequest represents the request to register a webhook on any game
+type IntegrationWebhookRequest struct {
+	Code   string   `json:"code"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret,omitempty"`
+}
+
+// SetAPIKeys sets the store used to issue and check integration API keys
+func (h *Handler) SetAPIKeys(store *APIKeyStore) {
+	h.apiKeys = store
+}
+
+// RequireAPIKey returns middleware rejecting requests whose API key is missing,
+// revoked or lacks the scope. The key is read from X-API-Key, or from an
+// Authorization bearer token.
+func (h *Handler) RequireAPIKey(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, err := h.apiKeys.Authorize(apiKeyFromRequest(r), scope); err != nil {
+				status := http.StatusUnauthorized
+				if errors.Is(err, ErrAPIKeyScope) {
+					status = http.StatusForbidden
+				}
+				respondWithErr(w, err, status)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// apiKeyFromRequest reads an API key from X-API-Key or an Authorization bearer token
+func apiKeyFromRequest(r *http.Request) string {
+	if token := r.Header.Get(APIKeyHeader); token != "" {
+		return token
+	}
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+// ListAPIKeys returns every issued integration API key (operator)
+func (h *Handler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, map[string]interface{}{
+		"keys": h.apiKeys.List(),
+	}, http.StatusOK)
+}
+
+// IssueAPIKey creates an integration API key (operator)
+func (h *Handler) IssueAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req IssueAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		respondWithError(w, "name is required", http.StatusBadRequest)
+		return
+	}
+
+	key, token, err := h.apiKeys.Issue(strings.TrimSpace(req.Name), req.Scopes, req.Role)
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+	respondWithJSON(w, map[string]interface{}{
+		"key":     key,
+		"api_key": token, // Only returned once
+	}, http.StatusCreated)
+}
+
+// RevokeAPIKey disables an integration API key (admin only)
+func (h *Handler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req RevokeAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	key, err := h.apiKeys.Revoke(req.ID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrAPIKeyNotFound) {
+			status = http.StatusNotFound
+		}
+		respondWithErr(w, err, status)
+		return
+	}
+	respondWithJSON(w, map[string]interface{}{
+		"key": key,
+	}, http.StatusOK)
+}
+
+// IntegrationCreateGames creates a batch of games, e.g. for a tournament round (games:create)
+func (h *Handler) IntegrationCreateGames(w http.ResponseWriter, r *http.Request) {
+	var req IntegrationCreateGamesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+	if len(req.Games) == 0 || len(req.Games) > MaxIntegrationGames {
+		respondWithError(w, fmt.Sprintf("games must list 1-%d games", MaxIntegrationGames), http.StatusBadRequest)
+		return
+	}
+	for _, g := range req.Games {
+		if g.PlayerID == "" || g.PlayerName == "" {
+			respondWithError(w, "Player ID and name are required for every game", http.StatusBadRequest)
+			return
+		}
+	}
+
+	created := make([]CreateGameResponse, 0, len(req.Games))
+	for _, g := range req.Games {
+		game, err := h.gameManager.CreateGame(r.Context(), g.PlayerID, g.PlayerName, g.MaxPlayers)
+		if err != nil {
+			// Don't leave half a batch behind
+			for _, c := range created {
+				h.gameManager.RemoveGame(c.Code)
+			}
+			respondWithErr(w, err, unavailableStatus(err, http.StatusBadRequest))
+			return
+		}
+		created = append(created, CreateGameResponse{
+			Code:          game.Code,
+			Message:       "Game created successfully. Share this code with other players.",
+			MaxPlayers:    game.MaxPlayers,
+			SessionSecret: game.SessionSecret(g.PlayerID),
+		})
+	}
+
+	respondWithJSON(w, map[string]interface{}{
+		"games": created,
+	}, http.StatusCreated)
+}
+
+// IntegrationGameState returns the state of one game, or of every game when no code is given (games:read)
+func (h *Handler) IntegrationGameState(w http.ResponseWriter, r *http.Request) {
+	if code := r.URL.Query().Get("code"); code != "" {
+		game, err := h.gameManager.GetGame(r.Context(), code)
+		if err != nil {
+			respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+			return
+		}
+		respondWithJSON(w, game.GetGameState(), http.StatusOK)
+		return
+	}
+
+	games := h.gameManager.GetAllGames()
+	states := make([]map[string]interface{}, 0, len(games))
+	for _, game := range games {
+		if err := r.Context().Err(); err != nil {
+			respondWithErr(w, err, unavailableStatus(err, http.StatusServiceUnavailable))
+			return
+		}
+		states = append(states, game.GetGameState())
+	}
+	respondWithJSON(w, map[string]interface{}{
+		"games": states,
+	}, http.StatusOK)
+}
+
+// IntegrationRegisterWebhook registers a webhook on any game without hosting it (webhooks:write)
+func (h *Handler) IntegrationRegisterWebhook(w http.ResponseWriter, r *http.Request) {
+	var req IntegrationWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+	if _, err := h.gameManager.GetGame(r.Context(), req.Code); err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	webhook, secret, err := h.gameWebhooks.Register(req.Code, req.URL, req.Events, req.Secret)
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+	respondWithJSON(w, map[string]interface{}{
+		"webhook": webhook,
+		"secret":  secret, // Only returned once; used to verify X-Ludo-Signature
+	}, http.StatusOK)
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// GetArchivedGame returns the full record of the last game with a code to end,
+// kept after the game itself is cleaned up: its summary, final state and
+// complete history. ?id= picks an earlier game by its replay ID.
+func (h *Handler) GetArchivedGame(w http.ResponseWriter, r *http.Request) {
+	code := gameCodeParam(r)
+	if code == "" {
+		respondWithErr(w, errCodeRequired, http.StatusBadRequest)
+		return
+	}
+
+	record, err := h.gameManager.Archive().Load(r.Context(), code, r.URL.Query().Get("id"))
+	if err != nil {
+		status := http.StatusBadGateway // The archive's storage or bucket failed
+		if errors.Is(err, models.ErrArchiveNotFound) || errors.Is(err, models.ErrArchiveDisabled) {
+			status = http.StatusNotFound
+		}
+		respondWithErr(w, err, unavailableStatus(err, status))
+		return
+	}
+	w.Header().Set("Cache-Control", replayCacheControl)
+	respondWithJSON(w, record, http.StatusOK)
+}
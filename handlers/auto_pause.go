@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"log"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// autoPauseQueueSize bounds presence changes waiting to be checked
+const autoPauseQueueSize = 1024
+
+// AutoPause pauses games whose human players have all disconnected and resumes
+// them once enough are back. It is a hub sink: events queue the game for a check
+// on its own worker, since pausing broadcasts through the hub.
+type AutoPause struct {
+	gameManager *models.GameManager
+	hub         *Hub
+	events      chan Event
+	abandoned   map[string]bool // Games whose players all dropped; only touched by the worker
+}
+
+// NewAutoPause creates the auto-pauser and starts its worker. Register it as a hub sink.
+func NewAutoPause(gm *models.GameManager, hub *Hub) *AutoPause {
+	a := &AutoPause{
+		gameManager: gm,
+		hub:         hub,
+		events:      make(chan Event, autoPauseQueueSize),
+		abandoned:   make(map[string]bool),
+	}
+	go a.run()
+	return a
+}
+
+// Publish queues presence changes, and resumes that may have left a game with
+// nobody connected (such as a pause running out)
+func (a *AutoPause) Publish(event Event) {
+	switch event.Type {
+	case "player_connected", "player_disconnected", "game_resumed", "game_removed":
+	default:
+		return
+	}
+	select {
+	case a.events <- event:
+	default:
+		log.Printf("Auto-pause: queue full, dropped check for game %s", event.GameCode)
+	}
+}
+
+// run checks each queued game
+func (a *AutoPause) run() {
+	for event := range a.events {
+		switch event.Type {
+		case "game_removed":
+			delete(a.abandoned, event.GameCode)
+		case "game_resumed":
+			// Games played without WebSockets never count as abandoned
+			if a.abandoned[event.GameCode] {
+				a.check(event.GameCode)
+			}
+		default:
+			a.check(event.GameCode)
+		}
+	}
+}
+
+// check pauses or resumes a game to match who is connected
+func (a *AutoPause) check(code string) {
+	game, err := a.gameManager.GetGame(context.Background(), code)
+	if err != nil {
+		delete(a.abandoned, code)
+		return
+	}
+	connected := a.hub.DeviceCounts(code)
+	if game.Abandoned(connected) {
+		a.abandoned[code] = true
+	} else {
+		delete(a.abandoned, code)
+	}
+
+	if game.PauseIfAbandoned(connected) {
+		log.Printf("All players disconnected from game %s, pausing", code)
+		a.hub.BroadcastRefresh(code, "game_paused")
+	} else if game.ResumeIfReconnected(connected) {
+		log.Printf("Players reconnected to game %s, resuming", code)
+		a.hub.BroadcastRefresh(code, "game_resumed")
+	} else if game.IsPausedBy(models.DisconnectPausedBy) {
+		missing, needed := game.WaitingFor("", connected)
+		a.hub.BroadcastWaiting(code, missing, needed)
+	}
+}
+
+// WaitingEvent tells a game's clients that a resume is waiting for players to reconnect
+type WaitingEvent struct {
+	Type    string   `json:"type"`    // Always "waiting_for_players"
+	Missing []string `json:"missing"` // Human players who aren't connected
+	Needed  int      `json:"needed"`  // How many more must connect
+}
+
+// BroadcastWaiting tells clients and integrations who a resume is waiting for
+func (h *Hub) BroadcastWaiting(gameCode string, missing []string, needed int) {
+	event := WaitingEvent{Type: "waiting_for_players", Missing: missing, Needed: needed}
+	h.publish(gameCode, event.Type, event)
+	h.BroadcastEvent(gameCode, event)
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// autoTurnQueueSize bounds games waiting to be checked for an automatic roll or move
+const autoTurnQueueSize = 1024
+
+// AutoRollRequest represents the request to turn automatic rolling on or off.
+// Players set their own unless GameDefault is set, which changes the game's (host only).
+type AutoRollRequest struct {
+	Code        string `json:"code"`
+	PlayerID    string `json:"player_id"`
+	Enabled     bool   `json:"enabled"`
+	GameDefault bool   `json:"game_default,omitempty"`
+}
+
+// AutoMoveRequest represents the request to turn automatic play of a lone valid
+// move on or off, for a player or, with GameDefault, the whole game (host only)
+type AutoMoveRequest struct {
+	Code        string `json:"code"`
+	PlayerID    string `json:"player_id"`
+	Enabled     bool   `json:"enabled"`
+	GameDefault bool   `json:"game_default,omitempty"`
+}
+
+// PreMoveRequest represents the request to queue a move for the player's next
+// turn. A null piece_id clears it.
+type PreMoveRequest struct {
+	Code     string `json:"code"`
+	PlayerID string `json:"player_id"`
+	PieceID  *int   `json:"piece_id"`
+}
+
+// AutoTurns rolls for players who have asked for it as soon as their turn
+// starts, and plays their move when only one piece can move. It is a hub sink:
+// any game event may have started a turn or rolled the dice, so the game is
+// queued for a check on its own worker, since playing broadcasts through the hub.
+type AutoTurns struct {
+	gameManager *models.GameManager
+	hub         *Hub
+	changed     chan string
+}
+
+// NewAutoTurns creates the turn automation and starts its worker. Register it as a hub sink.
+func NewAutoTurns(gm *models.GameManager, hub *Hub) *AutoTurns {
+	a := &AutoTurns{
+		gameManager: gm,
+		hub:         hub,
+		changed:     make(chan string, autoTurnQueueSize),
+	}
+	go a.run()
+	return a
+}
+
+// Publish queues the event's game to be checked for a turn waiting on a roll or move
+func (a *AutoTurns) Publish(event Event) {
+	select {
+	case a.changed <- event.GameCode:
+	default:
+		log.Printf("Auto-turns: queue full, dropped check for game %s", event.GameCode)
+	}
+}
+
+// run takes one automatic step for each queued game that needs it. The step's
+// own event queues the game again, so a roll is followed by its move, and a
+// turn passed on by three sixes or a move gets its roll.
+func (a *AutoTurns) run() {
+	for code := range a.changed {
+		game, err := a.gameManager.GetGame(context.Background(), code)
+		if err != nil {
+			continue
+		}
+		switch playerID, action := game.PlayAutoStep(); action {
+		case models.AutoRolled:
+			a.hub.BroadcastAutoRefresh(code, "dice_rolled")
+			a.hub.sendMoveHints(game, playerID)
+		case models.AutoMoved, models.AutoPreMoved:
+			a.hub.BroadcastAutoRefresh(code, "piece_moved")
+			if game.ConsumeBotChat() {
+				a.hub.BroadcastRefresh(code, "chat_message")
+			}
+			if lines := game.ConsumeCommentary(); len(lines) > 0 {
+				a.hub.BroadcastCommentary(code, lines)
+			}
+		}
+	}
+}
+
+// SetAutoRoll turns automatic rolling on or off for a player, or for the whole game
+func (h *Handler) SetAutoRoll(w http.ResponseWriter, r *http.Request) {
+	var req AutoRollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	if req.GameDefault {
+		err = game.SetAutoRoll(req.PlayerID, req.Enabled)
+	} else {
+		err = game.SetPlayerAutoRoll(req.PlayerID, req.Enabled)
+	}
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	// Also rolls straight away if the player whose turn it is now auto-rolls
+	h.broadcastRefresh(req.Code, "auto_roll_changed")
+
+	respondWithJSON(w, map[string]interface{}{
+		"message":   "Auto-roll updated",
+		"auto_roll": req.Enabled,
+	}, http.StatusOK)
+}
+
+// SetAutoMove turns automatic play of a lone valid move on or off for a player,
+// or for the whole game
+func (h *Handler) SetAutoMove(w http.ResponseWriter, r *http.Request) {
+	var req AutoMoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	if req.GameDefault {
+		err = game.SetAutoMove(req.PlayerID, req.Enabled)
+	} else {
+		err = game.SetPlayerAutoMove(req.PlayerID, req.Enabled)
+	}
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	// Also plays the current move straight away if it is now automatic
+	h.broadcastRefresh(req.Code, "auto_move_changed")
+
+	respondWithJSON(w, map[string]interface{}{
+		"message":   "Auto-move updated",
+		"auto_move": req.Enabled,
+	}, http.StatusOK)
+}
+
+// QueueMove queues or clears a player's move for their next turn. Nothing is
+// broadcast: opponents don't get to see it coming.
+func (h *Handler) QueueMove(w http.ResponseWriter, r *http.Request) {
+	var req PreMoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	pieceID := -1
+	if req.PieceID != nil {
+		pieceID = *req.PieceID
+	}
+	if err := game.QueueMove(req.PlayerID, pieceID); err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	message := "Pre-move cleared"
+	if req.PieceID != nil {
+		message = "Pre-move queued"
+	}
+	respondWithJSON(w, map[string]interface{}{
+		"message":  message,
+		"piece_id": req.PieceID,
+	}, http.StatusOK)
+}
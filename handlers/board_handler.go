@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// PaletteRequest represents the request to pick a display palette. Players set
+// their own unless GameDefault is set, which changes the game's (host only).
+type PaletteRequest struct {
+	Code        string `json:"code"`
+	PlayerID    string `json:"player_id"`
+	Palette     string `json:"palette"` // Empty clears a player's own choice
+	GameDefault bool   `json:"game_default,omitempty"`
+}
+
+// GetBoard returns the canonical board geometry so clients can render from server truth
+func (h *Handler) GetBoard(w http.ResponseWriter, r *http.Request) {
+	boardType := models.BoardType(r.URL.Query().Get("type"))
+	if boardType == "" {
+		boardType = models.BoardSquare
+	}
+
+	board, ok := models.BoardByType(boardType)
+	if !ok {
+		respondWithError(w, "type must be square or hex", http.StatusBadRequest)
+		return
+	}
+
+	geometry := board.Geometry()
+	if paletteID := r.URL.Query().Get("palette"); paletteID != "" {
+		palette, ok := models.PaletteByID(paletteID)
+		if !ok {
+			respondWithErr(w, models.ErrUnknownPalette, http.StatusBadRequest)
+			return
+		}
+		geometry.UsePalette(palette)
+	}
+
+	respondWithJSON(w, geometry, http.StatusOK)
+}
+
+// GetPalettes returns the catalogue of display palettes
+func (h *Handler) GetPalettes(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, map[string]interface{}{
+		"default":  models.DefaultPalette,
+		"palettes": models.Palettes,
+	}, http.StatusOK)
+}
+
+// SetPalette picks the palette a player sees a game in, or the game's own
+func (h *Handler) SetPalette(w http.ResponseWriter, r *http.Request) {
+	var req PaletteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	if req.GameDefault {
+		err = game.SetPalette(req.PlayerID, req.Palette)
+	} else {
+		err = game.SetPlayerPalette(req.PlayerID, req.Palette)
+	}
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	if req.GameDefault {
+		h.broadcastRefresh(req.Code, "palette_changed")
+	}
+
+	palette, seatColors := game.SeatColors(req.PlayerID)
+	respondWithJSON(w, map[string]interface{}{
+		"message":     "Palette updated",
+		"palette":     palette,
+		"seat_colors": seatColors,
+	}, http.StatusOK)
+}
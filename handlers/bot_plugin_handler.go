@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// BotPromptEvent asks an external controller to act for its bot seat
+type BotPromptEvent struct {
+	Type string `json:"type"` // Always "bot_prompt"
+	*models.BotPrompt
+}
+
+// botPromptClient delivers prompts to controller callback URLs, which only reach
+// destinations CheckOutboundURL allows
+var botPromptClient = newOutboundClient(5 * time.Second)
+
+// SendBotPrompt delivers a turn prompt over WebSocket and, if registered, the controller's callback URL
+func (h *Hub) SendBotPrompt(prompt *models.BotPrompt) {
+	event := BotPromptEvent{Type: "bot_prompt", BotPrompt: prompt}
+	h.BroadcastEvent(prompt.GameCode, event)
+	if prompt.CallbackURL != "" {
+		go postJSON(botPromptClient, prompt.CallbackURL, event)
+	}
+}
+
+// postJSON POSTs a JSON payload to a URL and logs failures
+func postJSON(client *http.Client, url string, payload interface{}) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Error marshaling webhook payload: %v", err)
+		return
+	}
+
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Printf("Webhook %s failed: %v", url, err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		log.Printf("Webhook %s returned status %d", url, resp.StatusCode)
+	}
+}
+
+// ClaimBotRequest represents the request to hand a bot seat to an external controller
+type ClaimBotRequest struct {
+	Code        string `json:"code"`
+	HostID      string `json:"host_id"`
+	BotID       string `json:"bot_id"`
+	CallbackURL string `json:"callback_url,omitempty"` // Optional webhook for turn prompts
+}
+
+// ReleaseBotRequest represents the request to return a bot seat to the built-in AI
+type ReleaseBotRequest struct {
+	Code  string `json:"code"`
+	BotID string `json:"bot_id"`
+	Token string `json:"token"`
+}
+
+// BotActionRequest represents an action taken by an external bot controller
+type BotActionRequest struct {
+	Code    string `json:"code"`
+	BotID   string `json:"bot_id"`
+	Token   string `json:"token"`
+	Action  string `json:"action"` // "roll", "move" or "skip"
+	PieceID int    `json:"piece_id"`
+}
+
+// ClaimBot handles an external controller claiming a bot seat
+func (h *Handler) ClaimBot(w http.ResponseWriter, r *http.Request) {
+	var req ClaimBotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	if req.CallbackURL != "" {
+		if err := CheckOutboundURL(req.CallbackURL); err != nil {
+			respondWithErr(w, err, http.StatusBadRequest)
+			return
+		}
+	}
+
+	token, err := game.ClaimBotSeat(req.HostID, req.BotID, req.CallbackURL)
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	h.broadcastRefresh(req.Code, "bot_claimed")
+
+	respondWithJSON(w, map[string]interface{}{
+		"message":          "Bot seat claimed",
+		"bot_id":           req.BotID,
+		"token":            token,
+		"response_timeout": models.ExternalBotResponseTimeout.Seconds(),
+	}, http.StatusOK)
+}
+
+// ReleaseBot handles an external controller giving a bot seat back
+func (h *Handler) ReleaseBot(w http.ResponseWriter, r *http.Request) {
+	var req ReleaseBotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	if err := game.ReleaseBotSeat(req.BotID, req.Token); err != nil {
+		respondWithErr(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	h.broadcastRefresh(req.Code, "bot_released")
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Bot seat released",
+	}, http.StatusOK)
+}
+
+// BotAction handles a roll, move or skip submitted by an external bot controller
+func (h *Handler) BotAction(w http.ResponseWriter, r *http.Request) {
+	var req BotActionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	if err := game.AuthorizeBotController(req.BotID, req.Token); err != nil {
+		respondWithErr(w, err, http.StatusUnauthorized)
+		return
+	}
+
+	var hint string
+	switch req.Action {
+	case "roll":
+		roll, err := game.RollDice(req.BotID)
+		if err != nil && err != models.ErrThreeSixes {
+			respondWithErr(w, err, http.StatusBadRequest)
+			return
+		}
+		h.broadcastRefresh(req.Code, "dice_rolled")
+		respondWithJSON(w, RollDiceResponse{
+			Roll:       roll,
+			ValidMoves: game.GetValidMoves(req.BotID),
+			Moves:      game.GetValidMoveDetails(req.BotID),
+			HasMoves:   game.HasValidMoves(req.BotID),
+		}, http.StatusOK)
+		return
+	case "move":
+		err = game.MovePiece(req.BotID, req.PieceID)
+		hint = "piece_moved"
+	case "skip":
+		if game.HasValidMoves(req.BotID) {
+			respondWithErr(w, errSkipWithMoves, http.StatusBadRequest)
+			return
+		}
+		err = game.SkipTurn(req.BotID)
+		hint = "turn_skipped"
+	default:
+		respondWithError(w, "action must be roll, move or skip", http.StatusBadRequest)
+		return
+	}
+
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	h.broadcastRefresh(req.Code, hint)
+	h.broadcastBotChat(game)
+	h.broadcastCommentary(game)
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Action applied",
+		"game":    game.GetGameState(),
+	}, http.StatusOK)
+}
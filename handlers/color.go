@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// SetColorRequest represents the request to change color in the lobby
+type SetColorRequest struct {
+	Code     string             `json:"code"`
+	PlayerID string             `json:"player_id"`
+	Color    models.PlayerColor `json:"color"`
+}
+
+// SetColor handles a player changing color while the game waits for players
+func (h *Handler) SetColor(w http.ResponseWriter, r *http.Request) {
+	var req SetColorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	if req.PlayerID == "" || req.Color == "" {
+		respondWithError(w, "player_id and color are required", http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	if err := game.ChangeColor(req.PlayerID, req.Color); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, models.ErrColorTaken) {
+			status = http.StatusConflict
+		}
+		respondWithErr(w, err, status)
+		return
+	}
+
+	h.broadcastRefresh(req.Code, "color_changed")
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Color changed",
+		"game":    game.GetGameState(),
+	}, http.StatusOK)
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// Errors answering WebSocket commands
+var (
+	errUnknownCommand  = errors.New("unknown command")
+	errPieceIDRequired = errors.New("piece_id is required")
+	errCommandsOff     = errors.New("commands are not enabled on this server")
+)
+
+// WSCommand is a game action sent over the game's WebSocket instead of REST. It
+// acts as the connected player, and the reply (an ack or an error) carries the
+// same request ID so clients can match it up.
+type WSCommand struct {
+	Type      string `json:"type"` // roll, move, skip, ready, chat, emote or leave
+	RequestID string `json:"request_id,omitempty"`
+	PieceID   *int   `json:"piece_id,omitempty"` // move
+	Ready     *bool  `json:"ready,omitempty"`    // ready; default true
+	Message   string `json:"message,omitempty"`  // chat
+	Emote     string `json:"emote,omitempty"`    // emote
+	Nonce     string `json:"nonce,omitempty"`    // Signature over the command's REST path, in strict signing mode
+	Signature string `json:"signature,omitempty"`
+}
+
+// WSCommandAck reports a command that succeeded, with what the REST endpoint would return
+type WSCommandAck struct {
+	Type      string      `json:"type"` // Always "ack"
+	RequestID string      `json:"request_id,omitempty"`
+	Command   string      `json:"command"`
+	Result    interface{} `json:"result"`
+}
+
+// WSCommandError reports a command that failed
+type WSCommandError struct {
+	Type      string `json:"type"` // Always "error"
+	RequestID string `json:"request_id,omitempty"`
+	Command   string `json:"command"`
+	Error     string `json:"error"`
+	Code      string `json:"code"` // Machine-readable, as in REST error responses
+}
+
+// commandPaths maps each command to the REST endpoint it stands in for. In strict
+// signing mode a command is signed over that path, just like the request would be.
+var commandPaths = map[string]string{
+	"roll":  "/api/game/roll",
+	"move":  "/api/game/move",
+	"skip":  "/api/game/skip",
+	"ready": "/api/game/ready",
+	"chat":  "/api/game/chat",
+	"emote": "/api/game/emote",
+	"leave": "/api/game/leave",
+}
+
+// isCommand reports whether a WebSocket message type is a game command
+func isCommand(messageType string) bool {
+	_, ok := commandPaths[messageType]
+	return ok
+}
+
+// RunCommand carries out a WebSocket command for a connected player, through the
+// same game actions and broadcasts as the REST endpoints
+func (h *Handler) RunCommand(ctx context.Context, gameCode, playerID string, cmd WSCommand) (interface{}, error) {
+	path, ok := commandPaths[cmd.Type]
+	if !ok {
+		return nil, errUnknownCommand
+	}
+
+	game, err := h.gameManager.GetGame(ctx, gameCode)
+	if err != nil {
+		return nil, err
+	}
+	if h.strictSigning {
+		if err := game.VerifyActionSignature(playerID, path, cmd.Nonce, cmd.Signature); err != nil {
+			return nil, err
+		}
+	}
+
+	switch cmd.Type {
+	case "roll":
+		return h.rollDice(game, playerID)
+	case "move":
+		if cmd.PieceID == nil {
+			return nil, errPieceIDRequired
+		}
+		return h.movePiece(game, playerID, *cmd.PieceID)
+	case "skip":
+		return h.skipTurn(game, playerID)
+	case "ready":
+		ready := cmd.Ready == nil || *cmd.Ready
+		return h.setReady(game, playerID, ready)
+	case "chat":
+		return h.sendChat(game, playerID, cmd.Message)
+	case "emote":
+		return h.sendEmote(game, playerID, cmd.Emote)
+	default: // leave
+		return h.leaveGame(game, playerID)
+	}
+}
+
+// SetCommands lets clients send game commands over the WebSocket, carried out by
+// the REST handler so both transports behave the same
+func (wsh *WebSocketHandler) SetCommands(h *Handler) {
+	wsh.commands = h
+}
+
+// runCommand carries out a command from the client and replies with an ack or error
+func (c *Client) runCommand(wsh *WebSocketHandler, message []byte) {
+	var cmd WSCommand
+	if err := json.Unmarshal(message, &cmd); err != nil {
+		return
+	}
+
+	var result interface{}
+	err := errCommandsOff
+	if wsh.commands != nil {
+		result, err = wsh.commands.RunCommand(context.Background(), c.gameCode, c.playerID, cmd)
+	}
+
+	var reply interface{} = WSCommandAck{Type: "ack", RequestID: cmd.RequestID, Command: cmd.Type, Result: result}
+	if err != nil {
+		reply = WSCommandError{Type: "error", RequestID: cmd.RequestID, Command: cmd.Type, Error: err.Error(), Code: errorCode(err, http.StatusBadRequest)}
+	}
+	response, _ := json.Marshal(reply)
+	c.reply(response)
+}
+
+// rejectCommand answers a message of an unknown type that expects a reply
+func (c *Client) rejectCommand(messageType, requestID string) {
+	response, _ := json.Marshal(WSCommandError{Type: "error", RequestID: requestID, Command: messageType, Error: errUnknownCommand.Error(), Code: errorCode(errUnknownCommand, http.StatusBadRequest)})
+	c.reply(response)
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var ErrInvalidOrigin = errors.New("invalid CORS origin")
+
+// corsAllowedHeaders are the request headers browsers may send cross-origin
+var corsAllowedHeaders = strings.Join([]string{
+	"Content-Type", "Authorization", "X-Signature", "X-Signature-Nonce", "X-API-Key", IdempotencyHeader,
+}, ", ")
+
+// CORSPolicy decides which browser origins may call the API and open WebSockets
+type CORSPolicy struct {
+	Origins     []string // e.g. https://ludo.example.com; empty or "*" allows any origin
+	Credentials bool     // Let browsers send cookies and auth headers cross-origin (needs Origins)
+}
+
+// corsPolicy applies to every route and WebSocket upgrade; any origin by default
+var corsPolicy CORSPolicy
+
+// SetCORSPolicy sets which origins may use the server. Call before the server starts.
+func SetCORSPolicy(policy CORSPolicy) error {
+	if policy.Credentials && policy.allowsAny() {
+		return fmt.Errorf("%w: credentials need a list of origins, not *", ErrInvalidOrigin)
+	}
+	corsPolicy = policy
+	return nil
+}
+
+// ParseCORSOrigins parses "https://a.example.com,https://b.example.com" or "*"
+func ParseCORSOrigins(spec string) ([]string, error) {
+	var origins []string
+	for _, origin := range strings.Split(spec, ",") {
+		origin = strings.TrimSuffix(strings.TrimSpace(origin), "/")
+		if origin == "" {
+			continue
+		}
+		if origin == "*" {
+			origins = append(origins, origin)
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.Path != "" {
+			return nil, fmt.Errorf("%w %q (want scheme://host[:port])", ErrInvalidOrigin, origin)
+		}
+		origins = append(origins, strings.ToLower(origin))
+	}
+	return origins, nil
+}
+
+// allowsAny reports whether the policy lets in every origin
+func (p CORSPolicy) allowsAny() bool {
+	if len(p.Origins) == 0 {
+		return true
+	}
+	for _, origin := range p.Origins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// allows reports whether an origin is on the list
+func (p CORSPolicy) allows(origin string) bool {
+	if p.allowsAny() {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	for _, allowed := range p.Origins {
+		if allowed == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// CORS is middleware that adds CORS headers for allowed origins and answers
+// preflight requests. Preflights from other origins are refused.
+func CORS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		policy := corsPolicy
+		origin := r.Header.Get("Origin")
+
+		if policy.allowsAny() {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			w.Header().Add("Vary", "Origin")
+			if origin != "" && policy.allows(origin) {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				if policy.Credentials {
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
+				}
+			} else if r.Method == http.MethodOptions && origin != "" {
+				respondWithError(w, "Origin not allowed", http.StatusForbidden)
+				return
+			}
+		}
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+
+		// Handle preflight requests
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// checkOrigin lets a WebSocket upgrade through from allowed origins, the server's
+// own pages, and clients that aren't browsers (which send no Origin)
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || corsPolicy.allows(origin) {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
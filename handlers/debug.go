@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// SetDebug enables the /api/debug routes, which let anyone set up arbitrary games.
+// Never enable them on a public server.
+func (h *Handler) SetDebug(enabled bool) {
+	h.debug = enabled
+}
+
+// RequireDebug is middleware rejecting requests unless debug mode is enabled
+func (h *Handler) RequireDebug(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !h.debug {
+			respondWithError(w, "Debug API is disabled", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// CreateFixtureGame creates a game in progress from a board-state fixture and
+// returns its code with every player's session secret
+func (h *Handler) CreateFixtureGame(w http.ResponseWriter, r *http.Request) {
+	var fixture models.Fixture
+	if err := json.NewDecoder(r.Body).Decode(&fixture); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.CreateFixtureGame(r.Context(), fixture)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	secrets := make(map[string]string, len(fixture.Players))
+	for _, p := range fixture.Players {
+		secrets[p.ID] = game.SessionSecret(p.ID)
+	}
+	h.broadcastRefresh(game.Code, "game_created")
+
+	respondWithJSON(w, map[string]interface{}{
+		"code":            game.Code,
+		"session_secrets": secrets,
+		"state":           game.GetGameState(),
+	}, http.StatusOK)
+}
+
+// GetFixture returns a game's board as a fixture, for reproducing it elsewhere
+func (h *Handler) GetFixture(w http.ResponseWriter, r *http.Request) {
+	game, err := h.gameManager.GetGame(r.Context(), r.URL.Query().Get("code"))
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+	respondWithJSON(w, game.Fixture(), http.StatusOK)
+}
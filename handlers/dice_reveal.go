@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// RollAckRequest represents the request to report a dice animation has finished
+type RollAckRequest struct {
+	Code     string `json:"code"`
+	PlayerID string `json:"player_id"` // Player or spectator
+	RollID   string `json:"roll_id"`
+}
+
+// DiceRollingEvent tells clients to start animating a roll whose value comes later
+type DiceRollingEvent struct {
+	Type string `json:"type"` // Always "dice_rolling"
+	models.PendingRoll
+}
+
+// DiceRevealedEvent gives the value of a roll every client has been animating
+type DiceRevealedEvent struct {
+	Type     string `json:"type"` // Always "dice_revealed"
+	RollID   string `json:"roll_id"`
+	PlayerID string `json:"player_id"`
+	Roll     int    `json:"roll"`
+}
+
+// beginRoll starts a held-back roll and schedules its reveal, returning the response
+func (h *Handler) beginRoll(game *models.Game, pending models.PendingRoll) map[string]interface{} {
+	if h.hub != nil {
+		event := DiceRollingEvent{Type: "dice_rolling", PendingRoll: pending}
+		h.hub.publish(game.Code, event.Type, event)
+		h.hub.BroadcastEvent(game.Code, event)
+	}
+	time.AfterFunc(time.Until(pending.RevealAt.Time), func() {
+		h.revealRoll(game, pending.RollID)
+	})
+
+	return map[string]interface{}{
+		"message":   "Dice rolling",
+		"roll_id":   pending.RollID,
+		"reveal_at": pending.RevealAt,
+	}
+}
+
+// revealRoll reveals a held-back roll, unless an ack or the timer already did
+func (h *Handler) revealRoll(game *models.Game, rollID string) {
+	playerID, roll, err := game.RevealRoll(rollID)
+	if err != nil && err != models.ErrThreeSixes {
+		return
+	}
+	if h.hub != nil {
+		event := DiceRevealedEvent{Type: "dice_revealed", RollID: rollID, PlayerID: playerID, Roll: roll}
+		h.hub.BroadcastEvent(game.Code, event)
+	}
+	h.broadcastRefresh(game.Code, "dice_rolled")
+	if h.hub != nil {
+		h.hub.sendMoveHints(game, playerID)
+	}
+}
+
+// AckRoll records that a client's dice animation has finished, revealing the roll
+// early once every connected client has
+func (h *Handler) AckRoll(w http.ResponseWriter, r *http.Request) {
+	var req RollAckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	var connected map[string]int
+	if h.hub != nil {
+		connected = h.hub.DeviceCounts(req.Code)
+	}
+	ready, err := game.AckRoll(req.PlayerID, req.RollID, connected)
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+	if ready {
+		h.revealRoll(game, req.RollID)
+	}
+
+	respondWithJSON(w, map[string]interface{}{
+		"message":  "Roll acknowledged",
+		"revealed": ready,
+	}, http.StatusOK)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// EmoteRequest represents the request to send a quick-chat emote
+type EmoteRequest struct {
+	Code     string `json:"code"`
+	PlayerID string `json:"player_id"`
+	Emote    string `json:"emote"`
+}
+
+// EmoteEvent carries an emote to every client in the game
+type EmoteEvent struct {
+	Type string `json:"type"` // Always "emote"
+	models.Emote
+}
+
+// GetEmotes lists the emotes clients can send
+func (h *Handler) GetEmotes(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, map[string]interface{}{
+		"emotes":         models.Emotes,
+		"burst":          models.EmoteBurst,
+		"window_seconds": int(models.EmoteWindow.Seconds()),
+	}, http.StatusOK)
+}
+
+// SendEmote handles sending an emote
+func (h *Handler) SendEmote(w http.ResponseWriter, r *http.Request) {
+	var req EmoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	response, err := h.sendEmote(game, req.PlayerID, req.Emote)
+	if err != nil {
+		status := http.StatusBadRequest
+		if err == models.ErrEmoteRateLimited {
+			status = http.StatusTooManyRequests
+		}
+		respondWithErr(w, err, status)
+		return
+	}
+
+	respondWithJSON(w, response, http.StatusOK)
+}
+
+// sendEmote sends an emote and broadcasts it; shared by the HTTP and WebSocket APIs
+func (h *Handler) sendEmote(game *models.Game, playerID, emoteID string) (map[string]interface{}, error) {
+	emote, err := game.SendEmote(playerID, emoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if h.hub != nil {
+		event := EmoteEvent{Type: "emote", Emote: emote}
+		h.hub.publish(game.Code, event.Type, event)
+		h.hub.BroadcastEvent(game.Code, event)
+	}
+
+	return map[string]interface{}{
+		"message": "Emote sent",
+	}, nil
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// Errors answering malformed requests
+var (
+	errInvalidBody  = errors.New("Invalid request body")
+	errCodeRequired = errors.New("code parameter is required")
+)
+
+// handlerErrorCodes are the codes of errors raised outside the models
+var handlerErrorCodes = map[error]string{
+	errInvalidBody:           "INVALID_REQUEST_BODY",
+	errCodeRequired:          "MISSING_GAME_CODE",
+	errSkipWithMoves:         "VALID_MOVES_AVAILABLE",
+	errUnknownCommand:        "UNKNOWN_COMMAND",
+	errPieceIDRequired:       "MISSING_PIECE_ID",
+	errCommandsOff:           "COMMANDS_DISABLED",
+	errIdempotencyKeyTooLong: "IDEMPOTENCY_KEY_TOO_LONG",
+	ErrAPIKeyMissing:         "API_KEY_REQUIRED",
+	ErrAPIKeyInvalid:         "INVALID_API_KEY",
+	ErrAPIKeyScope:           "API_KEY_SCOPE",
+	ErrUnknownScope:          "UNKNOWN_SCOPE",
+	ErrAPIKeyNotFound:        "API_KEY_NOT_FOUND",
+	ErrUnknownRole:           "UNKNOWN_ROLE",
+	ErrInvalidOutboundURL:    "INVALID_URL",
+	ErrBlockedDestination:    "BLOCKED_DESTINATION",
+	context.DeadlineExceeded: "TIMEOUT",
+}
+
+// errorCode returns an error's machine-readable code, falling back to one for
+// the HTTP status when it has none of its own
+func errorCode(err error, statusCode int) string {
+	if code := models.ErrorCode(err); code != "" {
+		return code
+	}
+	for known, code := range handlerErrorCodes {
+		if errors.Is(err, known) {
+			return code
+		}
+	}
+	return statusErrorCode(statusCode)
+}
+
+// statusErrorCode turns an HTTP status into a code, e.g. 404 into NOT_FOUND
+func statusErrorCode(statusCode int) string {
+	return strings.ToUpper(strings.ReplaceAll(http.StatusText(statusCode), " ", "_"))
+}
+
+// ErrorCodes lists every error code an endpoint can return, sorted
+func ErrorCodes() []string {
+	codes := models.ErrorCodes()
+	for _, code := range handlerErrorCodes {
+		codes = append(codes, code)
+	}
+	for _, status := range []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound,
+		http.StatusConflict, http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusNotImplemented, http.StatusServiceUnavailable, http.StatusGatewayTimeout} {
+		codes = append(codes, statusErrorCode(status))
+	}
+	sort.Strings(codes)
+	return codes
+}
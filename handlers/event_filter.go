@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// maxEventFilter caps how many event types one client can select
+const maxEventFilter = 64
+
+// wants reports whether the client selected an event type. Messages without a
+// type (replies, server notices) always go through.
+func (c *Client) wants(kind string) bool {
+	if kind == "" {
+		return true
+	}
+	filter := c.events.Load()
+	return filter == nil || (*filter)[kind]
+}
+
+// setEventFilter limits the client to the given event types: refresh hints such
+// as "piece_moved" and commentary kinds such as "capture". An empty list selects everything.
+func (c *Client) setEventFilter(events []string) []string {
+	if len(events) == 0 {
+		c.events.Store(nil)
+		return nil
+	}
+
+	filter := make(map[string]bool)
+	var selected []string
+	for _, event := range events {
+		event = strings.TrimSpace(event)
+		if event == "" || filter[event] || len(filter) >= maxEventFilter {
+			continue
+		}
+		filter[event] = true
+		selected = append(selected, event)
+	}
+	c.events.Store(&filter)
+	return selected
+}
+
+// subscribeEvents applies a subscribe message's "events" list and confirms the selection
+func (c *Client) subscribeEvents(raw []interface{}) {
+	events := make([]string, 0, len(raw))
+	for _, v := range raw {
+		if s, ok := v.(string); ok {
+			events = append(events, s)
+		}
+	}
+
+	selected := c.setEventFilter(events)
+	if selected == nil {
+		selected = []string{} // Everything
+	}
+	response, _ := json.Marshal(map[string]interface{}{
+		"type":   "subscribed",
+		"events": selected,
+	})
+	c.reply(response)
+}
+
+// messageKind reads the "type" of an arbitrary event so clients can filter on it
+func messageKind(message []byte) string {
+	var event struct {
+		Type string `json:"type"`
+	}
+	json.Unmarshal(message, &event)
+	return event.Type
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import "github.com/aminearbi/ludo-nadwa-server/models"
+
+// Event is a game event mirrored to integrations such as webhooks.
+// Type is the refresh hint ("piece_moved", "game_ended", ...) or a
+// commentary kind ("capture", "finish", ...).
+type Event struct {
+	Type      string           `json:"event"`
+	GameCode  string           `json:"game_code"`
+	Timestamp models.Timestamp `json:"timestamp"`
+	Data      interface{}      `json:"data,omitempty"`
+}
+
+// EventSink receives every game event the hub broadcasts. Publish must not block.
+type EventSink interface {
+	Publish(event Event)
+}
+
+// AddSink registers a sink for game events. Call before the server starts.
+func (h *Hub) AddSink(sink EventSink) {
+	h.sinks = append(h.sinks, sink)
+}
+
+// publish hands an event to every registered sink
+func (h *Hub) publish(gameCode, eventType string, data interface{}) {
+	if len(h.sinks) == 0 {
+		return
+	}
+	event := Event{
+		Type:      eventType,
+		GameCode:  gameCode,
+		Timestamp: models.Now(),
+		Data:      data,
+	}
+	for _, sink := range h.sinks {
+		sink.Publish(event)
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// ExperimentRequest represents the request to start a rules experiment
+type ExperimentRequest struct {
+	ID       string                 `json:"id"`
+	Fraction float64                `json:"fraction"` // Share of casual games given the treatment, between 0 and 1
+	Rules    models.ExperimentRules `json:"rules"`
+}
+
+// StartExperiment starts placing a fraction of new casual games into an experimental ruleset
+func (h *Handler) StartExperiment(w http.ResponseWriter, r *http.Request) {
+	var req ExperimentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	experiment, err := h.gameManager.StartExperiment(req.ID, req.Fraction, req.Rules)
+	if err != nil {
+		status := http.StatusBadRequest
+		if err == models.ErrExperimentRunning {
+			status = http.StatusConflict
+		}
+		respondWithErr(w, err, status)
+		return
+	}
+	respondWithJSON(w, experiment, http.StatusOK)
+}
+
+// StopExperiment stops assigning new games to the running experiment
+func (h *Handler) StopExperiment(w http.ResponseWriter, r *http.Request) {
+	experiment, err := h.gameManager.StopExperiment()
+	if err != nil {
+		respondWithErr(w, err, http.StatusNotFound)
+		return
+	}
+	respondWithJSON(w, experiment, http.StatusOK)
+}
+
+// ListExperiments compares completion and duration between the arms of every experiment
+func (h *Handler) ListExperiments(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, map[string]interface{}{
+		"experiments": h.gameManager.ExperimentReports(),
+	}, http.StatusOK)
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Export formats for ?format=
+const (
+	ExportJSON = "json"
+	ExportText = "text"
+)
+
+// ExportGame returns a portable record of a whole game: players, rules, and every
+// roll, move, skip and chat message with its time. ?format=text gives a PGN-like
+// text form instead of JSON.
+func (h *Handler) ExportGame(w http.ResponseWriter, r *http.Request) {
+	code := gameCodeParam(r)
+	if code == "" {
+		respondWithErr(w, errCodeRequired, http.StatusBadRequest)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = ExportJSON
+	}
+	if format != ExportJSON && format != ExportText {
+		respondWithError(w, "format must be json or text", http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	export := game.Export()
+	if format == ExportText {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"ludo-%s.txt\"", code))
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(export.Text()))
+		return
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"ludo-%s.json\"", code))
+	respondWithJSON(w, export, http.StatusOK)
+}
@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// PresenceHook is told when a player's first connection to any game opens
+// (online) or their last one closes. It runs on the hub's loop and must not block.
+type PresenceHook func(playerID, gameCode string, online bool)
+
+// FriendEvent tells a player about a friend: "friend_online", "friend_offline"
+// or "friend_started_game", or about another player: "friend_request" when they
+// ask to be friends and "friend_added" when they accept
+type FriendEvent struct {
+	Type     string `json:"type"`
+	PlayerID string `json:"player_id"`
+	GameCode string `json:"game_code,omitempty"`
+}
+
+// FriendStatus is one friend's presence
+type FriendStatus struct {
+	PlayerID  string           `json:"player_id"`
+	Name      string           `json:"name,omitempty"` // As shown in their current game
+	Online    bool             `json:"online"`
+	GameCode  string           `json:"game_code,omitempty"`
+	GameState models.GameState `json:"game_state,omitempty"`
+}
+
+// FriendRequest represents the request to add or remove a friend, by player ID
+// or (when adding) by friend code
+type FriendRequest struct {
+	Code       string `json:"code"` // A game the player is in; its session secret signs the request
+	PlayerID   string `json:"player_id"`
+	FriendID   string `json:"friend_id,omitempty"`
+	FriendCode string `json:"friend_code,omitempty"`
+}
+
+// OnPresenceChanged registers the presence hook. Call before the server starts.
+func (h *Hub) OnPresenceChanged(hook PresenceHook) {
+	h.onPresence = hook
+}
+
+// IsOnline reports whether a player has a connection to any game
+func (h *Hub) IsOnline(playerID string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.online[playerID] > 0
+}
+
+// ConnectedGames returns the codes of the games a player is connected to
+func (h *Hub) ConnectedGames(playerID string) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var codes []string
+	for code, clients := range h.games {
+		for client := range clients {
+			if client.playerID == playerID {
+				codes = append(codes, code)
+				break
+			}
+		}
+	}
+	return codes
+}
+
+// notifyPlayer sends a message to every connection a player has open, in any game.
+// It never blocks, so it is safe on the hub's loop; a client with a full buffer misses it.
+func (h *Hub) notifyPlayer(playerID string, message []byte) {
+	kind := messageKind(message)
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, clients := range h.games {
+		for client := range clients {
+			if client.playerID != playerID || client.stateOnly.Load() || !client.wants(kind) {
+				continue
+			}
+			select {
+			case client.send <- message:
+			default:
+			}
+		}
+	}
+}
+
+// FriendPresence pushes friends' comings and goings to the players who added them.
+// Register PresenceChanged with the hub, and the FriendPresence itself as a sink
+// so it hears about games starting.
+type FriendPresence struct {
+	gameManager *models.GameManager
+	hub         *Hub
+}
+
+// NewFriendPresence creates the friend notifier
+func NewFriendPresence(gm *models.GameManager, hub *Hub) *FriendPresence {
+	return &FriendPresence{
+		gameManager: gm,
+		hub:         hub,
+	}
+}
+
+// PresenceChanged tells a player's followers they came online or went offline
+func (fp *FriendPresence) PresenceChanged(playerID, gameCode string, online bool) {
+	event := FriendEvent{Type: "friend_offline", PlayerID: playerID}
+	if online {
+		event = FriendEvent{Type: "friend_online", PlayerID: playerID, GameCode: gameCode}
+	}
+	fp.notifyFollowers(playerID, event)
+}
+
+// Publish tells the followers of every human in a game that just started
+func (fp *FriendPresence) Publish(event Event) {
+	if event.Type != "game_started" {
+		return
+	}
+	game, err := fp.gameManager.GetGame(context.Background(), event.GameCode)
+	if err != nil {
+		return
+	}
+	for _, playerID := range game.HumanPlayerIDs() {
+		fp.notifyFollowers(playerID, FriendEvent{Type: "friend_started_game", PlayerID: playerID, GameCode: event.GameCode})
+	}
+}
+
+// notifyFollowers sends an event to everyone with playerID on their friend list
+func (fp *FriendPresence) notifyFollowers(playerID string, event FriendEvent) {
+	followers := fp.gameManager.Friends().Followers(playerID)
+	if len(followers) == 0 {
+		return
+	}
+	message, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	for _, follower := range followers {
+		fp.hub.notifyPlayer(follower, message)
+	}
+}
+
+// friendStatus looks up one friend's presence, preferring a game in progress
+// when they are connected to several
+func (h *Handler) friendStatus(r *http.Request, friendID string) FriendStatus {
+	status := FriendStatus{PlayerID: friendID}
+	if h.hub == nil {
+		return status
+	}
+	status.Online = h.hub.IsOnline(friendID)
+
+	for _, code := range h.hub.ConnectedGames(friendID) {
+		game, err := h.gameManager.GetGame(r.Context(), code)
+		if err != nil {
+			continue
+		}
+		name, state, ok := game.PresenceInfo(friendID)
+		if !ok {
+			continue
+		}
+		if status.GameCode == "" || state == models.Playing {
+			status.Name, status.GameCode, status.GameState = name, code, state
+		}
+	}
+	return status
+}
+
+// notifyFriendEvent sends a friend event to every connection a player has open
+func (h *Handler) notifyFriendEvent(playerID string, event FriendEvent) {
+	if h.hub == nil {
+		return
+	}
+	message, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	h.hub.notifyPlayer(playerID, message)
+}
+
+// GetFriends returns each of a player's friends with their online status and
+// current game, and the players asking to be their friend
+func (h *Handler) GetFriends(w http.ResponseWriter, r *http.Request) {
+	playerID := r.URL.Query().Get("player_id")
+	if playerID == "" {
+		respondWithError(w, "player_id parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	friends := []FriendStatus{}
+	for _, friendID := range h.gameManager.Friends().List(playerID) {
+		if err := r.Context().Err(); err != nil {
+			respondWithErr(w, err, unavailableStatus(err, http.StatusServiceUnavailable))
+			return
+		}
+		friends = append(friends, h.friendStatus(r, friendID))
+	}
+
+	respondWithJSON(w, map[string]interface{}{
+		"friends":  friends,
+		"requests": h.gameManager.Friends().Requests(playerID),
+	}, http.StatusOK)
+}
+
+// AddFriend asks a player to be the caller's friend, or accepts their request
+func (h *Handler) AddFriend(w http.ResponseWriter, r *http.Request) {
+	var req FriendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	if req.FriendID == "" && req.FriendCode != "" {
+		friendID, err := h.gameManager.Profiles().ByFriendCode(req.FriendCode)
+		if err != nil {
+			respondWithErr(w, err, http.StatusNotFound)
+			return
+		}
+		req.FriendID = friendID
+	}
+
+	friends, err := h.gameManager.Friends().Add(req.PlayerID, req.FriendID)
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	if !friends {
+		h.notifyFriendEvent(req.FriendID, FriendEvent{Type: "friend_request", PlayerID: req.PlayerID})
+		respondWithJSON(w, map[string]interface{}{
+			"message": "Friend request sent",
+			"friend":  FriendStatus{PlayerID: req.FriendID}, // Presence shows once they accept
+			"pending": true,
+		}, http.StatusOK)
+		return
+	}
+
+	h.notifyFriendEvent(req.FriendID, FriendEvent{Type: "friend_added", PlayerID: req.PlayerID})
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Friend added",
+		"friend":  h.friendStatus(r, req.FriendID),
+		"pending": false,
+	}, http.StatusOK)
+}
+
+// GetFriendCode returns the code others can add a player by, creating it on first use
+func (h *Handler) GetFriendCode(w http.ResponseWriter, r *http.Request) {
+	playerID := r.URL.Query().Get("player_id")
+	if playerID == "" {
+		respondWithError(w, "player_id parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	code, err := h.gameManager.Profiles().FriendCode(playerID)
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, map[string]interface{}{
+		"friend_code": code,
+	}, http.StatusOK)
+}
+
+// RemoveFriend ends a friendship, or withdraws or declines a friend request
+func (h *Handler) RemoveFriend(w http.ResponseWriter, r *http.Request) {
+	var req FriendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	h.gameManager.Friends().Remove(req.PlayerID, req.FriendID)
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Friend removed",
+	}, http.StatusOK)
+}
@@ -1,783 +1,1469 @@
-package handlers
-
-import (
-	"encoding/json"
-	"net/http"
-
-	"github.com/aminearbi/ludo-nadwa-server/models"
-)
-
-// Handler wraps the game manager and provides HTTP endpoints
-type Handler struct {
-	gameManager *models.GameManager
-	hub         *Hub // WebSocket hub for broadcasting
-}
-
-// NewHandler creates a new handler
-func NewHandler(gm *models.GameManager) *Handler {
-	return &Handler{
-		gameManager: gm,
-		hub:         nil,
-	}
-}
-
-// SetHub sets the WebSocket hub for broadcasting
-func (h *Handler) SetHub(hub *Hub) {
-	h.hub = hub
-}
-
-// broadcastRefresh sends a simple refresh hint to all clients in a game
-func (h *Handler) broadcastRefresh(gameCode string, hint string) {
-	if h.hub != nil {
-		h.hub.BroadcastRefresh(gameCode, hint)
-	}
-}
-
-// CreateGameRequest represents the request to create a game
-type CreateGameRequest struct {
-	MaxPlayers int    `json:"max_players"`
-	PlayerName string `json:"player_name"`
-	PlayerID   string `json:"player_id"`
-}
-
-// CreateGameResponse represents the response when creating a game
-type CreateGameResponse struct {
-	Code       string `json:"code"`
-	Message    string `json:"message"`
-	MaxPlayers int    `json:"max_players"`
-}
-
-// JoinGameRequest represents the request to join a game
-type JoinGameRequest struct {
-	Code       string `json:"code"`
-	PlayerID   string `json:"player_id"`
-	PlayerName string `json:"player_name"`
-}
-
-// JoinGameResponse represents the response when joining a game
-type JoinGameResponse struct {
-	Message string                 `json:"message"`
-	Game    map[string]interface{} `json:"game"`
-}
-
-// StartGameRequest represents the request to start a game
-type StartGameRequest struct {
-	Code     string `json:"code"`
-	PlayerID string `json:"player_id"`
-}
-
-// RollDiceRequest represents the request to roll dice
-type RollDiceRequest struct {
-	Code     string `json:"code"`
-	PlayerID string `json:"player_id"`
-}
-
-// RollDiceResponse represents the response when rolling dice
-type RollDiceResponse struct {
-	Roll       int   `json:"roll"`
-	ValidMoves []int `json:"valid_moves"` // IDs of pieces that can be moved
-	HasMoves   bool  `json:"has_moves"`   // Whether any valid move exists
-}
-
-// MovePieceRequest represents the request to move a piece
-type MovePieceRequest struct {
-	Code     string `json:"code"`
-	PlayerID string `json:"player_id"`
-	PieceID  int    `json:"piece_id"`
-}
-
-// SkipTurnRequest represents the request to skip a turn
-type SkipTurnRequest struct {
-	Code     string `json:"code"`
-	PlayerID string `json:"player_id"`
-}
-
-// SetReadyRequest represents the request to set player ready status
-type SetReadyRequest struct {
-	Code     string `json:"code"`
-	PlayerID string `json:"player_id"`
-	Ready    bool   `json:"ready"`
-}
-
-// KickPlayerRequest represents the request to kick a player
-type KickPlayerRequest struct {
-	Code         string `json:"code"`
-	HostID       string `json:"host_id"`
-	PlayerToKick string `json:"player_to_kick"`
-}
-
-// LeaveGameRequest represents the request to leave a game
-type LeaveGameRequest struct {
-	Code     string `json:"code"`
-	PlayerID string `json:"player_id"`
-}
-
-// PauseGameRequest represents the request to pause a game
-type PauseGameRequest struct {
-	Code     string `json:"code"`
-	PlayerID string `json:"player_id"`
-}
-
-// ResumeGameRequest represents the request to resume a game
-type ResumeGameRequest struct {
-	Code     string `json:"code"`
-	PlayerID string `json:"player_id"`
-}
-
-// ChatMessageRequest represents the request to send a chat message
-type ChatMessageRequest struct {
-	Code     string `json:"code"`
-	PlayerID string `json:"player_id"`
-	Message  string `json:"message"`
-}
-
-// SpectateRequest represents the request to join as a spectator
-type SpectateRequest struct {
-	Code         string `json:"code"`
-	SpectatorID  string `json:"spectator_id"`
-	SpectatorName string `json:"spectator_name"`
-}
-
-// RematchRequest represents the request to start a rematch
-type RematchRequest struct {
-	Code   string `json:"code"`
-	HostID string `json:"host_id"`
-}
-
-// AddBotRequest represents the request to add a bot to a game
-type AddBotRequest struct {
-	Code   string `json:"code"`
-	HostID string `json:"host_id"`
-}
-
-// RemoveBotRequest represents the request to remove a bot from a game
-type RemoveBotRequest struct {
-	Code   string `json:"code"`
-	HostID string `json:"host_id"`
-	BotID  string `json:"bot_id"`
-}
-
-// ErrorResponse represents an error response
-type ErrorResponse struct {
-	Error string `json:"error"`
-}
-
-// CreateGame handles game creation
-func (h *Handler) CreateGame(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	var req CreateGameRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	// Require player info for host
-	if req.PlayerID == "" || req.PlayerName == "" {
-		respondWithError(w, "Player ID and name are required to create a game", http.StatusBadRequest)
-		return
-	}
-
-	game, err := h.gameManager.CreateGame(req.PlayerID, req.PlayerName, req.MaxPlayers)
-	if err != nil {
-		respondWithError(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	response := CreateGameResponse{
-		Code:       game.Code,
-		Message:    "Game created successfully. Share this code with other players.",
-		MaxPlayers: game.MaxPlayers,
-	}
-
-	respondWithJSON(w, response, http.StatusCreated)
-}
-
-// JoinGame handles joining a game
-func (h *Handler) JoinGame(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	var req JoinGameRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	if req.Code == "" || req.PlayerID == "" || req.PlayerName == "" {
-		respondWithError(w, "code, player_id, and player_name are required", http.StatusBadRequest)
-		return
-	}
-
-	game, err := h.gameManager.JoinGame(req.Code, req.PlayerID, req.PlayerName)
-	if err != nil {
-		respondWithError(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	// Broadcast player joined event
-	h.broadcastRefresh(req.Code, "player_joined")
-
-	response := JoinGameResponse{
-		Message: "Successfully joined the game",
-		Game:    game.GetGameState(),
-	}
-
-	respondWithJSON(w, response, http.StatusOK)
-}
-
-// StartGame handles starting a game
-func (h *Handler) StartGame(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	var req StartGameRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	if req.PlayerID == "" {
-		respondWithError(w, "player_id is required", http.StatusBadRequest)
-		return
-	}
-
-	game, err := h.gameManager.GetGame(req.Code)
-	if err != nil {
-		respondWithError(w, err.Error(), http.StatusNotFound)
-		return
-	}
-
-	if err := game.StartGame(req.PlayerID); err != nil {
-		respondWithError(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	// Broadcast game started event
-	h.broadcastRefresh(req.Code, "game_started")
-
-	respondWithJSON(w, map[string]interface{}{
-		"message": "Game started successfully",
-		"game":    game.GetGameState(),
-	}, http.StatusOK)
-}
-
-// GetGameState handles retrieving game state
-func (h *Handler) GetGameState(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	code := r.URL.Query().Get("code")
-	if code == "" {
-		respondWithError(w, "code parameter is required", http.StatusBadRequest)
-		return
-	}
-
-	game, err := h.gameManager.GetGame(code)
-	if err != nil {
-		respondWithError(w, err.Error(), http.StatusNotFound)
-		return
-	}
-
-	respondWithJSON(w, game.GetGameState(), http.StatusOK)
-}
-
-// RollDice handles dice rolling
-func (h *Handler) RollDice(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	var req RollDiceRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	game, err := h.gameManager.GetGame(req.Code)
-	if err != nil {
-		respondWithError(w, err.Error(), http.StatusNotFound)
-		return
-	}
-
-	roll, rollErr := game.RollDice(req.PlayerID)
-	
-	// Handle the three-sixes case - still report the roll but turn is lost
-	if rollErr != nil && rollErr != models.ErrThreeSixes {
-		respondWithError(w, rollErr.Error(), http.StatusBadRequest)
-		return
-	}
-	
-	validMoves := game.GetValidMoves(req.PlayerID)
-	game.UpdateActivity()
-
-	// Broadcast dice roll event
-	h.broadcastRefresh(req.Code, "dice_rolled")
-
-	response := RollDiceResponse{
-		Roll:       roll,
-		ValidMoves: validMoves,
-		HasMoves:   len(validMoves) > 0,
-	}
-
-	respondWithJSON(w, response, http.StatusOK)
-}
-
-// MovePiece handles moving a piece
-func (h *Handler) MovePiece(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	var req MovePieceRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	game, err := h.gameManager.GetGame(req.Code)
-	if err != nil {
-		respondWithError(w, err.Error(), http.StatusNotFound)
-		return
-	}
-
-	if err := game.MovePiece(req.PlayerID, req.PieceID); err != nil {
-		respondWithError(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	gameState := game.GetGameState()
-
-	// Broadcast piece moved event
-	h.broadcastRefresh(req.Code, "piece_moved")
-
-	respondWithJSON(w, map[string]interface{}{
-		"message": "Piece moved successfully",
-		"game":    gameState,
-	}, http.StatusOK)
-}
-
-// SkipTurn handles skipping a turn when no valid moves are available
-func (h *Handler) SkipTurn(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	var req SkipTurnRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	game, err := h.gameManager.GetGame(req.Code)
-	if err != nil {
-		respondWithError(w, err.Error(), http.StatusNotFound)
-		return
-	}
-
-	// Verify player has no valid moves before allowing skip
-	if game.HasValidMoves(req.PlayerID) {
-		respondWithError(w, "Cannot skip turn when valid moves are available", http.StatusBadRequest)
-		return
-	}
-
-	if err := game.SkipTurn(req.PlayerID); err != nil {
-		respondWithError(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	// Broadcast turn skipped event
-	h.broadcastRefresh(req.Code, "turn_skipped")
-
-	respondWithJSON(w, map[string]interface{}{
-		"message": "Turn skipped",
-		"game":    game.GetGameState(),
-	}, http.StatusOK)
-}
-
-// SetReady handles setting a player's ready status
-func (h *Handler) SetReady(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	var req SetReadyRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	game, err := h.gameManager.GetGame(req.Code)
-	if err != nil {
-		respondWithError(w, err.Error(), http.StatusNotFound)
-		return
-	}
-
-	if err := game.SetPlayerReady(req.PlayerID, req.Ready); err != nil {
-		respondWithError(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	// Broadcast player ready status change
-	h.broadcastRefresh(req.Code, "player_ready")
-
-	respondWithJSON(w, map[string]interface{}{
-		"message":          "Ready status updated",
-		"ready":            req.Ready,
-		"all_players_ready": game.AreAllPlayersReady(),
-	}, http.StatusOK)
-}
-
-// KickPlayer handles kicking a player from the game
-func (h *Handler) KickPlayer(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	var req KickPlayerRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	game, err := h.gameManager.GetGame(req.Code)
-	if err != nil {
-		respondWithError(w, err.Error(), http.StatusNotFound)
-		return
-	}
-
-	if err := game.KickPlayer(req.HostID, req.PlayerToKick); err != nil {
-		respondWithError(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	// Broadcast player kicked event
-	h.broadcastRefresh(req.Code, "player_kicked")
-
-	respondWithJSON(w, map[string]interface{}{
-		"message": "Player kicked successfully",
-		"game":    game.GetGameState(),
-	}, http.StatusOK)
-}
-
-// LeaveGame handles a player leaving the game
-func (h *Handler) LeaveGame(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	var req LeaveGameRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	game, err := h.gameManager.GetGame(req.Code)
-	if err != nil {
-		respondWithError(w, err.Error(), http.StatusNotFound)
-		return
-	}
-
-	if err := game.LeaveGame(req.PlayerID); err != nil {
-		respondWithError(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	// Broadcast player left event
-	h.broadcastRefresh(req.Code, "player_left")
-
-	respondWithJSON(w, map[string]interface{}{
-		"message": "Left game successfully",
-	}, http.StatusOK)
-}
-
-// PauseGame handles pausing the game
-func (h *Handler) PauseGame(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	var req PauseGameRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	game, err := h.gameManager.GetGame(req.Code)
-	if err != nil {
-		respondWithError(w, err.Error(), http.StatusNotFound)
-		return
-	}
-
-	if err := game.PauseGame(req.PlayerID); err != nil {
-		respondWithError(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	// Broadcast game paused event
-	h.broadcastRefresh(req.Code, "game_paused")
-
-	respondWithJSON(w, map[string]interface{}{
-		"message": "Game paused",
-		"game":    game.GetGameState(),
-	}, http.StatusOK)
-}
-
-// ResumeGame handles resuming the game
-func (h *Handler) ResumeGame(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	var req ResumeGameRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	game, err := h.gameManager.GetGame(req.Code)
-	if err != nil {
-		respondWithError(w, err.Error(), http.StatusNotFound)
-		return
-	}
-
-	if err := game.ResumeGame(req.PlayerID); err != nil {
-		respondWithError(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	// Broadcast game resumed event
-	h.broadcastRefresh(req.Code, "game_resumed")
-
-	respondWithJSON(w, map[string]interface{}{
-		"message": "Game resumed",
-		"game":    game.GetGameState(),
-	}, http.StatusOK)
-}
-
-// SendChat handles sending a chat message
-func (h *Handler) SendChat(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	var req ChatMessageRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	game, err := h.gameManager.GetGame(req.Code)
-	if err != nil {
-		respondWithError(w, err.Error(), http.StatusNotFound)
-		return
-	}
-
-	if err := game.SendChatMessage(req.PlayerID, req.Message); err != nil {
-		respondWithError(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	// Broadcast chat message event
-	h.broadcastRefresh(req.Code, "chat_message")
-
-	respondWithJSON(w, map[string]interface{}{
-		"message": "Chat message sent",
-	}, http.StatusOK)
-}
-
-// JoinAsSpectator handles joining a game as a spectator
-func (h *Handler) JoinAsSpectator(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	var req SpectateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	game, err := h.gameManager.JoinAsSpectator(req.Code, req.SpectatorID, req.SpectatorName)
-	if err != nil {
-		respondWithError(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	// Broadcast spectator joined event
-	h.broadcastRefresh(req.Code, "spectator_joined")
-
-	respondWithJSON(w, map[string]interface{}{
-		"message": "Joined as spectator",
-		"game":    game.GetGameState(),
-	}, http.StatusOK)
-}
-
-// Rematch handles requesting a rematch
-func (h *Handler) Rematch(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	var req RematchRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	game, err := h.gameManager.GetGame(req.Code)
-	if err != nil {
-		respondWithError(w, err.Error(), http.StatusNotFound)
-		return
-	}
-
-	if err := game.Rematch(req.HostID); err != nil {
-		respondWithError(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	// Broadcast rematch event
-	h.broadcastRefresh(req.Code, "rematch")
-
-	respondWithJSON(w, map[string]interface{}{
-		"message": "Rematch started - waiting for all players to be ready",
-		"game":    game.GetGameState(),
-	}, http.StatusOK)
-}
-
-// GetMoveHistory handles getting the move history
-func (h *Handler) GetMoveHistory(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	code := r.URL.Query().Get("code")
-	if code == "" {
-		respondWithError(w, "code parameter is required", http.StatusBadRequest)
-		return
-	}
-
-	game, err := h.gameManager.GetGame(code)
-	if err != nil {
-		respondWithError(w, err.Error(), http.StatusNotFound)
-		return
-	}
-
-	respondWithJSON(w, map[string]interface{}{
-		"move_history": game.MoveHistory,
-	}, http.StatusOK)
-}
-
-// GetChat handles getting the chat history
-func (h *Handler) GetChat(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	code := r.URL.Query().Get("code")
-	if code == "" {
-		respondWithError(w, "code parameter is required", http.StatusBadRequest)
-		return
-	}
-
-	game, err := h.gameManager.GetGame(code)
-	if err != nil {
-		respondWithError(w, err.Error(), http.StatusNotFound)
-		return
-	}
-
-	respondWithJSON(w, map[string]interface{}{
-		"chat_messages": game.GetRecentChat(100),
-	}, http.StatusOK)
-}
-
-// AddBot handles adding an AI player to the game
-func (h *Handler) AddBot(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	var req AddBotRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	game, bot, err := h.gameManager.AddBot(req.Code, req.HostID)
-	if err != nil {
-		respondWithError(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	// Broadcast bot joined event
-	h.broadcastRefresh(req.Code, "player_joined")
-
-	respondWithJSON(w, map[string]interface{}{
-		"message": "Bot added successfully",
-		"bot_id":  bot.ID,
-		"game":    game.GetGameState(),
-	}, http.StatusOK)
-}
-
-// RemoveBot handles removing an AI player from the game
-func (h *Handler) RemoveBot(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	var req RemoveBotRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondWithError(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	game, err := h.gameManager.RemoveBot(req.Code, req.HostID, req.BotID)
-	if err != nil {
-		respondWithError(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	// Broadcast bot left event
-	h.broadcastRefresh(req.Code, "player_left")
-
-	respondWithJSON(w, map[string]interface{}{
-		"message": "Bot removed successfully",
-		"game":    game.GetGameState(),
-	}, http.StatusOK)
-}
-
-// respondWithJSON sends a JSON response
-func respondWithJSON(w http.ResponseWriter, data interface{}, statusCode int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(data)
-}
-
-// respondWithError sends an error response
-func respondWithError(w http.ResponseWriter, message string, statusCode int) {
-	respondWithJSON(w, ErrorResponse{Error: message}, statusCode)
-}
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+	"github.com/go-chi/chi/v5"
+)
+
+// Handler wraps the game manager and provides HTTP endpoints
+type Handler struct {
+	gameManager    *models.GameManager
+	hub            *Hub              // WebSocket hub for broadcasting
+	strictSigning  bool              // Require HMAC-signed mutating requests
+	translator     models.Translator // Optional chat translation provider
+	adminToken     string            // Bearer token for /api/admin routes, granting the operator role
+	identityHeader string            // Header an OAuth proxy puts the caller's identity in
+	identityRoles  map[string]Role   // Admin roles by identity
+	announcements  *announcementBoard
+	maintenance    *maintenanceScheduler
+	restart        *restartCoordinator
+	gameWebhooks   *GameWebhooks
+	metrics        *GameMetrics // Per-game time series; nil until SetGameMetrics
+	apiKeys        *APIKeyStore // Integration API keys
+	idempotency    *idempotencyCache
+	debug          bool         // Serve the /api/debug routes
+}
+
+// NewHandler creates a new handler
+func NewHandler(gm *models.GameManager) *Handler {
+	return &Handler{
+		gameManager:   gm,
+		hub:           nil,
+		announcements: &announcementBoard{},
+		maintenance:   &maintenanceScheduler{},
+		restart:       &restartCoordinator{},
+		gameWebhooks:  NewGameWebhooks(),
+		apiKeys:       &APIKeyStore{},
+		idempotency:   &idempotencyCache{},
+	}
+}
+
+// SetHub sets the WebSocket hub for broadcasting
+func (h *Handler) SetHub(hub *Hub) {
+	h.hub = hub
+}
+
+// broadcastRefresh sends a simple refresh hint to all clients in a game
+func (h *Handler) broadcastRefresh(gameCode string, hint string) {
+	if h.hub != nil {
+		h.hub.BroadcastRefresh(gameCode, hint)
+	}
+}
+
+// broadcastCommentary sends new commentary lines to subscribed clients
+func (h *Handler) broadcastCommentary(game *models.Game) {
+	if lines := game.ConsumeCommentary(); len(lines) > 0 && h.hub != nil {
+		h.hub.BroadcastCommentary(game.Code, lines)
+	}
+}
+
+// broadcastBotChat sends a chat refresh if bots posted reactions
+func (h *Handler) broadcastBotChat(game *models.Game) {
+	if game.ConsumeBotChat() {
+		h.broadcastRefresh(game.Code, "chat_message")
+	}
+}
+
+// CreateGameRequest represents the request to create a game
+type CreateGameRequest struct {
+	MaxPlayers      int    `json:"max_players"`
+	PlayerName      string `json:"player_name"`
+	PlayerID        string `json:"player_id"`
+	TimeoutAction   string `json:"timeout_action,omitempty"`   // "skip" (default) or "auto_play"
+	BotChat         *bool  `json:"bot_chat,omitempty"`         // Bots post chat reactions (default true)
+	DeparturePolicy string `json:"departure_policy,omitempty"` // remove, home, freeze (default), or bot
+	AFKLimit        *int   `json:"afk_limit,omitempty"`        // Timed-out turns in a row before removal (default 0, never; 3 if only afk_action is set)
+	AFKAction       string `json:"afk_action,omitempty"`       // remove, home, freeze, or bot (default)
+	BotTakeoverSeconds int `json:"bot_takeover_seconds,omitempty"` // A bot plays for players disconnected this long (default never)
+	Public          bool   `json:"public,omitempty"`           // List the game in the lobby's game browser
+	Palette         string `json:"palette,omitempty"`          // Display palette, see /api/palettes
+	PauseBudget     *int   `json:"pause_budget,omitempty"`     // Pauses each player may call (default 2)
+	MaxPauseSeconds int    `json:"max_pause_seconds,omitempty"` // Longest pause without a vote (default 180)
+	ResumeQuorum    int    `json:"resume_quorum,omitempty"`    // Connected players needed to resume (default all)
+	Preset          string `json:"preset,omitempty"`           // "standard" (default) or "blitz"
+	AutoRoll        bool   `json:"auto_roll,omitempty"`        // Roll for every player when their turn starts
+	AutoMove        bool   `json:"auto_move,omitempty"`        // Play every player's move when only one piece can move
+	DiceRevealMs    int    `json:"dice_reveal_ms,omitempty"`   // Hold rolls back this long for animations (default 0)
+	Password        string `json:"password,omitempty"`         // Required to join as a player or spectator
+	Rules           *models.RuleSet `json:"rules,omitempty"`    // House rules; fields left out keep the standard rules
+	Ranked          bool   `json:"ranked,omitempty"`           // The result moves the players' ratings
+	TurnTimeoutSeconds *int `json:"turn_timeout_seconds,omitempty"` // Time allowed per turn; 0 for unlimited (default 60)
+}
+
+// CreateGameResponse represents the response when creating a game
+type CreateGameResponse struct {
+	Code          string `json:"code"`
+	Message       string `json:"message"`
+	MaxPlayers    int    `json:"max_players"`
+	SessionSecret string `json:"session_secret"` // Key for signing actions in strict mode
+}
+
+// JoinGameRequest represents the request to join a game
+type JoinGameRequest struct {
+	Code       string `json:"code"`
+	PlayerID   string `json:"player_id"`
+	PlayerName string `json:"player_name"`
+	Password   string `json:"password,omitempty"` // Needed if the host set one
+	Color      models.PlayerColor `json:"color,omitempty"` // Preferred color, taken if free
+}
+
+// JoinGameResponse represents the response when joining a game
+type JoinGameResponse struct {
+	Message       string                 `json:"message"`
+	Game          map[string]interface{} `json:"game"`
+	SessionSecret string                 `json:"session_secret"` // Key for signing actions in strict mode
+}
+
+// StartGameRequest represents the request to start a game
+type StartGameRequest struct {
+	Code     string `json:"code"`
+	PlayerID string `json:"player_id"`
+}
+
+// RollDiceRequest represents the request to roll dice
+type RollDiceRequest struct {
+	Code      string `json:"code"`
+	PlayerID  string `json:"player_id"`
+	RequestID string `json:"request_id,omitempty"` // Idempotency key, if not sent as a header
+}
+
+// RollDiceResponse represents the response when rolling dice
+type RollDiceResponse struct {
+	Roll       int                  `json:"roll"`
+	ValidMoves []int                `json:"valid_moves"` // IDs of pieces that can be moved
+	Moves      []models.MovePreview `json:"moves"`       // Where each of those pieces would land
+	HasMoves   bool                 `json:"has_moves"`   // Whether any valid move exists
+}
+
+// MovePieceRequest represents the request to move a piece
+type MovePieceRequest struct {
+	Code      string `json:"code"`
+	PlayerID  string `json:"player_id"`
+	PieceID   int    `json:"piece_id"`
+	RequestID string `json:"request_id,omitempty"` // Idempotency key, if not sent as a header
+}
+
+// SkipTurnRequest represents the request to skip a turn
+type SkipTurnRequest struct {
+	Code      string `json:"code"`
+	PlayerID  string `json:"player_id"`
+	RequestID string `json:"request_id,omitempty"` // Idempotency key, if not sent as a header
+}
+
+// SetReadyRequest represents the request to set player ready status
+type SetReadyRequest struct {
+	Code     string `json:"code"`
+	PlayerID string `json:"player_id"`
+	Ready    bool   `json:"ready"`
+}
+
+// KickPlayerRequest represents the request to kick a player
+type KickPlayerRequest struct {
+	Code         string `json:"code"`
+	HostID       string `json:"host_id"`
+	PlayerToKick string `json:"player_to_kick"`
+	PieceAction  string `json:"piece_action,omitempty"` // Required mid-game: remove, home, freeze, or bot
+}
+
+// LeaveGameRequest represents the request to leave a game
+type LeaveGameRequest struct {
+	Code     string `json:"code"`
+	PlayerID string `json:"player_id"`
+}
+
+// PauseGameRequest represents the request to pause a game
+type PauseGameRequest struct {
+	Code     string `json:"code"`
+	PlayerID string `json:"player_id"`
+	Seconds  int    `json:"seconds,omitempty"` // Pause length; longer than the game's limit starts a vote
+}
+
+// PauseVoteRequest represents the request to vote on a longer pause
+type PauseVoteRequest struct {
+	Code     string `json:"code"`
+	PlayerID string `json:"player_id"`
+	Approve  bool   `json:"approve"`
+}
+
+// ResumeGameRequest represents the request to resume a game
+type ResumeGameRequest struct {
+	Code     string `json:"code"`
+	PlayerID string `json:"player_id"`
+}
+
+// ChatMessageRequest represents the request to send a chat message
+type ChatMessageRequest struct {
+	Code      string `json:"code"`
+	PlayerID  string `json:"player_id"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty"` // Idempotency key, if not sent as a header
+}
+
+// ChatReadRequest represents the request to mark chat as read up to a timestamp
+type ChatReadRequest struct {
+	Code     string    `json:"code"`
+	PlayerID string    `json:"player_id"`
+	ReadAt   time.Time `json:"read_at"` // Optional; defaults to now
+}
+
+// SpectateRequest represents the request to join as a spectator
+type SpectateRequest struct {
+	Code         string `json:"code"`
+	SpectatorID  string `json:"spectator_id"`
+	SpectatorName string `json:"spectator_name"`
+	Password      string `json:"password,omitempty"` // Needed if the host set one
+}
+
+// RematchRequest represents the request to start a rematch
+type RematchRequest struct {
+	Code   string `json:"code"`
+	HostID string `json:"host_id"`
+}
+
+// AddBotRequest represents the request to add a bot to a game
+type AddBotRequest struct {
+	Code        string `json:"code"`
+	HostID      string `json:"host_id"`
+	Name        string `json:"name,omitempty"`        // Defaults to "Bot <Name>"
+	Difficulty  string `json:"difficulty,omitempty"`  // easy, medium (default), hard
+	Personality string `json:"personality,omitempty"` // balanced (default), aggressive, defensive, runner
+}
+
+// FillBotsRequest represents the request to fill the remaining seats with bots
+type FillBotsRequest struct {
+	Code        string `json:"code"`
+	HostID      string `json:"host_id"`
+	Difficulty  string `json:"difficulty,omitempty"`  // easy, medium (default), hard
+	Personality string `json:"personality,omitempty"` // balanced (default), aggressive, defensive, runner
+}
+
+// TakeOverBotRequest represents a human asking the host for a bot's seat
+type TakeOverBotRequest struct {
+	Code       string `json:"code"`
+	BotID      string `json:"bot_id"`
+	PlayerID   string `json:"player_id"`
+	PlayerName string `json:"player_name"`
+	Password   string `json:"password,omitempty"` // Required unless already spectating a password-protected game
+}
+
+// TakeoverDecisionRequest represents the host answering a request for a bot's seat
+type TakeoverDecisionRequest struct {
+	Code        string `json:"code"`
+	HostID      string `json:"host_id"`
+	RequesterID string `json:"requester_id"`
+}
+
+// BotChatRequest represents the request to toggle bot chat reactions
+type BotChatRequest struct {
+	Code    string `json:"code"`
+	HostID  string `json:"host_id"`
+	Enabled bool   `json:"enabled"`
+}
+
+// BotStandInRequest represents the request to have a bot play for a disconnected player
+type BotStandInRequest struct {
+	Code     string `json:"code"`
+	HostID   string `json:"host_id"`
+	PlayerID string `json:"player_id"`
+}
+
+// RemoveBotRequest represents the request to remove a bot from a game
+type RemoveBotRequest struct {
+	Code   string `json:"code"`
+	HostID string `json:"host_id"`
+	BotID  string `json:"bot_id"`
+}
+
+// RestoreGameRequest represents the request to restore a cleaned-up game
+type RestoreGameRequest struct {
+	Code   string `json:"code"`
+	HostID string `json:"host_id"`
+}
+
+// FastForwardRequest represents the request to simulate the rest of a bot-only game
+type FastForwardRequest struct {
+	Code   string `json:"code"`
+	HostID string `json:"host_id"`
+}
+
+// ErrorResponse represents an error response. Code is machine-readable and
+// stable, e.g. "NOT_YOUR_TURN"; Error is for people.
+type ErrorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// CreateGame handles game creation
+func (h *Handler) CreateGame(w http.ResponseWriter, r *http.Request) {
+	var req CreateGameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	// Require player info for host; the name can come from their profile
+	if req.PlayerID == "" {
+		respondWithError(w, "Player ID is required to create a game", http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.CreateGame(r.Context(), req.PlayerID, req.PlayerName, req.MaxPlayers)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	if req.TimeoutAction != "" {
+		if err := game.SetTimeoutAction(req.PlayerID, models.TimeoutAction(req.TimeoutAction)); err != nil {
+			h.gameManager.RemoveGame(game.Code)
+			respondWithErr(w, err, http.StatusBadRequest)
+			return
+		}
+	}
+
+	if req.BotChat != nil {
+		game.SetBotChat(req.PlayerID, *req.BotChat)
+	}
+
+	if req.DeparturePolicy != "" {
+		if err := game.SetDeparturePolicy(req.PlayerID, models.DepartureAction(req.DeparturePolicy)); err != nil {
+			h.gameManager.RemoveGame(game.Code)
+			respondWithErr(w, err, http.StatusBadRequest)
+			return
+		}
+	}
+
+	if req.AFKLimit != nil || req.AFKAction != "" {
+		limit, action := models.DefaultAFKLimit, models.DepartureBot
+		if req.AFKLimit != nil {
+			limit = *req.AFKLimit
+		}
+		if req.AFKAction != "" {
+			action = models.DepartureAction(req.AFKAction)
+		}
+		if err := game.SetAFKPolicy(req.PlayerID, limit, action); err != nil {
+			h.gameManager.RemoveGame(game.Code)
+			respondWithErr(w, err, http.StatusBadRequest)
+			return
+		}
+	}
+
+	if req.BotTakeoverSeconds != 0 {
+		if err := game.SetBotTakeover(req.PlayerID, time.Duration(req.BotTakeoverSeconds)*time.Second); err != nil {
+			h.gameManager.RemoveGame(game.Code)
+			respondWithErr(w, err, http.StatusBadRequest)
+			return
+		}
+	}
+
+	if req.Public {
+		game.SetPublic(req.PlayerID, true)
+	}
+
+	if req.Ranked {
+		game.SetRanked(req.PlayerID, true)
+	}
+
+	if req.Palette != "" {
+		if err := game.SetPalette(req.PlayerID, req.Palette); err != nil {
+			h.gameManager.RemoveGame(game.Code)
+			respondWithErr(w, err, http.StatusBadRequest)
+			return
+		}
+	}
+
+	if req.PauseBudget != nil || req.MaxPauseSeconds != 0 {
+		budget, maxLength := models.DefaultPauseBudget, models.DefaultMaxPauseLength
+		if req.PauseBudget != nil {
+			budget = *req.PauseBudget
+		}
+		if req.MaxPauseSeconds != 0 {
+			maxLength = time.Duration(req.MaxPauseSeconds) * time.Second
+		}
+		if err := game.SetPauseRules(req.PlayerID, budget, maxLength); err != nil {
+			h.gameManager.RemoveGame(game.Code)
+			respondWithErr(w, err, http.StatusBadRequest)
+			return
+		}
+	}
+
+	if req.ResumeQuorum != 0 {
+		if err := game.SetResumeQuorum(req.PlayerID, req.ResumeQuorum); err != nil {
+			h.gameManager.RemoveGame(game.Code)
+			respondWithErr(w, err, http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Before the preset, which overrides the rules' turn length
+	if req.Rules != nil {
+		if err := game.SetRules(req.PlayerID, *req.Rules); err != nil {
+			h.gameManager.RemoveGame(game.Code)
+			respondWithErr(w, err, http.StatusBadRequest)
+			return
+		}
+	}
+
+	if req.Preset != "" {
+		if err := game.SetPreset(req.PlayerID, req.Preset); err != nil {
+			h.gameManager.RemoveGame(game.Code)
+			respondWithErr(w, err, http.StatusBadRequest)
+			return
+		}
+	}
+
+	// After the preset, so an explicit turn length wins
+	if req.TurnTimeoutSeconds != nil {
+		if err := game.SetTurnTimeout(req.PlayerID, time.Duration(*req.TurnTimeoutSeconds)*time.Second); err != nil {
+			h.gameManager.RemoveGame(game.Code)
+			respondWithErr(w, err, http.StatusBadRequest)
+			return
+		}
+	}
+
+	if req.AutoRoll {
+		game.SetAutoRoll(req.PlayerID, true)
+	}
+
+	if req.AutoMove {
+		game.SetAutoMove(req.PlayerID, true)
+	}
+
+	if req.DiceRevealMs != 0 {
+		if err := game.SetDiceReveal(req.PlayerID, time.Duration(req.DiceRevealMs)*time.Millisecond); err != nil {
+			h.gameManager.RemoveGame(game.Code)
+			respondWithErr(w, err, http.StatusBadRequest)
+			return
+		}
+	}
+
+	if req.Password != "" {
+		if err := game.SetPassword(req.PlayerID, req.Password); err != nil {
+			h.gameManager.RemoveGame(game.Code)
+			respondWithErr(w, err, http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Casual games, where the host kept the default rules, may join a rules experiment
+	if req.Preset == "" && req.TimeoutAction == "" && req.Rules == nil && !req.Ranked && req.TurnTimeoutSeconds == nil {
+		h.gameManager.AssignExperiment(game)
+	}
+
+	// Nobody is connected yet, but integrations and the lobby hear about the new game
+	h.broadcastRefresh(game.Code, "game_created")
+
+	response := CreateGameResponse{
+		Code:       game.Code,
+		Message:       "Game created successfully. Share this code with other players.",
+		MaxPlayers:    game.MaxPlayers,
+		SessionSecret: game.SessionSecret(req.PlayerID),
+	}
+
+	respondWithJSON(w, response, http.StatusCreated)
+}
+
+// JoinGame handles joining a game
+func (h *Handler) JoinGame(w http.ResponseWriter, r *http.Request) {
+	var req JoinGameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	if req.Code == "" || req.PlayerID == "" {
+		respondWithError(w, "code and player_id are required", http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.JoinGameWithColor(r.Context(), req.Code, req.PlayerID, req.PlayerName, req.Password, req.Color)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, passwordStatus(err)))
+		return
+	}
+
+	// Broadcast player joined event
+	h.broadcastRefresh(req.Code, "player_joined")
+
+	response := JoinGameResponse{
+		Message:       "Successfully joined the game",
+		Game:          game.GetGameState(),
+		SessionSecret: game.SessionSecret(req.PlayerID),
+	}
+
+	respondWithJSON(w, response, http.StatusOK)
+}
+
+// StartGame handles starting a game
+func (h *Handler) StartGame(w http.ResponseWriter, r *http.Request) {
+	var req StartGameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	if req.PlayerID == "" {
+		respondWithError(w, "player_id is required", http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	if err := game.StartGame(req.PlayerID); err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	// Broadcast game started event
+	h.broadcastRefresh(req.Code, "game_started")
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Game started successfully",
+		"game":    game.GetGameState(),
+	}, http.StatusOK)
+}
+
+// GetGameState handles retrieving game state
+func (h *Handler) GetGameState(w http.ResponseWriter, r *http.Request) {
+	code := gameCodeParam(r)
+	if code == "" {
+		respondWithErr(w, errCodeRequired, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	state := game.GetGameState()
+	if h.hub != nil {
+		state["connected"] = h.hub.DeviceCounts(code) // Devices per player; absent means offline
+	}
+	if viewer := r.URL.Query().Get("player_id"); viewer != "" {
+		state["palette"], state["seat_colors"] = game.SeatColors(viewer)
+	}
+	respondWithJSON(w, state, http.StatusOK)
+}
+
+// RollDice handles dice rolling
+func (h *Handler) RollDice(w http.ResponseWriter, r *http.Request) {
+	var req RollDiceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	response, err := h.rollDice(game, req.PlayerID)
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, response, http.StatusOK)
+}
+
+// rollDice rolls for a player and broadcasts it; shared by the HTTP and WebSocket APIs
+func (h *Handler) rollDice(game *models.Game, playerID string) (interface{}, error) {
+	// Games that sync dice animations reveal the value later
+	pending, err := game.BeginRoll(playerID)
+	if err == nil {
+		return h.beginRoll(game, pending), nil
+	}
+	if err != models.ErrInstantDice {
+		return nil, err
+	}
+
+	roll, rollErr := game.RollDice(playerID)
+	
+	// Handle the three-sixes case - still report the roll but turn is lost
+	if rollErr != nil && rollErr != models.ErrThreeSixes {
+		return nil, rollErr
+	}
+	
+	validMoves := game.GetValidMoves(playerID)
+	moves := game.GetValidMoveDetails(playerID)
+	game.UpdateActivity()
+
+	// Broadcast dice roll event, and the moves it allows to the roller alone
+	h.broadcastRefresh(game.Code, "dice_rolled")
+	if h.hub != nil {
+		h.hub.sendMoveHints(game, playerID)
+	}
+
+	return RollDiceResponse{
+		Roll:       roll,
+		ValidMoves: validMoves,
+		Moves:      moves,
+		HasMoves:   len(validMoves) > 0,
+	}, nil
+}
+
+// MovePiece handles moving a piece
+func (h *Handler) MovePiece(w http.ResponseWriter, r *http.Request) {
+	var req MovePieceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	response, err := h.movePiece(game, req.PlayerID, req.PieceID)
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, response, http.StatusOK)
+}
+
+// movePiece moves a player's piece and broadcasts it; shared by the HTTP and WebSocket APIs
+func (h *Handler) movePiece(game *models.Game, playerID string, pieceID int) (map[string]interface{}, error) {
+	if err := game.MovePiece(playerID, pieceID); err != nil {
+		return nil, err
+	}
+
+	gameState := game.GetGameState()
+
+	// Broadcast piece moved event
+	h.broadcastRefresh(game.Code, "piece_moved")
+	h.broadcastBotChat(game)
+	h.broadcastCommentary(game)
+
+	return map[string]interface{}{
+		"message": "Piece moved successfully",
+		"game":    gameState,
+	}, nil
+}
+
+// PreviewMove reports where a piece would land with the current roll and what it
+// would capture, without moving it
+func (h *Handler) PreviewMove(w http.ResponseWriter, r *http.Request) {
+	code := gameCodeParam(r)
+	if code == "" {
+		respondWithErr(w, errCodeRequired, http.StatusBadRequest)
+		return
+	}
+	raw := r.URL.Query().Get("piece_id")
+	if raw == "" {
+		respondWithErr(w, errPieceIDRequired, http.StatusBadRequest)
+		return
+	}
+	pieceID, err := strconv.Atoi(raw)
+	if err != nil {
+		respondWithError(w, "piece_id must be a number", http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	preview, err := game.PreviewMove(r.URL.Query().Get("player_id"), pieceID)
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, preview, http.StatusOK)
+}
+
+// SkipTurn handles skipping a turn when no valid moves are available
+func (h *Handler) SkipTurn(w http.ResponseWriter, r *http.Request) {
+	var req SkipTurnRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	response, err := h.skipTurn(game, req.PlayerID)
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, response, http.StatusOK)
+}
+
+var errSkipWithMoves = errors.New("Cannot skip turn when valid moves are available")
+
+// skipTurn passes a turn that has no valid moves; shared by the HTTP and WebSocket APIs
+func (h *Handler) skipTurn(game *models.Game, playerID string) (map[string]interface{}, error) {
+	// Verify player has no valid moves before allowing skip
+	if game.HasValidMoves(playerID) {
+		return nil, errSkipWithMoves
+	}
+
+	if err := game.SkipTurn(playerID); err != nil {
+		return nil, err
+	}
+
+	// Broadcast turn skipped event
+	h.broadcastRefresh(game.Code, "turn_skipped")
+
+	return map[string]interface{}{
+		"message": "Turn skipped",
+		"game":    game.GetGameState(),
+	}, nil
+}
+
+// SetReady handles setting a player's ready status
+func (h *Handler) SetReady(w http.ResponseWriter, r *http.Request) {
+	var req SetReadyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	response, err := h.setReady(game, req.PlayerID, req.Ready)
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, response, http.StatusOK)
+}
+
+// setReady changes a player's ready status; shared by the HTTP and WebSocket APIs
+func (h *Handler) setReady(game *models.Game, playerID string, ready bool) (map[string]interface{}, error) {
+	if err := game.SetPlayerReady(playerID, ready); err != nil {
+		return nil, err
+	}
+
+	// Broadcast player ready status change
+	h.broadcastRefresh(game.Code, "player_ready")
+
+	return map[string]interface{}{
+		"message":          "Ready status updated",
+		"ready":            ready,
+		"all_players_ready": game.AreAllPlayersReady(),
+	}, nil
+}
+
+// KickPlayer handles kicking a player from the game
+func (h *Handler) KickPlayer(w http.ResponseWriter, r *http.Request) {
+	var req KickPlayerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	if err := game.KickPlayer(req.HostID, req.PlayerToKick, models.DepartureAction(req.PieceAction)); err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	// Tell the kicked player first, while their connection is still about
+	if h.hub != nil {
+		h.hub.SendToPlayer(req.Code, req.PlayerToKick, KickedEvent{Type: "kicked", GameCode: req.Code})
+	}
+
+	// Broadcast player kicked event
+	h.broadcastRefresh(req.Code, "player_kicked")
+	if game.HasEnded() {
+		h.broadcastRefresh(req.Code, "game_ended")
+		h.broadcastCommentary(game)
+	}
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Player kicked successfully",
+		"game":    game.GetGameState(),
+	}, http.StatusOK)
+}
+
+// LeaveGame handles a player leaving the game
+func (h *Handler) LeaveGame(w http.ResponseWriter, r *http.Request) {
+	var req LeaveGameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	response, err := h.leaveGame(game, req.PlayerID)
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, response, http.StatusOK)
+}
+
+// leaveGame takes a player out of the game; shared by the HTTP and WebSocket APIs
+func (h *Handler) leaveGame(game *models.Game, playerID string) (map[string]interface{}, error) {
+	if err := game.LeaveGame(playerID); err != nil {
+		return nil, err
+	}
+
+	// Broadcast player left event
+	h.broadcastRefresh(game.Code, "player_left")
+	h.broadcastHostChanges(game)
+	if game.HasEnded() {
+		h.broadcastRefresh(game.Code, "game_ended")
+		h.broadcastCommentary(game)
+	}
+
+	return map[string]interface{}{
+		"message": "Left game successfully",
+	}, nil
+}
+
+// PauseGame handles pausing the game
+func (h *Handler) PauseGame(w http.ResponseWriter, r *http.Request) {
+	var req PauseGameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	if err := game.RequestPause(req.PlayerID, time.Duration(req.Seconds)*time.Second); err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	// Broadcast game paused event
+	h.broadcastRefresh(req.Code, "game_paused")
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Game paused",
+		"game":    game.GetGameState(),
+	}, http.StatusOK)
+}
+
+// ResumeGame handles resuming the game
+func (h *Handler) ResumeGame(w http.ResponseWriter, r *http.Request) {
+	var req ResumeGameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	var connected map[string]int
+	if h.hub != nil {
+		connected = h.hub.DeviceCounts(req.Code)
+	}
+	if err := game.ResumeGame(req.PlayerID, connected); err != nil {
+		if err == models.ErrWaitingForPlayers {
+			missing, needed := game.WaitingFor(req.PlayerID, connected)
+			if h.hub != nil {
+				h.hub.BroadcastWaiting(req.Code, missing, needed)
+			}
+			respondWithJSON(w, map[string]interface{}{
+				"error":   err.Error(),
+				"missing": missing,
+				"needed":  needed,
+			}, http.StatusConflict)
+			return
+		}
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	// Broadcast game resumed event
+	h.broadcastRefresh(req.Code, "game_resumed")
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Game resumed",
+		"game":    game.GetGameState(),
+	}, http.StatusOK)
+}
+
+// VotePause handles a vote on a pause longer than the game's limit
+func (h *Handler) VotePause(w http.ResponseWriter, r *http.Request) {
+	var req PauseVoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	result, err := game.VotePause(req.PlayerID, req.Approve)
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	switch result {
+	case models.PauseVoteApproved:
+		h.broadcastRefresh(req.Code, "pause_extended")
+	case models.PauseVoteRejected:
+		h.broadcastRefresh(req.Code, "pause_vote_rejected")
+	default:
+		h.broadcastRefresh(req.Code, "pause_vote")
+	}
+
+	respondWithJSON(w, map[string]interface{}{
+		"result": result,
+		"game":   game.GetGameState(),
+	}, http.StatusOK)
+}
+
+// SendChat handles sending a chat message
+func (h *Handler) SendChat(w http.ResponseWriter, r *http.Request) {
+	var req ChatMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	response, err := h.sendChat(game, req.PlayerID, req.Message)
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, response, http.StatusOK)
+}
+
+// sendChat posts a chat message and broadcasts it; shared by the HTTP and WebSocket APIs
+func (h *Handler) sendChat(game *models.Game, playerID, message string) (map[string]interface{}, error) {
+	msg, err := game.SendChatMessage(playerID, message)
+	if err != nil {
+		return nil, err
+	}
+
+	// Translate for the connected readers first so the message arrives in their language.
+	// This outlives the request, so it gets its own deadline rather than the request's.
+	if h.translator != nil && h.hub != nil {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), translationTimeout)
+			defer cancel()
+			game.TranslateChat(ctx, msg.ID, h.translator, h.hub.Locales(game.Code))
+			h.broadcastRefresh(game.Code, "chat_message")
+		}()
+	} else {
+		h.broadcastRefresh(game.Code, "chat_message")
+	}
+
+	return map[string]interface{}{
+		"message": "Chat message sent",
+	}, nil
+}
+
+// MarkChatRead handles moving a participant's chat read marker
+func (h *Handler) MarkChatRead(w http.ResponseWriter, r *http.Request) {
+	var req ChatReadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	unread, err := game.MarkChatRead(req.PlayerID, req.ReadAt)
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, map[string]interface{}{
+		"unread_chat": unread,
+	}, http.StatusOK)
+}
+
+// JoinAsSpectator handles joining a game as a spectator
+func (h *Handler) JoinAsSpectator(w http.ResponseWriter, r *http.Request) {
+	var req SpectateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.JoinAsSpectator(r.Context(), req.Code, req.SpectatorID, req.SpectatorName, req.Password)
+	if err != nil {
+		respondWithErr(w, err, passwordStatus(err))
+		return
+	}
+
+	// Broadcast spectator joined event
+	h.broadcastRefresh(req.Code, "spectator_joined")
+
+	respondWithJSON(w, map[string]interface{}{
+		"message":        "Joined as spectator",
+		"game":           game.GetGameState(),
+		"session_secret": game.SessionSecret(req.SpectatorID),
+	}, http.StatusOK)
+}
+
+// Rematch handles requesting a rematch
+func (h *Handler) Rematch(w http.ResponseWriter, r *http.Request) {
+	var req RematchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	if err := game.Rematch(req.HostID); err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	// Broadcast rematch event
+	h.broadcastRefresh(req.Code, "rematch")
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Rematch started - waiting for all players to be ready",
+		"game":    game.GetGameState(),
+	}, http.StatusOK)
+}
+
+// GetMoveHistory handles getting the move history
+func (h *Handler) GetMoveHistory(w http.ResponseWriter, r *http.Request) {
+	code := gameCodeParam(r)
+	if code == "" {
+		respondWithErr(w, errCodeRequired, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	respondWithJSON(w, map[string]interface{}{
+		"move_history": game.MoveHistory,
+	}, http.StatusOK)
+}
+
+// GetChat handles getting the chat history, translated for the optional ?locale=
+// reader, or the locale in ?player_id='s profile
+func (h *Handler) GetChat(w http.ResponseWriter, r *http.Request) {
+	code := gameCodeParam(r)
+	if code == "" {
+		respondWithErr(w, errCodeRequired, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	locale := r.URL.Query().Get("locale")
+	if playerID := r.URL.Query().Get("player_id"); locale == "" && playerID != "" {
+		locale = h.gameManager.Profiles().Get(playerID).Locale
+	}
+
+	respondWithJSON(w, map[string]interface{}{
+		"chat_messages": game.GetRecentChatFor(100, locale),
+	}, http.StatusOK)
+}
+
+// GetCommentary returns recent commentary so late spectators can catch up
+func (h *Handler) GetCommentary(w http.ResponseWriter, r *http.Request) {
+	code := gameCodeParam(r)
+	if code == "" {
+		respondWithErr(w, errCodeRequired, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	respondWithJSON(w, map[string]interface{}{
+		"commentary": game.GetCommentary(),
+	}, http.StatusOK)
+}
+
+// AddBot handles adding an AI player to the game
+func (h *Handler) AddBot(w http.ResponseWriter, r *http.Request) {
+	var req AddBotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, bot, err := h.gameManager.AddBot(r.Context(), req.Code, req.HostID, models.BotOptions{
+		Name:        req.Name,
+		Personality: models.BotPersonality(req.Personality),
+		Difficulty:  models.BotDifficulty(req.Difficulty),
+	})
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	// Broadcast bot joined event
+	h.broadcastRefresh(req.Code, "player_joined")
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Bot added successfully",
+		"bot_id":  bot.ID,
+		"game":    game.GetGameState(),
+	}, http.StatusOK)
+}
+
+// FillBots handles topping the lobby up to max players with bots
+func (h *Handler) FillBots(w http.ResponseWriter, r *http.Request) {
+	var req FillBotsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, bots, err := h.gameManager.FillWithBots(r.Context(), req.Code, req.HostID, models.BotOptions{
+		Personality: models.BotPersonality(req.Personality),
+		Difficulty:  models.BotDifficulty(req.Difficulty),
+	})
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	botIDs := make([]string, len(bots))
+	for i, bot := range bots {
+		botIDs[i] = bot.ID
+	}
+
+	h.broadcastRefresh(req.Code, "player_joined")
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": fmt.Sprintf("Added %d bots", len(bots)),
+		"bot_ids": botIDs,
+		"game":    game.GetGameState(),
+	}, http.StatusOK)
+}
+
+// TakeOverBot handles a human player asking the host for a bot's seat
+func (h *Handler) TakeOverBot(w http.ResponseWriter, r *http.Request) {
+	var req TakeOverBotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	if req.Code == "" || req.BotID == "" || req.PlayerID == "" || req.PlayerName == "" {
+		respondWithError(w, "code, bot_id, player_id, and player_name are required", http.StatusBadRequest)
+		return
+	}
+
+	game, secret, err := h.gameManager.RequestBotTakeover(r.Context(), req.Code, req.BotID, req.PlayerID, req.PlayerName, req.Password)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, passwordStatus(err)))
+		return
+	}
+
+	h.broadcastRefresh(req.Code, "takeover_requested")
+
+	respondWithJSON(w, JoinGameResponse{
+		Message:       "Asked the host for the bot's seat",
+		Game:          game.GetGameState(),
+		SessionSecret: secret,
+	}, http.StatusAccepted)
+}
+
+// ApproveTakeover handles the host letting a player take the bot seat they asked for
+func (h *Handler) ApproveTakeover(w http.ResponseWriter, r *http.Request) {
+	var req TakeoverDecisionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	if _, err := game.ApproveBotTakeover(req.HostID, req.RequesterID); err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	h.broadcastRefresh(req.Code, "seat_taken_over")
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Took over the bot's seat",
+		"game":    game.GetGameState(),
+	}, http.StatusOK)
+}
+
+// DeclineTakeover handles the host turning down a request for a bot seat
+func (h *Handler) DeclineTakeover(w http.ResponseWriter, r *http.Request) {
+	var req TakeoverDecisionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	if err := game.DeclineBotTakeover(req.HostID, req.RequesterID); err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	h.broadcastRefresh(req.Code, "takeover_declined")
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Turned down the request",
+		"game":    game.GetGameState(),
+	}, http.StatusOK)
+}
+
+// SetBotChat handles toggling bot chat reactions for a game
+func (h *Handler) SetBotChat(w http.ResponseWriter, r *http.Request) {
+	var req BotChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	if err := game.SetBotChat(req.HostID, req.Enabled); err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, map[string]interface{}{
+		"message":  "Bot chat updated",
+		"bot_chat": req.Enabled,
+	}, http.StatusOK)
+}
+
+// BotStandIn handles the host having a bot play for a disconnected player until
+// they reconnect
+func (h *Handler) BotStandIn(w http.ResponseWriter, r *http.Request) {
+	var req BotStandInRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	if err := game.HandToBot(req.HostID, req.PlayerID); err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	h.broadcastRefresh(req.Code, "bot_took_over")
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "A bot is playing for the player until they reconnect",
+		"game":    game.GetGameState(),
+	}, http.StatusOK)
+}
+
+// RemoveBot handles removing an AI player from the game
+func (h *Handler) RemoveBot(w http.ResponseWriter, r *http.Request) {
+	var req RemoveBotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.RemoveBot(r.Context(), req.Code, req.HostID, req.BotID)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	// Broadcast bot left event
+	h.broadcastRefresh(req.Code, "player_left")
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Bot removed successfully",
+		"game":    game.GetGameState(),
+	}, http.StatusOK)
+}
+
+// VerifyReplay handles re-simulating a game's move history and checking the result
+func (h *Handler) VerifyReplay(w http.ResponseWriter, r *http.Request) {
+	code := gameCodeParam(r)
+	if code == "" {
+		respondWithErr(w, errCodeRequired, http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.gameManager.VerifyReplay(r.Context(), code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	respondWithJSON(w, result, http.StatusOK)
+}
+
+// FastForward handles running the rest of a bot-only game at engine speed
+func (h *Handler) FastForward(w http.ResponseWriter, r *http.Request) {
+	var req FastForwardRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	result, err := game.FastForward(req.HostID)
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	// Broadcast fast-forward event
+	h.broadcastRefresh(req.Code, "fast_forwarded")
+	h.broadcastBotChat(game)
+	h.broadcastCommentary(game)
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Game fast-forwarded",
+		"result":  result,
+		"game":    game.GetGameState(),
+	}, http.StatusOK)
+}
+
+// RestoreGame handles restoring a soft-deleted game within its restore window
+func (h *Handler) RestoreGame(w http.ResponseWriter, r *http.Request) {
+	var req RestoreGameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.RestoreGame(r.Context(), req.Code, req.HostID)
+	if err != nil {
+		status := http.StatusBadRequest
+		if err == models.ErrGameNotFound {
+			status = http.StatusNotFound
+		}
+		respondWithErr(w, err, unavailableStatus(err, status))
+		return
+	}
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Game restored",
+		"game":    game.GetGameState(),
+	}, http.StatusOK)
+}
+
+// gameCodeParam returns the game code from the {code} path parameter, falling
+// back to the ?code= query parameter used by the flat routes
+func gameCodeParam(r *http.Request) string {
+	if code := chi.URLParam(r, "code"); code != "" {
+		return code
+	}
+	return r.URL.Query().Get("code")
+}
+
+// respondWithJSON sends a JSON response
+func respondWithJSON(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+// respondWithError sends an error response coded by its HTTP status
+func respondWithError(w http.ResponseWriter, message string, statusCode int) {
+	respondWithJSON(w, ErrorResponse{Error: message, Code: statusErrorCode(statusCode)}, statusCode)
+}
+
+// respondWithErr sends an error response carrying the error's code
+func respondWithErr(w http.ResponseWriter, err error, statusCode int) {
+	respondWithJSON(w, ErrorResponse{Error: err.Error(), Code: errorCode(err, statusCode)}, statusCode)
+}
@@ -0,0 +1,463 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// Per-game webhook limits
+const (
+	MaxWebhooksPerGame    = 5
+	MaxWebhookDeliveryLog = 50  // Delivery attempts kept per webhook
+	MaxWebhookDeadLetters = 200 // Undeliverable events kept for operators
+	webhookMaxAttempts    = 5
+)
+
+// DefaultGlobalWebhookEvents are the events server-wide webhooks get unless configured otherwise
+var DefaultGlobalWebhookEvents = []string{"game_started", "game_ended", "player_joined", "turn_timeout"}
+
+// Headers sent with every per-game webhook delivery
+const (
+	WebhookSignatureHeader = "X-Ludo-Signature" // "sha256=" + hex HMAC of the body
+	WebhookEventHeader     = "X-Ludo-Event"
+	WebhookDeliveryHeader  = "X-Ludo-Delivery"
+)
+
+// GameWebhook is an integration's subscription to events of one game, or of
+// every game when GameCode is empty
+type GameWebhook struct {
+	ID        string           `json:"id"`
+	GameCode  string           `json:"game_code,omitempty"`
+	URL       string           `json:"url"`
+	Events    []string         `json:"events,omitempty"` // Empty means every event
+	CreatedAt models.Timestamp `json:"created_at"`
+	secret    string
+	trusted   bool // Configured by the operator, so it may point at private addresses
+}
+
+// wants reports whether the webhook subscribed to an event type
+func (wh *GameWebhook) wants(eventType string) bool {
+	if len(wh.Events) == 0 {
+		return true
+	}
+	for _, e := range wh.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery records one attempt to deliver an event
+type WebhookDelivery struct {
+	DeliveryID string           `json:"delivery_id"`
+	Event      string           `json:"event"`
+	Attempt    int              `json:"attempt"`
+	StatusCode int              `json:"status_code,omitempty"`
+	Error      string           `json:"error,omitempty"`
+	Success    bool             `json:"success"`
+	Timestamp  models.Timestamp `json:"timestamp"`
+}
+
+// WebhookDeadLetter is an event that could not be delivered after every retry
+type WebhookDeadLetter struct {
+	DeliveryID string           `json:"delivery_id"`
+	WebhookID  string           `json:"webhook_id"`
+	GameCode   string           `json:"game_code"`
+	URL        string           `json:"url"`
+	Event      string           `json:"event"`
+	Attempts   int              `json:"attempts"`
+	LastError  string           `json:"last_error"`
+	Payload    json.RawMessage  `json:"payload"`
+	FailedAt   models.Timestamp `json:"failed_at"`
+}
+
+// GameWebhooks delivers game events to per-game and server-wide subscriptions
+// with HMAC signatures and exponential-backoff retries. Events that still fail
+// go to a dead-letter log.
+type GameWebhooks struct {
+	hooks       map[string][]*GameWebhook    // By game code; server-wide under ""
+	deliveries  map[string][]WebhookDelivery // By webhook ID
+	deadLetters []WebhookDeadLetter          // Oldest first
+	client      *http.Client                 // For server-wide webhooks the operator configured
+	userClient  *http.Client                 // For webhooks users registered; refuses private addresses
+	backoff     time.Duration                // Delay before the first retry; doubles each attempt
+	seq         int
+	mu          sync.Mutex
+}
+
+// NewGameWebhooks creates an empty per-game webhook registry
+func NewGameWebhooks() *GameWebhooks {
+	return &GameWebhooks{
+		hooks:      make(map[string][]*GameWebhook),
+		deliveries: make(map[string][]WebhookDelivery),
+		client:     &http.Client{Timeout: 5 * time.Second},
+		userClient: newOutboundClient(5 * time.Second),
+		backoff:    time.Second,
+	}
+}
+
+// RegisterGlobal subscribes a URL to events of every game, signed with the given
+// secret. Server-wide webhooks come from configuration, so they aren't capped.
+func (gw *GameWebhooks) RegisterGlobal(rawURL string, events []string, secret string) (*GameWebhook, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("a signing secret is required")
+	}
+	wh, _, err := gw.register("", rawURL, events, secret, 0, true)
+	return wh, err
+}
+
+// Register subscribes a URL to events of a game and returns the webhook and its
+// signing secret. The URL must be on a public address, and on an allowed host if
+// the operator limited them.
+func (gw *GameWebhooks) Register(gameCode, rawURL string, events []string, secret string) (*GameWebhook, string, error) {
+	if err := CheckOutboundURL(rawURL); err != nil {
+		return nil, "", err
+	}
+	return gw.register(gameCode, rawURL, events, secret, MaxWebhooksPerGame, false)
+}
+
+// register adds a webhook under a game code, refusing it once limit webhooks
+// are registered there; zero means no limit
+func (gw *GameWebhooks) register(gameCode, rawURL string, events []string, secret string, limit int, trusted bool) (*GameWebhook, string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, "", ErrInvalidOutboundURL
+	}
+	if secret == "" {
+		secret = models.GenerateSessionSecret()
+	}
+
+	gw.mu.Lock()
+	defer gw.mu.Unlock()
+
+	if limit > 0 && len(gw.hooks[gameCode]) >= limit {
+		return nil, "", fmt.Errorf("a game can have at most %d webhooks", limit)
+	}
+
+	gw.seq++
+	wh := &GameWebhook{
+		ID:        fmt.Sprintf("wh_%d_%d", time.Now().Unix(), gw.seq),
+		GameCode:  gameCode,
+		URL:       rawURL,
+		Events:    events,
+		CreatedAt: models.Now(),
+		secret:    secret,
+		trusted:   trusted,
+	}
+	gw.hooks[gameCode] = append(gw.hooks[gameCode], wh)
+	return wh, secret, nil
+}
+
+// Unregister removes a webhook from a game. Returns false if it doesn't exist.
+func (gw *GameWebhooks) Unregister(gameCode, webhookID string) bool {
+	gw.mu.Lock()
+	defer gw.mu.Unlock()
+
+	hooks := gw.hooks[gameCode]
+	for i, wh := range hooks {
+		if wh.ID == webhookID {
+			gw.hooks[gameCode] = append(hooks[:i:i], hooks[i+1:]...)
+			delete(gw.deliveries, webhookID)
+			return true
+		}
+	}
+	return false
+}
+
+// List returns the webhooks registered for a game
+func (gw *GameWebhooks) List(gameCode string) []*GameWebhook {
+	gw.mu.Lock()
+	defer gw.mu.Unlock()
+	return append([]*GameWebhook{}, gw.hooks[gameCode]...)
+}
+
+// Deliveries returns the recent delivery attempts of a game's webhook
+func (gw *GameWebhooks) Deliveries(gameCode, webhookID string) ([]WebhookDelivery, bool) {
+	gw.mu.Lock()
+	defer gw.mu.Unlock()
+
+	for _, wh := range gw.hooks[gameCode] {
+		if wh.ID == webhookID {
+			return append([]WebhookDelivery{}, gw.deliveries[webhookID]...), true
+		}
+	}
+	return nil, false
+}
+
+// RemoveGame drops every webhook of a game that no longer exists
+func (gw *GameWebhooks) RemoveGame(gameCode string) {
+	gw.mu.Lock()
+	defer gw.mu.Unlock()
+
+	for _, wh := range gw.hooks[gameCode] {
+		delete(gw.deliveries, wh.ID)
+	}
+	delete(gw.hooks, gameCode)
+}
+
+// Publish delivers the event in the background to every matching webhook
+func (gw *GameWebhooks) Publish(event Event) {
+	gw.mu.Lock()
+	var targets []*GameWebhook
+	for _, hooks := range [][]*GameWebhook{gw.hooks[event.GameCode], gw.hooks[""]} {
+		for _, wh := range hooks {
+			if wh.wants(event.Type) {
+				targets = append(targets, wh)
+			}
+		}
+	}
+	gw.seq++
+	deliveryID := fmt.Sprintf("dl_%d_%d", event.Timestamp.UnixNano(), gw.seq)
+	gw.mu.Unlock()
+
+	if len(targets) == 0 {
+		return
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	for _, wh := range targets {
+		go gw.deliver(wh, event.GameCode, event.Type, deliveryID, body)
+	}
+}
+
+// deliver posts a signed event, retrying failures with exponential backoff and
+// dead-lettering it if every attempt fails
+func (gw *GameWebhooks) deliver(wh *GameWebhook, gameCode, eventType, deliveryID string, body []byte) {
+	mac := hmac.New(sha256.New, []byte(wh.secret))
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	client := gw.userClient
+	if wh.trusted {
+		client = gw.client
+	}
+
+	delay := gw.backoff
+	var record WebhookDelivery
+	for attempt := 1; attempt <= webhookMaxAttempts; attempt++ {
+		record = WebhookDelivery{
+			DeliveryID: deliveryID,
+			Event:      eventType,
+			Attempt:    attempt,
+			Timestamp:  models.Now(),
+		}
+
+		req, err := http.NewRequest(http.MethodPost, wh.URL, bytes.NewReader(body))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(WebhookSignatureHeader, signature)
+			req.Header.Set(WebhookEventHeader, eventType)
+			req.Header.Set(WebhookDeliveryHeader, deliveryID)
+
+			var resp *http.Response
+			resp, err = client.Do(req)
+			if err == nil {
+				resp.Body.Close()
+				record.StatusCode = resp.StatusCode
+				record.Success = resp.StatusCode < 300
+				if !record.Success {
+					err = fmt.Errorf("status %d", resp.StatusCode)
+				}
+			}
+		}
+		if err != nil {
+			record.Error = err.Error()
+		}
+
+		if !gw.record(wh, record) || record.Success {
+			return
+		}
+		// Client errors other than rate limiting won't succeed on retry
+		if record.StatusCode >= 400 && record.StatusCode < 500 && record.StatusCode != http.StatusTooManyRequests {
+			break
+		}
+		if attempt < webhookMaxAttempts {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+	gw.deadLetter(wh, gameCode, record, body)
+}
+
+// deadLetter keeps an event that could not be delivered, dropping the oldest
+// once the log is full
+func (gw *GameWebhooks) deadLetter(wh *GameWebhook, gameCode string, last WebhookDelivery, body []byte) {
+	log.Printf("Webhook %s: giving up on %s delivery %s after %d attempts: %s", wh.ID, last.Event, last.DeliveryID, last.Attempt, last.Error)
+
+	gw.mu.Lock()
+	defer gw.mu.Unlock()
+
+	gw.deadLetters = append(gw.deadLetters, WebhookDeadLetter{
+		DeliveryID: last.DeliveryID,
+		WebhookID:  wh.ID,
+		GameCode:   gameCode,
+		URL:        wh.URL,
+		Event:      last.Event,
+		Attempts:   last.Attempt,
+		LastError:  last.Error,
+		Payload:    body,
+		FailedAt:   models.Now(),
+	})
+	if len(gw.deadLetters) > MaxWebhookDeadLetters {
+		gw.deadLetters = gw.deadLetters[len(gw.deadLetters)-MaxWebhookDeadLetters:]
+	}
+}
+
+// DeadLetters returns the events that could not be delivered, oldest first
+func (gw *GameWebhooks) DeadLetters() []WebhookDeadLetter {
+	gw.mu.Lock()
+	defer gw.mu.Unlock()
+	return append([]WebhookDeadLetter{}, gw.deadLetters...)
+}
+
+// record appends a delivery attempt to the webhook's log. Returns false if the
+// webhook was unregistered meanwhile, so retries stop.
+func (gw *GameWebhooks) record(wh *GameWebhook, d WebhookDelivery) bool {
+	gw.mu.Lock()
+	defer gw.mu.Unlock()
+
+	registered := false
+	for _, h := range gw.hooks[wh.GameCode] {
+		if h == wh {
+			registered = true
+			break
+		}
+	}
+	if !registered {
+		return false
+	}
+
+	log := append(gw.deliveries[wh.ID], d)
+	if len(log) > MaxWebhookDeliveryLog {
+		log = log[len(log)-MaxWebhookDeliveryLog:]
+	}
+	gw.deliveries[wh.ID] = log
+	return true
+}
+
+// GetWebhookDeadLetters returns webhook events that could not be delivered (admin only)
+func (h *Handler) GetWebhookDeadLetters(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, map[string]interface{}{
+		"dead_letters": h.gameWebhooks.DeadLetters(),
+	}, http.StatusOK)
+}
+
+// GameWebhookRequest represents the request to register a webhook for a game
+type GameWebhookRequest struct {
+	Code   string   `json:"code"`
+	HostID string   `json:"host_id"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`           // e.g. ["game_ended", "capture"]; empty for all
+	Secret string   `json:"secret,omitempty"` // Generated if omitted
+}
+
+// DeleteGameWebhookRequest represents the request to remove a game webhook
+type DeleteGameWebhookRequest struct {
+	Code      string `json:"code"`
+	HostID    string `json:"host_id"`
+	WebhookID string `json:"webhook_id"`
+}
+
+// SetGameWebhooks sets the registry that per-game webhook routes manage
+func (h *Handler) SetGameWebhooks(gw *GameWebhooks) {
+	h.gameWebhooks = gw
+}
+
+// hostGame looks up a game and checks the caller hosts it, writing the error response if not
+func (h *Handler) hostGame(w http.ResponseWriter, r *http.Request, code, hostID string) (*models.Game, bool) {
+	game, err := h.gameManager.GetGame(r.Context(), code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return nil, false
+	}
+	if !game.IsHost(hostID) {
+		respondWithErr(w, models.ErrNotHost, http.StatusForbidden)
+		return nil, false
+	}
+	return game, true
+}
+
+// ListGameWebhooks returns a game's webhooks (host only)
+func (h *Handler) ListGameWebhooks(w http.ResponseWriter, r *http.Request) {
+	code := gameCodeParam(r)
+	if _, ok := h.hostGame(w, r, code, r.URL.Query().Get("host_id")); !ok {
+		return
+	}
+	respondWithJSON(w, map[string]interface{}{
+		"webhooks": h.gameWebhooks.List(code),
+	}, http.StatusOK)
+}
+
+// RegisterGameWebhook subscribes a URL to a game's events (host only)
+func (h *Handler) RegisterGameWebhook(w http.ResponseWriter, r *http.Request) {
+	var req GameWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+	if _, ok := h.hostGame(w, r, req.Code, req.HostID); !ok {
+		return
+	}
+
+	webhook, secret, err := h.gameWebhooks.Register(req.Code, req.URL, req.Events, req.Secret)
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+	respondWithJSON(w, map[string]interface{}{
+		"webhook": webhook,
+		"secret":  secret, // Only returned once; used to verify X-Ludo-Signature
+	}, http.StatusOK)
+}
+
+// DeleteGameWebhook removes a game's webhook (host only)
+func (h *Handler) DeleteGameWebhook(w http.ResponseWriter, r *http.Request) {
+	var req DeleteGameWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+	if _, ok := h.hostGame(w, r, req.Code, req.HostID); !ok {
+		return
+	}
+
+	if !h.gameWebhooks.Unregister(req.Code, req.WebhookID) {
+		respondWithError(w, "webhook not found", http.StatusNotFound)
+		return
+	}
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Webhook removed",
+	}, http.StatusOK)
+}
+
+// GetWebhookDeliveries returns the delivery log of a game's webhook (host only)
+func (h *Handler) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	code := gameCodeParam(r)
+	if _, ok := h.hostGame(w, r, code, query.Get("host_id")); !ok {
+		return
+	}
+
+	deliveries, ok := h.gameWebhooks.Deliveries(code, query.Get("webhook_id"))
+	if !ok {
+		respondWithError(w, "webhook not found", http.StatusNotFound)
+		return
+	}
+	respondWithJSON(w, map[string]interface{}{
+		"deliveries": deliveries,
+	}, http.StatusOK)
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// TransferHostRequest represents the request to hand the host role to another player
+type TransferHostRequest struct {
+	Code      string `json:"code"`
+	HostID    string `json:"host_id"`
+	NewHostID string `json:"new_host_id"`
+}
+
+// HostChangedEvent tells a game's clients who the host is now
+type HostChangedEvent struct {
+	Type string `json:"type"` // Always "host_changed"
+	models.HostChange
+}
+
+// BroadcastHostChanged tells clients and integrations that the host role moved
+func (h *Hub) BroadcastHostChanged(gameCode string, change models.HostChange) {
+	event := HostChangedEvent{Type: "host_changed", HostChange: change}
+	h.publish(gameCode, event.Type, event)
+	h.BroadcastEvent(gameCode, event)
+}
+
+// broadcastHostChanges announces the game's host changes since the last call
+func (h *Handler) broadcastHostChanges(game *models.Game) {
+	for _, change := range game.ConsumeHostChanges() {
+		if h.hub != nil {
+			h.hub.BroadcastHostChanged(game.Code, change)
+		}
+	}
+}
+
+// TransferHost handles the host handing the role to another human player
+func (h *Handler) TransferHost(w http.ResponseWriter, r *http.Request) {
+	var req TransferHostRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	if err := game.TransferHost(req.HostID, req.NewHostID); err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	h.broadcastRefresh(req.Code, "host_changed")
+	h.broadcastHostChanges(game)
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Host transferred",
+		"host_id": req.NewHostID,
+	}, http.StatusOK)
+}
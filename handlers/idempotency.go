@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Idempotency settings
+const (
+	IdempotencyHeader       = "Idempotency-Key"
+	IdempotencyReplayHeader = "Idempotent-Replayed" // Set to "true" on cached responses
+	IdempotencyTTL          = 10 * time.Minute      // How long a response is kept for retries
+	maxIdempotencyKeyLength = 128
+)
+
+var errIdempotencyKeyTooLong = errors.New("idempotency key must be at most 128 characters")
+
+// idempotentFields are the request body fields used to find a cached response
+type idempotentFields struct {
+	Code      string `json:"code"`
+	PlayerID  string `json:"player_id"`
+	RequestID string `json:"request_id"`
+}
+
+// idempotentResponse is the first response to a request with a given key
+type idempotentResponse struct {
+	done        chan struct{} // Closed once the response is recorded
+	status      int
+	contentType string
+	body        []byte
+	expires     time.Time
+}
+
+// kept reports whether a response is replayed to retries. Server errors aren't,
+// nor are rejected signatures, conflicts and rate limits, which a retry may get past.
+func (resp *idempotentResponse) kept() bool {
+	switch resp.status {
+	case 0, http.StatusUnauthorized, http.StatusConflict, http.StatusTooManyRequests:
+		return false
+	}
+	return resp.status < http.StatusInternalServerError
+}
+
+// idempotencyCache remembers responses by game, player, path and key
+type idempotencyCache struct {
+	responses map[string]*idempotentResponse
+	mu        sync.Mutex
+}
+
+// reserve returns the response recorded for a key, or reserves the key for the
+// caller to record one, reporting true
+func (c *idempotencyCache) reserve(key string) (*idempotentResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if resp, ok := c.responses[key]; ok {
+		select {
+		case <-resp.done:
+			if now.Before(resp.expires) {
+				return resp, false
+			}
+		default:
+			return resp, false // Still running; the caller waits for it
+		}
+	}
+
+	// Drop expired responses while we hold the lock
+	for k, resp := range c.responses {
+		select {
+		case <-resp.done:
+			if !now.Before(resp.expires) {
+				delete(c.responses, k)
+			}
+		default:
+		}
+	}
+
+	if c.responses == nil {
+		c.responses = make(map[string]*idempotentResponse)
+	}
+	resp := &idempotentResponse{done: make(chan struct{})}
+	c.responses[key] = resp
+	return resp, true
+}
+
+// forget drops a reserved key so the request can be retried for real
+func (c *idempotencyCache) forget(key string, resp *idempotentResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.responses[key] == resp {
+		delete(c.responses, key)
+	}
+}
+
+// responseRecorder passes a response through while keeping a copy
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+// Idempotent is middleware that makes retries of a mutating request safe. A request
+// carrying an Idempotency-Key header, or a request_id in its body, runs once per
+// game; repeats within IdempotencyTTL get the first response again, waiting for it
+// if it is still running. Server errors, rejected signatures, conflicts and rate
+// limits aren't kept, so those requests can be retried. It goes before
+// RequireSignature, since a retry reuses its nonce; in strict signing mode a
+// retry's signature is still checked before it gets the response.
+func (h *Handler) Idempotent(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var fields idempotentFields
+		json.Unmarshal(body, &fields) // A bad body is rejected by the handler
+		key := r.Header.Get(IdempotencyHeader)
+		if key == "" {
+			key = fields.RequestID
+		}
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			respondWithErr(w, errIdempotencyKeyTooLong, http.StatusBadRequest)
+			return
+		}
+
+		cacheKey := fields.Code + "|" + fields.PlayerID + "|" + r.URL.Path + "|" + key
+		resp, first := h.idempotency.reserve(cacheKey)
+		for !first {
+			select {
+			case <-resp.done:
+			case <-r.Context().Done():
+				respondWithErr(w, r.Context().Err(), http.StatusGatewayTimeout)
+				return
+			}
+			if resp.kept() {
+				if status, err := h.checkRetrySignature(r, fields); err != nil {
+					respondWithErr(w, err, status)
+					return
+				}
+				w.Header().Set("Content-Type", resp.contentType)
+				w.Header().Set(IdempotencyReplayHeader, "true")
+				w.WriteHeader(resp.status)
+				w.Write(resp.body)
+				return
+			}
+			// The request we waited for failed; run this one instead
+			resp, first = h.idempotency.reserve(cacheKey)
+		}
+
+		rec := &responseRecorder{ResponseWriter: w}
+		defer func() {
+			resp.status = rec.status
+			if !resp.kept() {
+				h.idempotency.forget(cacheKey, resp)
+			}
+			resp.contentType = w.Header().Get("Content-Type")
+			resp.body = rec.body.Bytes()
+			resp.expires = time.Now().Add(IdempotencyTTL)
+			close(resp.done)
+		}()
+		next.ServeHTTP(rec, r)
+	})
+}
+
+// checkRetrySignature checks a retry was signed by its player before it is
+// answered from the cache, returning the status to reject it with
+func (h *Handler) checkRetrySignature(r *http.Request, fields idempotentFields) (int, error) {
+	if !h.strictSigning {
+		return 0, nil
+	}
+	game, err := h.gameManager.GetGame(r.Context(), fields.Code)
+	if err != nil {
+		return unavailableStatus(err, http.StatusNotFound), err
+	}
+	nonce := r.Header.Get(NonceHeader)
+	signature := r.Header.Get(SignatureHeader)
+	if err := game.CheckRetrySignature(fields.PlayerID, r.URL.Path, nonce, signature); err != nil {
+		return http.StatusUnauthorized, err
+	}
+	return 0, nil
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+const testRollPath = "/api/v1/game/roll"
+
+// countingHandler answers with the given status and how many times it has run
+func countingHandler(calls *int32, status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(calls, 1)
+		respondWithJSON(w, map[string]int32{"call": n}, status)
+	})
+}
+
+// idempotentRequest builds a roll request with a request_id, signed with secret
+// when it isn't empty
+func idempotentRequest(code, playerID, key, secret, nonce string) *http.Request {
+	body := fmt.Sprintf(`{"code": %q, "player_id": %q, "request_id": %q}`, code, playerID, key)
+	req := httptest.NewRequest(http.MethodPost, testRollPath, strings.NewReader(body))
+	if secret != "" {
+		req.Header.Set(NonceHeader, nonce)
+		req.Header.Set(SignatureHeader, models.SignAction(secret, code, testRollPath, nonce))
+	}
+	return req
+}
+
+// testNonce returns a fresh nonce, as clients make them
+func testNonce(suffix string) string {
+	return fmt.Sprintf("%d-%s", time.Now().UnixMilli(), suffix)
+}
+
+func TestIdempotentReplay(t *testing.T) {
+	h := NewHandler(models.NewGameManager())
+	var calls int32
+	handler := h.Idempotent(countingHandler(&calls, http.StatusOK))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, idempotentRequest("12345678", "p1", "k1", "", ""))
+	retry := httptest.NewRecorder()
+	handler.ServeHTTP(retry, idempotentRequest("12345678", "p1", "k1", "", ""))
+
+	if calls != 1 {
+		t.Fatalf("Expected the request to run once, ran %d times", calls)
+	}
+	if retry.Code != http.StatusOK || retry.Body.String() != first.Body.String() {
+		t.Errorf("Expected the first response again, got %d %s", retry.Code, retry.Body.String())
+	}
+	if retry.Header().Get(IdempotencyReplayHeader) != "true" {
+		t.Error("Expected the replay to be marked")
+	}
+
+	other := httptest.NewRecorder()
+	handler.ServeHTTP(other, idempotentRequest("12345678", "p2", "k1", "", ""))
+	if calls != 2 || other.Header().Get(IdempotencyReplayHeader) != "" {
+		t.Error("Expected another player's request with the same key to run")
+	}
+}
+
+func TestIdempotentFailuresNotKept(t *testing.T) {
+	for _, status := range []int{http.StatusUnauthorized, http.StatusConflict, http.StatusTooManyRequests, http.StatusInternalServerError} {
+		h := NewHandler(models.NewGameManager())
+		var calls int32
+		handler := h.Idempotent(countingHandler(&calls, status))
+
+		handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest("12345678", "p1", "k1", "", ""))
+		handler.ServeHTTP(httptest.NewRecorder(), idempotentRequest("12345678", "p1", "k1", "", ""))
+		if calls != 2 {
+			t.Errorf("Expected a %d response not to be replayed, ran %d times", status, calls)
+		}
+	}
+}
+
+func TestIdempotentConcurrentRetriesWait(t *testing.T) {
+	h := NewHandler(models.NewGameManager())
+	var calls int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := h.Idempotent(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		close(started)
+		<-release
+		respondWithJSON(w, map[string]string{"result": "rolled"}, http.StatusOK)
+	}))
+
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(first, idempotentRequest("12345678", "p1", "k1", "", ""))
+		close(done)
+	}()
+	<-started
+
+	const waiters = 5
+	retries := make([]*httptest.ResponseRecorder, waiters)
+	var wg sync.WaitGroup
+	for i := range retries {
+		retries[i] = httptest.NewRecorder()
+		wg.Add(1)
+		go func(rec *httptest.ResponseRecorder) {
+			defer wg.Done()
+			handler.ServeHTTP(rec, idempotentRequest("12345678", "p1", "k1", "", ""))
+		}(retries[i])
+	}
+	close(release)
+	wg.Wait()
+	<-done
+
+	if calls != 1 {
+		t.Fatalf("Expected the request to run once, ran %d times", calls)
+	}
+	for _, rec := range retries {
+		if rec.Code != http.StatusOK || rec.Body.String() != first.Body.String() {
+			t.Errorf("Expected waiters to get the first response, got %d %s", rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestIdempotentRetrySignature(t *testing.T) {
+	gm := models.NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+	gm.JoinGame(context.Background(), game.Code, "p2", "Player 2", "")
+	h := NewHandler(gm)
+	h.SetStrictSigning(true)
+	var calls int32
+	handler := h.Idempotent(h.RequireSignature(countingHandler(&calls, http.StatusOK)))
+
+	// A request signed by someone else is refused and not kept for the real one
+	forged := httptest.NewRecorder()
+	handler.ServeHTTP(forged, idempotentRequest(game.Code, "host1", "k1", game.SessionSecret("p2"), testNonce("n1")))
+	if forged.Code != http.StatusUnauthorized || calls != 0 {
+		t.Fatalf("Expected a forged request to be refused, got %d", forged.Code)
+	}
+
+	nonce := testNonce("n2")
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, idempotentRequest(game.Code, "host1", "k1", game.SessionSecret("host1"), nonce))
+	if first.Code != http.StatusOK || calls != 1 {
+		t.Fatalf("Expected the signed request to run, got %d %s", first.Code, first.Body.String())
+	}
+
+	retry := httptest.NewRecorder()
+	handler.ServeHTTP(retry, idempotentRequest(game.Code, "host1", "k1", game.SessionSecret("host1"), nonce))
+	if retry.Code != http.StatusOK || retry.Header().Get(IdempotencyReplayHeader) != "true" {
+		t.Errorf("Expected a retry with the same nonce to be replayed, got %d %s", retry.Code, retry.Body.String())
+	}
+
+	forgedRetry := httptest.NewRecorder()
+	handler.ServeHTTP(forgedRetry, idempotentRequest(game.Code, "host1", "k1", game.SessionSecret("p2"), testNonce("n3")))
+	if forgedRetry.Code != http.StatusUnauthorized || forgedRetry.Header().Get(IdempotencyReplayHeader) != "" {
+		t.Errorf("Expected a retry signed by someone else to be refused, got %d %s", forgedRetry.Code, forgedRetry.Body.String())
+	}
+	if calls != 1 {
+		t.Errorf("Expected the request to run once, ran %d times", calls)
+	}
+}
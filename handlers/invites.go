@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// InviteEvent tells a player about a game invite: "game_invite" to the invitee,
+// or "invite_declined" to the sender
+type InviteEvent struct {
+	Type   string        `json:"type"`
+	Invite models.Invite `json:"invite"`
+}
+
+// InviteRequest represents the request to invite a friend to a game
+type InviteRequest struct {
+	Code     string `json:"code"`
+	PlayerID string `json:"player_id"`
+	FriendID string `json:"friend_id"`
+}
+
+// InviteAnswerRequest represents the request to accept or decline an invite
+type InviteAnswerRequest struct {
+	Code       string `json:"code"` // A game the player is in; its session secret signs the request
+	InviteID   string `json:"invite_id"`
+	PlayerID   string `json:"player_id"`
+	PlayerName string `json:"player_name,omitempty"` // Accepting only; defaults to the profile's name
+	Password   string `json:"password,omitempty"`    // Accepting only; needed unless the host sent the invite
+}
+
+// inviteStatus maps invite errors to HTTP statuses
+func inviteStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrNotFriends), errors.Is(err, models.ErrWrongPassword):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrInviteNotFound), errors.Is(err, models.ErrGameNotFound):
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
+
+// notifyInvite sends an invite event to every connection a player has open
+func (h *Handler) notifyInvite(playerID, kind string, invite models.Invite) {
+	if h.hub == nil {
+		return
+	}
+	message, err := json.Marshal(InviteEvent{Type: kind, Invite: invite})
+	if err != nil {
+		return
+	}
+	h.hub.notifyPlayer(playerID, message)
+}
+
+// SendInvite invites a friend to the caller's game while it waits for players.
+// The friend gets a game_invite event if connected, and can list it until it expires.
+func (h *Handler) SendInvite(w http.ResponseWriter, r *http.Request) {
+	var req InviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+	if req.Code == "" || req.PlayerID == "" || req.FriendID == "" {
+		respondWithError(w, "code, player_id, and friend_id are required", http.StatusBadRequest)
+		return
+	}
+
+	invite, err := h.gameManager.InvitePlayer(r.Context(), req.Code, req.PlayerID, req.FriendID)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, inviteStatus(err)))
+		return
+	}
+	h.notifyInvite(invite.ToID, "game_invite", invite)
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Invite sent",
+		"invite":  invite,
+		"online":  h.hub != nil && h.hub.IsOnline(invite.ToID),
+	}, http.StatusOK)
+}
+
+// GetInvites returns the invites waiting for a player, newest first
+func (h *Handler) GetInvites(w http.ResponseWriter, r *http.Request) {
+	playerID := r.URL.Query().Get("player_id")
+	if playerID == "" {
+		respondWithError(w, "player_id parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	respondWithJSON(w, map[string]interface{}{
+		"invites": h.gameManager.Invites().Pending(playerID),
+	}, http.StatusOK)
+}
+
+// AcceptInvite joins the game an invite is for in one step, without the lobby.
+// Only invites from the host skip the game's password.
+func (h *Handler) AcceptInvite(w http.ResponseWriter, r *http.Request) {
+	var req InviteAnswerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+	if req.InviteID == "" || req.PlayerID == "" {
+		respondWithError(w, "invite_id and player_id are required", http.StatusBadRequest)
+		return
+	}
+
+	game, invite, err := h.gameManager.AcceptInvite(r.Context(), req.InviteID, req.PlayerID, req.PlayerName, req.Password)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, inviteStatus(err)))
+		return
+	}
+
+	h.broadcastRefresh(invite.GameCode, "player_joined")
+
+	respondWithJSON(w, JoinGameResponse{
+		Message:       "Successfully joined the game",
+		Game:          game.GetGameState(),
+		SessionSecret: game.SessionSecret(req.PlayerID),
+	}, http.StatusOK)
+}
+
+// DeclineInvite drops an invite and tells its sender
+func (h *Handler) DeclineInvite(w http.ResponseWriter, r *http.Request) {
+	var req InviteAnswerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	invite, err := h.gameManager.Invites().Decline(req.InviteID, req.PlayerID)
+	if err != nil {
+		respondWithErr(w, err, inviteStatus(err))
+		return
+	}
+	h.notifyInvite(invite.FromID, "invite_declined", invite)
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Invite declined",
+	}, http.StatusOK)
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+	"github.com/gorilla/websocket"
+)
+
+// lobbyQueueSize bounds game changes waiting to be turned into lobby events
+const lobbyQueueSize = 1024
+
+// LobbyEvent is a "game_created", "game_updated" or "game_removed" change to the
+// public game list. Lobby sockets get a "lobby" snapshot of every public game first.
+type LobbyEvent struct {
+	Type string            `json:"type"`
+	Code string            `json:"code"`
+	Game *models.LobbyGame `json:"game,omitempty"`
+}
+
+// VisibilityRequest represents the request to list or unlist a game in the lobby
+type VisibilityRequest struct {
+	Code   string `json:"code"`
+	HostID string `json:"host_id"`
+	Public bool   `json:"public"`
+}
+
+// Lobby streams changes to the public game list to lobby sockets. It is a hub
+// sink: every game event is a hint that the game's lobby entry may have changed.
+type Lobby struct {
+	gameManager *models.GameManager
+	hub         *Hub
+	changed     chan string
+	listed      map[string]models.LobbyGame // What lobby clients were last told
+	clients     map[*lobbyClient]bool
+	mu          sync.Mutex
+}
+
+// lobbyClient is one lobby socket
+type lobbyClient struct {
+	conn *websocket.Conn
+	send chan []byte
+}
+
+// NewLobby creates the lobby feed and starts its worker. Register it as a hub sink.
+func NewLobby(gm *models.GameManager, hub *Hub) *Lobby {
+	l := &Lobby{
+		gameManager: gm,
+		hub:         hub,
+		changed:     make(chan string, lobbyQueueSize),
+		listed:      make(map[string]models.LobbyGame),
+		clients:     make(map[*lobbyClient]bool),
+	}
+	for _, game := range gm.ListPublicGames() {
+		l.listed[game.Code] = game
+	}
+	go l.run()
+	return l
+}
+
+// Publish queues the event's game to have its lobby entry checked
+func (l *Lobby) Publish(event Event) {
+	select {
+	case l.changed <- event.GameCode:
+	default:
+		log.Printf("Lobby: queue full, dropped update for game %s", event.GameCode)
+	}
+}
+
+// run turns game changes into lobby events
+func (l *Lobby) run() {
+	for code := range l.changed {
+		l.update(code)
+	}
+}
+
+// update compares a game's lobby entry with what clients were last told and
+// broadcasts the difference
+func (l *Lobby) update(code string) {
+	var listing models.LobbyGame
+	listed := false
+	if game, err := l.gameManager.GetGame(context.Background(), code); err == nil {
+		listing, listed = game.LobbyListing()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	previous, wasListed := l.listed[code]
+	switch {
+	case listed && !wasListed:
+		l.listed[code] = listing
+		l.broadcastLocked(LobbyEvent{Type: "game_created", Code: code, Game: &listing})
+	case listed && listing != previous:
+		l.listed[code] = listing
+		l.broadcastLocked(LobbyEvent{Type: "game_updated", Code: code, Game: &listing})
+	case !listed && wasListed:
+		delete(l.listed, code)
+		l.broadcastLocked(LobbyEvent{Type: "game_removed", Code: code})
+	}
+}
+
+// broadcastLocked sends an event to every lobby socket, dropping any that can't
+// keep up (caller must hold lock)
+func (l *Lobby) broadcastLocked(event LobbyEvent) {
+	message, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Error marshaling lobby event: %v", err)
+		return
+	}
+	for client := range l.clients {
+		select {
+		case client.send <- message:
+		default:
+			close(client.send)
+			delete(l.clients, client)
+		}
+	}
+}
+
+// HandleWebSocket streams lobby events to a game browser, starting with a snapshot
+func (l *Lobby) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade error: %v", err)
+		return
+	}
+	client := &lobbyClient{conn: conn, send: make(chan []byte, 256)}
+
+	// Queue the snapshot while holding the lock so no event can slip in ahead of it
+	l.mu.Lock()
+	games := make([]models.LobbyGame, 0, len(l.listed))
+	for _, game := range l.listed {
+		games = append(games, game)
+	}
+	sort.Slice(games, func(i, j int) bool {
+		return games[i].CreatedAt.Before(games[j].CreatedAt.Time)
+	})
+	snapshot, _ := json.Marshal(map[string]interface{}{"type": "lobby", "games": games})
+	client.send <- snapshot
+	l.clients[client] = true
+	l.mu.Unlock()
+
+	go l.writePump(client)
+	go l.readPump(client)
+}
+
+// readPump only watches for the socket closing; lobby clients have nothing to say
+func (l *Lobby) readPump(c *lobbyClient) {
+	defer func() {
+		l.mu.Lock()
+		if l.clients[c] {
+			delete(l.clients, c)
+			close(c.send)
+		}
+		l.mu.Unlock()
+		c.conn.Close()
+	}()
+
+	pongWait := l.hub.config.PongWait
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+	for {
+		if _, _, err := c.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// writePump sends lobby events and keeps the socket alive with pings
+func (l *Lobby) writePump(c *lobbyClient) {
+	cfg := l.hub.config
+	ticker := time.NewTicker(cfg.pingPeriod())
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case message, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(cfg.WriteWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, l.hub.closeMessage())
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				return
+			}
+
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(cfg.WriteWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// GetLobby returns every public game waiting for players
+func (h *Handler) GetLobby(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, map[string]interface{}{
+		"games": h.gameManager.ListPublicGames(),
+	}, http.StatusOK)
+}
+
+// SetVisibility lists or unlists a game in the lobby (host only)
+func (h *Handler) SetVisibility(w http.ResponseWriter, r *http.Request) {
+	var req VisibilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	if err := game.SetPublic(req.HostID, req.Public); err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	h.broadcastRefresh(req.Code, "visibility_changed")
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Visibility updated",
+		"public":  req.Public,
+	}, http.StatusOK)
+}
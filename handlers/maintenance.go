@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// MaintenanceRequest represents the request to start or end a maintenance window
+type MaintenanceRequest struct {
+	Enabled         bool   `json:"enabled"`
+	Message         string `json:"message"`
+	DeadlineSeconds int    `json:"deadline_seconds"` // Countdown until games stop; 0 for none
+	PauseGames      bool   `json:"pause_games"`      // Pause games in progress at the deadline
+}
+
+// MaintenanceEvent tells every connected client about a maintenance window
+type MaintenanceEvent struct {
+	Type string `json:"type"` // Always "maintenance"
+	models.Maintenance
+}
+
+// maintenanceScheduler holds the timer that pauses games at the deadline
+type maintenanceScheduler struct {
+	timer *time.Timer
+	mu    sync.Mutex
+}
+
+// schedule replaces any pending deadline action
+func (s *maintenanceScheduler) schedule(after time.Duration, action func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.timer = time.AfterFunc(after, action)
+}
+
+// cancel stops any pending deadline action
+func (s *maintenanceScheduler) cancel() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+}
+
+// unavailableStatus maps maintenance and game limit errors to 503 so clients know
+// to retry later, and requests that ran past their deadline to 504
+func unavailableStatus(err error, fallback int) int {
+	if errors.Is(err, models.ErrMaintenance) || errors.Is(err, models.ErrTooManyGames) {
+		return http.StatusServiceUnavailable
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout
+	}
+	return fallback
+}
+
+// SetMaintenance starts or ends maintenance mode (admin only)
+func (h *Handler) SetMaintenance(w http.ResponseWriter, r *http.Request) {
+	var req MaintenanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+	if req.DeadlineSeconds < 0 {
+		respondWithError(w, "deadline_seconds must not be negative", http.StatusBadRequest)
+		return
+	}
+
+	if !req.Enabled {
+		h.maintenance.cancel()
+		resumed := h.gameManager.EndMaintenance()
+		for _, code := range resumed {
+			h.broadcastRefresh(code, "game_resumed")
+		}
+		status := h.gameManager.MaintenanceStatus()
+		h.broadcastMaintenance(status)
+		respondWithJSON(w, map[string]interface{}{
+			"maintenance": status,
+			"resumed":     resumed,
+		}, http.StatusOK)
+		return
+	}
+
+	var deadline time.Time
+	if req.DeadlineSeconds > 0 {
+		deadline = time.Now().Add(time.Duration(req.DeadlineSeconds) * time.Second)
+	}
+	status := h.gameManager.StartMaintenance(strings.TrimSpace(req.Message), deadline, req.PauseGames)
+
+	h.maintenance.cancel()
+	if req.PauseGames && !deadline.IsZero() {
+		h.maintenance.schedule(time.Until(deadline), h.pauseForMaintenance)
+	}
+	h.broadcastMaintenance(status)
+
+	respondWithJSON(w, map[string]interface{}{
+		"maintenance": status,
+	}, http.StatusOK)
+}
+
+// GetMaintenance returns the current maintenance window so clients can show a countdown
+func (h *Handler) GetMaintenance(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, h.gameManager.MaintenanceStatus(), http.StatusOK)
+}
+
+// pauseForMaintenance pauses every game in progress once the deadline passes
+func (h *Handler) pauseForMaintenance() {
+	paused := h.gameManager.PauseAllForMaintenance()
+	for _, code := range paused {
+		h.broadcastRefresh(code, "game_paused")
+	}
+	log.Printf("Maintenance: paused %d games", len(paused))
+}
+
+// broadcastMaintenance tells every connected client about the maintenance window
+func (h *Handler) broadcastMaintenance(status models.Maintenance) {
+	if h.hub != nil {
+		h.hub.BroadcastToAll(MaintenanceEvent{Type: "maintenance", Maintenance: status})
+	}
+}
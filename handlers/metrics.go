@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// Metrics settings
+const (
+	DefaultMetricsInterval = 10 * time.Second
+	MaxMetricsSamples      = 360 // One hour at the default interval
+)
+
+// MetricsSample is one point in a game's time series
+type MetricsSample struct {
+	Timestamp           models.Timestamp `json:"timestamp"`
+	State               models.GameState `json:"state"`
+	PlayersConnected    int              `json:"players_connected"`
+	SpectatorsConnected int              `json:"spectators_connected"`
+	SlowClients         int              `json:"slow_clients"` // Connections downgraded to refresh signals
+	Events              int              `json:"events"`
+	EventsPerSec        float64          `json:"events_per_sec"`
+	TurnsCompleted      int              `json:"turns_completed"`
+	AvgTurnMs           int64            `json:"avg_turn_ms"`
+	MaxTurnMs           int64            `json:"max_turn_ms"`
+	CurrentTurnMs       int64            `json:"current_turn_ms"` // How long the running turn has taken so far
+}
+
+// GameMetrics samples every game at a fixed interval and keeps a rolling
+// time series per game, so operators can see which table is lagging
+type GameMetrics struct {
+	gameManager *models.GameManager
+	hub         *Hub
+	interval    time.Duration
+	events      map[string]int // Events per game since the last sample
+	series      map[string][]MetricsSample
+	lastSample  time.Time
+	mu          sync.Mutex
+}
+
+// NewGameMetrics creates a recorder; register it as a hub sink and call Start
+func NewGameMetrics(gm *models.GameManager, hub *Hub, interval time.Duration) *GameMetrics {
+	if interval <= 0 {
+		interval = DefaultMetricsInterval
+	}
+	return &GameMetrics{
+		gameManager: gm,
+		hub:         hub,
+		interval:    interval,
+		events:      make(map[string]int),
+		series:      make(map[string][]MetricsSample),
+		lastSample:  time.Now(),
+	}
+}
+
+// Start samples games in the background
+func (m *GameMetrics) Start() {
+	go func() {
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			m.sample()
+		}
+	}()
+}
+
+// Publish counts an event towards its game's event rate
+func (m *GameMetrics) Publish(event Event) {
+	m.mu.Lock()
+	m.events[event.GameCode]++
+	m.mu.Unlock()
+}
+
+// Series returns the samples recorded for a game, oldest first
+func (m *GameMetrics) Series(code string) []MetricsSample {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]MetricsSample{}, m.series[code]...)
+}
+
+// Latest returns the most recent sample of every game
+func (m *GameMetrics) Latest() map[string]MetricsSample {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	latest := make(map[string]MetricsSample, len(m.series))
+	for code, samples := range m.series {
+		if len(samples) > 0 {
+			latest[code] = samples[len(samples)-1]
+		}
+	}
+	return latest
+}
+
+// RemoveGame drops a game's time series once it is cleaned up
+func (m *GameMetrics) RemoveGame(code string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.series, code)
+	delete(m.events, code)
+}
+
+// sample records one point for every game
+func (m *GameMetrics) sample() {
+	now := time.Now()
+	samples := make(map[string]MetricsSample)
+	for _, game := range m.gameManager.GetAllGames() {
+		s := MetricsSample{Timestamp: models.At(now)}
+		if m.hub != nil {
+			for _, id := range m.hub.ConnectedIDs(game.Code) {
+				if game.IsPlayer(id) {
+					s.PlayersConnected++
+				} else {
+					s.SpectatorsConnected++
+				}
+			}
+			s.SlowClients = m.hub.SlowClients(game.Code)
+		}
+
+		timings := game.ConsumeTurnTimings()
+		s.State = timings.State
+		s.CurrentTurnMs = timings.Current.Milliseconds()
+		s.TurnsCompleted = len(timings.Completed)
+		var total time.Duration
+		for _, d := range timings.Completed {
+			total += d
+			if d.Milliseconds() > s.MaxTurnMs {
+				s.MaxTurnMs = d.Milliseconds()
+			}
+		}
+		if s.TurnsCompleted > 0 {
+			s.AvgTurnMs = total.Milliseconds() / int64(s.TurnsCompleted)
+		}
+		samples[game.Code] = s
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elapsed := now.Sub(m.lastSample).Seconds()
+	m.lastSample = now
+	for code, s := range samples {
+		s.Events = m.events[code]
+		if elapsed > 0 {
+			s.EventsPerSec = float64(s.Events) / elapsed
+		}
+		series := append(m.series[code], s)
+		if len(series) > MaxMetricsSamples {
+			series = series[len(series)-MaxMetricsSamples:]
+		}
+		m.series[code] = series
+	}
+	m.events = make(map[string]int)
+}
+
+// SetGameMetrics sets the recorder served by the admin metrics route
+func (h *Handler) SetGameMetrics(m *GameMetrics) {
+	h.metrics = m
+}
+
+// GetGameMetrics returns one game's time series, or the latest sample of every
+// game (slowest running turn first) when no code is given (admin only)
+func (h *Handler) GetGameMetrics(w http.ResponseWriter, r *http.Request) {
+	if h.metrics == nil {
+		respondWithError(w, "Metrics are not enabled", http.StatusNotFound)
+		return
+	}
+
+	code := gameCodeParam(r)
+	if code != "" {
+		if _, err := h.gameManager.GetGame(r.Context(), code); err != nil {
+			respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+			return
+		}
+		respondWithJSON(w, map[string]interface{}{
+			"code":             code,
+			"interval_seconds": h.metrics.interval.Seconds(),
+			"samples":          h.metrics.Series(code),
+		}, http.StatusOK)
+		return
+	}
+
+	type gameSample struct {
+		Code string `json:"code"`
+		MetricsSample
+	}
+	var games []gameSample
+	for code, s := range h.metrics.Latest() {
+		games = append(games, gameSample{Code: code, MetricsSample: s})
+	}
+	sort.Slice(games, func(i, j int) bool {
+		return games[i].CurrentTurnMs > games[j].CurrentTurnMs
+	})
+
+	response := map[string]interface{}{
+		"interval_seconds": h.metrics.interval.Seconds(),
+		"games":            games,
+	}
+	if h.hub != nil {
+		response["slow_clients"] = h.hub.SlowClientStats()
+	}
+	respondWithJSON(w, response, http.StatusOK)
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// MuteRequest represents the request to silence a player or spectator in chat
+type MuteRequest struct {
+	Code     string `json:"code"`
+	HostID   string `json:"host_id"`
+	PlayerID string `json:"player_id"` // Player or spectator to mute
+	Minutes  int    `json:"minutes"`   // 0 unmutes
+}
+
+// DeleteChatRequest represents the request to remove a chat message
+type DeleteChatRequest struct {
+	Code      string `json:"code"`
+	HostID    string `json:"host_id"`
+	MessageID int    `json:"message_id"`
+}
+
+// ChatDeletedEvent tells clients to remove a chat message
+type ChatDeletedEvent struct {
+	Type      string `json:"type"` // Always "chat_deleted"
+	MessageID int    `json:"message_id"`
+	PlayerID  string `json:"player_id"` // Who sent it
+}
+
+// MuteChat handles the host muting or unmuting someone in chat
+func (h *Handler) MuteChat(w http.ResponseWriter, r *http.Request) {
+	var req MuteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	until, err := game.MuteChat(req.HostID, req.PlayerID, time.Duration(req.Minutes)*time.Minute)
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	message := "Player muted"
+	if until.IsZero() {
+		message = "Player unmuted"
+	}
+	h.broadcastRefresh(req.Code, "chat_muted")
+
+	respondWithJSON(w, map[string]interface{}{
+		"message":     message,
+		"muted_until": until,
+	}, http.StatusOK)
+}
+
+// DeleteChat handles the host removing a chat message
+func (h *Handler) DeleteChat(w http.ResponseWriter, r *http.Request) {
+	var req DeleteChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	msg, err := game.DeleteChatMessage(req.HostID, req.MessageID)
+	if err != nil {
+		status := http.StatusBadRequest
+		if err == models.ErrChatMessageNotFound {
+			status = http.StatusNotFound
+		}
+		respondWithErr(w, err, status)
+		return
+	}
+
+	if h.hub != nil {
+		event := ChatDeletedEvent{Type: "chat_deleted", MessageID: msg.ID, PlayerID: msg.PlayerID}
+		h.hub.publish(req.Code, event.Type, event)
+		h.hub.BroadcastEvent(req.Code, event)
+	}
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Chat message deleted",
+	}, http.StatusOK)
+}
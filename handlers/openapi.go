@@ -0,0 +1,356 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+	"github.com/go-chi/chi/v5"
+)
+
+// requestBodies maps each handler to the JSON body it decodes
+var requestBodies = map[string]interface{}{
+	"CreateGame":                 CreateGameRequest{},
+	"JoinGame":                   JoinGameRequest{},
+	"JoinAsSpectator":            SpectateRequest{},
+	"RestoreGame":                RestoreGameRequest{},
+	"Reconnect":                  ReconnectRequest{},
+	"TakeOverBot":                TakeOverBotRequest{},
+	"ApproveTakeover":            TakeoverDecisionRequest{},
+	"DeclineTakeover":            TakeoverDecisionRequest{},
+	"ReleaseBot":                 ReleaseBotRequest{},
+	"BotAction":                  BotActionRequest{},
+	"StartGame":                  StartGameRequest{},
+	"RollDice":                   RollDiceRequest{},
+	"AckRoll":                    RollAckRequest{},
+	"MovePiece":                  MovePieceRequest{},
+	"SkipTurn":                   SkipTurnRequest{},
+	"SetReady":                   SetReadyRequest{},
+	"SetColor":                   SetColorRequest{},
+	"KickPlayer":                 KickPlayerRequest{},
+	"TransferHost":               TransferHostRequest{},
+	"LeaveGame":                  LeaveGameRequest{},
+	"PauseGame":                  PauseGameRequest{},
+	"ResumeGame":                 ResumeGameRequest{},
+	"VotePause":                  PauseVoteRequest{},
+	"SendChat":                   ChatMessageRequest{},
+	"MarkChatRead":               ChatReadRequest{},
+	"SendEmote":                  EmoteRequest{},
+	"DeleteChat":                 DeleteChatRequest{},
+	"MuteChat":                   MuteRequest{},
+	"Rematch":                    RematchRequest{},
+	"RegisterGameWebhook":        GameWebhookRequest{},
+	"DeleteGameWebhook":          DeleteGameWebhookRequest{},
+	"AddBot":                     AddBotRequest{},
+	"FillBots":                   FillBotsRequest{},
+	"RemoveBot":                  RemoveBotRequest{},
+	"ClaimBot":                   ClaimBotRequest{},
+	"BotStandIn":                 BotStandInRequest{},
+	"SetBotChat":                 BotChatRequest{},
+	"FastForward":                FastForwardRequest{},
+	"SetVisibility":              VisibilityRequest{},
+	"SetPassword":                PasswordRequest{},
+	"SetPalette":                 PaletteRequest{},
+	"SetAutoRoll":                AutoRollRequest{},
+	"SetAutoMove":                AutoMoveRequest{},
+	"SetTurnTimeout":             TurnTimeoutRequest{},
+	"QueueMove":                  PreMoveRequest{},
+	"SetProfileTheme":            ThemeSelectionRequest{},
+	"UpdateProfile":              ProfileRequest{},
+	"RenamePlayer":               PlayerNameRequest{},
+	"AddFriend":                  FriendRequest{},
+	"RemoveFriend":               FriendRequest{},
+	"SendInvite":                 InviteRequest{},
+	"AcceptInvite":               InviteAnswerRequest{},
+	"DeclineInvite":              InviteAnswerRequest{},
+	"Announce":                   AnnounceRequest{},
+	"CancelAnnouncement":         CancelAnnouncementRequest{},
+	"SetMaintenance":             MaintenanceRequest{},
+	"Restart":                    RestartRequest{},
+	"IssueAPIKey":                IssueAPIKeyRequest{},
+	"RevokeAPIKey":               RevokeAPIKeyRequest{},
+	"AdminDeleteGame":            AdminGameRequest{},
+	"AdminRestoreGame":           AdminGameRequest{},
+	"AdminSkipTurn":              AdminGameRequest{},
+	"AdminEndGame":               AdminEndGameRequest{},
+	"AdminKickPlayer":            AdminKickRequest{},
+	"StartExperiment":            ExperimentRequest{},
+	"IntegrationCreateGames":     IntegrationCreateGamesRequest{},
+	"IntegrationRegisterWebhook": IntegrationWebhookRequest{},
+	"CreateFixtureGame":          models.Fixture{},
+}
+
+// websocketEvents lists the messages each socket sends, by type
+var websocketEvents = map[string][]struct {
+	Type    string
+	Payload interface{}
+}{
+	"/ws": {
+		{"refresh", RefreshEvent{}},
+		{"state", StateEvent{}},
+		{"state_delta", StateDeltaEvent{}},
+		{"commentary", CommentaryEvent{}},
+		{"valid_moves", MoveHintsEvent{}},
+		{"kicked", KickedEvent{}},
+		{"dice_rolling", DiceRollingEvent{}},
+		{"dice_revealed", DiceRevealedEvent{}},
+		{"countdown", CountdownEvent{}},
+		{"turn_warning", TurnWarningEvent{}},
+		{"host_changed", HostChangedEvent{}},
+		{"afk_removed", AFKRemovedEvent{}},
+		{"waiting_for_players", WaitingEvent{}},
+		{"bot_prompt", BotPromptEvent{}},
+		{"emote", EmoteEvent{}},
+		{"chat_deleted", ChatDeletedEvent{}},
+		{"announcement", Announcement{}},
+		{"maintenance", MaintenanceEvent{}},
+		{"server_restarting", RestartEvent{}},
+		{"server_shutdown", ShutdownEvent{}},
+		{"friend_online", FriendEvent{}},
+		{"friend_offline", FriendEvent{}},
+		{"friend_started_game", FriendEvent{}},
+		{"ack", WSCommandAck{}},
+		{"error", WSCommandError{}},
+	},
+	"/ws/lobby": {
+		{"game_created", LobbyEvent{}},
+		{"game_updated", LobbyEvent{}},
+		{"game_removed", LobbyEvent{}},
+	},
+	"/ws/replay": {
+		{"replay_start", replayMessage{}},
+		{"replay_event", replayMessage{}},
+		{"replay_end", replayMessage{}},
+	},
+}
+
+// pathParam matches a chi URL parameter such as {code}
+var pathParam = regexp.MustCompile(`\{(\w+)\}`)
+
+// OpenAPI serves an OpenAPI 3 document generated from the routes: every endpoint
+// with its path parameters and request body, the error response with every error
+// code, and under x-websocket-events the messages each socket sends
+func (h *Handler) OpenAPI(routes chi.Routes) http.HandlerFunc {
+	var once sync.Once
+	var doc map[string]interface{}
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Routes are only complete once the router is built, so wait for the first request
+		once.Do(func() { doc = buildOpenAPI(routes) })
+		respondWithJSON(w, doc, http.StatusOK)
+	}
+}
+
+// buildOpenAPI walks the routes and describes them
+func buildOpenAPI(routes chi.Routes) map[string]interface{} {
+	schemas := map[string]interface{}{}
+	errorSchema := schemaOf(reflect.TypeOf(ErrorResponse{}), schemas)
+	schemas["ErrorResponse"].(map[string]interface{})["properties"].(map[string]interface{})["code"] = map[string]interface{}{
+		"type": "string",
+		"enum": ErrorCodes(),
+	}
+
+	paths := map[string]map[string]interface{}{}
+	chi.Walk(routes, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		if strings.Contains(route, "*") || method == http.MethodHead {
+			return nil // The web client
+		}
+		if strings.HasPrefix(route, "/api/") && !strings.HasPrefix(route, APIPrefix+"/") {
+			return nil // Unversioned aliases of the current API
+		}
+		route = strings.TrimSuffix(route, "/")
+		name, summary := handlerName(handler), ""
+		if name == "" {
+			// Routes with inline handlers are described by their path
+			name, summary = method+" "+route, method+" "+route
+		} else {
+			summary = sentence(name)
+		}
+
+		op := map[string]interface{}{
+			"operationId": name,
+			"summary":     summary,
+			"tags":        []string{tagOf(route)},
+			"responses": map[string]interface{}{
+				"200":     map[string]interface{}{"description": "Success", "content": jsonContent(map[string]interface{}{"type": "object"})},
+				"default": map[string]interface{}{"description": "Error", "content": jsonContent(errorSchema)},
+			},
+		}
+		var params []interface{}
+		for _, match := range pathParam.FindAllStringSubmatch(route, -1) {
+			params = append(params, map[string]interface{}{"name": match[1], "in": "path", "required": true, "schema": map[string]string{"type": "string"}})
+		}
+		if method == http.MethodGet && strings.HasPrefix(route, APIPrefix+"/game/") {
+			params = append(params, map[string]interface{}{"name": "code", "in": "query", "schema": map[string]string{"type": "string"}})
+		}
+		if params != nil {
+			op["parameters"] = params
+		}
+		if body, ok := requestBodies[name]; ok {
+			op["requestBody"] = map[string]interface{}{"required": true, "content": jsonContent(schemaOf(reflect.TypeOf(body), schemas))}
+		}
+
+		if paths[route] == nil {
+			paths[route] = map[string]interface{}{}
+		}
+		paths[route][strings.ToLower(method)] = op
+		return nil
+	})
+
+	events := map[string]interface{}{}
+	for socket, messages := range websocketEvents {
+		byType := map[string]interface{}{}
+		for _, m := range messages {
+			byType[m.Type] = schemaOf(reflect.TypeOf(m.Payload), schemas)
+		}
+		events[socket] = byType
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "Ludo Nadwa Server",
+			"version": Version,
+			"description": "Every error response carries a machine-readable code. " +
+				"WebSocket messages by socket and type are under x-websocket-events; commands clients send over /ws are under x-websocket-commands.",
+		},
+		"paths":                paths,
+		"components":           map[string]interface{}{"schemas": schemas},
+		"x-websocket-events":   events,
+		"x-websocket-commands": schemaOf(reflect.TypeOf(WSCommand{}), schemas),
+	}
+}
+
+// handlerName returns the name of the Handler method behind a route, looking
+// through any middleware chain, or of the method that built it; empty for a
+// function literal
+func handlerName(handler http.Handler) string {
+	for {
+		chain, ok := handler.(*chi.ChainHandler)
+		if !ok {
+			break
+		}
+		handler = chain.Endpoint
+	}
+	name := runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name()
+	_, method, ok := strings.Cut(name, "(*Handler).")
+	if !ok {
+		return ""
+	}
+	method, _, _ = strings.Cut(method, ".") // Closures are named like OpenAPI.func1
+	return strings.TrimSuffix(method, "-fm")
+}
+
+// sentence turns a handler name such as SendChat into "Send chat"
+func sentence(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) && !unicode.IsUpper(rune(name[i-1])) {
+			b.WriteRune(' ')
+		}
+		b.WriteRune(r)
+	}
+	s := b.String()
+	return s[:1] + strings.ToLower(s[1:])
+}
+
+// tagOf groups a route by its first segment after the API prefix, e.g.
+// /api/v1/game/roll by "game"
+func tagOf(route string) string {
+	return strings.Split(strings.Trim(strings.TrimPrefix(route, APIPrefix), "/"), "/")[0]
+}
+
+// jsonContent wraps a schema as an application/json media type
+func jsonContent(schema interface{}) map[string]interface{} {
+	return map[string]interface{}{"application/json": map[string]interface{}{"schema": schema}}
+}
+
+var (
+	timestampType  = reflect.TypeOf(models.Timestamp{})
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaOf describes a Go type as a JSON schema as encoding/json would write it.
+// Named structs are added to schemas once and referenced.
+func schemaOf(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	switch t {
+	case timestampType, timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case rawMessageType:
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := schemaOf(t.Elem(), schemas)
+		if _, isRef := schema["$ref"]; isRef {
+			return map[string]interface{}{"allOf": []interface{}{schema}, "nullable": true}
+		}
+		schema["nullable"] = true
+		return schema
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": schemaOf(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaOf(t.Elem(), schemas)}
+	case reflect.Struct:
+		name := t.Name()
+		if name == "" {
+			return structSchema(t, schemas)
+		}
+		ref := map[string]interface{}{"$ref": "#/components/schemas/" + name}
+		if _, seen := schemas[name]; !seen {
+			schemas[name] = map[string]interface{}{} // Placeholder so recursive types terminate
+			schemas[name] = structSchema(t, schemas)
+		}
+		return ref
+	}
+	return map[string]interface{}{} // interface{}: anything
+}
+
+// structSchema lists a struct's JSON fields, flattening embedded structs
+func structSchema(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	props := map[string]interface{}{}
+	var fields func(t reflect.Type)
+	fields = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, _, _ := strings.Cut(tag, ",")
+			if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+				fields(f.Type)
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			props[name] = schemaOf(f.Type, schemas)
+		}
+	}
+	fields(t)
+	return map[string]interface{}{"type": "object", "properties": props}
+}
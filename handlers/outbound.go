@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrInvalidOutboundURL = errors.New("url must be an absolute http or https URL")
+	ErrBlockedDestination = errors.New("url points to a host the server may not send requests to")
+)
+
+// outboundHosts are the hosts URLs users register may point at, e.g.
+// hooks.example.com or *.example.com; empty allows any public host
+var outboundHosts []string
+
+// sharedAddressSpace is carrier-grade NAT (RFC 6598), private like RFC 1918 space
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// SetOutboundHosts limits webhooks and bot callbacks to the given hosts. Call
+// before the server starts.
+func SetOutboundHosts(hosts []string) {
+	outboundHosts = nil
+	for _, host := range hosts {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			outboundHosts = append(outboundHosts, host)
+		}
+	}
+}
+
+// CheckOutboundURL validates a URL a user wants the server to send requests to:
+// it must be http or https, on an allowed host, and not a private address.
+// Host names are resolved again at each delivery, since they may change.
+func CheckOutboundURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidOutboundURL
+	}
+	if !outboundHostAllowed(u.Hostname()) {
+		return ErrBlockedDestination
+	}
+	if addr, err := netip.ParseAddr(u.Hostname()); err == nil && blockedAddr(addr) {
+		return ErrBlockedDestination
+	}
+	return nil
+}
+
+// outboundHostAllowed reports whether a host is on the allowlist, if there is one
+func outboundHostAllowed(host string) bool {
+	if len(outboundHosts) == 0 {
+		return true
+	}
+	host = strings.ToLower(host)
+	for _, allowed := range outboundHosts {
+		if suffix, wildcard := strings.CutPrefix(allowed, "*"); wildcard && strings.HasSuffix(host, suffix) {
+			return true
+		}
+		if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// blockedAddr reports whether an address is loopback, link-local, private or
+// otherwise not on the public internet
+func blockedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() || sharedAddressSpace.Contains(addr)
+}
+
+// newOutboundClient returns a client for URLs users registered. It checks the
+// address every connection actually dials, so a host name can't be pointed at
+// the server's own network after it was registered, and the host of every redirect.
+func newOutboundClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil || blockedAddr(addr) {
+				return fmt.Errorf("%w: %s", ErrBlockedDestination, host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // A proxy would be dialed instead of the destination
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return CheckOutboundURL(req.URL.String())
+		},
+	}
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// PasswordRequest represents the request to set or remove a game's join password
+type PasswordRequest struct {
+	Code     string `json:"code"`
+	HostID   string `json:"host_id"`
+	Password string `json:"password"` // Empty removes the password
+}
+
+// SetPassword sets or removes the password needed to join a game (host only)
+func (h *Handler) SetPassword(w http.ResponseWriter, r *http.Request) {
+	var req PasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	if err := game.SetPassword(req.HostID, req.Password); err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	h.broadcastRefresh(req.Code, "password_changed")
+
+	respondWithJSON(w, map[string]interface{}{
+		"message":      "Password updated",
+		"has_password": req.Password != "",
+	}, http.StatusOK)
+}
+
+// passwordStatus maps a wrong game password to 403 and other join errors to 400
+func passwordStatus(err error) int {
+	if errors.Is(err, models.ErrWrongPassword) {
+		return http.StatusForbidden
+	}
+	return http.StatusBadRequest
+}
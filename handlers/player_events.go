@@ -0,0 +1,26 @@
+package handlers
+
+import "github.com/aminearbi/ludo-nadwa-server/models"
+
+// MoveHintsEvent tells the player who just rolled which pieces they can move and
+// where each would land. Only that player's connections get it.
+type MoveHintsEvent struct {
+	Type       string               `json:"type"` // Always "valid_moves"
+	ValidMoves []int                `json:"valid_moves"`
+	Moves      []models.MovePreview `json:"moves"`
+}
+
+// KickedEvent tells a player the host has removed them from the game
+type KickedEvent struct {
+	Type     string `json:"type"` // Always "kicked"
+	GameCode string `json:"game_code"`
+}
+
+// sendMoveHints privately tells a player which pieces their roll lets them move
+func (h *Hub) sendMoveHints(game *models.Game, playerID string) {
+	moves := game.GetValidMoves(playerID)
+	if moves == nil {
+		moves = []int{}
+	}
+	h.SendToPlayer(game.Code, playerID, MoveHintsEvent{Type: "valid_moves", ValidMoves: moves, Moves: game.GetValidMoveDetails(playerID)})
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// PlayerNameRequest represents the request to change a player's display name
+type PlayerNameRequest struct {
+	Code     string `json:"code"` // A game the player is in; its session secret signs the request
+	PlayerID string `json:"player_id"`
+	Name     string `json:"name"`
+}
+
+// RenamePlayer changes a player's name in every active game they are in and in their profile
+func (h *Handler) RenamePlayer(w http.ResponseWriter, r *http.Request) {
+	var req PlayerNameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	codes, err := h.gameManager.RenamePlayer(r.Context(), req.PlayerID, req.Name)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	for _, code := range codes {
+		h.broadcastRefresh(code, "player_renamed")
+	}
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Name updated",
+		"name":    h.gameManager.Profiles().Get(req.PlayerID).DisplayName,
+		"games":   codes,
+	}, http.StatusOK)
+}
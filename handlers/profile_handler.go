@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// ProfileRequest represents the request to update a player's profile. Fields
+// left out are unchanged; an empty string clears one.
+type ProfileRequest struct {
+	Code        string              `json:"code"` // A game the player is in; its session secret signs the request
+	PlayerID    string              `json:"player_id"`
+	DisplayName *string             `json:"display_name,omitempty"`
+	Avatar      *string             `json:"avatar,omitempty"` // An emoji or https image URL
+	Color       *models.PlayerColor `json:"color,omitempty"`  // Preferred seat color
+	Locale      *string             `json:"locale,omitempty"`
+	BoardTheme  string              `json:"board_theme,omitempty"`
+	PieceSkin   string              `json:"piece_skin,omitempty"`
+}
+
+// GetProfile returns a player's profile, with defaults if none is saved
+func (h *Handler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	playerID := r.URL.Query().Get("player_id")
+	if playerID == "" {
+		respondWithError(w, "player_id is required", http.StatusBadRequest)
+		return
+	}
+	respondWithJSON(w, h.gameManager.Profiles().Get(playerID), http.StatusOK)
+}
+
+// UpdateProfile saves a player's display name, avatar, preferred color, locale
+// and theme, applied to games they create or join from now on. In strict signing
+// mode the player signs it as an action in one of their games.
+func (h *Handler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	var req ProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	profiles := h.gameManager.Profiles()
+	profile, err := profiles.Update(req.PlayerID, models.ProfileUpdate{
+		DisplayName: req.DisplayName,
+		Avatar:      req.Avatar,
+		Color:       req.Color,
+		Locale:      req.Locale,
+	})
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+	if req.BoardTheme != "" || req.PieceSkin != "" {
+		if profile, err = profiles.SetTheme(req.PlayerID, req.BoardTheme, req.PieceSkin); err != nil {
+			respondWithErr(w, err, http.StatusBadRequest)
+			return
+		}
+	}
+	respondWithJSON(w, profile, http.StatusOK)
+}
@@ -0,0 +1,373 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// Role is an admin access level. Each role includes the ones below it.
+type Role string
+
+const (
+	RoleViewer    Role = "viewer"    // Read metrics, game lists and announcements
+	RoleModerator Role = "moderator" // Also read any chat, post announcements and kick players
+	RoleOperator  Role = "operator"  // Also delete and settle games, maintenance, restarts and API keys
+)
+
+var ErrUnknownRole = errors.New("unknown role")
+
+// roleRank orders roles from least to most privileged
+var roleRank = map[Role]int{
+	RoleViewer:    1,
+	RoleModerator: 2,
+	RoleOperator:  3,
+}
+
+// valid reports whether the role is one of the known roles
+func (r Role) valid() bool {
+	return roleRank[r] > 0
+}
+
+// allows reports whether the role grants at least the required one
+func (r Role) allows(required Role) bool {
+	return r.valid() && roleRank[r] >= roleRank[required]
+}
+
+// ParseIdentityRoles parses "alice@example.com=operator,bob@example.com=moderator"
+func ParseIdentityRoles(spec string) (map[string]Role, error) {
+	roles := make(map[string]Role)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		identity, role, ok := strings.Cut(entry, "=")
+		identity = strings.ToLower(strings.TrimSpace(identity))
+		if !ok || identity == "" || !Role(strings.TrimSpace(role)).valid() {
+			return nil, fmt.Errorf("%w in %q (want identity=viewer|moderator|operator)", ErrUnknownRole, entry)
+		}
+		roles[identity] = Role(strings.TrimSpace(role))
+	}
+	return roles, nil
+}
+
+// AdminGameRequest represents the request to delete, restore or skip a turn in a game as an admin
+type AdminGameRequest struct {
+	Code string `json:"code"`
+}
+
+// AdminEndGameRequest represents the request to end a game with a declared winner
+type AdminEndGameRequest struct {
+	Code     string `json:"code"`
+	WinnerID string `json:"winner_id"`
+}
+
+// AdminKickRequest represents the request to remove a player from a game as an admin
+type AdminKickRequest struct {
+	Code        string `json:"code"`
+	PlayerID    string `json:"player_id"`
+	PieceAction string `json:"piece_action,omitempty"` // Mid-game: remove, home, freeze or bot; default the game's departure policy
+}
+
+// SetIdentityRoles grants admin roles to identities asserted by an OAuth proxy in
+// the given header (e.g. X-Forwarded-Email). Only use it behind a proxy that
+// strips the header from client requests.
+func (h *Handler) SetIdentityRoles(header string, roles map[string]Role) {
+	h.identityHeader = header
+	h.identityRoles = roles
+}
+
+// adminEnabled reports whether any way of authenticating as an admin is configured
+func (h *Handler) adminEnabled() bool {
+	return h.adminToken != "" ||
+		(h.identityHeader != "" && len(h.identityRoles) > 0) ||
+		h.apiKeys.HasRoles()
+}
+
+// adminRole resolves the caller's role from the admin token, an API key with a
+// role, or an OAuth identity header. Returns "" if the caller has none.
+func (h *Handler) adminRole(r *http.Request) Role {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if h.adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) == 1 {
+		return RoleOperator
+	}
+
+	if key, err := h.apiKeys.Lookup(apiKeyFromRequest(r)); err == nil && key.Role != "" {
+		return key.Role
+	}
+
+	if h.identityHeader != "" {
+		identity := strings.ToLower(strings.TrimSpace(r.Header.Get(h.identityHeader)))
+		if role, ok := h.identityRoles[identity]; ok && identity != "" {
+			return role
+		}
+	}
+	return ""
+}
+
+// RequireRole returns middleware rejecting callers without at least the given admin
+// role. Admin routes are disabled entirely when no admin credentials are configured.
+func (h *Handler) RequireRole(role Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !h.adminEnabled() {
+				respondWithError(w, "Admin API is disabled", http.StatusForbidden)
+				return
+			}
+
+			granted := h.adminRole(r)
+			if granted == "" {
+				respondWithError(w, "Invalid admin credentials", http.StatusUnauthorized)
+				return
+			}
+			if !granted.allows(role) {
+				respondWithError(w, fmt.Sprintf("Requires the %s role", role), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// AdminWhoAmI returns the caller's admin role (viewer)
+func (h *Handler) AdminWhoAmI(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, map[string]interface{}{
+		"role": h.adminRole(r),
+	}, http.StatusOK)
+}
+
+// AdminListGames returns a summary of every game (viewer), optionally only those in a
+// given state, with a given player, or idle for at least idle_minutes
+func (h *Handler) AdminListGames(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	wantState := models.GameState(query.Get("state"))
+	switch wantState {
+	case "", models.Waiting, models.Playing, models.Paused, models.Ended:
+	default:
+		respondWithError(w, "state must be waiting, playing, paused or ended", http.StatusBadRequest)
+		return
+	}
+	var idleFor time.Duration
+	if s := query.Get("idle_minutes"); s != "" {
+		minutes, err := strconv.Atoi(s)
+		if err != nil || minutes < 0 {
+			respondWithError(w, "idle_minutes must be a non-negative number", http.StatusBadRequest)
+			return
+		}
+		idleFor = time.Duration(minutes) * time.Minute
+	}
+	playerID := query.Get("player_id")
+
+	games := h.gameManager.GetAllGames()
+	summaries := make([]map[string]interface{}, 0, len(games))
+	for _, game := range games {
+		state := game.GetGameState()
+		players, _ := state["players"].(map[string]*models.Player)
+		lastActivity, _ := state["last_activity"].(models.Timestamp)
+		if wantState != "" && state["state"] != wantState {
+			continue
+		}
+		if _, seated := players[playerID]; playerID != "" && !seated {
+			continue
+		}
+		if idleFor > 0 && time.Since(lastActivity.Time) < idleFor {
+			continue
+		}
+		summaries = append(summaries, map[string]interface{}{
+			"code":          state["code"],
+			"state":         state["state"],
+			"host_id":       state["host_id"],
+			"players":       len(players),
+			"max_players":   state["max_players"],
+			"last_activity": state["last_activity"],
+		})
+	}
+
+	respondWithJSON(w, map[string]interface{}{
+		"games": summaries,
+	}, http.StatusOK)
+}
+
+// AdminGetChat returns the full chat history of any game (moderator)
+func (h *Handler) AdminGetChat(w http.ResponseWriter, r *http.Request) {
+	game, err := h.gameManager.GetGame(r.Context(), gameCodeParam(r))
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	respondWithJSON(w, map[string]interface{}{
+		"chat_messages": game.GetRecentChatFor(0, ""),
+	}, http.StatusOK)
+}
+
+// AdminDeleteGame removes a game and disconnects its clients (operator).
+// The game can be brought back with AdminRestoreGame until it is purged.
+func (h *Handler) AdminDeleteGame(w http.ResponseWriter, r *http.Request) {
+	var req AdminGameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.gameManager.AdminDeleteGame(r.Context(), req.Code); err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Game deleted",
+		"code":    req.Code,
+	}, http.StatusOK)
+}
+
+// AdminRestoreGame brings back a deleted game (operator)
+func (h *Handler) AdminRestoreGame(w http.ResponseWriter, r *http.Request) {
+	var req AdminGameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.AdminRestoreGame(r.Context(), req.Code)
+	if err != nil {
+		status := http.StatusNotFound
+		if errors.Is(err, models.ErrGameNotDeleted) {
+			status = http.StatusBadRequest
+		}
+		respondWithErr(w, err, unavailableStatus(err, status))
+		return
+	}
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Game restored",
+		"state":   game.GetGameState(),
+	}, http.StatusOK)
+}
+
+// AdminGetGame dumps everything about a game (viewer): its full state, the engine
+// history it can be replayed from, and how many clients are connected
+func (h *Handler) AdminGetGame(w http.ResponseWriter, r *http.Request) {
+	code := gameCodeParam(r)
+	game, err := h.gameManager.GetGame(r.Context(), code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	response := map[string]interface{}{
+		"state":         game.GetGameState(),
+		"history_start": nil,
+		"history":       []models.HistoryEvent{},
+	}
+	if start, events, err := game.History(); err == nil {
+		response["history_start"] = start
+		response["history"] = events
+	}
+	if h.hub != nil {
+		response["connections"] = h.hub.ConnectionCount(code)
+	}
+	respondWithJSON(w, response, http.StatusOK)
+}
+
+// AdminSkipTurn passes the current player's turn in a stuck game (operator)
+func (h *Handler) AdminSkipTurn(w http.ResponseWriter, r *http.Request) {
+	var req AdminGameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	skipped, err := game.AdminSkipTurn()
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	h.broadcastRefresh(req.Code, "turn_skipped")
+
+	respondWithJSON(w, map[string]interface{}{
+		"message":        "Turn skipped",
+		"skipped_player": skipped,
+		"game":           game.GetGameState(),
+	}, http.StatusOK)
+}
+
+// AdminEndGame ends a game in progress with a declared winner (operator)
+func (h *Handler) AdminEndGame(w http.ResponseWriter, r *http.Request) {
+	var req AdminEndGameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	if err := game.AdminEndGame(req.WinnerID); err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	h.broadcastRefresh(req.Code, "game_ended")
+	h.broadcastCommentary(game)
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Game ended",
+		"game":    game.GetGameState(),
+	}, http.StatusOK)
+}
+
+// AdminKickPlayer removes any player from a game, the host included (moderator)
+func (h *Handler) AdminKickPlayer(w http.ResponseWriter, r *http.Request) {
+	var req AdminKickRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	if err := game.AdminKickPlayer(req.PlayerID, models.DepartureAction(req.PieceAction)); err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	// Tell the kicked player first, while their connection is still about
+	if h.hub != nil {
+		h.hub.SendToPlayer(req.Code, req.PlayerID, KickedEvent{Type: "kicked", GameCode: req.Code})
+	}
+
+	h.broadcastRefresh(req.Code, "player_kicked")
+	h.broadcastHostChanges(game)
+	if game.HasEnded() {
+		h.broadcastRefresh(req.Code, "game_ended")
+		h.broadcastCommentary(game)
+	}
+
+	respondWithJSON(w, map[string]interface{}{
+		"message": "Player kicked successfully",
+		"game":    game.GetGameState(),
+	}, http.StatusOK)
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// DefaultEventBuffer is how many recent events the hub keeps per game for reconnecting clients
+const DefaultEventBuffer = 256
+
+// GamePresenceHook is told when a player's first connection to a game opens or
+// their last one closes. It runs on the hub's loop and must not block.
+type GamePresenceHook func(gameCode, playerID string, connected bool)
+
+// eventLog holds the most recent events broadcast to a whole game, oldest first
+type eventLog struct {
+	seq    uint64 // Sequence number of the last event
+	events []json.RawMessage
+}
+
+// ReconnectRequest represents the request to resume a game after a dropped connection
+type ReconnectRequest struct {
+	Code     string `json:"code"`
+	PlayerID string `json:"player_id"`
+	Since    uint64 `json:"since"` // seq of the last event the client received
+}
+
+// OnGamePresence registers the game presence hook. Call before the server starts.
+func (h *Hub) OnGamePresence(hook GamePresenceHook) {
+	h.onGamePresence = hook
+}
+
+// TrackDisconnects returns a game presence hook that records when players
+// disconnect, so their turns can time out once the reconnect grace runs out
+func TrackDisconnects(gm *models.GameManager) GamePresenceHook {
+	return func(gameCode, playerID string, connected bool) {
+		if game, err := gm.GetGame(context.Background(), gameCode); err == nil {
+			game.SetConnected(playerID, connected)
+		}
+	}
+}
+
+// recordLocked numbers an event broadcast to a whole game and keeps it for
+// clients that reconnect. Returns the event with its "seq" (caller must hold lock).
+func (h *Hub) recordLocked(gameCode string, message []byte) []byte {
+	history := h.eventLogs[gameCode]
+	if history == nil {
+		history = &eventLog{}
+		h.eventLogs[gameCode] = history
+	}
+	history.seq++
+	stamped := withSeq(message, history.seq)
+
+	history.events = append(history.events, stamped)
+	if size := h.config.EventBuffer; len(history.events) > size {
+		history.events = append(history.events[:0:0], history.events[len(history.events)-size:]...)
+	}
+	return stamped
+}
+
+// withSeq adds a "seq" field to a JSON object
+func withSeq(message []byte, seq uint64) []byte {
+	if len(message) < 2 || message[0] != '{' {
+		return message
+	}
+	stamped := append([]byte(`{"seq":`), strconv.FormatUint(seq, 10)...)
+	if message[1] != '}' {
+		stamped = append(stamped, ',')
+	}
+	return append(stamped, message[1:]...)
+}
+
+// EventsSince returns the buffered events of a game after a sequence number and
+// the latest one. complete is false if some were already dropped from the buffer
+// (or the number is from before a restart), in which case the client should rely
+// on the full state.
+func (h *Hub) EventsSince(gameCode string, since uint64) (events []json.RawMessage, latest uint64, complete bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	history := h.eventLogs[gameCode]
+	if history == nil {
+		return []json.RawMessage{}, 0, since == 0
+	}
+	if since > history.seq {
+		return []json.RawMessage{}, history.seq, false
+	}
+
+	missed := int(history.seq - since)
+	complete = missed <= len(history.events)
+	if missed > len(history.events) {
+		missed = len(history.events)
+	}
+	events = append([]json.RawMessage{}, history.events[len(history.events)-missed:]...)
+	return events, history.seq, complete
+}
+
+// Reconnect returns the full game state plus the events a player missed since
+// the last one they received
+func (h *Handler) Reconnect(w http.ResponseWriter, r *http.Request) {
+	var req ReconnectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+	if !game.IsParticipant(req.PlayerID) {
+		respondWithErr(w, models.ErrPlayerNotFound, http.StatusForbidden)
+		return
+	}
+
+	// Read the events first, so any change they miss is in the state
+	events, seq, complete := h.hub.EventsSince(req.Code, req.Since)
+	respondWithJSON(w, map[string]interface{}{
+		"game":     game.GetGameState(),
+		"events":   events,
+		"seq":      seq,
+		"complete": complete,
+	}, http.StatusOK)
+}
+
+// GetGameEvents returns the buffered events of a game after ?since=, so a client
+// that notices a gap in the seq numbers can fill it without reloading the state
+func (h *Handler) GetGameEvents(w http.ResponseWriter, r *http.Request) {
+	code := gameCodeParam(r)
+	if code == "" {
+		respondWithErr(w, errCodeRequired, http.StatusBadRequest)
+		return
+	}
+	var since uint64
+	if raw := r.URL.Query().Get("since"); raw != "" {
+		var err error
+		if since, err = strconv.ParseUint(raw, 10, 64); err != nil {
+			respondWithError(w, "since must be a sequence number", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if _, err := h.gameManager.GetGame(r.Context(), code); err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	events, seq, complete := h.hub.EventsSince(code, since)
+	respondWithJSON(w, map[string]interface{}{
+		"events":   events,
+		"seq":      seq,
+		"complete": complete,
+	}, http.StatusOK)
+}
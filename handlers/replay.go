@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+)
+
+// replayCacheControl lets shared replays be cached; a finished game never changes
+const replayCacheControl = "public, max-age=86400"
+
+// GetReplay returns the public replay of a finished game by its replay ID: the
+// players, board geometry and every move with where its pieces landed
+func (h *Handler) GetReplay(w http.ResponseWriter, r *http.Request) {
+	replay, err := h.gameManager.Archive().Replay(chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithErr(w, err, http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Cache-Control", replayCacheControl)
+	respondWithJSON(w, replay, http.StatusOK)
+}
+
+// WantsHTML reports whether a request comes from a browser navigating to a page
+// rather than a client asking for data
+func WantsHTML(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "json" {
+		return false
+	}
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
+// Live replay stream limits
+const (
+	MaxReplaySpeed = 16.0
+	MaxReplayGap   = 10 * time.Second // Longest wait between events, at normal speed
+)
+
+// GetGameReplay returns a game's board reconstructed after the first ?move=
+// events of its history, or after the latest event without one
+func (h *Handler) GetGameReplay(w http.ResponseWriter, r *http.Request) {
+	code := gameCodeParam(r)
+	if code == "" {
+		respondWithErr(w, errCodeRequired, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	start, events, err := game.History()
+	if err != nil {
+		respondWithErr(w, err, http.StatusConflict)
+		return
+	}
+	move := len(events)
+	if raw := r.URL.Query().Get("move"); raw != "" {
+		if move, err = strconv.Atoi(raw); err != nil {
+			respondWithError(w, "move must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+
+	frame, err := models.ReplayHistory(start, events, move)
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+	respondWithJSON(w, frame, http.StatusOK)
+}
+
+// replayMessage is one message of a live replay stream
+type replayMessage struct {
+	Type string `json:"type"` // replay_start, replay_event or replay_end
+	*models.ReplayFrame
+}
+
+// HandleReplay streams a game's history over a WebSocket with its original
+// timing: the position at ?from= (default 0), then each later event as it
+// happened, ?speed= times faster (default 1). Waits are capped at MaxReplayGap
+// so pauses and timed-out turns don't stall the stream.
+func (wsh *WebSocketHandler) HandleReplay(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	game, err := wsh.gameManager.GetGame(r.Context(), query.Get("code"))
+	if err != nil {
+		http.Error(w, "Game not found", http.StatusNotFound)
+		return
+	}
+	start, events, err := game.History()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	speed := 1.0
+	if raw := query.Get("speed"); raw != "" {
+		if speed, err = strconv.ParseFloat(raw, 64); err != nil || speed <= 0 || speed > MaxReplaySpeed {
+			http.Error(w, fmt.Sprintf("speed must be above 0 and at most %g", MaxReplaySpeed), http.StatusBadRequest)
+			return
+		}
+	}
+	from := 0
+	if raw := query.Get("from"); raw != "" {
+		if from, err = strconv.Atoi(raw); err != nil {
+			http.Error(w, "from must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+	frame, err := models.ReplayHistory(start, events, from)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade error: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	// The viewer has nothing to say; reading only notices when it goes away
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	send := func(msg replayMessage) bool {
+		conn.SetWriteDeadline(time.Now().Add(wsh.hub.config.WriteWait))
+		return conn.WriteJSON(msg) == nil
+	}
+	if !send(replayMessage{Type: "replay_start", ReplayFrame: frame}) {
+		return
+	}
+
+	state, move := frame.State, from
+	for i := from; i < len(events); i++ {
+		if i > 0 {
+			gap := min(max(events[i].At.Sub(events[i-1].At.Time), 0), MaxReplayGap)
+			select {
+			case <-closed:
+				return
+			case <-time.After(time.Duration(float64(gap) / speed)):
+			}
+		}
+
+		next, err := models.ApplyEvent(state, events[i].EngineEvent)
+		if err != nil && err != models.ErrThreeSixes {
+			log.Printf("Replay of %s stopped at event %d: %v", game.Code, i, err)
+			break
+		}
+		state, move = next, i+1
+		event := events[i]
+		if !send(replayMessage{Type: "replay_event", ReplayFrame: &models.ReplayFrame{Move: move, Total: len(events), Event: &event, State: state}}) {
+			return
+		}
+	}
+
+	send(replayMessage{Type: "replay_end", ReplayFrame: &models.ReplayFrame{Move: move, Total: len(events), State: state}})
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+}
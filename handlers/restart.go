@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Restart defaults
+const (
+	DefaultRestartMaxWait = 10 * time.Minute // Longest a restart waits for games to finish
+	restartPollInterval   = 2 * time.Second
+)
+
+// RestartRequest represents the request to restart the server once it is idle
+type RestartRequest struct {
+	Cancel         bool   `json:"cancel"`           // Abort a pending restart
+	Immediate      bool   `json:"immediate"`        // Skip waiting for games to finish
+	MaxWaitSeconds int    `json:"max_wait_seconds"` // Restart anyway after this long
+	Message        string `json:"message"`          // Shown to players while draining
+}
+
+// RestartEvent tells clients the server is going away and they should reconnect
+type RestartEvent struct {
+	Type             string `json:"type"` // Always "server_restarting"
+	ReconnectAfterMs int    `json:"reconnect_after_ms"`
+}
+
+// restartCoordinator tracks a pending restart so only one runs at a time
+type restartCoordinator struct {
+	hook    func()        // Persists games and restarts the process
+	cancel  chan struct{} // Closed to abort the pending restart
+	pending bool
+	mu      sync.Mutex
+}
+
+// SetRestartHook sets the function that persists games and restarts the process.
+// Restart requests are rejected until a hook is set.
+func (h *Handler) SetRestartHook(hook func()) {
+	h.restart.mu.Lock()
+	defer h.restart.mu.Unlock()
+	h.restart.hook = hook
+}
+
+// Restart drains the server and restarts it once no human games are in progress (admin only).
+// New games and joins are blocked while draining; games still running at the
+// deadline are paused, persisted and resumed after the restart.
+func (h *Handler) Restart(w http.ResponseWriter, r *http.Request) {
+	var req RestartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+	if req.MaxWaitSeconds < 0 {
+		respondWithError(w, "max_wait_seconds must not be negative", http.StatusBadRequest)
+		return
+	}
+
+	rc := h.restart
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if req.Cancel {
+		if !rc.pending {
+			respondWithError(w, "No restart pending", http.StatusConflict)
+			return
+		}
+		close(rc.cancel)
+		rc.pending = false
+		h.maintenance.cancel()
+		h.gameManager.EndMaintenance()
+		h.broadcastMaintenance(h.gameManager.MaintenanceStatus())
+		respondWithJSON(w, map[string]interface{}{"message": "Restart cancelled"}, http.StatusOK)
+		return
+	}
+
+	if rc.hook == nil {
+		respondWithError(w, "Restart is not configured on this server", http.StatusNotImplemented)
+		return
+	}
+	if rc.pending {
+		respondWithError(w, "Restart already pending", http.StatusConflict)
+		return
+	}
+
+	maxWait := DefaultRestartMaxWait
+	if req.Immediate {
+		maxWait = 0
+	} else if req.MaxWaitSeconds > 0 {
+		maxWait = time.Duration(req.MaxWaitSeconds) * time.Second
+	}
+
+	message := req.Message
+	if message == "" {
+		message = "The server will restart shortly - games in progress will be saved"
+	}
+	deadline := time.Now().Add(maxWait)
+	status := h.gameManager.StartMaintenance(message, deadline, true)
+	h.maintenance.cancel()
+	h.broadcastMaintenance(status)
+
+	rc.pending = true
+	rc.cancel = make(chan struct{})
+	go h.runRestart(deadline, rc.cancel)
+
+	respondWithJSON(w, map[string]interface{}{
+		"message":     "Restart scheduled",
+		"maintenance": status,
+		"in_progress": h.gameManager.GamesInProgress(),
+	}, http.StatusAccepted)
+}
+
+// runRestart waits until the server is idle or the deadline passes, then restarts
+func (h *Handler) runRestart(deadline time.Time, cancel <-chan struct{}) {
+	ticker := time.NewTicker(restartPollInterval)
+	defer ticker.Stop()
+
+	for h.gameManager.GamesInProgress() > 0 && time.Now().Before(deadline) {
+		select {
+		case <-cancel:
+			return
+		case <-ticker.C:
+		}
+	}
+
+	h.restart.mu.Lock()
+	select {
+	case <-cancel:
+		h.restart.mu.Unlock()
+		return
+	default:
+	}
+	hook := h.restart.hook
+	h.restart.mu.Unlock()
+
+	// Freeze turn clocks so nobody times out while the server is down
+	paused := h.gameManager.PauseAllForMaintenance()
+	log.Printf("Restart: %d games still in progress will be persisted", len(paused))
+
+	if h.hub != nil {
+		h.hub.BroadcastToAll(RestartEvent{Type: "server_restarting", ReconnectAfterMs: 3000})
+	}
+	hook()
+
+	// The hook only returns if the restart failed; reopen so players aren't stuck
+	log.Printf("Restart failed, leaving maintenance mode")
+	h.restart.mu.Lock()
+	h.restart.pending = false
+	h.restart.mu.Unlock()
+	for _, code := range h.gameManager.EndMaintenance() {
+		h.broadcastRefresh(code, "game_resumed")
+	}
+	h.broadcastMaintenance(h.gameManager.MaintenanceStatus())
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+	"github.com/gorilla/websocket"
+)
+
+// ShutdownEvent tells clients the server is stopping, when, and how long to wait
+// before reconnecting. Games in progress are paused and saved.
+type ShutdownEvent struct {
+	Type             string           `json:"type"` // Always "server_shutdown"
+	Message          string           `json:"message"`
+	ShutdownAt       models.Timestamp `json:"shutdown_at"`
+	ETAMs            int64            `json:"eta_ms"` // Until sockets are closed
+	ReconnectAfterMs int              `json:"reconnect_after_ms"`
+}
+
+// BeginShutdown blocks new games and joins, pauses every game in progress so no
+// turn times out while the server is down, and warns every client that sockets
+// close after the grace period. Returns the number of games paused.
+func (h *Handler) BeginShutdown(grace time.Duration) int {
+	message := "The server is shutting down - games in progress will be saved"
+	shutdownAt := time.Now().Add(grace)
+	status := h.gameManager.StartMaintenance(message, shutdownAt, true)
+	h.maintenance.cancel()
+	h.broadcastMaintenance(status)
+
+	paused := h.gameManager.PauseAllForMaintenance()
+	if h.hub != nil {
+		h.hub.BroadcastToAll(ShutdownEvent{
+			Type:             "server_shutdown",
+			Message:          message,
+			ShutdownAt:       models.At(shutdownAt),
+			ETAMs:            grace.Milliseconds(),
+			ReconnectAfterMs: int(grace.Milliseconds()) + 3000,
+		})
+	}
+	return len(paused)
+}
+
+// CloseAll disconnects every game client with a going-away close frame. Presence
+// hooks aren't run, so players are still seated as connected when games are saved.
+func (h *Hub) CloseAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closing = true
+	for code, clients := range h.games {
+		for client := range clients {
+			close(client.send)
+			h.forgetLocked(client)
+		}
+		delete(h.games, code)
+		delete(h.states, code)
+	}
+}
+
+// closeMessage is the payload of the close frame sent when a client is disconnected
+func (h *Hub) closeMessage() []byte {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.closing {
+		return websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	}
+	return []byte{}
+}
+
+// CloseAll disconnects every lobby socket
+func (l *Lobby) CloseAll() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for client := range l.clients {
+		close(client.send)
+		delete(l.clients, client)
+	}
+}
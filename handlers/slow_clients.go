@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+// SlowClientStats counts how slow clients have been handled since the server started
+type SlowClientStats struct {
+	SlowWrites   uint64 `json:"slow_writes"`
+	Overflows    uint64 `json:"send_overflows"` // Messages that didn't fit in a client's send buffer
+	Downgraded   uint64 `json:"downgraded"`
+	Disconnected uint64 `json:"disconnected"`
+}
+
+// slowClientCounters backs SlowClientStats
+type slowClientCounters struct {
+	slowWrites   atomic.Uint64
+	overflows    atomic.Uint64
+	downgraded   atomic.Uint64
+	disconnected atomic.Uint64
+}
+
+// SlowClientStats returns the slow-client counters
+func (h *Hub) SlowClientStats() SlowClientStats {
+	return SlowClientStats{
+		SlowWrites:   h.slow.slowWrites.Load(),
+		Overflows:    h.slow.overflows.Load(),
+		Downgraded:   h.slow.downgraded.Load(),
+		Disconnected: h.slow.disconnected.Load(),
+	}
+}
+
+// SlowClients counts the clients of a game currently downgraded to refresh signals only
+func (h *Hub) SlowClients(gameCode string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	count := 0
+	for client := range h.games[gameCode] {
+		if client.stateOnly.Load() {
+			count++
+		}
+	}
+	return count
+}
+
+// dropSlowClient records that a client is being disconnected for being too slow
+func (h *Hub) dropSlowClient(c *Client, reason string) {
+	h.slow.disconnected.Add(1)
+	log.Printf("WS: disconnecting slow client %s from game %s (%s)", c.playerID, c.gameCode, reason)
+	h.publish(c.gameCode, "client_slow_disconnected", map[string]string{"player_id": c.playerID, "reason": reason})
+}
+
+// strike records a slow write or a send buffer overflow, downgrading the client
+// once it has enough strikes. Returns true once the client should be disconnected.
+func (c *Client) strike() bool {
+	cfg := c.hub.config
+	strikes := int(c.strikes.Add(1))
+	if cfg.DisconnectAfter > 0 && strikes >= cfg.DisconnectAfter {
+		return true
+	}
+	if cfg.DowngradeAfter > 0 && strikes >= cfg.DowngradeAfter && c.stateOnly.CompareAndSwap(false, true) {
+		c.hub.slow.downgraded.Add(1)
+		log.Printf("WS: %s in game %s is slow, sending refresh signals only", c.playerID, c.gameCode)
+		c.hub.publish(c.gameCode, "client_downgraded", map[string]string{"player_id": c.playerID})
+	}
+	return false
+}
+
+// write sends one frame within the write deadline and tracks how long it took.
+// Returns false once the connection should be dropped.
+func (c *Client) write(messageType int, data []byte) bool {
+	start := time.Now()
+	c.conn.SetWriteDeadline(start.Add(c.hub.config.WriteWait))
+	if err := c.conn.WriteMessage(messageType, data); err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			c.hub.dropSlowClient(c, "write deadline exceeded")
+		}
+		return false
+	}
+
+	if time.Since(start) < c.hub.config.SlowWrite {
+		c.strikes.Store(0)
+		return true
+	}
+	c.hub.slow.slowWrites.Add(1)
+	if c.strike() {
+		c.hub.dropSlowClient(c, "repeated slow writes")
+		return false
+	}
+	return true
+}
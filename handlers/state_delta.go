@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// StateSource looks up a game's state, normalized to its JSON form, and version
+type StateSource func(gameCode string) (uint64, map[string]interface{}, error)
+
+// StateEvent carries a game's whole state. Clients following deltas get it when
+// they connect, when they send {"type": "sync"}, and whenever they fall behind.
+type StateEvent struct {
+	Type         string                 `json:"type"` // Always "state"
+	StateVersion uint64                 `json:"state_version"`
+	State        map[string]interface{} `json:"state"`
+}
+
+// StateDeltaEvent carries what changed in a game's state since base_version, as a
+// JSON merge patch (RFC 7396): changed fields with their new values, objects
+// patched field by field, and null for fields that were removed or became null
+type StateDeltaEvent struct {
+	Type         string                 `json:"type"` // Always "state_delta"
+	BaseVersion  uint64                 `json:"base_version"`
+	StateVersion uint64                 `json:"state_version"`
+	Changes      map[string]interface{} `json:"changes"`
+}
+
+// stateCache is the last state a game's delta clients were sent
+type stateCache struct {
+	version uint64
+	state   map[string]interface{}
+}
+
+// GameStates reads game states from the game manager
+func GameStates(gm *models.GameManager) StateSource {
+	return func(gameCode string) (uint64, map[string]interface{}, error) {
+		game, err := gm.GetGame(context.Background(), gameCode)
+		if err != nil {
+			return 0, nil, err
+		}
+		return game.StateSnapshot()
+	}
+}
+
+// SetStateSource registers where state deltas are read from. Call before the
+// server starts; without one, clients asking for deltas only get refresh signals.
+func (h *Hub) SetStateSource(source StateSource) {
+	h.stateSource = source
+}
+
+// deliverState brings a game's delta clients up to date: clients holding the last
+// version sent get the changes since, others the whole state. Runs on the hub's
+// loop, which alone touches the clients' versions.
+func (h *Hub) deliverState(gameCode string) {
+	if h.stateSource == nil {
+		return
+	}
+
+	h.mu.RLock()
+	following := false
+	for client := range h.games[gameCode] {
+		if client.deltas {
+			following = true
+			break
+		}
+	}
+	last := h.states[gameCode]
+	h.mu.RUnlock()
+	if !following {
+		return
+	}
+
+	version, state, err := h.stateSource(gameCode)
+	if err != nil {
+		return
+	}
+	var delta, snapshot []byte
+	if last != nil && last.version != version {
+		delta, _ = json.Marshal(StateDeltaEvent{
+			Type:         "state_delta",
+			BaseVersion:  last.version,
+			StateVersion: version,
+			Changes:      models.DiffStates(last.state, state),
+		})
+	}
+
+	h.mu.RLock()
+	for client := range h.games[gameCode] {
+		if !client.deltas || client.stateVersion == version {
+			continue
+		}
+		// Downgraded clients refetch the whole state on refresh signals instead
+		if client.stateOnly.Load() {
+			client.stateVersion = 0
+			continue
+		}
+
+		message := delta
+		if last == nil || client.stateVersion != last.version {
+			if snapshot == nil {
+				snapshot, _ = json.Marshal(StateEvent{Type: "state", StateVersion: version, State: state})
+			}
+			message = snapshot
+		}
+		select {
+		case client.send <- message:
+			client.stateVersion = version
+		default:
+			client.stateVersion = 0 // Gets the whole state next time
+		}
+	}
+	h.mu.RUnlock()
+
+	h.mu.Lock()
+	if h.games[gameCode] != nil {
+		h.states[gameCode] = &stateCache{version: version, state: state}
+	}
+	h.mu.Unlock()
+}
+
+// resync sends a client the whole state again
+func (c *Client) resync() {
+	c.hub.broadcast <- &GameMessage{GameCode: c.gameCode, resync: c}
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// Cache policies for the web client
+const (
+	indexCacheControl   = "no-cache"             // Always revalidate so new releases are picked up
+	assetCacheControl   = "public, max-age=3600" // Scripts, styles and images
+	spaFallbackDocument = "index.html"
+)
+
+// apiPrefixes are never answered with the SPA fallback
+var apiPrefixes = []string{"/api/", "/ws", "/health"}
+
+// StaticHandler serves the web client with cache headers and falls back to
+// index.html for client-side routes
+type StaticHandler struct {
+	root  fs.FS
+	files http.Handler
+}
+
+// NewStaticHandler creates a handler serving files from root
+func NewStaticHandler(root fs.FS) *StaticHandler {
+	return &StaticHandler{
+		root:  root,
+		files: http.FileServer(http.FS(root)),
+	}
+}
+
+// ServeHTTP serves a static file, or index.html for unknown extension-less paths
+func (s *StaticHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	for _, prefix := range apiPrefixes {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			http.NotFound(w, r)
+			return
+		}
+	}
+
+	name := strings.TrimPrefix(path.Clean(r.URL.Path), "/")
+	if name == "" {
+		name = spaFallbackDocument
+	}
+
+	info, err := fs.Stat(s.root, name)
+	if err != nil || info.IsDir() {
+		if path.Ext(name) != "" {
+			// A missing asset is a real 404, not a client-side route
+			http.NotFound(w, r)
+			return
+		}
+		name = spaFallbackDocument
+		r = r.Clone(r.Context())
+		r.URL.Path = "/"
+	}
+
+	if name == spaFallbackDocument {
+		w.Header().Set("Cache-Control", indexCacheControl)
+	} else {
+		w.Header().Set("Cache-Control", assetCacheControl)
+	}
+	s.files.ServeHTTP(w, r)
+}
+
+// ServeFile serves one page of the web client regardless of the request path
+func (s *StaticHandler) ServeFile(w http.ResponseWriter, r *http.Request, name string) {
+	if _, err := fs.Stat(s.root, name); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	r = r.Clone(r.Context())
+	r.URL.Path = "/" + name
+	w.Header().Set("Cache-Control", indexCacheControl)
+	s.files.ServeHTTP(w, r)
+}
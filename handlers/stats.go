@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+	"github.com/go-chi/chi/v5"
+)
+
+// GetMetaStats returns win rates by seat color and turn order across every
+// archived game, overall and per board type
+func (h *Handler) GetMetaStats(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, h.gameManager.Archive().MetaStats(), http.StatusOK)
+}
+
+// GetAnalytics aggregates archived games that ended between ?from and ?to
+// (RFC3339, both optional), grouped by ?group_by: players, preset or board
+func (h *Handler) GetAnalytics(w http.ResponseWriter, r *http.Request) {
+	query := models.AnalyticsQuery{GroupBy: r.URL.Query().Get("group_by")}
+	for param, bound := range map[string]*time.Time{"from": &query.From, "to": &query.To} {
+		value := r.URL.Query().Get(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			respondWithError(w, param+" must be an RFC3339 time", http.StatusBadRequest)
+			return
+		}
+		*bound = t
+	}
+
+	report, err := h.gameManager.Archive().Analytics(query)
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+	respondWithJSON(w, report, http.StatusOK)
+}
+
+// GetPlayerStats returns a player's career stats across every finished game
+func (h *Handler) GetPlayerStats(w http.ResponseWriter, r *http.Request) {
+	stats, exists := h.gameManager.Stats().Get(chi.URLParam(r, "id"))
+	if !exists {
+		respondWithError(w, "No finished games for this player", http.StatusNotFound)
+		return
+	}
+	respondWithJSON(w, stats, http.StatusOK)
+}
+
+// GetLeaderboard returns a page of players ranked by ?sort= (wins, games,
+// win_rate or rating), starting at ?offset= with up to ?limit= players
+func (h *Handler) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	query := models.LeaderboardQuery{Sort: r.URL.Query().Get("sort")}
+	for param, value := range map[string]*int{"offset": &query.Offset, "limit": &query.Limit} {
+		raw := r.URL.Query().Get(param)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			respondWithError(w, param+" must be a non-negative number", http.StatusBadRequest)
+			return
+		}
+		*value = n
+	}
+
+	board, err := h.gameManager.Stats().Leaderboard(query)
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+	respondWithJSON(w, board, http.StatusOK)
+}
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event stream settings
+const (
+	DefaultStreamSubject = "ludo.events"
+	DefaultStreamTopic   = "ludo-events"
+	streamQueueSize      = 1024
+)
+
+// StreamPublisher writes keyed records to a streaming platform such as Kafka or NATS JetStream
+type StreamPublisher interface {
+	Publish(key string, value []byte) error
+}
+
+// StreamSink publishes every game event to a StreamPublisher, keyed by game code
+// so consumers see each game's events in order. Publishing happens on a
+// background worker; events are dropped if the queue fills up.
+type StreamSink struct {
+	publisher StreamPublisher
+	queue     chan Event
+}
+
+// NewStreamSink creates a sink for the publisher and starts its worker
+func NewStreamSink(publisher StreamPublisher) *StreamSink {
+	s := &StreamSink{
+		publisher: publisher,
+		queue:     make(chan Event, streamQueueSize),
+	}
+	go s.run()
+	return s
+}
+
+// NewStreamSinkFromURL picks a publisher from the URL scheme:
+// nats://host:4222/ludo.events publishes to subject ludo.events.{code}, and
+// kafka+http://proxy:8082/ludo-events posts to topic ludo-events through a Kafka REST proxy.
+func NewStreamSinkFromURL(rawURL string) (*StreamSink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.Trim(u.Path, "/")
+	switch u.Scheme {
+	case "nats":
+		if name == "" {
+			name = DefaultStreamSubject
+		}
+		return NewStreamSink(NewNATSPublisher(u, name)), nil
+	case "kafka+http", "kafka+https":
+		if name == "" {
+			name = DefaultStreamTopic
+		}
+		proxy := *u
+		proxy.Scheme = strings.TrimPrefix(u.Scheme, "kafka+")
+		proxy.Path = ""
+		return NewStreamSink(NewKafkaRESTPublisher(proxy.String(), name)), nil
+	default:
+		return nil, fmt.Errorf("unsupported event stream scheme %q", u.Scheme)
+	}
+}
+
+// Publish queues an event for the stream
+func (s *StreamSink) Publish(event Event) {
+	select {
+	case s.queue <- event:
+	default:
+		log.Printf("Event stream: queue full, dropped %s for game %s", event.Type, event.GameCode)
+	}
+}
+
+// run publishes queued events one at a time so per-game order is kept
+func (s *StreamSink) run() {
+	for event := range s.queue {
+		value, err := json.Marshal(event)
+		if err != nil {
+			continue
+		}
+		if err := s.publisher.Publish(event.GameCode, value); err != nil {
+			log.Printf("Event stream: publish %s for game %s failed: %v", event.Type, event.GameCode, err)
+		}
+	}
+}
+
+// KafkaRESTPublisher produces records through a Kafka REST proxy (Confluent REST API v2)
+type KafkaRESTPublisher struct {
+	url    string
+	client *http.Client
+}
+
+// NewKafkaRESTPublisher creates a publisher for a topic on the proxy at baseURL
+func NewKafkaRESTPublisher(baseURL, topic string) *KafkaRESTPublisher {
+	return &KafkaRESTPublisher{
+		url:    strings.TrimRight(baseURL, "/") + "/topics/" + url.PathEscape(topic),
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Publish produces one record with the given key
+func (kp *KafkaRESTPublisher) Publish(key string, value []byte) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"records": []map[string]interface{}{
+			{"key": key, "value": json.RawMessage(value)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := kp.client.Post(kp.url, "application/vnd.kafka.json.v2+json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("kafka proxy returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// NATSPublisher publishes to subject {prefix}.{key} over the NATS client protocol.
+// A JetStream stream bound to {prefix}.> persists the events.
+type NATSPublisher struct {
+	addr     string
+	user     string
+	password string
+	prefix   string
+	conn     net.Conn
+	mu       sync.Mutex
+}
+
+// NewNATSPublisher creates a publisher for the server in u; it connects on first use
+func NewNATSPublisher(u *url.URL, subjectPrefix string) *NATSPublisher {
+	np := &NATSPublisher{
+		addr:   hostWithPort(u, "4222"),
+		prefix: subjectPrefix,
+	}
+	if u.User != nil {
+		np.user = u.User.Username()
+		np.password, _ = u.User.Password()
+	}
+	return np
+}
+
+// Publish sends one message, reconnecting once if the connection dropped
+func (np *NATSPublisher) Publish(key string, value []byte) error {
+	np.mu.Lock()
+	defer np.mu.Unlock()
+
+	subject := np.prefix + "." + natsToken(key)
+	msg := append([]byte(fmt.Sprintf("PUB %s %d\r\n", subject, len(value))), value...)
+	msg = append(msg, "\r\n"...)
+
+	for attempt := 0; attempt < 2; attempt++ {
+		if np.conn == nil {
+			if err := np.connectLocked(); err != nil {
+				return err
+			}
+		}
+		np.conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+		if _, err := np.conn.Write(msg); err == nil {
+			return nil
+		}
+		np.conn.Close()
+		np.conn = nil
+	}
+	return fmt.Errorf("nats: could not publish to %s", np.addr)
+}
+
+// connectLocked dials the server and sends CONNECT (caller must hold lock)
+func (np *NATSPublisher) connectLocked() error {
+	conn, err := net.DialTimeout("tcp", np.addr, 10*time.Second)
+	if err != nil {
+		return err
+	}
+
+	// The server greets with INFO before accepting CONNECT
+	reader := bufio.NewReader(conn)
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	if line, err := reader.ReadString('\n'); err != nil || !strings.HasPrefix(line, "INFO") {
+		conn.Close()
+		return fmt.Errorf("nats: unexpected greeting from %s", np.addr)
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	options, _ := json.Marshal(map[string]interface{}{
+		"verbose":  false,
+		"pedantic": false,
+		"name":     "ludo-nadwa-server",
+		"lang":     "go",
+		"user":     np.user,
+		"pass":     np.password,
+	})
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\n", options); err != nil {
+		conn.Close()
+		return err
+	}
+
+	np.conn = conn
+	go np.answerPings(conn, reader)
+	return nil
+}
+
+// answerPings replies to server PINGs so the connection isn't considered stale
+func (np *NATSPublisher) answerPings(conn net.Conn, reader *bufio.Reader) {
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		if strings.HasPrefix(line, "PING") {
+			np.mu.Lock()
+			conn.Write([]byte("PONG\r\n"))
+			np.mu.Unlock()
+		} else if strings.HasPrefix(line, "-ERR") {
+			log.Printf("NATS: %s", strings.TrimSpace(line))
+		}
+	}
+}
+
+// natsToken makes a key safe to use as a single subject token
+func natsToken(key string) string {
+	return strings.NewReplacer(".", "_", " ", "_", "*", "_", ">", "_").Replace(key)
+}
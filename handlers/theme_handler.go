@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// ThemeSelectionRequest represents the request to save a player's theme choice
+type ThemeSelectionRequest struct {
+	Code       string `json:"code"` // A game the player is in; its session secret signs the request
+	PlayerID   string `json:"player_id"`
+	BoardTheme string `json:"board_theme,omitempty"`
+	PieceSkin  string `json:"piece_skin,omitempty"`
+}
+
+// GetThemes returns the manifest of board themes and piece skins
+func (h *Handler) GetThemes(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, models.GetThemeManifest(), http.StatusOK)
+}
+
+// GetProfileTheme returns a player's selected theme
+func (h *Handler) GetProfileTheme(w http.ResponseWriter, r *http.Request) {
+	playerID := r.URL.Query().Get("player_id")
+	if playerID == "" {
+		respondWithError(w, "player_id is required", http.StatusBadRequest)
+		return
+	}
+	respondWithJSON(w, h.gameManager.Profiles().Get(playerID), http.StatusOK)
+}
+
+// SetProfileTheme saves a player's selected theme
+func (h *Handler) SetProfileTheme(w http.ResponseWriter, r *http.Request) {
+	var req ThemeSelectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	profile, err := h.gameManager.Profiles().SetTheme(req.PlayerID, req.BoardTheme, req.PieceSkin)
+	if err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+	respondWithJSON(w, profile, http.StatusOK)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// translationTimeout bounds translating one chat message into every reader's locale
+const translationTimeout = 10 * time.Second
+
+// SetTranslator sets the provider used to translate chat messages for readers
+func (h *Handler) SetTranslator(t models.Translator) {
+	h.translator = t
+}
+
+// LibreTranslator translates chat through a LibreTranslate-compatible HTTP API
+type LibreTranslator struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+// NewLibreTranslator creates a translator for the /translate endpoint at url
+func NewLibreTranslator(url, apiKey string) *LibreTranslator {
+	return &LibreTranslator{
+		url:    url,
+		apiKey: apiKey,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Translate sends the text to the translation service with automatic source detection
+func (lt *LibreTranslator) Translate(ctx context.Context, text, targetLocale string) (string, error) {
+	body, err := json.Marshal(map[string]string{
+		"q":       text,
+		"source":  "auto",
+		"target":  targetLocale,
+		"format":  "text",
+		"api_key": lt.apiKey,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, lt.url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := lt.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("translation service returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		TranslatedText string `json:"translatedText"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+	return result.TranslatedText, nil
+}
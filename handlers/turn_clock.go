@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// CountdownWindow is how long before a turn's deadline countdown events start
+const CountdownWindow = 10 * time.Second
+
+// TurnClockSource looks up whose turn it is in a game and when it times out
+type TurnClockSource func(gameCode string) (models.TurnClock, bool)
+
+// CountdownEvent is sent once a second in the last CountdownWindow of a turn.
+// Clients should count down to turn_deadline; seconds_left is for display only.
+type CountdownEvent struct {
+	Type        string `json:"type"` // Always "countdown"
+	SecondsLeft int    `json:"seconds_left"`
+	models.TurnClock
+}
+
+// TurnWarningEvent tells the player on turn, and only them, that their turn is
+// about to time out. It is sent once per turn, models.TurnTimeoutWarning before
+// the deadline.
+type TurnWarningEvent struct {
+	Type        string `json:"type"` // Always "turn_warning"
+	SecondsLeft int    `json:"seconds_left"`
+	models.TurnClock
+}
+
+// TurnTimeoutRequest sets how long each turn may last
+type TurnTimeoutRequest struct {
+	Code    string `json:"code"`
+	HostID  string `json:"host_id"`
+	Seconds int    `json:"seconds"` // 0 for untimed turns
+}
+
+// TurnClocks reads turn clocks from the game manager
+func TurnClocks(gm *models.GameManager) TurnClockSource {
+	return func(gameCode string) (models.TurnClock, bool) {
+		game, err := gm.GetGame(context.Background(), gameCode)
+		if err != nil {
+			return models.TurnClock{}, false
+		}
+		return game.TurnClock()
+	}
+}
+
+// SetTurnClock registers where refresh events get the turn clock from. Call before
+// the server starts; without one, refresh events carry no turn clock.
+func (h *Hub) SetTurnClock(source TurnClockSource) {
+	h.turnClock = source
+}
+
+// currentTurnClock returns a game's turn clock, if a source is set and a turn is running
+func (h *Hub) currentTurnClock(gameCode string) (models.TurnClock, bool) {
+	if h.turnClock == nil {
+		return models.TurnClock{}, false
+	}
+	return h.turnClock(gameCode)
+}
+
+// BroadcastCountdown tells a game's clients how long the current turn has left.
+// Countdowns are frequent and short-lived, so they skip the event sinks and
+// clients downgraded for being slow.
+func (h *Hub) BroadcastCountdown(gameCode string, clock models.TurnClock) {
+	secondsLeft := int(math.Ceil(time.Until(clock.Deadline.Time).Seconds()))
+	if secondsLeft < 0 {
+		secondsLeft = 0
+	}
+	message, err := json.Marshal(CountdownEvent{Type: "countdown", SecondsLeft: secondsLeft, TurnClock: clock})
+	if err != nil {
+		return
+	}
+	h.broadcast <- &GameMessage{
+		GameCode: gameCode,
+		Message:  message,
+		kind:     "countdown",
+	}
+}
+
+// SendTurnWarning warns the player on turn that it is about to time out
+func (h *Hub) SendTurnWarning(gameCode string, clock models.TurnClock) {
+	secondsLeft := int(math.Ceil(time.Until(clock.Deadline.Time).Seconds()))
+	if secondsLeft < 0 {
+		secondsLeft = 0
+	}
+	h.SendToPlayer(gameCode, clock.Turn, TurnWarningEvent{Type: "turn_warning", SecondsLeft: secondsLeft, TurnClock: clock})
+}
+
+// SetTurnTimeout sets how long each turn may last, or leaves turns untimed (host
+// only, before the game starts)
+func (h *Handler) SetTurnTimeout(w http.ResponseWriter, r *http.Request) {
+	var req TurnTimeoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondWithErr(w, errInvalidBody, http.StatusBadRequest)
+		return
+	}
+
+	game, err := h.gameManager.GetGame(r.Context(), req.Code)
+	if err != nil {
+		respondWithErr(w, err, unavailableStatus(err, http.StatusNotFound))
+		return
+	}
+
+	if err := game.SetTurnTimeout(req.HostID, time.Duration(req.Seconds)*time.Second); err != nil {
+		respondWithErr(w, err, http.StatusBadRequest)
+		return
+	}
+
+	h.broadcastRefresh(req.Code, "turn_timeout_changed")
+
+	respondWithJSON(w, map[string]interface{}{
+		"message":              "Turn timeout updated",
+		"turn_timeout_seconds": req.Seconds,
+	}, http.StatusOK)
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// ProtocolVersion is bumped on breaking changes to the REST/WebSocket protocol
+const ProtocolVersion = 1
+
+// APIPrefix is the path of the current REST API version. Routes are also served
+// unversioned under /api for clients written before versioning.
+const APIPrefix = "/api/v1"
+
+// MinClientProtocol is the oldest client protocol version the server still supports
+const MinClientProtocol = 1
+
+// Version is the server release, set at build time with
+// -ldflags "-X github.com/aminearbi/ludo-nadwa-server/handlers.Version=1.2.3"
+var Version = "dev"
+
+// VersionInfo describes the running server build
+type VersionInfo struct {
+	Version           string `json:"version"`
+	Commit            string `json:"commit,omitempty"`
+	BuildTime         string `json:"build_time,omitempty"`
+	Modified          bool   `json:"modified,omitempty"` // Built from a dirty working tree
+	GoVersion         string `json:"go_version"`
+	ProtocolVersion   int    `json:"protocol_version"`
+	MinClientProtocol int    `json:"min_client_protocol"`
+}
+
+// ServerTime is the server clock, for clients correcting their own countdowns
+type ServerTime struct {
+	Time   models.Timestamp `json:"time"`
+	UnixMs int64            `json:"unix_ms"`
+}
+
+// GetVersionInfo collects build information embedded by the Go toolchain
+func GetVersionInfo() VersionInfo {
+	info := VersionInfo{
+		Version:           Version,
+		GoVersion:         runtime.Version(),
+		ProtocolVersion:   ProtocolVersion,
+		MinClientProtocol: MinClientProtocol,
+	}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				info.Commit = setting.Value
+			case "vcs.time":
+				info.BuildTime = setting.Value
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+	return info
+}
+
+// GetVersion returns server build info and the supported protocol range
+func (h *Handler) GetVersion(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, GetVersionInfo(), http.StatusOK)
+}
+
+// GetTime returns the server clock. Clients estimate their skew as the server time
+// minus the midpoint of when they sent the request and got the response.
+func (h *Handler) GetTime(w http.ResponseWriter, r *http.Request) {
+	now := models.Now()
+	w.Header().Set("Cache-Control", "no-store")
+	respondWithJSON(w, ServerTime{Time: now, UnixMs: now.UnixMilli()}, http.StatusOK)
+}
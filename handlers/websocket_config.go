@@ -0,0 +1,60 @@
+package handlers
+
+import "time"
+
+// WebSocket defaults
+const (
+	DefaultWriteWait       = 10 * time.Second
+	DefaultPongWait        = 60 * time.Second
+	DefaultDisconnectAfter = 6
+	DefaultDowngradeAfter  = 3
+	DefaultMaxPerGame      = 500
+)
+
+// WebSocketConfig tunes connection deadlines and how slow clients are handled.
+// A client earns a strike for each write slower than SlowWrite and each message
+// that didn't fit in its send buffer; a fast write clears its strikes.
+type WebSocketConfig struct {
+	WriteWait       time.Duration // Deadline for each write to a client
+	PongWait        time.Duration // How long a client may go without answering a ping
+	SlowWrite       time.Duration // Writes slower than this are a strike (default: WriteWait/4)
+	DowngradeAfter  int           // Strikes before a client only gets refresh signals (0 = never)
+	DisconnectAfter int           // Strikes before a client is disconnected (0 = never)
+	MaxPerGame      int           // Connections allowed per game, players and spectators (0 = no limit)
+	EventBuffer     int           // Recent events kept per game for reconnecting clients (default: DefaultEventBuffer)
+}
+
+// DefaultWebSocketConfig returns the default deadlines and limits, without downgrading slow clients
+func DefaultWebSocketConfig() WebSocketConfig {
+	return WebSocketConfig{
+		WriteWait:       DefaultWriteWait,
+		PongWait:        DefaultPongWait,
+		SlowWrite:       DefaultWriteWait / 4,
+		DisconnectAfter: DefaultDisconnectAfter,
+		MaxPerGame:      DefaultMaxPerGame,
+		EventBuffer:     DefaultEventBuffer,
+	}
+}
+
+// pingPeriod sends pings often enough that a healthy client always answers within PongWait
+func (c WebSocketConfig) pingPeriod() time.Duration {
+	return (c.PongWait * 9) / 10
+}
+
+// SetConfig sets WebSocket deadlines and slow-client handling. Zero durations
+// keep their defaults. Call before the server starts.
+func (h *Hub) SetConfig(cfg WebSocketConfig) {
+	if cfg.WriteWait <= 0 {
+		cfg.WriteWait = DefaultWriteWait
+	}
+	if cfg.PongWait <= 0 {
+		cfg.PongWait = DefaultPongWait
+	}
+	if cfg.SlowWrite <= 0 {
+		cfg.SlowWrite = cfg.WriteWait / 4
+	}
+	if cfg.EventBuffer <= 0 {
+		cfg.EventBuffer = DefaultEventBuffer
+	}
+	h.config = cfg
+}
@@ -1,30 +1,27 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/aminearbi/ludo-nadwa-server/models"
 	"github.com/gorilla/websocket"
 )
 
-// WebSocket configuration
-const (
-	writeWait      = 10 * time.Second
-	pongWait       = 60 * time.Second
-	pingPeriod     = (pongWait * 9) / 10
-	maxMessageSize = 512
-)
+// Largest message accepted from a client, room for a full chat command
+const maxMessageSize = 2048
 
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		return true
-	},
+	CheckOrigin:     checkOrigin,
 }
 
 // Client represents a WebSocket client connection
@@ -34,40 +31,82 @@ type Client struct {
 	send     chan []byte
 	gameCode string
 	playerID string
+	locale   string  // Preferred chat language, e.g. "ar" or "en"
+	deltas   bool    // Follows the state through snapshots and deltas (?updates=delta)
+	codec    wsCodec // Wire format of messages sent to the client
+
+	stateVersion uint64 // Last state version sent; only the hub's loop touches it
+
+	commentary atomic.Bool                     // Subscribed to the commentary channel
+	events     atomic.Pointer[map[string]bool] // Event types the client selected; nil means all
+	strikes    atomic.Int32                    // Slow writes and send buffer overflows since the last fast write
+	stateOnly  atomic.Bool                     // Downgraded to refresh signals for being slow
 }
 
 // Hub maintains active clients and broadcasts refresh signals
 type Hub struct {
-	games      map[string]map[*Client]bool
-	register   chan *Client
-	unregister chan *Client
-	broadcast  chan *GameMessage
-	mu         sync.RWMutex
+	games          map[string]map[*Client]bool
+	devices        map[deviceKey]map[*Client]bool // Each player's connections to each game
+	register       chan *Client
+	unregister     chan *Client
+	broadcast      chan *GameMessage
+	sinks          []EventSink    // Integrations mirroring game events
+	online         map[string]int // Connections per player across every game
+	onPresence     PresenceHook
+	onGamePresence GamePresenceHook
+	eventLogs      map[string]*eventLog // Recent game-wide events by game, for reconnecting clients
+	turnClock      TurnClockSource
+	stateSource    StateSource
+	states         map[string]*stateCache // Last state sent to each game's delta clients
+	config         WebSocketConfig
+	slow           slowClientCounters
+	closing        bool // Shutting down; clients are told the server is going away
+	mu             sync.RWMutex
+}
+
+// deviceKey identifies a player's connections to one game
+type deviceKey struct {
+	gameCode string
+	playerID string
 }
 
 // GameMessage represents a message to broadcast
 type GameMessage struct {
 	GameCode string
 	Message  []byte
+	player   string             // Only this player's connections, when set
+	only     func(*Client) bool // Optional recipient filter
+	refresh  bool               // A refresh signal, still sent to downgraded clients
+	kind     string             // Event type clients can filter on; empty always goes through
+	resync   *Client            // Send this client the whole state instead of a message
+	close    bool               // Disconnect the game's clients once the message is queued
 }
 
 // RefreshEvent is the simplified event - just tells clients to fetch new state
 type RefreshEvent struct {
 	Type string `json:"type"` // Always "refresh"
 	Hint string `json:"hint"` // What changed: "dice_rolled", "piece_moved", "player_joined", etc.
+	Auto bool   `json:"auto,omitempty"` // The server rolled or moved on the player's behalf
+	*models.TurnClock // Whose turn it is and its deadline, while a turn is running
 }
 
 // NewHub creates a new Hub
 func NewHub() *Hub {
 	return &Hub{
 		games:      make(map[string]map[*Client]bool),
+		devices:    make(map[deviceKey]map[*Client]bool),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 		broadcast:  make(chan *GameMessage),
+		online:     make(map[string]int),
+		states:     make(map[string]*stateCache),
+		eventLogs:  make(map[string]*eventLog),
+		config:     DefaultWebSocketConfig(),
 	}
 }
 
-// Run starts the hub's main loop
+// Run starts the hub's main loop. A player may be connected from several devices;
+// presence changes are only announced for their first and last connection.
 func (h *Hub) Run() {
 	for {
 		select {
@@ -77,55 +116,354 @@ func (h *Hub) Run() {
 				h.games[client.gameCode] = make(map[*Client]bool)
 			}
 			h.games[client.gameCode][client] = true
+			key := deviceKey{client.gameCode, client.playerID}
+			if h.devices[key] == nil {
+				h.devices[key] = make(map[*Client]bool)
+			}
+			h.devices[key][client] = true
+			h.online[client.playerID]++
+			first := h.deviceCountLocked(client.gameCode, client.playerID) == 1
+			cameOnline := h.online[client.playerID] == 1
 			h.mu.Unlock()
 			log.Printf("WS: %s connected to game %s", client.playerID, client.gameCode)
-
-		case client := <-h.unregister:
-			h.mu.Lock()
-			if clients, ok := h.games[client.gameCode]; ok {
-				if _, ok := clients[client]; ok {
-					delete(clients, client)
-					close(client.send)
-					if len(clients) == 0 {
-						delete(h.games, client.gameCode)
-					}
+			if first {
+				if h.onGamePresence != nil {
+					h.onGamePresence(client.gameCode, client.playerID, true)
 				}
+				h.presenceChanged(client.gameCode, "player_connected")
 			}
-			h.mu.Unlock()
+			if cameOnline && h.onPresence != nil {
+				h.onPresence(client.playerID, client.gameCode, true)
+			}
+			if client.deltas {
+				h.deliverState(client.gameCode)
+			}
+
+		case client := <-h.unregister:
+			h.removeAndAnnounce(client)
 			log.Printf("WS: %s disconnected from game %s", client.playerID, client.gameCode)
 
 		case message := <-h.broadcast:
-			h.mu.RLock()
-			if clients, ok := h.games[message.GameCode]; ok {
-				for client := range clients {
-					select {
-					case client.send <- message.Message:
-					default:
-						close(client.send)
-						delete(clients, client)
-					}
-				}
+			h.deliver(message)
+		}
+	}
+}
+
+// deliver queues a message for its recipients, dropping clients that can't keep up.
+// Messages for the whole game are numbered and kept for reconnecting clients.
+func (h *Hub) deliver(message *GameMessage) {
+	if message.resync != nil {
+		message.resync.stateVersion = 0
+		h.deliverState(message.GameCode)
+		return
+	}
+	if message.close {
+		defer h.closeGame(message.GameCode)
+	}
+	// Delta clients get the new state before the signal announcing it
+	if message.refresh && message.only == nil && message.player == "" {
+		h.deliverState(message.GameCode)
+	}
+
+	if message.only == nil && message.player == "" && message.kind != "game_removed" {
+		h.mu.Lock()
+		message.Message = h.recordLocked(message.GameCode, message.Message)
+		h.mu.Unlock()
+	}
+
+	var dropped []*Client
+	h.mu.RLock()
+	recipients := h.games[message.GameCode]
+	if message.player != "" {
+		recipients = h.devices[deviceKey{message.GameCode, message.player}]
+	}
+	for client := range recipients {
+		if message.only != nil && !message.only(client) {
+			continue
+		}
+		if !client.wants(message.kind) {
+			continue
+		}
+		// Downgraded clients refetch the whole state on any refresh, so one queued message is enough
+		if client.stateOnly.Load() && (!message.refresh || len(client.send) > 0) {
+			continue
+		}
+		select {
+		case client.send <- message.Message:
+		default:
+			h.slow.overflows.Add(1)
+			if h.config.DowngradeAfter == 0 || client.strike() {
+				h.dropSlowClient(client, "send buffer full")
+				dropped = append(dropped, client)
 			}
-			h.mu.RUnlock()
 		}
 	}
+	h.mu.RUnlock()
+
+	for _, client := range dropped {
+		h.removeAndAnnounce(client)
+	}
+}
+
+// removeAndAnnounce detaches a client and announces the player leaving the game,
+// or going offline, if that was their last connection
+func (h *Hub) removeAndAnnounce(client *Client) {
+	lastInGame, wentOffline := h.remove(client)
+	if lastInGame {
+		if h.onGamePresence != nil {
+			h.onGamePresence(client.gameCode, client.playerID, false)
+		}
+		h.presenceChanged(client.gameCode, "player_disconnected")
+	}
+	if wentOffline && h.onPresence != nil {
+		h.onPresence(client.playerID, client.gameCode, false)
+	}
+}
+
+// remove detaches a client and closes its send channel. Reports whether it was
+// the player's last connection to the game, and to any game.
+func (h *Hub) remove(client *Client) (lastInGame, wentOffline bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	clients, ok := h.games[client.gameCode]
+	if !ok || !clients[client] {
+		return false, false
+	}
+	delete(clients, client)
+	close(client.send)
+	if len(clients) == 0 {
+		delete(h.games, client.gameCode)
+		delete(h.states, client.gameCode)
+	}
+	h.forgetLocked(client)
+	return h.deviceCountLocked(client.gameCode, client.playerID) == 0, h.online[client.playerID] == 0
+}
+
+// forgetLocked drops a closed client from the online counts and the player's
+// connections (caller must hold lock)
+func (h *Hub) forgetLocked(client *Client) {
+	if h.online[client.playerID]--; h.online[client.playerID] <= 0 {
+		delete(h.online, client.playerID)
+	}
+	key := deviceKey{client.gameCode, client.playerID}
+	if delete(h.devices[key], client); len(h.devices[key]) == 0 {
+		delete(h.devices, key)
+	}
+}
+
+// deviceCountLocked counts a player's connections to a game (caller must hold lock)
+func (h *Hub) deviceCountLocked(gameCode, playerID string) int {
+	return len(h.devices[deviceKey{gameCode, playerID}])
+}
+
+// presenceChanged tells a game's clients and sinks that someone came or went.
+// It delivers directly because it runs on the hub's own loop.
+func (h *Hub) presenceChanged(gameCode, hint string) {
+	h.deliver(h.refreshMessage(gameCode, hint, false))
+}
+
+// refreshMessage publishes a hint to the sinks and builds its refresh signal.
+// Both carry the turn clock while a turn is running.
+func (h *Hub) refreshMessage(gameCode, hint string, auto bool) *GameMessage {
+	event := RefreshEvent{Type: "refresh", Hint: hint, Auto: auto}
+	if clock, ok := h.currentTurnClock(gameCode); ok {
+		event.TurnClock = &clock
+		h.publish(gameCode, hint, clock)
+	} else {
+		h.publish(gameCode, hint, nil)
+	}
+	message, _ := json.Marshal(event)
+	return &GameMessage{
+		GameCode: gameCode,
+		Message:  message,
+		refresh:  true,
+		kind:     hint,
+	}
 }
 
 // BroadcastRefresh sends a simple refresh signal to all clients in a game
 func (h *Hub) BroadcastRefresh(gameCode string, hint string) {
-	event := RefreshEvent{
-		Type: "refresh",
-		Hint: hint,
+	h.broadcast <- h.refreshMessage(gameCode, hint, false)
+}
+
+// BroadcastAutoRefresh sends a refresh signal marked as an action the server
+// took for a player, so clients can animate it rather than wait for input
+func (h *Hub) BroadcastAutoRefresh(gameCode string, hint string) {
+	h.broadcast <- h.refreshMessage(gameCode, hint, true)
+}
+
+// SendToPlayer sends an event to every device a player has connected to a game,
+// and nobody else. Private events aren't numbered or kept for reconnecting clients.
+func (h *Hub) SendToPlayer(gameCode, playerID string, event interface{}) {
+	message, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	h.sendToPlayer(gameCode, playerID, message)
+}
+
+// sendToPlayer delivers a message to every device a player has connected to a game
+func (h *Hub) sendToPlayer(gameCode, playerID string, message []byte) {
+	h.broadcast <- &GameMessage{
+		GameCode: gameCode,
+		Message:  message,
+		player:   playerID,
+	}
+}
+
+// DeviceCounts returns how many devices each player or spectator has connected to a game.
+// Anyone listed is present.
+func (h *Hub) DeviceCounts(gameCode string) map[string]int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for client := range h.games[gameCode] {
+		counts[client.playerID]++
 	}
+	return counts
+}
+
+// Locales returns the distinct chat locales of the clients connected to a game
+func (h *Hub) Locales(gameCode string) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var locales []string
+	for client := range h.games[gameCode] {
+		if client.locale != "" && !seen[client.locale] {
+			seen[client.locale] = true
+			locales = append(locales, client.locale)
+		}
+	}
+	return locales
+}
+
+// ConnectedIDs returns the distinct player and spectator IDs connected to a game
+func (h *Hub) ConnectedIDs(gameCode string) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var ids []string
+	for client := range h.games[gameCode] {
+		if client.playerID != "" && !seen[client.playerID] {
+			seen[client.playerID] = true
+			ids = append(ids, client.playerID)
+		}
+	}
+	return ids
+}
+
+// ConnectionCount returns how many connections a game has, counting every device
+func (h *Hub) ConnectionCount(gameCode string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.games[gameCode])
+}
+
+// BroadcastEvent sends an arbitrary JSON event to all clients in a game
+func (h *Hub) BroadcastEvent(gameCode string, event interface{}) {
 	message, err := json.Marshal(event)
 	if err != nil {
-		log.Printf("Error marshaling refresh event: %v", err)
+		log.Printf("Error marshaling event: %v", err)
 		return
 	}
 
 	h.broadcast <- &GameMessage{
 		GameCode: gameCode,
 		Message:  message,
+		kind:     messageKind(message),
+	}
+}
+
+// CommentaryEvent carries one line of commentary to subscribed clients
+type CommentaryEvent struct {
+	Type string `json:"type"` // Always "commentary"
+	models.Commentary
+}
+
+// BroadcastCommentary sends commentary lines to the clients subscribed to the commentary channel
+func (h *Hub) BroadcastCommentary(gameCode string, lines []models.Commentary) {
+	for _, line := range lines {
+		h.publish(gameCode, string(line.Kind), line)
+
+		message, err := json.Marshal(CommentaryEvent{Type: "commentary", Commentary: line})
+		if err != nil {
+			log.Printf("Error marshaling commentary: %v", err)
+			continue
+		}
+
+		h.broadcast <- &GameMessage{
+			GameCode: gameCode,
+			Message:  message,
+			only:     func(c *Client) bool { return c.commentary.Load() },
+			kind:     string(line.Kind),
+		}
+	}
+}
+
+// BroadcastToAll sends a JSON event to every connected client in every game
+func (h *Hub) BroadcastToAll(event interface{}) {
+	message, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Error marshaling event: %v", err)
+		return
+	}
+
+	h.mu.RLock()
+	codes := make([]string, 0, len(h.games))
+	for code := range h.games {
+		codes = append(codes, code)
+	}
+	h.mu.RUnlock()
+
+	for _, code := range codes {
+		h.broadcast <- &GameMessage{
+			GameCode: code,
+			Message:  message,
+		}
+	}
+}
+
+// closeGame disconnects every client still attached to a game. It runs on the
+// hub's loop, after anything already queued for them.
+func (h *Hub) closeGame(gameCode string) {
+	h.mu.Lock()
+	var offline []string
+	for client := range h.games[gameCode] {
+		close(client.send)
+		h.forgetLocked(client)
+		if h.online[client.playerID] == 0 {
+			offline = append(offline, client.playerID)
+		}
+	}
+	delete(h.games, gameCode)
+	delete(h.eventLogs, gameCode)
+	delete(h.states, gameCode)
+	h.mu.Unlock()
+
+	if h.onPresence != nil {
+		for _, playerID := range offline {
+			h.onPresence(playerID, gameCode, false)
+		}
+	}
+}
+
+// NotifyGameRemoved tells clients a game no longer exists and disconnects them,
+// and tells sinks why it was removed. The hub does both in one step, so the event
+// is queued before the sockets close.
+func (h *Hub) NotifyGameRemoved(gameCode, reason string) {
+	h.publish(gameCode, "game_removed", map[string]string{"reason": reason})
+	event, _ := json.Marshal(RefreshEvent{Type: "refresh", Hint: "game_removed"})
+	h.broadcast <- &GameMessage{
+		GameCode: gameCode,
+		Message:  event,
+		refresh:  true,
+		kind:     "game_removed",
+		close:    true,
 	}
 }
 
@@ -133,6 +471,7 @@ func (h *Hub) BroadcastRefresh(gameCode string, hint string) {
 type WebSocketHandler struct {
 	hub         *Hub
 	gameManager *models.GameManager
+	commands    *Handler // Carries out game commands; nil ignores them
 }
 
 // NewWebSocketHandler creates a new WebSocket handler
@@ -154,13 +493,14 @@ func (wsh *WebSocketHandler) HandleWebSocket(w http.ResponseWriter, r *http.Requ
 	}
 
 	// Verify game exists and player is in it
-	game, err := wsh.gameManager.GetGame(gameCode)
+	game, err := wsh.gameManager.GetGame(r.Context(), gameCode)
 	if err != nil {
 		http.Error(w, "Game not found", http.StatusNotFound)
 		return
 	}
 
-	if _, exists := game.Players[playerID]; !exists {
+	_, isPlayer := game.Players[playerID]
+	if !isPlayer {
 		// Also allow spectators
 		if _, specExists := game.Spectators[playerID]; !specExists {
 			http.Error(w, "Player not in game", http.StatusForbidden)
@@ -168,38 +508,58 @@ func (wsh *WebSocketHandler) HandleWebSocket(w http.ResponseWriter, r *http.Requ
 		}
 	}
 
-	conn, err := upgrader.Upgrade(w, r, nil)
+	conn, err := gameUpgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("WebSocket upgrade error: %v", err)
 		return
 	}
 
+	// Players are always let in so a crowd of spectators can't lock them out of their own game.
+	// Browsers can't read an HTTP error on a WebSocket handshake, so the reason goes in a close frame.
+	if limit := wsh.hub.config.MaxPerGame; limit > 0 && !isPlayer && wsh.hub.ConnectionCount(gameCode) >= limit {
+		reason := fmt.Sprintf("game connection limit reached (%d)", limit)
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, reason), time.Now().Add(time.Second))
+		conn.Close()
+		log.Printf("WS: rejected %s from game %s, %s", playerID, gameCode, reason)
+		return
+	}
+
+	locale := r.URL.Query().Get("locale")
+	if locale == "" {
+		locale = wsh.gameManager.Profiles().Get(playerID).Locale
+	}
+
 	client := &Client{
 		hub:      wsh.hub,
 		conn:     conn,
 		send:     make(chan []byte, 256),
 		gameCode: gameCode,
 		playerID: playerID,
+		locale:   models.NormalizeLocale(locale),
+		deltas:   r.URL.Query().Get("updates") == "delta",
+		codec:    codecFor(conn, r),
+	}
+	client.commentary.Store(r.URL.Query().Get("commentary") == "true")
+	if events := r.URL.Query().Get("events"); events != "" {
+		client.setEventFilter(strings.Split(events, ","))
 	}
 
+	// The hub tells the others if this is the player's first device
 	wsh.hub.register <- client
 
-	// Notify others that someone connected (they should refresh)
-	wsh.hub.BroadcastRefresh(gameCode, "player_connected")
-
 	go client.writePump()
 	go client.readPump(wsh)
 }
 
-// readPump handles incoming messages (just ping/pong)
+// readPump handles incoming messages: pings, subscriptions and game commands
 func (c *Client) readPump(wsh *WebSocketHandler) {
 	defer func() {
-		// Notify others on disconnect
-		wsh.hub.BroadcastRefresh(c.gameCode, "player_disconnected")
+		// The hub tells the others if this was the player's last device
 		c.hub.unregister <- c
 		c.conn.Close()
 	}()
 
+	pongWait := c.hub.config.PongWait
 	c.conn.SetReadLimit(maxMessageSize)
 	c.conn.SetReadDeadline(time.Now().Add(pongWait))
 	c.conn.SetPongHandler(func(string) error {
@@ -208,28 +568,71 @@ func (c *Client) readPump(wsh *WebSocketHandler) {
 	})
 
 	for {
-		_, message, err := c.conn.ReadMessage()
+		frameType, frame, err := c.conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				log.Printf("WebSocket error: %v", err)
 			}
 			break
 		}
+		message, err := decodeFrame(frameType, frame)
+		if err != nil {
+			continue
+		}
 
-		// Handle ping from client
+		// Handle ping, chat read markers, channel subscriptions and commands from client
 		var msg map[string]interface{}
 		if err := json.Unmarshal(message, &msg); err == nil {
-			if msg["type"] == "ping" {
+			messageType, _ := msg["type"].(string)
+			if isCommand(messageType) {
+				c.runCommand(wsh, message)
+				continue
+			}
+			switch messageType {
+			case "ping":
 				response, _ := json.Marshal(map[string]string{"type": "pong"})
-				c.send <- response
+				c.reply(response)
+			case "chat_read":
+				c.markChatRead(wsh, msg)
+			case "sync":
+				c.resync()
+			case "subscribe", "unsubscribe":
+				if msg["channel"] == "commentary" {
+					c.commentary.Store(msg["type"] == "subscribe")
+				}
+				if events, ok := msg["events"].([]interface{}); ok && msg["type"] == "subscribe" {
+					c.subscribeEvents(events)
+				}
+			default:
+				if requestID, ok := msg["request_id"].(string); ok {
+					c.rejectCommand(messageType, requestID)
+				}
 			}
 		}
 	}
 }
 
+// reply answers something the client sent. The hub may close the send channel at
+// any time, so the reply is only queued under the hub's lock while the client is
+// still attached; a client with a full buffer misses it.
+func (c *Client) reply(message []byte) {
+	h := c.hub
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if !h.games[c.gameCode][c] {
+		return
+	}
+	select {
+	case c.send <- message:
+	default:
+		h.slow.overflows.Add(1)
+	}
+}
+
 // writePump sends messages to the client
 func (c *Client) writePump() {
-	ticker := time.NewTicker(pingPeriod)
+	ticker := time.NewTicker(c.hub.config.pingPeriod())
 	defer func() {
 		ticker.Stop()
 		c.conn.Close()
@@ -238,21 +641,46 @@ func (c *Client) writePump() {
 	for {
 		select {
 		case message, ok := <-c.send:
-			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
 			if !ok {
-				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				c.conn.SetWriteDeadline(time.Now().Add(c.hub.config.WriteWait))
+				c.conn.WriteMessage(websocket.CloseMessage, c.hub.closeMessage())
 				return
 			}
 
-			if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+			frame, err := c.codec.encode(message)
+			if err != nil {
+				log.Printf("WS: could not encode message for %s: %v", c.playerID, err)
+				continue
+			}
+			if !c.write(c.codec.frameType(), frame) {
 				return
 			}
 
 		case <-ticker.C:
-			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
-			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+			if !c.write(websocket.PingMessage, nil) {
 				return
 			}
 		}
 	}
 }
+
+// markChatRead moves the client's chat read marker to the optional read_at timestamp
+func (c *Client) markChatRead(wsh *WebSocketHandler, msg map[string]interface{}) {
+	game, err := wsh.gameManager.GetGame(context.Background(), c.gameCode)
+	if err != nil {
+		return
+	}
+
+	var readAt time.Time
+	if s, ok := msg["read_at"].(string); ok {
+		readAt, _ = time.Parse(time.RFC3339Nano, s)
+	}
+	unread, err := game.MarkChatRead(c.playerID, readAt)
+	if err != nil {
+		return
+	}
+
+	// Keep the unread count in step on all of the player's devices
+	response, _ := json.Marshal(map[string]interface{}{"type": "chat_unread", "unread_chat": unread})
+	c.hub.sendToPlayer(c.gameCode, c.playerID, response)
+}
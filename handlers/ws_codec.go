@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// WebSocket encodings a client can pick with ?encoding= or a subprotocol
+const (
+	EncodingJSON    = "json"
+	EncodingMsgpack = "msgpack"
+)
+
+// wsCodec turns the JSON messages the hub queues into a client's wire format
+type wsCodec interface {
+	frameType() int                        // Frame type messages are written in
+	encode(message []byte) ([]byte, error) // From JSON to the wire format
+}
+
+// jsonCodec sends messages as they are, in text frames
+type jsonCodec struct{}
+
+func (jsonCodec) frameType() int { return websocket.TextMessage }
+
+func (jsonCodec) encode(message []byte) ([]byte, error) { return message, nil }
+
+// msgpackCodec sends messages as MessagePack, in binary frames
+type msgpackCodec struct{}
+
+func (msgpackCodec) frameType() int { return websocket.BinaryMessage }
+
+func (msgpackCodec) encode(message []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(message))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := msgpack.NewEncoder(&buf)
+	encoder.UseCompactInts(true)
+	encoder.UseCompactFloats(true)
+	err := encoder.Encode(msgpackValue(value))
+	return buf.Bytes(), err
+}
+
+// msgpackValue turns JSON numbers into integers where they are whole, so they
+// don't all go out as floats
+func msgpackValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, field := range v {
+			v[key] = msgpackValue(field)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = msgpackValue(item)
+		}
+	}
+	return value
+}
+
+// gameUpgrader upgrades /ws connections, offering each encoding as a subprotocol.
+// Clients offering both get MessagePack.
+var gameUpgrader = func() websocket.Upgrader {
+	u := upgrader
+	u.Subprotocols = []string{EncodingMsgpack, EncodingJSON}
+	return u
+}()
+
+// codecFor picks the encoding a client asked for: the negotiated subprotocol,
+// else ?encoding=, else JSON
+func codecFor(conn *websocket.Conn, r *http.Request) wsCodec {
+	encoding := conn.Subprotocol()
+	if encoding == "" {
+		encoding = r.URL.Query().Get("encoding")
+	}
+	if encoding == EncodingMsgpack {
+		return msgpackCodec{}
+	}
+	return jsonCodec{}
+}
+
+// decodeFrame reads a client message as JSON. Binary frames are MessagePack,
+// whatever the client's encoding; text frames are JSON.
+func decodeFrame(frameType int, data []byte) ([]byte, error) {
+	if frameType != websocket.BinaryMessage {
+		return data, nil
+	}
+	var value interface{}
+	if err := msgpack.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
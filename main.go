@@ -1,234 +1,781 @@
-package main
-
-import (
-	"encoding/json"
-	"flag"
-	"log"
-	"net/http"
-	"os"
-	"time"
-
-	"github.com/aminearbi/ludo-nadwa-server/handlers"
-	"github.com/aminearbi/ludo-nadwa-server/models"
-)
-
-func main() {
-	// Parse command line flags
-	portFlag := flag.String("port", "", "Port to run the server on (default: 8080)")
-	flag.Parse()
-
-	// Create game manager
-	gameManager := models.NewGameManager()
-
-	// Create WebSocket hub and start it
-	hub := handlers.NewHub()
-	go hub.Run()
-
-	// Create handlers
-	handler := handlers.NewHandler(gameManager)
-	handler.SetHub(hub)
-
-	wsHandler := handlers.NewWebSocketHandler(hub, gameManager)
-
-	// Start cleanup goroutine
-	go startCleanupRoutine(gameManager, hub)
-
-	// Start turn timeout checker
-	go startTurnTimeoutChecker(gameManager, hub)
-
-	// Start bot turn handler
-	go startBotTurnHandler(gameManager, hub)
-
-	// Register REST API routes
-	http.HandleFunc("/api/game/create", corsMiddleware(handler.CreateGame))
-	http.HandleFunc("/api/game/join", corsMiddleware(handler.JoinGame))
-	http.HandleFunc("/api/game/start", corsMiddleware(handler.StartGame))
-	http.HandleFunc("/api/game/state", corsMiddleware(handler.GetGameState))
-	http.HandleFunc("/api/game/roll", corsMiddleware(handler.RollDice))
-	http.HandleFunc("/api/game/move", corsMiddleware(handler.MovePiece))
-	http.HandleFunc("/api/game/skip", corsMiddleware(handler.SkipTurn))
-	
-	// New endpoints
-	http.HandleFunc("/api/game/ready", corsMiddleware(handler.SetReady))
-	http.HandleFunc("/api/game/kick", corsMiddleware(handler.KickPlayer))
-	http.HandleFunc("/api/game/leave", corsMiddleware(handler.LeaveGame))
-	http.HandleFunc("/api/game/pause", corsMiddleware(handler.PauseGame))
-	http.HandleFunc("/api/game/resume", corsMiddleware(handler.ResumeGame))
-	http.HandleFunc("/api/game/chat", corsMiddleware(handler.SendChat))
-	http.HandleFunc("/api/game/spectate", corsMiddleware(handler.JoinAsSpectator))
-	http.HandleFunc("/api/game/rematch", corsMiddleware(handler.Rematch))
-	http.HandleFunc("/api/game/history", corsMiddleware(handler.GetMoveHistory))
-	http.HandleFunc("/api/game/chat/history", corsMiddleware(handler.GetChat))
-	
-	// Bot endpoints
-	http.HandleFunc("/api/game/bot/add", corsMiddleware(handler.AddBot))
-	http.HandleFunc("/api/game/bot/remove", corsMiddleware(handler.RemoveBot))
-
-	// WebSocket endpoint
-	http.HandleFunc("/ws", wsHandler.HandleWebSocket)
-
-	// Stats endpoint
-	http.HandleFunc("/api/stats", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(gameManager.GetGameStats())
-	}))
-
-	// Health check endpoint
-	http.HandleFunc("/health", corsMiddleware(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	}))
-
-	// Serve static web files
-	fs := http.FileServer(http.Dir("./web"))
-	http.Handle("/", fs)
-
-	// Get port from flag, environment, or use default
-	port := *portFlag
-	if port == "" {
-		port = os.Getenv("PORT")
-	}
-	if port == "" {
-		port = "8080"
-	}
-
-	log.Printf("Ludo Nadwa Server starting on port %s", port)
-	log.Printf("Endpoints:")
-	log.Printf("  POST   /api/game/create       - Create a new game (host)")
-	log.Printf("  POST   /api/game/join         - Join an existing game")
-	log.Printf("  POST   /api/game/start        - Start a game (host only)")
-	log.Printf("  GET    /api/game/state        - Get game state")
-	log.Printf("  POST   /api/game/roll         - Roll the dice")
-	log.Printf("  POST   /api/game/move         - Move a piece")
-	log.Printf("  POST   /api/game/skip         - Skip turn (when no valid moves)")
-	log.Printf("  POST   /api/game/ready        - Set player ready status")
-	log.Printf("  POST   /api/game/kick         - Kick a player (host only)")
-	log.Printf("  POST   /api/game/leave        - Leave a game")
-	log.Printf("  POST   /api/game/pause        - Pause a game")
-	log.Printf("  POST   /api/game/resume       - Resume a paused game")
-	log.Printf("  POST   /api/game/chat         - Send a chat message")
-	log.Printf("  GET    /api/game/chat/history - Get chat history")
-	log.Printf("  POST   /api/game/spectate     - Join as spectator")
-	log.Printf("  POST   /api/game/rematch      - Request a rematch (host only)")
-	log.Printf("  GET    /api/game/history      - Get move history")
-	log.Printf("  WS     /ws                    - WebSocket connection")
-	log.Printf("  GET    /api/stats             - Server statistics")
-	log.Printf("  GET    /health                - Health check")
-	log.Printf("  GET    /                      - Web interface")
-	log.Printf("")
-	log.Printf("🎲 Open http://localhost:%s in your browser to play!", port)
-
-	if err := http.ListenAndServe("0.0.0.0:"+port, nil); err != nil {
-		log.Fatal(err)
-	}
-}
-
-// startCleanupRoutine periodically cleans up abandoned games
-func startCleanupRoutine(gm *models.GameManager, hub *handlers.Hub) {
-	ticker := time.NewTicker(models.CleanupInterval)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		removed := gm.CleanupAbandonedGames()
-		if len(removed) > 0 {
-			log.Printf("Cleaned up %d abandoned games: %v", len(removed), removed)
-		}
-	}
-}
-
-// startTurnTimeoutChecker checks for turn timeouts and auto-skips
-func startTurnTimeoutChecker(gm *models.GameManager, hub *handlers.Hub) {
-	ticker := time.NewTicker(5 * time.Second)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		games := gm.GetAllGames()
-		for _, game := range games {
-			if game.IsTurnTimedOut() {
-				skippedPlayer := game.ForceSkipTurn()
-				if skippedPlayer != "" {
-					log.Printf("Turn timeout for player %s in game %s", skippedPlayer, game.Code)
-					hub.BroadcastRefresh(game.Code, "turn_timeout")
-				}
-			}
-		}
-	}
-}
-
-// startBotTurnHandler checks if it's a bot's turn and plays automatically
-func startBotTurnHandler(gm *models.GameManager, hub *handlers.Hub) {
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		games := gm.GetAllGames()
-		for _, game := range games {
-			if game.IsCurrentPlayerBot() {
-				handleBotTurn(game, hub)
-			}
-		}
-	}
-}
-
-// handleBotTurn plays a turn for the bot
-func handleBotTurn(game *models.Game, hub *handlers.Hub) {
-	gameState := game.GetGameState()
-	currentTurn := gameState["current_turn"].(string)
-	hasRolled := gameState["has_rolled"].(bool)
-	
-	// If bot hasn't rolled yet, roll the dice
-	if !hasRolled {
-		_, err := game.RollDice(currentTurn)
-		if err != nil {
-			if err == models.ErrThreeSixes {
-				// Three sixes - turn is forfeited, broadcast and return
-				hub.BroadcastRefresh(game.Code, "dice_rolled")
-			}
-			return
-		}
-		
-		hub.BroadcastRefresh(game.Code, "dice_rolled")
-		
-		// Small delay before moving to make it feel more natural
-		time.Sleep(500 * time.Millisecond)
-	}
-	
-	// Check for valid move and make it
-	pieceID, hasMove := game.GetBotMove()
-	if hasMove {
-		if err := game.MovePiece(currentTurn, pieceID); err != nil {
-			// No valid moves, skip turn
-			game.SkipTurn(currentTurn)
-			hub.BroadcastRefresh(game.Code, "turn_skipped")
-			return
-		}
-		
-		hub.BroadcastRefresh(game.Code, "piece_moved")
-	} else {
-		// No valid moves, skip turn
-		game.SkipTurn(currentTurn)
-		hub.BroadcastRefresh(game.Code, "turn_skipped")
-	}
-}
-
-// corsMiddleware adds CORS headers to allow cross-origin requests
-func corsMiddleware(next http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		// Set CORS headers
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-		// Handle preflight requests
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
-
-		next(w, r)
-	}
-}
+package main
+
+import (
+	"context"
+	"embed"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/aminearbi/ludo-nadwa-server/handlers"
+	"github.com/aminearbi/ludo-nadwa-server/models"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// webFiles holds the web client so the binary is self-contained
+//
+//go:embed web
+var webFiles embed.FS
+
+// Deadlines for API requests, for saving or loading a snapshot, and for
+// in-flight requests to finish on shutdown
+const (
+	defaultRequestTimeout = 10 * time.Second
+	snapshotTimeout       = 30 * time.Second
+	shutdownTimeout       = 15 * time.Second
+	defaultShutdownGrace  = 5 * time.Second
+	defaultBotTick        = time.Second
+)
+
+func main() {
+	// Settings come from flags, the environment and an optional config file
+	cfg, err := loadConfig(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Invalid config: %v", err)
+	}
+
+	// Create game manager
+	gameManager := models.NewGameManager()
+	if err := gameManager.SetConfig(models.ManagerConfig{
+		TurnTimeout:   cfg.TurnTimeout,
+		GameTTL:       cfg.GameTTL,
+		InactivityTTL: cfg.InactivityTTL,
+		RestoreWindow: cfg.RestoreWindow,
+		MaxGames:      cfg.MaxGames,
+		MaxPlayers:    cfg.MaxPlayers,
+	}); err != nil {
+		log.Fatalf("Invalid game settings: %v", err)
+	}
+
+	// Persist games to storage, so they survive a crash as well as a restart
+	storageSpec := cfg.Storage
+	var storage models.Storage
+	if storageSpec != "" {
+		var err error
+		storage, err = models.OpenStorage(storageSpec)
+		if err != nil {
+			log.Fatalf("Could not open storage %s: %v", storageSpec, err)
+		}
+		gameManager.OnStorageError(func(code string, err error) {
+			log.Printf("Could not persist game %s: %v", code, err)
+		})
+		gameManager.SetStorage(storage)
+	}
+
+	// Archive finished games long term, beside stored games unless sent elsewhere
+	var archiveSink models.ArchiveSink
+	if cfg.Archive != "" {
+		sink, err := models.OpenArchiveSink(cfg.Archive)
+		if err != nil {
+			log.Fatalf("Could not open archive %s: %v", cfg.Archive, err)
+		}
+		archiveSink = sink
+	} else if ns, ok := storage.(models.NamespacedStorage); ok {
+		archived, err := ns.Namespace(models.ArchiveNamespace)
+		if err != nil {
+			log.Fatalf("Could not open the archive in storage %s: %v", storageSpec, err)
+		}
+		archiveSink = models.StorageArchive(archived)
+	}
+	if archiveSink != nil {
+		gameManager.Archive().SetSink(archiveSink, cfg.ArchiveRetention, func(code string, err error) {
+			log.Printf("Could not archive game %s: %v", code, err)
+		})
+	}
+
+	// Disconnected players' turns time out early once the grace runs out
+	if cfg.ReconnectGrace != 0 {
+		gameManager.SetReconnectGrace(cfg.ReconnectGrace)
+	}
+
+	// Rehydrate games saved by a coordinated restart
+	snapshotFile := cfg.SnapshotFile
+	if snapshotFile == "" {
+		snapshotFile = "ludo-snapshot.json"
+	}
+	loadSnapshot(gameManager, snapshotFile)
+	if storageSpec != "" {
+		loadStorage(gameManager, storageSpec)
+	}
+
+	// Create WebSocket hub and start it
+	hub := handlers.NewHub()
+	wsConfig := handlers.DefaultWebSocketConfig()
+	wsConfig.WriteWait = max(cfg.WSWriteWait, 0)
+	wsConfig.PongWait = max(cfg.WSPongWait, 0)
+	wsConfig.SlowWrite = 0 // A quarter of whatever WriteWait ends up being
+	if cfg.WSMaxPerGame > 0 {
+		wsConfig.MaxPerGame = cfg.WSMaxPerGame
+	}
+	if cfg.WSEventBuffer > 0 {
+		wsConfig.EventBuffer = cfg.WSEventBuffer
+	}
+	if cfg.WSDowngradeSlow {
+		wsConfig.DowngradeAfter = handlers.DefaultDowngradeAfter
+	}
+	hub.SetConfig(wsConfig)
+	hub.SetTurnClock(handlers.TurnClocks(gameManager))
+	hub.SetStateSource(handlers.GameStates(gameManager))
+	hub.OnGamePresence(handlers.TrackDisconnects(gameManager))
+	go hub.Run()
+
+	// Create handlers
+	handler := handlers.NewHandler(gameManager)
+	handler.SetHub(hub)
+	handler.SetStrictSigning(cfg.StrictSigning)
+	handler.SetDebug(cfg.Debug)
+	handler.SetAdminToken(cfg.AdminToken)
+
+	apiKeys, err := handlers.NewAPIKeyStore(cfg.APIKeysFile)
+	if err != nil {
+		log.Fatalf("Failed to load API keys: %v", err)
+	}
+	handler.SetAPIKeys(apiKeys)
+
+	// Admin roles for identities asserted by an OAuth proxy in front of the server
+	identityRoles, err := handlers.ParseIdentityRoles(cfg.AdminRoles)
+	if err != nil {
+		log.Fatalf("Invalid admin roles: %v", err)
+	}
+	handler.SetIdentityRoles(cfg.IdentityHeader, identityRoles)
+
+	handler.SetRestartHook(func() {
+		restartServer(gameManager, snapshotFile, cfg.RestartExec)
+	})
+
+	// Translate chat for mixed-language rooms when a provider is configured
+	if cfg.TranslateURL != "" {
+		handler.SetTranslator(handlers.NewLibreTranslator(cfg.TranslateURL, cfg.TranslateAPIKey))
+	}
+
+	// Mask blocked words in chat
+	gameManager.SetChatFilter(models.NewChatFilter(splitList(cfg.ChatFilter)))
+
+	// Browser origins allowed to use the API and WebSockets
+	origins, err := handlers.ParseCORSOrigins(cfg.CORSOrigins)
+	if err != nil {
+		log.Fatalf("Invalid CORS origins: %v", err)
+	}
+	if err := handlers.SetCORSPolicy(handlers.CORSPolicy{Origins: origins, Credentials: cfg.CORSCredentials}); err != nil {
+		log.Fatalf("Invalid CORS policy: %v", err)
+	}
+	if len(origins) > 0 {
+		log.Printf("Allowing browser origins: %s", strings.Join(origins, ", "))
+	}
+
+	// Hosts that webhooks and bot callbacks registered by users may be sent to
+	handlers.SetOutboundHosts(splitList(cfg.OutboundHosts))
+
+	wsHandler := handlers.NewWebSocketHandler(hub, gameManager)
+	wsHandler.SetCommands(handler)
+
+	// Deliver game events to per-game and server-wide webhooks
+	gameWebhooks := handlers.NewGameWebhooks()
+	handler.SetGameWebhooks(gameWebhooks)
+	hub.AddSink(gameWebhooks)
+
+	// Send signed events from every game to server-wide webhooks
+	if cfg.EventWebhooks != "" {
+		eventTypes := splitList(cfg.EventWebhookEvents)
+		if len(eventTypes) == 0 {
+			eventTypes = handlers.DefaultGlobalWebhookEvents
+		}
+		for _, url := range splitList(cfg.EventWebhooks) {
+			if _, err := gameWebhooks.RegisterGlobal(url, eventTypes, cfg.EventWebhookSecret); err != nil {
+				log.Fatalf("Invalid event webhook %s: %v", url, err)
+			}
+		}
+	}
+
+	// Sample per-game metrics for the admin API
+	gameMetrics := handlers.NewGameMetrics(gameManager, hub, handlers.DefaultMetricsInterval)
+	handler.SetGameMetrics(gameMetrics)
+	hub.AddSink(gameMetrics)
+	gameMetrics.Start()
+
+	// Stream public game list changes to lobby sockets
+	lobby := handlers.NewLobby(gameManager, hub)
+	hub.AddSink(lobby)
+
+	// Tell players when their friends come online or start a game
+	friendPresence := handlers.NewFriendPresence(gameManager, hub)
+	hub.OnPresenceChanged(friendPresence.PresenceChanged)
+	hub.AddSink(friendPresence)
+
+	// Pause games nobody is connected to instead of timing out their turns
+	hub.AddSink(handlers.NewAutoPause(gameManager, hub))
+
+	// Roll and play lone moves for players who opted in as soon as they can
+	hub.AddSink(handlers.NewAutoTurns(gameManager, hub))
+
+	// Mirror game events to MQTT for devices that don't speak WebSocket
+	if cfg.MQTTURL != "" {
+		bridge, err := handlers.NewMQTTBridge(cfg.MQTTURL, cfg.MQTTPrefix)
+		if err != nil {
+			log.Fatalf("Invalid MQTT URL: %v", err)
+		}
+		bridge.Start()
+		hub.AddSink(bridge)
+	}
+
+	// Stream every game event to Kafka or NATS JetStream, keyed by game code
+	if cfg.EventStream != "" {
+		sink, err := handlers.NewStreamSinkFromURL(cfg.EventStream)
+		if err != nil {
+			log.Fatalf("Invalid event stream URL: %v", err)
+		}
+		hub.AddSink(sink)
+	}
+	gameManager.OnGameRemoved(func(code, reason string) {
+		// Tell clients and webhooks, the game's own included, before dropping its webhooks
+		hub.NotifyGameRemoved(code, reason)
+		gameWebhooks.RemoveGame(code)
+		gameMetrics.RemoveGame(code)
+	})
+
+	// Start cleanup goroutine
+	cleanupInterval := cfg.CleanupInterval
+	if cleanupInterval <= 0 {
+		cleanupInterval = models.CleanupInterval
+	}
+	go startCleanupRoutine(gameManager, hub, cleanupInterval)
+
+	// Start turn timeout checker
+	go startTurnTimeoutChecker(gameManager, hub)
+	go startTurnWarnings(gameManager, hub)
+
+	// Count down the end of each turn if enabled
+	if cfg.TurnCountdown {
+		go startTurnCountdown(gameManager, hub)
+	}
+
+	// Start bot turn handler
+	botTick := cfg.BotTick
+	if botTick <= 0 {
+		botTick = defaultBotTick
+	}
+	go startBotTurnHandler(gameManager, hub, botTick)
+
+	// Roll and skip for players in games with automated turns
+	go startAutoTurnHandler(gameManager, hub, botTick)
+
+	// Serve the web client from disk if configured, otherwise the embedded copy
+	webDir := cfg.WebRoot
+	var webRoot fs.FS
+	if webDir != "" {
+		log.Printf("Serving web client from %s", webDir)
+		webRoot = os.DirFS(webDir)
+	} else {
+		embedded, err := fs.Sub(webFiles, "web")
+		if err != nil {
+			log.Fatalf("Failed to load embedded web client: %v", err)
+		}
+		webRoot = embedded
+	}
+	// Give up on API requests that outlive their deadline
+	requestTimeout := cfg.RequestTimeout
+	if requestTimeout <= 0 {
+		requestTimeout = defaultRequestTimeout
+	}
+	router := newRouter(handler, wsHandler, gameManager, webRoot, lobby, requestTimeout)
+	if cfg.LogLevel == LogDebug {
+		router = middleware.Logger(router)
+	}
+
+	port := cfg.Port
+	if port == "" && (cfg.TLSCert != "" || cfg.TLSDomains != "") {
+		port = "443"
+	}
+	if port == "" {
+		port = "8080"
+	}
+
+	// Serve HTTPS directly when given a certificate or domains
+	tlsConfig, redirect, err := serverTLS(cfg, port)
+	if err != nil {
+		log.Fatalf("Invalid TLS settings: %v", err)
+	}
+	scheme := "http"
+	if tlsConfig != nil {
+		scheme = "https"
+		redirectPort := cfg.HTTPRedirectPort
+		if redirectPort == "" && cfg.TLSDomains != "" {
+			redirectPort = "80" // Let's Encrypt's HTTP-01 challenges come here
+		}
+		if redirectPort != "" && redirectPort != "off" {
+			log.Printf("Redirecting HTTP on port %s to HTTPS", redirectPort)
+			go serveRedirects(redirectPort, redirect)
+		}
+	}
+
+	log.Printf("Ludo Nadwa Server starting on port %s (%s)", port, scheme)
+	log.Printf("Endpoints (also served unversioned under /api until clients move to /api/v1):")
+	log.Printf("  POST   /api/v1/game/create    - Create a new game (host)")
+	log.Printf("  POST   /api/v1/game/join      - Join an existing game")
+	log.Printf("  POST   /api/v1/game/start     - Start a game (host only)")
+	log.Printf("  GET    /api/v1/game/state     - Get game state")
+	log.Printf("  GET    /api/v1/game/preview-move - Where a piece would land with the current roll")
+	log.Printf("  POST   /api/v1/game/roll      - Roll the dice")
+	log.Printf("  POST   /api/v1/game/roll/ack  - Report a dice animation finished (two-phase rolls)")
+	log.Printf("  POST   /api/v1/game/move      - Move a piece")
+	log.Printf("  POST   /api/v1/game/skip      - Skip turn (when no valid moves)")
+	log.Printf("  POST   /api/v1/game/ready     - Set player ready status")
+	log.Printf("  POST   /api/v1/game/color     - Change color while waiting")
+	log.Printf("  POST   /api/v1/game/kick      - Kick a player (host only)")
+	log.Printf("  POST   /api/v1/game/leave     - Leave a game")
+	log.Printf("  POST   /api/v1/game/pause     - Pause a game (uses one of the player's pauses)")
+	log.Printf("  POST   /api/v1/game/pause/vote   - Vote on a pause longer than the game's limit")
+	log.Printf("  POST   /api/v1/game/resume    - Resume a paused game")
+	log.Printf("  POST   /api/v1/game/chat      - Send a chat message")
+	log.Printf("  GET    /api/v1/game/chat/history - Get chat history")
+	log.Printf("  POST   /api/v1/game/chat/read - Mark chat read up to a timestamp")
+	log.Printf("  POST   /api/v1/game/emote     - Send a quick-chat emote")
+	log.Printf("  POST   /api/v1/game/chat/delete  - Delete a chat message (host only)")
+	log.Printf("  POST   /api/v1/game/mute      - Mute a player or spectator in chat (host only)")
+	log.Printf("  GET    /api/v1/game/commentary   - Recent commentary on notable plays")
+	log.Printf("  GET/POST /api/v1/game/webhooks   - List or register per-game webhooks (host only)")
+	log.Printf("  POST   /api/v1/game/webhooks/delete - Remove a per-game webhook (host only)")
+	log.Printf("  GET    /api/v1/game/webhooks/deliveries - Webhook delivery log (host only)")
+	log.Printf("  POST   /api/v1/game/spectate  - Join as spectator")
+	log.Printf("  POST   /api/v1/game/rematch   - Request a rematch (host only)")
+	log.Printf("  GET    /api/v1/game/history   - Get move history")
+	log.Printf("  POST   /api/v1/game/restore   - Restore a cleaned-up game (host only)")
+	log.Printf("  GET    /api/v1/game/replay    - Board reconstructed after ?move=N events")
+	log.Printf("  GET    /api/v1/game/replay/verify - Re-simulate and verify move history")
+	log.Printf("  GET    /api/v1/game/export    - Whole game record as JSON or PGN-like text (?format=)")
+	log.Printf("  GET    /api/v1/games/{code}   - Game state (also /moves, /chat, /commentary, /replay, /replay/verify, /export)")
+	log.Printf("  POST   /api/v1/game/bot/fill  - Fill empty seats with bots (host only)")
+	log.Printf("  POST   /api/v1/game/bot/takeover - Ask the host for a bot's seat as a human player")
+	log.Printf("  POST   /api/v1/game/bot/takeover/approve - Let a player take the bot seat they asked for (host only)")
+	log.Printf("  POST   /api/v1/game/bot/takeover/decline - Turn down a request for a bot seat (host only)")
+	log.Printf("  POST   /api/v1/game/bot/claim - Hand a bot seat to an external AI (host only)")
+	log.Printf("  POST   /api/v1/game/bot/stand-in - Have a bot play for a disconnected player (host only)")
+	log.Printf("  POST   /api/v1/game/bot/act   - Roll/move/skip for a claimed bot seat")
+	log.Printf("  POST   /api/v1/game/bot/fast-forward - Finish a bot-only game instantly (host only)")
+	log.Printf("  WS     /ws                    - WebSocket connection")
+	log.Printf("  POST   /api/v1/game/reconnect - Full state plus events missed since a dropped connection")
+	log.Printf("  GET    /api/v1/game/events    - Buffered events after a sequence number")
+	log.Printf("  WS     /ws/lobby              - Live public game list")
+	log.Printf("  WS     /ws/replay             - Play a game's history back with its original timing")
+	log.Printf("  GET    /api/v1/lobby          - Public games waiting for players")
+	log.Printf("  POST   /api/v1/game/visibility   - List or unlist a game in the lobby (host only)")
+	log.Printf("  POST   /api/v1/game/password  - Set or remove the game's join password (host only)")
+	log.Printf("  GET/POST /api/v1/friends      - List friends with presence and requests, or ask a player to be friends")
+	log.Printf("  POST   /api/v1/friends/remove - Remove a friend or decline a request")
+	log.Printf("  GET    /api/v1/friends/code   - A player's friend code, for adding them without their ID")
+	log.Printf("  GET/POST /api/v1/invites      - List invites waiting for a player, or invite a friend to your game")
+	log.Printf("  POST   /api/v1/invites/accept - Join the game an invite is for (also /decline)")
+	log.Printf("  GET    /api/v1/version        - Server build info and protocol version")
+	log.Printf("  GET    /api/v1/openapi.json   - OpenAPI document of every endpoint, error code and event")
+	log.Printf("  GET    /api/v1/time           - Server clock for clock-skew correction")
+	log.Printf("  GET    /api/v1/board          - Board geometry (?type=square|hex, ?palette=)")
+	log.Printf("  GET    /api/v1/palettes       - Color palettes, including color-blind safe ones")
+	log.Printf("  GET    /api/v1/emotes         - Emotes clients can send")
+	log.Printf("  POST   /api/v1/game/palette   - Pick your palette, or the game's (host)")
+	log.Printf("  POST   /api/v1/game/auto-roll - Roll automatically when your turn starts")
+	log.Printf("  POST   /api/v1/game/auto-move - Play automatically when only one piece can move")
+	log.Printf("  POST   /api/v1/game/turn-timeout - Set the turn length, or untimed turns (host only)")
+	log.Printf("  POST   /api/v1/game/transfer-host - Hand the host role to another player (host only)")
+	log.Printf("  POST   /api/v1/game/premove   - Queue a move for your next turn")
+	log.Printf("  GET    /api/v1/themes         - Board theme and piece skin manifest")
+	log.Printf("  GET/POST /api/v1/profile/theme   - Get or save a player's theme choice")
+	log.Printf("  POST   /api/v1/player/name    - Change a player's name in all their games")
+	log.Printf("  GET    /api/v1/announcements  - Active server announcements")
+	log.Printf("  POST   /api/v1/admin/announce - Broadcast an announcement to all games (moderator)")
+	log.Printf("  GET    /api/v1/maintenance    - Current maintenance window")
+	log.Printf("  POST   /api/v1/admin/maintenance - Start or end maintenance mode (operator)")
+	log.Printf("  POST   /api/v1/admin/restart  - Drain, save games and restart when idle (operator)")
+	log.Printf("  GET    /api/v1/admin/metrics  - Per-game time-series metrics (viewer)")
+	log.Printf("  GET    /api/v1/admin/analytics   - Aggregates over finished games (viewer)")
+	log.Printf("  GET    /api/v1/admin/experiments - Compare rules experiment arms (viewer)")
+	log.Printf("  POST   /api/v1/admin/experiments - Start a rules experiment (operator)")
+	log.Printf("  POST   /api/v1/admin/experiments/stop - Stop the running experiment (operator)")
+	log.Printf("  GET    /api/v1/admin/keys     - List integration API keys (operator)")
+	log.Printf("  POST   /api/v1/admin/keys     - Issue an integration API key (operator)")
+	log.Printf("  POST   /api/v1/admin/keys/revoke - Revoke an integration API key (operator)")
+	log.Printf("  GET    /api/v1/admin/whoami   - Show the caller's admin role (viewer)")
+	log.Printf("  GET    /api/v1/admin/games    - List all games (viewer)")
+	log.Printf("  GET    /api/v1/admin/webhooks/dead-letters - Webhook events that could not be delivered (viewer)")
+	log.Printf("  GET    /api/v1/admin/chat     - Read any game's full chat (moderator)")
+	log.Printf("  POST   /api/v1/admin/game/delete - Delete a game (operator)")
+	log.Printf("  POST   /api/v1/admin/game/restore - Restore a deleted game (operator)")
+	log.Printf("  POST   /api/v1/integrations/games    - Create a batch of games (games:create)")
+	log.Printf("  GET    /api/v1/integrations/games/state - Read any game's state (games:read)")
+	log.Printf("  POST   /api/v1/integrations/webhooks - Register a webhook on any game (webhooks:write)")
+	log.Printf("  GET    /api/v1/stats          - Server statistics")
+	log.Printf("  GET    /api/v1/stats/meta     - Win rates by color and turn order")
+	log.Printf("  GET    /api/v1/archive/{code} - Full record of a finished game, kept after cleanup (?id= for an earlier one)")
+	log.Printf("  GET    /api/v1/players/{id}/stats - A player's career stats")
+	log.Printf("  GET    /api/v1/leaderboard    - Players ranked by wins, games, win rate or rating")
+	log.Printf("  POST   /api/v1/debug/fixture  - Create a game from a board-state fixture (debug mode)")
+	log.Printf("  GET    /api/v1/debug/fixture  - Export a game as a fixture (debug mode)")
+	log.Printf("  GET    /replay/{id}           - Shareable replay of a finished game (viewer page in browsers)")
+	log.Printf("  GET    /health                - Health check")
+	log.Printf("  GET    /                      - Web interface")
+	log.Printf("")
+	log.Printf("🎲 Open %s://localhost:%s in your browser to play!", scheme, port)
+
+	// Clients are warned this long before a shutdown closes their sockets
+	shutdownGrace := cfg.ShutdownGrace
+	if shutdownGrace == 0 {
+		shutdownGrace = defaultShutdownGrace
+	}
+	shutdownGrace = max(shutdownGrace, 0)
+
+	server := &http.Server{Addr: "0.0.0.0:" + port, Handler: router, TLSConfig: tlsConfig}
+	go func() {
+		var err error
+		if tlsConfig != nil {
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	// Shut down gracefully on Ctrl-C or SIGTERM; a second signal exits at once
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	<-ctx.Done()
+	stop()
+	shutdownServer(server, handler, hub, lobby, gameManager, snapshotFile, shutdownGrace)
+}
+
+// shutdownServer warns clients, stops serving, closes every socket and saves
+// every game so the next start resumes them
+func shutdownServer(server *http.Server, handler *handlers.Handler, hub *handlers.Hub, lobby *handlers.Lobby, gm *models.GameManager, path string, grace time.Duration) {
+	paused := handler.BeginShutdown(grace)
+	log.Printf("Shutting down in %s, %d games in progress paused (press Ctrl-C again to exit now)", grace, paused)
+	time.Sleep(grace)
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Requests still running at the shutdown deadline: %v", err)
+	}
+	cancel()
+	hub.CloseAll()
+	lobby.CloseAll()
+
+	saved, err := saveSnapshot(gm, path)
+	if err != nil {
+		log.Printf("Could not save games on shutdown: %v", err)
+		os.Exit(1)
+	}
+	log.Printf("Saved %d games to %s, shut down", saved, path)
+
+	// Give write pumps a moment to send their close frames
+	time.Sleep(time.Second)
+}
+
+// loadSnapshot restores games left by a previous restart and resumes them
+func loadSnapshot(gm *models.GameManager, path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Could not open snapshot %s: %v", path, err)
+		}
+		return
+	}
+	defer f.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), snapshotTimeout)
+	defer cancel()
+	loaded, err := gm.LoadSnapshot(ctx, f)
+	if err != nil {
+		log.Printf("Could not load snapshot %s: %v", path, err)
+		return
+	}
+	gm.EndMaintenance()
+	log.Printf("Restored %d games from %s", len(loaded), path)
+
+	// Keep the file around for inspection but never load it twice
+	if err := os.Rename(path, path+".loaded"); err != nil {
+		log.Printf("Could not retire snapshot %s: %v", path, err)
+	}
+}
+
+// loadStorage restores stored games the snapshot didn't already restore; a
+// snapshot is taken at restart, so its copy of a game is never older
+func loadStorage(gm *models.GameManager, spec string) {
+	ctx, cancel := context.WithTimeout(context.Background(), snapshotTimeout)
+	defer cancel()
+	loaded, err := gm.LoadFromStorage(ctx)
+	if err != nil {
+		log.Printf("Could not load every game from storage %s: %v", spec, err)
+	}
+	if len(loaded) > 0 {
+		gm.EndMaintenance()
+		log.Printf("Restored %d games from storage %s", len(loaded), spec)
+	}
+}
+
+// restartServer saves every game and restarts the process. Without exec the
+// process exits cleanly so a supervisor (systemd, Docker, Kubernetes) starts it again.
+func restartServer(gm *models.GameManager, path string, exec bool) {
+	saved, err := saveSnapshot(gm, path)
+	if err != nil {
+		log.Printf("Restart aborted, could not write snapshot: %v", err)
+		return
+	}
+	log.Printf("Saved %d games to %s, restarting", saved, path)
+
+	// Give WebSocket clients a moment to receive the restart notice
+	time.Sleep(time.Second)
+
+	if exec {
+		executable, err := os.Executable()
+		if err == nil {
+			err = syscall.Exec(executable, os.Args, os.Environ())
+		}
+		log.Printf("Re-exec failed, exiting instead: %v", err)
+	}
+	os.Exit(0)
+}
+
+// saveSnapshot flushes storage and writes every game to the snapshot file,
+// replacing it only once the new snapshot is complete
+func saveSnapshot(gm *models.GameManager, path string) (int, error) {
+	flushCtx, cancel := context.WithTimeout(context.Background(), snapshotTimeout)
+	if err := gm.FlushStorage(flushCtx); err != nil {
+		log.Printf("Could not flush storage: %v", err)
+	}
+	cancel()
+
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), snapshotTimeout)
+	saved, err := gm.WriteSnapshot(ctx, f)
+	cancel()
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	return saved, err
+}
+
+// startCleanupRoutine periodically cleans up abandoned games
+func startCleanupRoutine(gm *models.GameManager, hub *handlers.Hub, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		removed := gm.CleanupAbandonedGames()
+		if len(removed) > 0 {
+			log.Printf("Cleaned up %d abandoned games: %v", len(removed), removed)
+		}
+		purged := gm.PurgeDeletedGames()
+		if len(purged) > 0 {
+			log.Printf("Purged %d deleted games past their restore window: %v", len(purged), purged)
+		}
+		expired, err := gm.Archive().Purge(context.Background())
+		if err != nil {
+			log.Printf("Could not purge expired archived games: %v", err)
+		} else if len(expired) > 0 {
+			log.Printf("Purged %d archived games past their retention", len(expired))
+		}
+	}
+}
+
+// startTurnTimeoutChecker checks for turn timeouts and auto-skips
+func startTurnTimeoutChecker(gm *models.GameManager, hub *handlers.Hub) {
+	// Clients are told each turn's deadline, so enforce it to within a second
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		games := gm.GetAllGames()
+		for _, game := range games {
+			if game.ResumeExpiredPause() {
+				log.Printf("Pause ran out in game %s, resuming", game.Code)
+				hub.BroadcastRefresh(game.Code, "game_resumed")
+				continue
+			}
+			if game.IsTurnTimedOut() {
+				timedOutPlayer, action := game.HandleTurnTimeout()
+				if timedOutPlayer == "" {
+					continue
+				}
+				log.Printf("Turn timeout for player %s in game %s (%s)", timedOutPlayer, game.Code, action)
+				if action == models.TimeoutAutoPlay {
+					hub.BroadcastRefresh(game.Code, "turn_auto_played")
+					broadcastBotChat(game, hub)
+					broadcastCommentary(game, hub)
+				} else {
+					hub.BroadcastRefresh(game.Code, "turn_timeout")
+				}
+				for _, removal := range game.ConsumeAFKRemovals() {
+					log.Printf("Removed AFK player %s from game %s (%s)", removal.PlayerID, game.Code, removal.Action)
+					hub.BroadcastAFKRemoved(game.Code, removal)
+				}
+				for _, change := range game.ConsumeHostChanges() {
+					hub.BroadcastHostChanged(game.Code, change)
+				}
+				if game.HasEnded() {
+					hub.BroadcastRefresh(game.Code, "game_ended")
+				}
+			}
+		}
+	}
+}
+
+// startTurnWarnings warns each player once per turn when it is about to time out
+func startTurnWarnings(gm *models.GameManager, hub *handlers.Hub) {
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		for _, game := range gm.GetAllGames() {
+			if clock, ok := game.TakeTurnWarning(); ok {
+				hub.SendTurnWarning(game.Code, clock)
+			}
+		}
+	}
+}
+
+// startTurnCountdown sends countdown events for turns about to time out
+func startTurnCountdown(gm *models.GameManager, hub *handlers.Hub) {
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		for _, game := range gm.GetAllGames() {
+			clock, ok := game.TurnClock()
+			if !ok {
+				continue
+			}
+			if left := time.Until(clock.Deadline.Time); left > 0 && left <= handlers.CountdownWindow {
+				hub.BroadcastCountdown(game.Code, clock)
+			}
+		}
+	}
+}
+
+// startBotTurnHandler checks if it's a bot's turn and plays automatically
+func startBotTurnHandler(gm *models.GameManager, hub *handlers.Hub, tick time.Duration) {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		games := gm.GetAllGames()
+		for _, game := range games {
+			if ids := game.HandOverDisconnected(); len(ids) > 0 {
+				log.Printf("Bot took over for disconnected players %v in game %s", ids, game.Code)
+				hub.BroadcastRefresh(game.Code, "bot_took_over")
+			}
+			switch action, prompt := game.NextBotTurnAction(); action {
+			case models.BotTurnBuiltIn:
+				handleBotTurn(game, hub)
+			case models.BotTurnPrompt:
+				hub.SendBotPrompt(prompt)
+			}
+		}
+	}
+}
+
+// startAutoTurnHandler rolls and skips for human players in games that automate it.
+// The AutoTurns sink usually rolls and moves first; acting once per tick here
+// leaves a skipped roll on screen for a moment.
+func startAutoTurnHandler(gm *models.GameManager, hub *handlers.Hub, tick time.Duration) {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		for _, game := range gm.GetAllGames() {
+			switch _, action := game.PlayAutoAction(); action {
+			case models.AutoRolled:
+				hub.BroadcastAutoRefresh(game.Code, "dice_rolled")
+			case models.AutoMoved, models.AutoPreMoved:
+				hub.BroadcastAutoRefresh(game.Code, "piece_moved")
+				broadcastBotChat(game, hub)
+				broadcastCommentary(game, hub)
+			case models.AutoSkipped:
+				hub.BroadcastAutoRefresh(game.Code, "turn_skipped")
+			}
+		}
+	}
+}
+
+// handleBotTurn plays a turn for the bot
+func handleBotTurn(game *models.Game, hub *handlers.Hub) {
+	gameState := game.GetGameState()
+	currentTurn := gameState["current_turn"].(string)
+	hasRolled := gameState["has_rolled"].(bool)
+	
+	// If bot hasn't rolled yet, roll the dice
+	if !hasRolled {
+		_, err := game.RollDice(currentTurn)
+		if err != nil {
+			if err == models.ErrThreeSixes {
+				// Three sixes - turn is forfeited, broadcast and return
+				hub.BroadcastRefresh(game.Code, "dice_rolled")
+			}
+			return
+		}
+		
+		hub.BroadcastRefresh(game.Code, "dice_rolled")
+		
+		// Small delay before moving to make it feel more natural
+		time.Sleep(500 * time.Millisecond)
+
+		// A player the bot stood in for may have reconnected to make the move
+		if !game.IsCurrentPlayerBot() {
+			return
+		}
+	}
+	
+	// Check for valid move and make it
+	pieceID, hasMove := game.GetBotMove()
+	if hasMove {
+		if err := game.MovePiece(currentTurn, pieceID); err != nil {
+			// No valid moves, skip turn
+			game.SkipTurn(currentTurn)
+			hub.BroadcastRefresh(game.Code, "turn_skipped")
+			return
+		}
+		
+		hub.BroadcastRefresh(game.Code, "piece_moved")
+		broadcastBotChat(game, hub)
+		broadcastCommentary(game, hub)
+	} else {
+		// No valid moves, skip turn
+		game.SkipTurn(currentTurn)
+		hub.BroadcastRefresh(game.Code, "turn_skipped")
+	}
+}
+
+// broadcastBotChat sends a chat refresh if bots posted reactions
+func broadcastBotChat(game *models.Game, hub *handlers.Hub) {
+	if game.ConsumeBotChat() {
+		hub.BroadcastRefresh(game.Code, "chat_message")
+	}
+}
+
+// broadcastCommentary sends new commentary lines to subscribed clients
+func broadcastCommentary(game *models.Game, hub *handlers.Hub) {
+	if lines := game.ConsumeCommentary(); len(lines) > 0 {
+		hub.BroadcastCommentary(game.Code, lines)
+	}
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
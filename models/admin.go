@@ -0,0 +1,90 @@
+package models
+
+// Operators can step into a game that is stuck or being abused. These bypass the
+// host checks, but go through the same engine events as play so the game's
+// history still replays to the same result.
+
+// AdminSkipTurn passes the current player's turn whether or not they have rolled.
+// Unlike a timeout it isn't counted against them. Returns whose turn was skipped.
+func (g *Game) AdminSkipTurn() (string, error) {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.State == Paused {
+		return "", ErrGamePaused
+	}
+
+	skipped := g.CurrentTurn
+	if err := g.passTurnLocked(skipped); err != nil {
+		return "", err
+	}
+	g.LastActivity = Now()
+	return skipped, nil
+}
+
+// AdminEndGame ends a game in progress, paused or not, with the given winner.
+// The result counts towards standings and ratings like any other.
+func (g *Game) AdminEndGame(winnerID string) error {
+	g.mu.Lock()
+	defer g.unlock()
+
+	return g.endGameLocked(winnerID)
+}
+
+// endGameLocked declares a winner and ends the game (caller must hold lock)
+func (g *Game) endGameLocked(winnerID string) error {
+	if g.State != Playing && g.State != Paused {
+		return ErrGameNotPlaying
+	}
+	if _, exists := g.Players[winnerID]; !exists {
+		return ErrPlayerNotFound
+	}
+
+	g.recordEventLocked(EngineEvent{Type: EventEnd, PlayerID: winnerID})
+	g.State = Ended
+	g.Winner = winnerID
+	g.HasRolled = false
+	g.pendingRoll = nil
+	g.PausedBy = ""
+	g.PauseDeadline = Timestamp{}
+	g.PauseVote = nil
+	g.LastActivity = Now()
+	g.recordResultLocked()
+	g.botReactToWinLocked(winnerID)
+	g.commentOnWinLocked(winnerID)
+	return nil
+}
+
+// AdminKickPlayer removes any player, the host included. Mid-game an empty action
+// falls back to the game's departure policy.
+func (g *Game) AdminKickPlayer(playerID string, action DepartureAction) error {
+	g.mu.Lock()
+	defer g.unlock()
+
+	player, exists := g.Players[playerID]
+	if !exists || player.HasLeft {
+		return ErrPlayerNotFound
+	}
+
+	switch g.State {
+	case Waiting:
+		delete(g.Players, playerID)
+		g.closeSeatGapLocked()
+		if g.HostID == playerID {
+			g.migrateHostLocked(-1)
+		}
+	case Playing, Paused:
+		if action == "" {
+			action = g.DeparturePolicy
+		}
+		if _, err := ParseDepartureAction(string(action)); err != nil {
+			return err
+		}
+		g.departLocked(playerID, action)
+	default:
+		return ErrGameNotPlaying
+	}
+
+	g.LastActivity = Now()
+	return nil
+}
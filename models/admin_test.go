@@ -0,0 +1,76 @@
+package models
+
+import "testing"
+
+func TestAdminSkipTurn(t *testing.T) {
+	game := newPauseTestGame(t)
+	current := game.CurrentTurn
+	game.HasRolled = true
+	game.LastDiceRoll = 6
+
+	skipped, err := game.AdminSkipTurn()
+	if err != nil {
+		t.Fatalf("Failed to skip turn: %v", err)
+	}
+	if skipped != current || game.CurrentTurn == current || game.HasRolled {
+		t.Errorf("Expected %s's turn to pass, got skipped %q and turn %s", current, skipped, game.CurrentTurn)
+	}
+	if game.Players[current].MissedTurns != 0 {
+		t.Error("Expected an admin skip not to count as a missed turn")
+	}
+
+	game.RequestPause("p1", 0)
+	if _, err := game.AdminSkipTurn(); err != ErrGamePaused {
+		t.Errorf("Expected ErrGamePaused, got %v", err)
+	}
+}
+
+func TestAdminEndGame(t *testing.T) {
+	game := newPauseTestGame(t)
+	game.RequestPause("p1", 0)
+
+	if err := game.AdminEndGame("stranger"); err != ErrPlayerNotFound {
+		t.Errorf("Expected ErrPlayerNotFound, got %v", err)
+	}
+	if err := game.AdminEndGame("p3"); err != nil {
+		t.Fatalf("Failed to end game: %v", err)
+	}
+	if game.State != Ended || game.Winner != "p3" || game.PausedBy != "" {
+		t.Errorf("Expected game ended with p3 winning, got state %s, winner %q", game.State, game.Winner)
+	}
+	_, events, _ := game.History()
+	frame, err := game.ReplayAt(len(events))
+	if err != nil || frame.State.State != Ended || frame.State.Winner != "p3" {
+		t.Errorf("Expected the history to replay to p3 winning, got %+v, %v", frame, err)
+	}
+
+	if err := game.AdminEndGame("p1"); err != ErrGameNotPlaying {
+		t.Errorf("Expected ErrGameNotPlaying, got %v", err)
+	}
+}
+
+func TestAdminKickPlayer(t *testing.T) {
+	game := newPauseTestGame(t)
+
+	// The host can be kicked too; the role moves on
+	if err := game.AdminKickPlayer("p1", ""); err != nil {
+		t.Fatalf("Failed to kick host: %v", err)
+	}
+	if !game.Players["p1"].HasLeft || game.HostID == "p1" {
+		t.Errorf("Expected p1 gone and host moved, got host %s", game.HostID)
+	}
+	if err := game.AdminKickPlayer("p1", ""); err != ErrPlayerNotFound {
+		t.Errorf("Expected ErrPlayerNotFound, got %v", err)
+	}
+	if err := game.AdminKickPlayer("p2", "teleport"); err != ErrInvalidDepartureAction {
+		t.Errorf("Expected ErrInvalidDepartureAction, got %v", err)
+	}
+
+	// Kicking the second to last player ends the game
+	if err := game.AdminKickPlayer("p2", DepartureRemove); err != nil {
+		t.Fatalf("Failed to kick p2: %v", err)
+	}
+	if game.State != Ended || game.Winner != "p3" {
+		t.Errorf("Expected p3 to win, got state %s, winner %q", game.State, game.Winner)
+	}
+}
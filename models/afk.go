@@ -0,0 +1,78 @@
+package models
+
+import "errors"
+
+// DefaultAFKLimit is how many turns in a row a player may let time out before
+// they are taken out of the game, once the host turns AFK removal on without
+// picking a count. New games never remove anyone.
+const DefaultAFKLimit = 3
+
+var ErrInvalidAFKPolicy = errors.New("invalid AFK policy")
+
+// AFKRemoval records a player taken out of a game for letting too many turns in
+// a row time out
+type AFKRemoval struct {
+	PlayerID    string          `json:"player_id"`
+	Name        string          `json:"name"`
+	Action      DepartureAction `json:"action"`
+	MissedTurns int             `json:"missed_turns"`     // Timed-out turns in a row
+	BotID       string          `json:"bot_id,omitempty"` // Bot now in the seat, for DepartureBot
+}
+
+// SetAFKPolicy sets how many timed-out turns in a row get a player removed, 0 to
+// never remove them, and what happens to their seat and pieces (host only)
+func (g *Game) SetAFKPolicy(hostID string, limit int, action DepartureAction) error {
+	if limit < 0 {
+		return ErrInvalidAFKPolicy
+	}
+	if _, err := ParseDepartureAction(string(action)); err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.HostID != hostID {
+		return ErrNotHost
+	}
+	g.AFKLimit = limit
+	g.AFKAction = action
+	g.LastActivity = Now()
+	return nil
+}
+
+// ConsumeAFKRemovals returns the players removed for being AFK since the last
+// call, so each removal is announced once
+func (g *Game) ConsumeAFKRemovals() []AFKRemoval {
+	g.mu.Lock()
+	defer g.unlock()
+
+	removals := g.afkRemovals
+	g.afkRemovals = nil
+	return removals
+}
+
+// removeIfAFKLocked takes a human out of the game once their turns have timed
+// out AFKLimit times in a row, applying the game's AFK action. Players a bot is
+// standing in for keep their seat, as they are expected back. (caller must hold lock)
+func (g *Game) removeIfAFKLocked(playerID string) {
+	player, exists := g.Players[playerID]
+	if g.AFKLimit <= 0 || g.State != Playing || !exists || player.botPlays() || player.HasLeft ||
+		player.ConsecutiveMissedTurns < g.AFKLimit {
+		return
+	}
+
+	removal := AFKRemoval{
+		PlayerID:    playerID,
+		Name:        player.Name,
+		Action:      g.AFKAction,
+		MissedTurns: player.ConsecutiveMissedTurns,
+	}
+	if g.AFKAction == DepartureBot {
+		removal.BotID = g.replaceWithBotLocked(playerID).ID
+	} else {
+		g.departLocked(playerID, g.AFKAction)
+	}
+	g.afkRemovals = append(g.afkRemovals, removal)
+	g.LastActivity = Now()
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// timeOutTurn lets a player's turn run out
+func timeOutTurn(game *Game, playerID string) {
+	game.CurrentTurn = playerID
+	game.HasRolled = false
+	game.TurnStartTime = At(time.Now().Add(-2 * game.TurnTimeout))
+	game.HandleTurnTimeout()
+}
+
+func TestAFKRemoval(t *testing.T) {
+	game := newPauseTestGame(t)
+
+	if err := game.SetAFKPolicy("p2", 2, DepartureHome); err != ErrNotHost {
+		t.Errorf("Expected ErrNotHost, got %v", err)
+	}
+	if err := game.SetAFKPolicy("p1", -1, DepartureHome); err != ErrInvalidAFKPolicy {
+		t.Errorf("Expected ErrInvalidAFKPolicy, got %v", err)
+	}
+	if err := game.SetAFKPolicy("p1", 2, "vanish"); err != ErrInvalidDepartureAction {
+		t.Errorf("Expected ErrInvalidDepartureAction, got %v", err)
+	}
+	if err := game.SetAFKPolicy("p1", 2, DepartureHome); err != nil {
+		t.Fatalf("Failed to set the AFK policy: %v", err)
+	}
+
+	timeOutTurn(game, "p2")
+	if removals := game.ConsumeAFKRemovals(); len(removals) != 0 {
+		t.Fatalf("Expected no removal after one timeout, got %+v", removals)
+	}
+	timeOutTurn(game, "p2")
+	removals := game.ConsumeAFKRemovals()
+	if len(removals) != 1 || removals[0].PlayerID != "p2" || removals[0].Action != DepartureHome || removals[0].MissedTurns != 2 {
+		t.Fatalf("Expected p2 sent home after 2 timeouts, got %+v", removals)
+	}
+	if !game.Players["p2"].HasLeft || game.CurrentTurn == "p2" {
+		t.Errorf("Expected p2 out of the rotation, turn is %s", game.CurrentTurn)
+	}
+	if again := game.ConsumeAFKRemovals(); len(again) != 0 {
+		t.Errorf("Expected each removal reported once, got %+v", again)
+	}
+}
+
+func TestAFKRemovalOffByDefault(t *testing.T) {
+	game := newPauseTestGame(t)
+
+	for i := 0; i < 2*DefaultAFKLimit; i++ {
+		timeOutTurn(game, "p2")
+	}
+	if removals := game.ConsumeAFKRemovals(); len(removals) != 0 {
+		t.Fatalf("Expected nobody removed until the host opts in, got %+v", removals)
+	}
+	if game.Players["p2"].HasLeft {
+		t.Error("Expected p2 still seated")
+	}
+}
+
+func TestAFKSkipsStandIn(t *testing.T) {
+	game := newPauseTestGame(t)
+	game.SetAFKPolicy("p1", 1, DepartureHome)
+	game.Players["p2"].ControlledByBot = true
+	game.Players["p2"].ConsecutiveMissedTurns = 5
+
+	game.mu.Lock()
+	game.removeIfAFKLocked("p2")
+	game.mu.Unlock()
+
+	if removals := game.ConsumeAFKRemovals(); len(removals) != 0 {
+		t.Fatalf("Expected a player with a bot standing in to keep the seat, got %+v", removals)
+	}
+}
+
+func TestAFKBotTakeover(t *testing.T) {
+	game := newPauseTestGame(t)
+	game.SetAFKPolicy("p1", DefaultAFKLimit, DepartureBot)
+
+	// Acting in between resets the streak
+	timeOutTurn(game, "p2")
+	game.recordPlayerAction("p2")
+	for i := 0; i < DefaultAFKLimit-1; i++ {
+		timeOutTurn(game, "p2")
+	}
+	if removals := game.ConsumeAFKRemovals(); len(removals) != 0 {
+		t.Fatalf("Expected p2 still seated, got %+v", removals)
+	}
+
+	timeOutTurn(game, "p2")
+	removals := game.ConsumeAFKRemovals()
+	if len(removals) != 1 || removals[0].Action != DepartureBot || removals[0].BotID == "" {
+		t.Fatalf("Expected a bot to take p2's seat, got %+v", removals)
+	}
+	if _, exists := game.Players["p2"]; exists || !game.Players[removals[0].BotID].IsBot {
+		t.Errorf("Expected bot %s in p2's seat", removals[0].BotID)
+	}
+
+	// A limit of 0 never removes anyone
+	game.SetAFKPolicy("p1", 0, DepartureBot)
+	for i := 0; i < 2*DefaultAFKLimit; i++ {
+		timeOutTurn(game, "p3")
+	}
+	if removals := game.ConsumeAFKRemovals(); len(removals) != 0 {
+		t.Errorf("Expected nobody removed, got %+v", removals)
+	}
+}
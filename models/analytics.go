@@ -0,0 +1,154 @@
+package models
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Ways to group archived games in an analytics query
+const (
+	GroupByNone       = ""
+	GroupByPlayers    = "players"    // Seats in the game
+	GroupByPreset     = "preset"     // Speed preset
+	GroupByBoard      = "board"      // Board type
+	GroupByExperiment = "experiment" // Experiment and arm, e.g. "capture-no-bonus/treatment"
+)
+
+var ErrUnknownGroupBy = errors.New("unknown analytics grouping")
+
+// AnalyticsQuery selects archived games by when they ended, from inclusive and
+// to exclusive, either bound zero for open-ended, and how to group them
+type AnalyticsQuery struct {
+	From    time.Time
+	To      time.Time
+	GroupBy string
+}
+
+// AnalyticsGroup aggregates the games sharing a grouping key
+type AnalyticsGroup struct {
+	Key             string              `json:"key"`
+	Games           int                 `json:"games"`
+	AvgDurationMs   int64               `json:"avg_duration_ms"`
+	AvgMoves        float64             `json:"avg_moves"`
+	AvgCaptures     float64             `json:"avg_captures"` // Per game
+	CapturesPerMove float64             `json:"captures_per_move"`
+	ByKind          map[string]*WinRate `json:"by_kind"` // "bot" and "human" seats
+	totalDurationMs int64
+	totalMoves      int
+	totalCaptures   int
+}
+
+// AnalyticsReport answers an analytics query
+type AnalyticsReport struct {
+	From    Timestamp        `json:"from"`
+	To      Timestamp        `json:"to"`
+	GroupBy string           `json:"group_by,omitempty"`
+	Games   int              `json:"games"`
+	Groups  []AnalyticsGroup `json:"groups"`
+}
+
+// Analytics aggregates the archived games matching a query
+func (a *Archive) Analytics(q AnalyticsQuery) (*AnalyticsReport, error) {
+	key, err := analyticsKey(q.GroupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &AnalyticsReport{GroupBy: q.GroupBy, Groups: []AnalyticsGroup{}}
+	if !q.From.IsZero() {
+		report.From = At(q.From)
+	}
+	if !q.To.IsZero() {
+		report.To = At(q.To)
+	}
+
+	groups := make(map[string]*AnalyticsGroup)
+	for _, game := range a.Games() {
+		if (!q.From.IsZero() && game.EndedAt.Before(q.From)) || (!q.To.IsZero() && !game.EndedAt.Before(q.To)) {
+			continue
+		}
+		k := key(game)
+		group, exists := groups[k]
+		if !exists {
+			group = &AnalyticsGroup{Key: k, ByKind: make(map[string]*WinRate)}
+			groups[k] = group
+		}
+		group.add(game)
+		report.Games++
+	}
+
+	for _, group := range groups {
+		group.finish()
+		report.Groups = append(report.Groups, *group)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		gi, gj := report.Groups[i].Key, report.Groups[j].Key
+		ni, errI := strconv.Atoi(gi)
+		nj, errJ := strconv.Atoi(gj)
+		if errI == nil && errJ == nil {
+			return ni < nj
+		}
+		return gi < gj
+	})
+	return report, nil
+}
+
+// analyticsKey returns the function that picks a game's group
+func analyticsKey(groupBy string) (func(ArchivedGame) string, error) {
+	switch groupBy {
+	case GroupByNone:
+		return func(ArchivedGame) string { return "all" }, nil
+	case GroupByPlayers:
+		return func(g ArchivedGame) string { return strconv.Itoa(len(g.Seats)) }, nil
+	case GroupByPreset:
+		return func(g ArchivedGame) string {
+			if g.Preset == "" {
+				return PresetStandard
+			}
+			return g.Preset
+		}, nil
+	case GroupByBoard:
+		return func(g ArchivedGame) string { return string(g.Board) }, nil
+	case GroupByExperiment:
+		return func(g ArchivedGame) string {
+			if g.Experiment == "" {
+				return "none"
+			}
+			return g.Experiment + "/" + g.ExperimentArm
+		}, nil
+	}
+	return nil, ErrUnknownGroupBy
+}
+
+// add counts one game
+func (g *AnalyticsGroup) add(game ArchivedGame) {
+	g.Games++
+	g.totalDurationMs += game.DurationMs
+	g.totalMoves += game.Moves
+
+	share := 0.0
+	if len(game.Seats) > 0 {
+		share = 1 / float64(len(game.Seats))
+	}
+	for _, seat := range game.Seats {
+		g.totalCaptures += seat.Captures
+		kind := "human"
+		if seat.IsBot {
+			kind = "bot"
+		}
+		countSeat(g.ByKind, kind, seat.PlayerID == game.Winner, share)
+	}
+}
+
+// finish turns totals into averages
+func (g *AnalyticsGroup) finish() {
+	g.AvgDurationMs = g.totalDurationMs / int64(g.Games)
+	g.AvgMoves = float64(g.totalMoves) / float64(g.Games)
+	g.AvgCaptures = float64(g.totalCaptures) / float64(g.Games)
+	if g.totalMoves > 0 {
+		g.CapturesPerMove = float64(g.totalCaptures) / float64(g.totalMoves)
+	}
+	finishWinRates(g.ByKind)
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnalytics(t *testing.T) {
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	archive := NewArchive()
+	archive.Add(ArchivedGame{EndedAt: At(base), DurationMs: 600000, Moves: 100, Winner: "bot", Seats: []ArchivedSeat{
+		{PlayerID: "bot", IsBot: true, Captures: 3},
+		{PlayerID: "h1", Captures: 1},
+	}})
+	archive.Add(ArchivedGame{EndedAt: At(base.Add(time.Hour)), DurationMs: 1200000, Moves: 200, Preset: PresetBlitz, Winner: "h2", Seats: []ArchivedSeat{
+		{PlayerID: "h2", Captures: 2},
+		{PlayerID: "h3", Captures: 2},
+		{PlayerID: "h4"},
+	}})
+	archive.Add(ArchivedGame{EndedAt: At(base.Add(48 * time.Hour)), DurationMs: 300000, Moves: 50, Winner: "h5", Seats: []ArchivedSeat{
+		{PlayerID: "h5"},
+		{PlayerID: "h6"},
+	}})
+
+	if _, err := archive.Analytics(AnalyticsQuery{GroupBy: "color"}); err != ErrUnknownGroupBy {
+		t.Errorf("Expected ErrUnknownGroupBy, got %v", err)
+	}
+
+	report, err := archive.Analytics(AnalyticsQuery{GroupBy: GroupByPlayers, To: base.Add(24 * time.Hour)})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if report.Games != 2 || len(report.Groups) != 2 || report.Groups[0].Key != "2" || report.Groups[1].Key != "3" {
+		t.Fatalf("Expected the first two games grouped by player count, got %+v", report)
+	}
+	two := report.Groups[0]
+	if two.AvgDurationMs != 600000 || two.AvgCaptures != 4 || two.CapturesPerMove != 0.04 {
+		t.Errorf("Unexpected averages %+v", two)
+	}
+	if bot := two.ByKind["bot"]; bot.Games != 1 || bot.WinRate != 1 || bot.Expected != 0.5 {
+		t.Errorf("Unexpected bot win rate %+v", bot)
+	}
+
+	report, _ = archive.Analytics(AnalyticsQuery{GroupBy: GroupByPreset, From: base.Add(time.Minute)})
+	if report.Games != 2 || report.Groups[0].Key != PresetBlitz || report.Groups[1].Key != PresetStandard {
+		t.Errorf("Expected blitz and standard after the first game, got %+v", report.Groups)
+	}
+}
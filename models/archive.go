@@ -0,0 +1,240 @@
+package models
+
+import (
+	crypto_rand "crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"sync"
+)
+
+// MaxArchivedGames bounds the archive; the oldest games are dropped first
+const MaxArchivedGames = 10000
+
+// MaxReplays is how many of the newest archived games keep their move log for
+// replays; older summaries stay for statistics
+const MaxReplays = 1000
+
+// ArchivedSeat is how one seat did in a finished game
+type ArchivedSeat struct {
+	PlayerID      string      `json:"player_id"`
+	Name          string      `json:"name"`
+	Color         PlayerColor `json:"color"`
+	Position      int         `json:"position"` // Turn order, 1 moves first
+	IsBot         bool        `json:"is_bot"`
+	HasLeft       bool        `json:"has_left"`
+	Place         int         `json:"place"` // 1 for the winner
+	Captures      int         `json:"captures"`
+	CapturesTaken int         `json:"captures_taken"` // Own pieces sent home
+}
+
+// ArchivedGame summarizes a finished game for statistics
+type ArchivedGame struct {
+	ID                string         `json:"id"` // Public replay ID, unlike the code never reused
+	Code              string         `json:"code"`
+	Board             BoardType      `json:"board"`
+	Preset            string         `json:"preset,omitempty"`
+	Ranked            bool           `json:"ranked,omitempty"`
+	Experiment        string         `json:"experiment,omitempty"`
+	ExperimentArm     string         `json:"experiment_arm,omitempty"`
+	CaptureGrantsTurn bool           `json:"capture_grants_turn"`
+	StartedAt         Timestamp      `json:"started_at"`
+	EndedAt           Timestamp      `json:"ended_at"`
+	DurationMs        int64          `json:"duration_ms"`
+	Moves             int            `json:"moves"`
+	Winner            string         `json:"winner"`
+	Seats             []ArchivedSeat `json:"seats"`
+	Events            []MoveRecord   `json:"events,omitempty"` // Dropped once the game is too old to replay
+}
+
+// Archive keeps summaries of finished games, oldest first
+type Archive struct {
+	games []ArchivedGame
+	sink  *archiveSink // Keeps full records long term; nil unless SetSink was called
+	mu    sync.RWMutex
+}
+
+// NewArchive creates an empty archive
+func NewArchive() *Archive {
+	return &Archive{}
+}
+
+// Add records a finished game
+func (a *Archive) Add(game ArchivedGame) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.games = append(a.games, game)
+	if len(a.games) > MaxArchivedGames {
+		a.games = append([]ArchivedGame(nil), a.games[len(a.games)-MaxArchivedGames:]...)
+	}
+	if expired := len(a.games) - MaxReplays - 1; expired >= 0 {
+		a.games[expired].Events = nil
+	}
+}
+
+// Get returns an archived game by its replay ID
+func (a *Archive) Get(id string) (ArchivedGame, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	for i := len(a.games) - 1; i >= 0; i-- {
+		if a.games[i].ID == id {
+			return a.games[i], true
+		}
+	}
+	return ArchivedGame{}, false
+}
+
+// Games returns every archived game, oldest first
+func (a *Archive) Games() []ArchivedGame {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return append([]ArchivedGame(nil), a.games...)
+}
+
+// Len returns how many games are archived
+func (a *Archive) Len() int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return len(a.games)
+}
+
+// Archive returns the store of finished games
+func (gm *GameManager) Archive() *Archive {
+	return gm.archive
+}
+
+// archiveLocked summarizes a game that just ended (caller must hold lock)
+func (g *Game) archiveLocked() ArchivedGame {
+	ended := Now()
+	summary := ArchivedGame{
+		ID:                newReplayID(),
+		Code:              g.Code,
+		Board:             BoardFor(g.MaxPlayers).Type,
+		Preset:            g.Preset,
+		Ranked:            g.Ranked,
+		Experiment:        g.Experiment,
+		ExperimentArm:     g.ExperimentArm,
+		CaptureGrantsTurn: g.CaptureGrantsTurn,
+		StartedAt:         g.StartedAt,
+		EndedAt:           ended,
+		Moves:             len(g.MoveHistory),
+		Winner:            g.Winner,
+		Events:            append([]MoveRecord(nil), g.MoveHistory...),
+	}
+	if !g.StartedAt.IsZero() {
+		summary.DurationMs = ended.Sub(g.StartedAt.Time).Milliseconds()
+	}
+	for place, p := range g.finishOrderLocked() {
+		summary.Seats = append(summary.Seats, ArchivedSeat{
+			PlayerID:      p.ID,
+			Name:          p.Name,
+			Color:         p.Color,
+			Position:      p.Order + 1,
+			IsBot:         p.IsBot,
+			HasLeft:       p.HasLeft,
+			Place:         place + 1,
+			Captures:      p.Stats.CapturesMade,
+			CapturesTaken: p.Stats.CapturesTaken,
+		})
+	}
+	return summary
+}
+
+// newReplayID returns a random, unguessable ID for an archived game
+func newReplayID() string {
+	var b [8]byte
+	crypto_rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// WinRate is how often seats with some trait won. Expected is the rate they would
+// have won at if every seat in their games were equally likely to.
+type WinRate struct {
+	Games    int     `json:"games"`
+	Wins     int     `json:"wins"`
+	WinRate  float64 `json:"win_rate"`
+	Expected float64 `json:"expected"`
+}
+
+// MetaStats breaks archived results down by seat color and turn order, overall
+// and per board type
+type MetaStats struct {
+	Games      int                   `json:"games"`
+	ByColor    map[string]*WinRate   `json:"by_color"`
+	ByPosition map[string]*WinRate   `json:"by_position"` // Turn order, "1" moves first
+	ByBoard    map[string]*MetaStats `json:"by_board,omitempty"`
+}
+
+// newMetaStats creates an empty breakdown
+func newMetaStats() *MetaStats {
+	return &MetaStats{
+		ByColor:    make(map[string]*WinRate),
+		ByPosition: make(map[string]*WinRate),
+	}
+}
+
+// add counts one game's seats
+func (m *MetaStats) add(game ArchivedGame) {
+	m.Games++
+	share := 1 / float64(len(game.Seats))
+	for _, seat := range game.Seats {
+		won := seat.PlayerID == game.Winner
+		countSeat(m.ByColor, string(seat.Color), won, share)
+		countSeat(m.ByPosition, strconv.Itoa(seat.Position), won, share)
+	}
+}
+
+// finish turns counts into rates
+func (m *MetaStats) finish() {
+	finishWinRates(m.ByColor)
+	finishWinRates(m.ByPosition)
+}
+
+// finishWinRates turns counted wins and expected wins into rates
+func finishWinRates(rates map[string]*WinRate) {
+	for _, r := range rates {
+		r.WinRate = float64(r.Wins) / float64(r.Games)
+		r.Expected /= float64(r.Games)
+	}
+}
+
+// countSeat adds one seat to a win rate, keeping the expected wins in Expected until finish
+func countSeat(rates map[string]*WinRate, key string, won bool, share float64) {
+	r, exists := rates[key]
+	if !exists {
+		r = &WinRate{}
+		rates[key] = r
+	}
+	r.Games++
+	r.Expected += share
+	if won {
+		r.Wins++
+	}
+}
+
+// MetaStats aggregates every archived game with a winner
+func (a *Archive) MetaStats() *MetaStats {
+	stats := newMetaStats()
+	stats.ByBoard = make(map[string]*MetaStats)
+
+	for _, game := range a.Games() {
+		if game.Winner == "" || len(game.Seats) == 0 {
+			continue
+		}
+		stats.add(game)
+		board, exists := stats.ByBoard[string(game.Board)]
+		if !exists {
+			board = newMetaStats()
+			stats.ByBoard[string(game.Board)] = board
+		}
+		board.add(game)
+	}
+
+	stats.finish()
+	for _, board := range stats.ByBoard {
+		board.finish()
+	}
+	return stats
+}
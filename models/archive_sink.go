@@ -0,0 +1,277 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultArchiveRetention is how long full records of finished games are kept
+const DefaultArchiveRetention = 90 * 24 * time.Hour
+
+// ArchiveNamespace is where finished games are kept in a storage with namespaces
+const ArchiveNamespace = "archive"
+
+var (
+	ErrArchiveNotFound   = errors.New("archived game not found")
+	ErrArchiveDisabled   = errors.New("game archive is not configured")
+	ErrInvalidArchiveURL = errors.New("archive bucket must be given as s3://bucket/prefix")
+)
+
+// ArchiveSink keeps full records of finished games long term, by key. Get returns
+// ErrArchiveNotFound for unknown keys. Implementations must be safe for concurrent use.
+type ArchiveSink interface {
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// ArchiveRecord is everything kept about a finished game
+type ArchiveRecord struct {
+	Summary ArchivedGame           `json:"summary"` // Result and how each seat did
+	State   map[string]interface{} `json:"state"`   // Final game state, as the state endpoint gave it
+	Record  *GameExport            `json:"record"`  // Players, rules and complete history
+}
+
+// storageSink keeps archived games in a storage
+type storageSink struct {
+	storage Storage
+}
+
+// StorageArchive returns a sink that keeps archived games in a storage
+func StorageArchive(storage Storage) ArchiveSink {
+	return storageSink{storage}
+}
+
+// OpenArchiveSink opens a sink from a spec: an S3-compatible bucket as
+// s3://bucket/prefix?endpoint=...&region=..., or any storage spec
+func OpenArchiveSink(spec string) (ArchiveSink, error) {
+	if strings.HasPrefix(spec, "s3://") {
+		sink, err := NewS3Archive(spec)
+		if err != nil {
+			return nil, err
+		}
+		return sink, nil
+	}
+	storage, err := OpenStorage(spec)
+	if err != nil {
+		return nil, err
+	}
+	return StorageArchive(storage), nil
+}
+
+func (s storageSink) Put(ctx context.Context, key string, data []byte) error {
+	return s.storage.Save(ctx, key, data)
+}
+
+func (s storageSink) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := s.storage.Load(ctx, key)
+	if errors.Is(err, ErrGameNotFound) {
+		return nil, ErrArchiveNotFound
+	}
+	return data, err
+}
+
+func (s storageSink) Delete(ctx context.Context, key string) error {
+	return s.storage.Delete(ctx, key)
+}
+
+func (s storageSink) List(ctx context.Context, prefix string) ([]string, error) {
+	keys, err := s.storage.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var matching []string
+	for _, key := range keys {
+		if strings.HasPrefix(key, prefix) {
+			matching = append(matching, key)
+		}
+	}
+	return matching, nil
+}
+
+// archiveSink writes records to a sink in the background
+type archiveSink struct {
+	sink      ArchiveSink
+	retention time.Duration // Negative keeps records forever
+	onError   StorageErrorHook
+	pending   sync.WaitGroup
+}
+
+// archiveKey names a record by game code, end time and replay ID, so the
+// latest game with a code and expired records are found from keys alone
+func archiveKey(summary ArchivedGame) string {
+	return fmt.Sprintf("%s-%d-%s", summary.Code, summary.EndedAt.UnixMilli(), summary.ID)
+}
+
+// parseArchiveKey splits a key made by archiveKey
+func parseArchiveKey(key string) (code string, ended time.Time, id string, ok bool) {
+	rest, id, found := cutLast(key, "-")
+	if !found {
+		return "", time.Time{}, "", false
+	}
+	code, millis, found := cutLast(rest, "-")
+	if !found {
+		return "", time.Time{}, "", false
+	}
+	ms, err := strconv.ParseInt(millis, 10, 64)
+	if err != nil {
+		return "", time.Time{}, "", false
+	}
+	return code, time.UnixMilli(ms), id, true
+}
+
+// cutLast slices s around the last instance of sep
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// SetSink keeps the full record of every game that ends from now on in a sink,
+// for retention (DefaultArchiveRetention when 0; forever when negative). Call
+// before the server starts.
+func (a *Archive) SetSink(sink ArchiveSink, retention time.Duration, onError StorageErrorHook) {
+	if retention == 0 {
+		retention = DefaultArchiveRetention
+	}
+	a.mu.Lock()
+	a.sink = &archiveSink{sink: sink, retention: retention, onError: onError}
+	a.mu.Unlock()
+}
+
+// keep writes a finished game's record to the sink in the background. The
+// record is made right away, and only when there's a sink to keep it in.
+func (a *Archive) keep(summary ArchivedGame, makeRecord func() ([]byte, error)) {
+	a.mu.RLock()
+	s := a.sink
+	a.mu.RUnlock()
+	if s == nil {
+		return
+	}
+	record, err := makeRecord()
+	if err != nil {
+		if s.onError != nil {
+			s.onError(summary.Code, err)
+		}
+		return
+	}
+
+	key := archiveKey(summary)
+	s.pending.Add(1)
+	go func() {
+		defer s.pending.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), StorageSaveTimeout)
+		defer cancel()
+		if err := s.sink.Put(ctx, key, record); err != nil && s.onError != nil {
+			s.onError(summary.Code, err)
+		}
+	}()
+}
+
+// Load returns the record of the last game with a code to end, or of the game
+// with replay ID id if given
+func (a *Archive) Load(ctx context.Context, code, id string) (*ArchiveRecord, error) {
+	a.mu.RLock()
+	s := a.sink
+	a.mu.RUnlock()
+	if s == nil {
+		return nil, ErrArchiveDisabled
+	}
+
+	keys, err := s.sink.List(ctx, code+"-")
+	if err != nil {
+		return nil, err
+	}
+	var latest string
+	var latestEnd time.Time
+	for _, key := range keys {
+		keyCode, ended, keyID, ok := parseArchiveKey(key)
+		if !ok || keyCode != code || (id != "" && keyID != id) {
+			continue
+		}
+		if latest == "" || ended.After(latestEnd) {
+			latest, latestEnd = key, ended
+		}
+	}
+	if latest == "" {
+		return nil, ErrArchiveNotFound
+	}
+
+	data, err := s.sink.Get(ctx, latest)
+	if err != nil {
+		return nil, err
+	}
+	var record ArchiveRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Purge deletes records kept longer than the retention and returns their keys
+func (a *Archive) Purge(ctx context.Context) ([]string, error) {
+	a.mu.RLock()
+	s := a.sink
+	a.mu.RUnlock()
+	if s == nil || s.retention < 0 {
+		return nil, nil
+	}
+
+	keys, err := s.sink.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	cutoff := time.Now().Add(-s.retention)
+	var purged []string
+	for _, key := range keys {
+		if _, ended, _, ok := parseArchiveKey(key); !ok || !ended.Before(cutoff) {
+			continue
+		}
+		if err := s.sink.Delete(ctx, key); err != nil {
+			return purged, err
+		}
+		purged = append(purged, key)
+	}
+	return purged, nil
+}
+
+// Flush waits for records still being written, or for ctx to be done
+func (a *Archive) Flush(ctx context.Context) error {
+	a.mu.RLock()
+	s := a.sink
+	a.mu.RUnlock()
+	if s == nil {
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// archiveRecordLocked serializes a game that just ended for the archive sink
+// (caller must hold lock)
+func (g *Game) archiveRecordLocked(summary ArchivedGame) ([]byte, error) {
+	summary.Events = nil // The record's history has them
+	return json.Marshal(ArchiveRecord{
+		Summary: summary,
+		State:   g.gameStateLocked(),
+		Record:  g.exportLocked(),
+	})
+}
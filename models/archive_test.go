@@ -0,0 +1,62 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGamesAreArchived(t *testing.T) {
+	game := newPauseTestGame(t)
+	if game.StartedAt.IsZero() {
+		t.Error("Expected the start time to be recorded")
+	}
+	endTestGame(game, "p2")
+
+	games := game.archive.Games()
+	if len(games) != 1 {
+		t.Fatalf("Expected 1 archived game, got %d", len(games))
+	}
+	archived := games[0]
+	if archived.Winner != "p2" || archived.Board != BoardSquare || len(archived.Seats) != 3 {
+		t.Errorf("Unexpected summary %+v", archived)
+	}
+	if archived.ID == "" || game.ReplayID != archived.ID {
+		t.Errorf("Expected the game to link its replay, got %q and %q", game.ReplayID, archived.ID)
+	}
+	if archived.Seats[0].PlayerID != "p2" || archived.Seats[0].Place != 1 {
+		t.Errorf("Expected the winner in first place, got %+v", archived.Seats[0])
+	}
+}
+
+func TestMetaStats(t *testing.T) {
+	archive := NewArchive()
+	archive.Add(ArchivedGame{Board: BoardSquare, Winner: "a", Seats: []ArchivedSeat{
+		{PlayerID: "a", Color: Red, Position: 1},
+		{PlayerID: "b", Color: Blue, Position: 2},
+	}})
+	archive.Add(ArchivedGame{Board: BoardSquare, Winner: "c", Seats: []ArchivedSeat{
+		{PlayerID: "c", Color: Red, Position: 2},
+		{PlayerID: "d", Color: Blue, Position: 1},
+		{PlayerID: "e", Color: Green, Position: 3},
+		{PlayerID: "f", Color: Yellow, Position: 4},
+	}})
+	archive.Add(ArchivedGame{Board: BoardSquare}) // Abandoned without a winner
+
+	stats := archive.MetaStats()
+	if stats.Games != 2 {
+		t.Fatalf("Expected 2 games counted, got %d", stats.Games)
+	}
+	red := stats.ByColor["red"]
+	if red.Games != 2 || red.Wins != 2 || red.WinRate != 1 {
+		t.Errorf("Unexpected red win rate %+v", red)
+	}
+	if want := (0.5 + 0.25) / 2; math.Abs(red.Expected-want) > 1e-9 {
+		t.Errorf("Expected red's fair share to be %v, got %v", want, red.Expected)
+	}
+	if first := stats.ByPosition["1"]; first.Games != 2 || first.Wins != 1 {
+		t.Errorf("Unexpected first-position win rate %+v", first)
+	}
+	if square := stats.ByBoard["square"]; square == nil || square.Games != 2 {
+		t.Errorf("Expected a square board breakdown, got %+v", square)
+	}
+}
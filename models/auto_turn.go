@@ -0,0 +1,137 @@
+package models
+
+// AutoAction is something the server did on a player's behalf
+type AutoAction string
+
+const (
+	AutoNone     AutoAction = ""
+	AutoRolled   AutoAction = "rolled"    // Rolled at the start of the turn
+	AutoMoved    AutoAction = "moved"     // Played the only valid move
+	AutoPreMoved AutoAction = "pre_moved" // Played the move the player queued
+	AutoSkipped  AutoAction = "skipped"   // Passed the turn after a roll with no move
+)
+
+// SetAutoRoll turns automatic rolling on or off for every human player (host only)
+func (g *Game) SetAutoRoll(hostID string, enabled bool) error {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.HostID != hostID {
+		return ErrNotHost
+	}
+	g.AutoRoll = enabled
+	g.LastActivity = Now()
+	return nil
+}
+
+// SetPlayerAutoRoll turns automatic rolling on or off for one player's turns.
+// It only adds to the game setting: a player can't opt out of a game that auto-rolls.
+func (g *Game) SetPlayerAutoRoll(playerID string, enabled bool) error {
+	g.mu.Lock()
+	defer g.unlock()
+
+	player, exists := g.Players[playerID]
+	if !exists || player.IsBot {
+		return ErrPlayerNotFound
+	}
+	player.AutoRoll = enabled
+	g.LastActivity = Now()
+	return nil
+}
+
+// SetAutoMove turns automatic play of a lone valid move on or off for every
+// human player (host only)
+func (g *Game) SetAutoMove(hostID string, enabled bool) error {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.HostID != hostID {
+		return ErrNotHost
+	}
+	g.AutoMove = enabled
+	g.LastActivity = Now()
+	return nil
+}
+
+// SetPlayerAutoMove turns automatic play of a lone valid move on or off for one
+// player. Like auto-roll, it only adds to the game setting.
+func (g *Game) SetPlayerAutoMove(playerID string, enabled bool) error {
+	g.mu.Lock()
+	defer g.unlock()
+
+	player, exists := g.Players[playerID]
+	if !exists || player.IsBot {
+		return ErrPlayerNotFound
+	}
+	player.AutoMove = enabled
+	g.LastActivity = Now()
+	return nil
+}
+
+// PlayAutoStep rolls for the current player if their turn has just started, or
+// plays their queued move or the only move there is, when they or the game want
+// it done automatically. It never passes the turn. Returns who was acted for.
+func (g *Game) PlayAutoStep() (playerID string, action AutoAction) {
+	g.mu.Lock()
+	defer g.unlock()
+
+	return g.autoActionLocked(false)
+}
+
+// PlayAutoAction takes the current turn's next step for a human player when the
+// game automates it: rolling when they haven't, playing the only valid move, or
+// passing the turn when their roll left no move. Bots are left to the bot
+// handler. Returns the player acted for, or AutoNone if there was nothing to do.
+func (g *Game) PlayAutoAction() (playerID string, action AutoAction) {
+	g.mu.Lock()
+	defer g.unlock()
+
+	return g.autoActionLocked(true)
+}
+
+// autoActionLocked rolls, moves, or skips if allowed, for the current human player.
+// A queued pre-move counts as asking for both the roll and the move.
+// (caller must hold lock)
+func (g *Game) autoActionLocked(allowSkip bool) (string, AutoAction) {
+	if g.State != Playing {
+		return "", AutoNone
+	}
+	player, exists := g.Players[g.CurrentTurn]
+	if !exists || player.botPlays() || player.HasLeft {
+		return "", AutoNone
+	}
+
+	if !g.HasRolled {
+		if !g.AutoRoll && !player.AutoRoll && player.PreMove == nil {
+			return "", AutoNone
+		}
+		_, err := g.rollDiceLocked(player.ID)
+		if err == ErrThreeSixes {
+			player.PreMove = nil // The turn it was queued for is over
+		} else if err != nil {
+			return "", AutoNone
+		}
+		return player.ID, AutoRolled
+	}
+
+	moves := g.getValidMovesInternal(player.ID)
+	if pieceID, ok := g.takePreMoveLocked(player, moves); ok {
+		if err := g.movePieceLocked(player.ID, pieceID); err != nil {
+			return "", AutoNone
+		}
+		return player.ID, AutoPreMoved
+	}
+	switch {
+	case len(moves) == 1 && (g.AutoMove || player.AutoMove):
+		if err := g.movePieceLocked(player.ID, moves[0]); err != nil {
+			return "", AutoNone
+		}
+		return player.ID, AutoMoved
+	case len(moves) == 0 && allowSkip && g.AutoSkip:
+		if err := g.skipTurnLocked(player.ID); err != nil {
+			return "", AutoNone
+		}
+		return player.ID, AutoSkipped
+	}
+	return "", AutoNone
+}
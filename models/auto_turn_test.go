@@ -0,0 +1,79 @@
+package models
+
+import "testing"
+
+func TestPlayerAutoRoll(t *testing.T) {
+	game := newPauseTestGame(t)
+	turn := game.CurrentTurn
+
+	if err := game.SetPlayerAutoRoll("stranger", true); err != ErrPlayerNotFound {
+		t.Errorf("Expected ErrPlayerNotFound, got %v", err)
+	}
+	for id := range game.Players {
+		if id != turn {
+			game.SetPlayerAutoRoll(id, true)
+		}
+	}
+	if _, action := game.PlayAutoStep(); action != AutoNone {
+		t.Errorf("Should not act for a player who didn't opt in, got %q", action)
+	}
+
+	if err := game.SetPlayerAutoRoll(turn, true); err != nil {
+		t.Fatalf("Failed to enable auto-roll: %v", err)
+	}
+	playerID, action := game.PlayAutoStep()
+	if action != AutoRolled || playerID != turn {
+		t.Fatalf("Expected a roll for %s, got %q for %q", turn, action, playerID)
+	}
+	if game.HasRolled {
+		// Auto-roll alone never moves or passes the turn
+		if _, action := game.PlayAutoAction(); action != AutoNone {
+			t.Errorf("Expected nothing more without auto-move or auto-skip, got %q", action)
+		}
+	}
+}
+
+func TestGameAutoRoll(t *testing.T) {
+	game := newPauseTestGame(t)
+	if err := game.SetAutoRoll("p2", true); err != ErrNotHost {
+		t.Errorf("Expected ErrNotHost, got %v", err)
+	}
+	if err := game.SetAutoRoll("p1", true); err != nil {
+		t.Fatalf("Failed to enable auto-roll: %v", err)
+	}
+	if playerID, action := game.PlayAutoStep(); action != AutoRolled || playerID == "" {
+		t.Errorf("Expected a roll for the current player, got %q for %q", action, playerID)
+	}
+}
+
+func TestAutoMove(t *testing.T) {
+	game := newPauseTestGame(t)
+	player := game.Players[game.CurrentTurn]
+	start := GetStartPosition(player.Color, game.MaxPlayers)
+	player.Pieces[0].IsHome = false
+	player.Pieces[0].Position = start
+	game.HasRolled = true
+	game.LastDiceRoll = 3
+
+	if _, action := game.PlayAutoStep(); action != AutoNone {
+		t.Errorf("Should not move without auto-move, got %q", action)
+	}
+	if err := game.SetPlayerAutoMove(player.ID, true); err != nil {
+		t.Fatalf("Failed to enable auto-move: %v", err)
+	}
+	playerID, action := game.PlayAutoStep()
+	if action != AutoMoved || playerID != player.ID {
+		t.Fatalf("Expected the lone move played for %s, got %q for %q", player.ID, action, playerID)
+	}
+	if want := (start + 3) % BoardSize; player.Pieces[0].Position != want {
+		t.Errorf("Expected piece at %d, got %d", want, player.Pieces[0].Position)
+	}
+
+	// A six gives a choice between the board piece and the ones at home
+	game.CurrentTurn = player.ID
+	game.HasRolled = true
+	game.LastDiceRoll = 6
+	if _, action := game.PlayAutoStep(); action != AutoNone {
+		t.Errorf("Should not pick between several moves, got %q", action)
+	}
+}
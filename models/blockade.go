@@ -0,0 +1,74 @@
+package models
+
+import "sort"
+
+// Blockade is a track square held by two or more pieces of one player. Under the
+// blockades rule, opponents can neither land on nor pass it.
+type Blockade struct {
+	Position int         `json:"position"`
+	PlayerID string      `json:"player_id"`
+	Color    PlayerColor `json:"color"`
+}
+
+// blockadesLocked lists the blockades on the track, ordered by position. Empty
+// unless the game plays with blockades (caller must hold lock).
+func (g *Game) blockadesLocked() []Blockade {
+	blockades := []Blockade{}
+	if !g.Rules.Blockades {
+		return blockades
+	}
+	for _, player := range g.Players {
+		counts := make(map[int]int)
+		for _, piece := range player.Pieces {
+			if onTrack(piece) {
+				counts[piece.Position]++
+			}
+		}
+		for position, count := range counts {
+			if count >= 2 {
+				blockades = append(blockades, Blockade{Position: position, PlayerID: player.ID, Color: player.Color})
+			}
+		}
+	}
+	sort.Slice(blockades, func(i, j int) bool { return blockades[i].Position < blockades[j].Position })
+	return blockades
+}
+
+// GetBlockades returns the blockades on the track
+func (g *Game) GetBlockades() []Blockade {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.blockadesLocked()
+}
+
+// blockedLocked reports whether an opponent's blockade stops a piece from making
+// the current roll: it would land on or pass one on the track (caller must hold lock)
+func (g *Game) blockedLocked(player *Player, piece Piece) bool {
+	if !g.Rules.Blockades || piece.IsFinished || piece.HomeStretchPosition > 0 {
+		return false
+	}
+	blocked := make(map[int]bool)
+	for _, blockade := range g.blockadesLocked() {
+		if blockade.PlayerID != player.ID {
+			blocked[blockade.Position] = true
+		}
+	}
+	if len(blocked) == 0 {
+		return false
+	}
+
+	// Leaving the yard lands on the start square
+	if piece.IsHome {
+		return blocked[g.board().Start(player.Color)]
+	}
+	for step := 1; step <= g.LastDiceRoll; step++ {
+		position, enteredHomeStretch, _ := g.calculateNewPosition(player.Color, piece.Position, step)
+		if enteredHomeStretch {
+			return false
+		}
+		if blocked[position] {
+			return true
+		}
+	}
+	return false
+}
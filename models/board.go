@@ -0,0 +1,143 @@
+package models
+
+// BoardType identifies a board layout
+type BoardType string
+
+const (
+	BoardSquare BoardType = "square" // Classic cross-shaped board for 2-4 players
+	BoardHex    BoardType = "hex"    // Six-armed board for 5-6 players
+)
+
+// Board describes the track layout of a board type. All movement is computed
+// from the distance a piece has travelled from its own start square, so the
+// same rules work for any number of arms.
+type Board struct {
+	Type              BoardType
+	Arms              int           // Number of arms (one per seat)
+	ArmLength         int           // Track squares per arm
+	TrackLength       int           // Total squares on the shared track
+	HomeStretchLength int           // Squares from the entry to the finish
+	Colors            []PlayerColor // Seat colors in join order
+	starts            map[PlayerColor]int
+	entries           map[PlayerColor]int
+	safe              map[int]bool
+}
+
+// SquareBoard is the 52-square board used for 2-4 players
+var SquareBoard = &Board{
+	Type:              BoardSquare,
+	Arms:              4,
+	ArmLength:         13,
+	TrackLength:       BoardSize,
+	HomeStretchLength: HomeStretchSize,
+	Colors:            []PlayerColor{Red, Blue, Green, Yellow},
+	starts:            PlayerStartPositions,
+	entries:           PlayerHomeStretchEntry,
+	safe:              SafeZones,
+}
+
+// HexBoard is the 72-square, six-armed board used for 5-6 players
+var HexBoard = &Board{
+	Type:              BoardHex,
+	Arms:              6,
+	ArmLength:         12,
+	TrackLength:       HexBoardSize,
+	HomeStretchLength: HomeStretchSize,
+	Colors:            []PlayerColor{Blue, Red, Green, Purple, Olive, Indigo},
+	starts:            HexPlayerStartPositions,
+	entries:           HexPlayerHomeStretchEntry,
+	safe:              HexSafeZones,
+}
+
+// BoardFor returns the board used by a game with the given player capacity
+func BoardFor(maxPlayers int) *Board {
+	if maxPlayers >= 5 {
+		return HexBoard
+	}
+	return SquareBoard
+}
+
+// BoardByType looks up a board by its type name
+func BoardByType(t BoardType) (*Board, bool) {
+	switch t {
+	case BoardSquare:
+		return SquareBoard, true
+	case BoardHex:
+		return HexBoard, true
+	}
+	return nil, false
+}
+
+// HasColor reports whether a color has a seat on this board
+func (b *Board) HasColor(color PlayerColor) bool {
+	_, exists := b.starts[color]
+	return exists
+}
+
+// SeatColor returns the color assigned to the seat at the given join order
+func (b *Board) SeatColor(order int) PlayerColor {
+	return b.Colors[order%len(b.Colors)]
+}
+
+// Start returns the track square where a color's pieces enter play
+func (b *Board) Start(color PlayerColor) int {
+	return b.starts[color]
+}
+
+// HomeStretchEntry returns the last track square before a color's home stretch
+func (b *Board) HomeStretchEntry(color PlayerColor) int {
+	return b.entries[color]
+}
+
+// IsSafe reports whether a track square protects pieces from capture
+func (b *Board) IsSafe(position int) bool {
+	return b.safe[position]
+}
+
+// SafeSquares returns the safe track squares in ascending order
+func (b *Board) SafeSquares() []int {
+	squares := make([]int, 0, len(b.safe))
+	for pos := 0; pos < b.TrackLength; pos++ {
+		if b.safe[pos] {
+			squares = append(squares, pos)
+		}
+	}
+	return squares
+}
+
+// Distance returns how many squares a piece at position has travelled from its start
+func (b *Board) Distance(color PlayerColor, position int) int {
+	return (position - b.Start(color) + b.TrackLength) % b.TrackLength
+}
+
+// LapLength returns the number of squares from a color's start to its home stretch entry
+func (b *Board) LapLength(color PlayerColor) int {
+	return b.Distance(color, b.HomeStretchEntry(color))
+}
+
+// Advance moves a piece on the track by a dice roll.
+// Returns: (newPosition, enteredHomeStretch, homeStretchPosition).
+// A homeStretchPosition beyond HomeStretchLength means the roll overshoots the finish.
+func (b *Board) Advance(color PlayerColor, position, roll int) (int, bool, int) {
+	travelled := b.Distance(color, position) + roll
+	lap := b.LapLength(color)
+	if travelled > lap {
+		return -2, true, travelled - lap
+	}
+	return (b.Start(color) + travelled) % b.TrackLength, false, 0
+}
+
+// Progress scores how far along a piece is, from 0 at home to the maximum when finished
+func (b *Board) Progress(color PlayerColor, piece Piece) int {
+	lap := b.LapLength(color)
+	switch {
+	case piece.IsHome:
+		return 0
+	case piece.IsFinished:
+		return lap + b.HomeStretchLength + 1
+	case piece.HomeStretchPosition > 0:
+		return lap + piece.HomeStretchPosition + 1
+	default:
+		return b.Distance(color, piece.Position) + 1
+	}
+}
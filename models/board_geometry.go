@@ -0,0 +1,241 @@
+package models
+
+import "math"
+
+// GeometrySize is the width and height of the coordinate space used by board geometry.
+// All coordinates are cell centers, so a square-board cell spans one unit.
+const GeometrySize = 15.0
+
+// PlayerColorHex is the default display color for each player color
+var PlayerColorHex = map[PlayerColor]string{
+	Red:    "#e74c3c",
+	Blue:   "#2196F3",
+	Green:  "#4CAF50",
+	Yellow: "#f1c40f",
+	Purple: "#9C27B0",
+	Orange: "#e67e22",
+	Olive:  "#808000",
+	Indigo: "#3F51B5",
+}
+
+// Point is a position in board geometry space
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// TrackSquare describes one square of the shared track
+type TrackSquare struct {
+	Index   int         `json:"index"`
+	Point   Point       `json:"point"`
+	Safe    bool        `json:"safe"`
+	StartOf PlayerColor `json:"start_of,omitempty"`
+	EntryOf PlayerColor `json:"entry_of,omitempty"`
+}
+
+// SeatGeometry describes where one color's pieces live on the board
+type SeatGeometry struct {
+	Color            PlayerColor `json:"color"`
+	Hex              string      `json:"hex"`
+	Label            string      `json:"label"`   // Color name under the palette
+	Pattern          string      `json:"pattern"` // Shape marking the color's pieces
+	Seat             int         `json:"seat"`
+	Start            int         `json:"start"`
+	HomeStretchEntry int         `json:"home_stretch_entry"`
+	Yard             []Point     `json:"yard"`         // Resting spots for pieces at home
+	HomeStretch      []Point     `json:"home_stretch"` // Stretch squares; the last one is the finish
+}
+
+// BoardGeometry is the canonical layout of a board for renderers
+type BoardGeometry struct {
+	Type              BoardType      `json:"type"`
+	Palette           string         `json:"palette"`
+	Width             float64        `json:"width"`
+	Height            float64        `json:"height"`
+	CellSize          float64        `json:"cell_size"`
+	TrackLength       int            `json:"track_length"`
+	HomeStretchLength int            `json:"home_stretch_length"`
+	Center            Point          `json:"center"`
+	SafeSquares       []int          `json:"safe_squares"`
+	Track             []TrackSquare  `json:"track"`
+	Seats             []SeatGeometry `json:"seats"`
+}
+
+// Geometry returns the canonical 2D layout of the board
+func (b *Board) Geometry() *BoardGeometry {
+	track, yards, stretches := squareLayout()
+	cellSize := 1.0
+	if b.Type == BoardHex {
+		track, yards, stretches = hexLayout(b)
+		cellSize = GeometrySize / 20
+	}
+
+	geo := &BoardGeometry{
+		Type:              b.Type,
+		Width:             GeometrySize,
+		Height:            GeometrySize,
+		CellSize:          cellSize,
+		TrackLength:       b.TrackLength,
+		HomeStretchLength: b.HomeStretchLength,
+		Center:            Point{GeometrySize / 2, GeometrySize / 2},
+		SafeSquares:       b.SafeSquares(),
+	}
+
+	starts := make(map[int]PlayerColor)
+	entries := make(map[int]PlayerColor)
+	for seat, color := range b.Colors {
+		starts[b.Start(color)] = color
+		entries[b.HomeStretchEntry(color)] = color
+		geo.Seats = append(geo.Seats, SeatGeometry{
+			Color:            color,
+			Seat:             seat,
+			Start:            b.Start(color),
+			HomeStretchEntry: b.HomeStretchEntry(color),
+			Yard:             yards[color],
+			HomeStretch:      stretches[color],
+		})
+	}
+
+	for i, p := range track {
+		geo.Track = append(geo.Track, TrackSquare{
+			Index:   i,
+			Point:   p,
+			Safe:    b.IsSafe(i),
+			StartOf: starts[i],
+			EntryOf: entries[i],
+		})
+	}
+
+	palette, _ := PaletteByID(DefaultPalette)
+	geo.UsePalette(palette)
+	return geo
+}
+
+// UsePalette redraws the seats in a palette's colors
+func (geo *BoardGeometry) UsePalette(p Palette) {
+	geo.Palette = p.ID
+	for i := range geo.Seats {
+		swatch := p.Colors[geo.Seats[i].Color]
+		geo.Seats[i].Hex = swatch.Hex
+		geo.Seats[i].Label = swatch.Label
+		geo.Seats[i].Pattern = swatch.Pattern
+	}
+}
+
+// gridCell converts square-board grid coordinates to a cell center
+func gridCell(x, y float64) Point {
+	return Point{x + 0.5, y + 0.5}
+}
+
+// squareLayout returns the 15×15 grid layout of the square board
+func squareLayout() ([]Point, map[PlayerColor][]Point, map[PlayerColor][]Point) {
+	grid := [][2]float64{
+		// 0-12: from Red's start
+		{6, 13}, {6, 12}, {6, 11}, {6, 10}, {6, 9}, {5, 8},
+		{4, 8}, {3, 8}, {2, 8}, {1, 8}, {0, 8}, {0, 7}, {0, 6},
+		// 13-25: from Blue's start
+		{1, 6}, {2, 6}, {3, 6}, {4, 6}, {5, 6}, {6, 5},
+		{6, 4}, {6, 3}, {6, 2}, {6, 1}, {6, 0}, {7, 0}, {8, 0},
+		// 26-38: from Green's start
+		{8, 1}, {8, 2}, {8, 3}, {8, 4}, {8, 5}, {9, 6},
+		{10, 6}, {11, 6}, {12, 6}, {13, 6}, {14, 6}, {14, 7}, {14, 8},
+		// 39-51: from Yellow's start
+		{13, 8}, {12, 8}, {11, 8}, {10, 8}, {9, 8}, {8, 9},
+		{8, 10}, {8, 11}, {8, 12}, {8, 13}, {8, 14}, {7, 14}, {6, 14},
+	}
+	track := make([]Point, len(grid))
+	for i, g := range grid {
+		track[i] = gridCell(g[0], g[1])
+	}
+
+	yard := func(x, y float64) []Point {
+		return []Point{gridCell(x, y), gridCell(x+2.6, y), gridCell(x, y+2.6), gridCell(x+2.6, y+2.6)}
+	}
+	yards := map[PlayerColor][]Point{
+		Blue:   yard(1.2, 1.2),
+		Green:  yard(10.2, 1.2),
+		Red:    yard(1.2, 10.2),
+		Yellow: yard(10.2, 10.2),
+	}
+
+	stretch := func(x, y, dx, dy float64) []Point {
+		points := make([]Point, HomeStretchSize)
+		for i := range points {
+			points[i] = gridCell(x+dx*float64(i), y+dy*float64(i))
+		}
+		return points
+	}
+	stretches := map[PlayerColor][]Point{
+		Blue:   stretch(1, 7, 1, 0),
+		Green:  stretch(7, 1, 0, 1),
+		Red:    stretch(7, 13, 0, -1),
+		Yellow: stretch(13, 7, -1, 0),
+	}
+
+	return track, yards, stretches
+}
+
+// hexLayout returns the layout of the six-armed board. Each arm holds ArmLength
+// track squares: three beside the home stretch, three around the inner corner,
+// three out along the next arm, and three across the outer junction.
+func hexLayout(b *Board) ([]Point, map[PlayerColor][]Point, map[PlayerColor][]Point) {
+	const size = GeometrySize
+	center := size / 2
+	cellW, cellH := size/18, size/15
+	offset := cellW * 0.7
+	outer, mid := size*0.40, size*0.28
+
+	polar := func(angle, dist float64) Point {
+		return Point{center + math.Cos(angle)*dist, center + math.Sin(angle)*dist}
+	}
+	armAngle := func(arm int) float64 {
+		return float64(arm*60-90) * math.Pi / 180
+	}
+
+	var track []Point
+	for arm := 0; arm < b.Arms; arm++ {
+		angle, next := armAngle(arm), armAngle(arm+1)
+		perp, nextPerp := angle+math.Pi/2, next+math.Pi/2
+
+		for i := 0; i < 3; i++ {
+			p := polar(angle, outer-float64(i)*cellH)
+			track = append(track, Point{p.X - math.Cos(perp)*offset, p.Y - math.Sin(perp)*offset})
+		}
+		for i := 0; i < 3; i++ {
+			t := (float64(i) + 0.5) / 3
+			track = append(track, polar(angle+t*math.Pi/3, mid))
+		}
+		for i := 0; i < 3; i++ {
+			p := polar(next, mid+float64(i)*cellH*0.9)
+			track = append(track, Point{p.X + math.Cos(nextPerp)*offset, p.Y + math.Sin(nextPerp)*offset})
+		}
+		for i := 0; i < 3; i++ {
+			t := (float64(i) + 0.5) / 4
+			from, to := next-math.Pi/10, next+math.Pi/10
+			track = append(track, polar(from+t*(to-from), outer+cellH*0.3))
+		}
+	}
+
+	yards := make(map[PlayerColor][]Point)
+	stretches := make(map[PlayerColor][]Point)
+	for arm, color := range b.Colors {
+		angle := armAngle(arm)
+
+		c := polar(angle, size*0.38)
+		spacing := size * 0.028
+		yards[color] = []Point{
+			{c.X - spacing, c.Y - spacing}, {c.X + spacing, c.Y - spacing},
+			{c.X - spacing, c.Y + spacing}, {c.X + spacing, c.Y + spacing},
+		}
+
+		from, to := size*0.28, size*0.08
+		points := make([]Point, b.HomeStretchLength)
+		for j := range points {
+			t := float64(j) / float64(b.HomeStretchLength-1)
+			points[j] = polar(angle, from-t*(from-to))
+		}
+		stretches[color] = points
+	}
+
+	return track, yards, stretches
+}
@@ -0,0 +1,316 @@
+package models
+
+import (
+	"context"
+	"testing"
+)
+
+// newHexTestGame starts a six-player game on the hex board
+func newHexTestGame(t *testing.T) (*Game, map[PlayerColor]*Player) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "p1", "Player 1", 6)
+	for _, id := range []string{"p2", "p3", "p4", "p5", "p6"} {
+		if _, err := gm.JoinGame(context.Background(), game.Code, id, "Player "+id[1:], ""); err != nil {
+			t.Fatalf("Failed to join: %v", err)
+		}
+	}
+	for id := range game.Players {
+		game.SetPlayerReady(id, true)
+	}
+	if err := game.StartGame("p1"); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+
+	byColor := make(map[PlayerColor]*Player)
+	for _, p := range game.Players {
+		byColor[p.Color] = p
+	}
+	if len(byColor) != 6 {
+		t.Fatalf("Expected 6 distinct colors, got %d", len(byColor))
+	}
+	return game, byColor
+}
+
+// hexMove sets up a roll for a player and moves the given piece
+func hexMove(game *Game, player *Player, pieceID, roll int) error {
+	game.CurrentTurn = player.ID
+	game.HasRolled = true
+	game.LastDiceRoll = roll
+	return game.MovePiece(player.ID, pieceID)
+}
+
+func TestBoardFor(t *testing.T) {
+	for players := 2; players <= 4; players++ {
+		if BoardFor(players) != SquareBoard {
+			t.Errorf("Expected square board for %d players", players)
+		}
+	}
+	for players := 5; players <= 6; players++ {
+		if BoardFor(players) != HexBoard {
+			t.Errorf("Expected hex board for %d players", players)
+		}
+	}
+	if b, ok := BoardByType(BoardHex); !ok || b != HexBoard {
+		t.Error("Expected to look up hex board by type")
+	}
+	if _, ok := BoardByType("triangle"); ok {
+		t.Error("Unknown board type should not resolve")
+	}
+}
+
+func TestHexBoardLayout(t *testing.T) {
+	b := HexBoard
+	if b.Arms*b.ArmLength != b.TrackLength {
+		t.Errorf("Arms × arm length should cover the track: %d × %d != %d", b.Arms, b.ArmLength, b.TrackLength)
+	}
+
+	starts := make(map[int]bool)
+	for i, color := range b.Colors {
+		start := b.Start(color)
+		if start != i*b.ArmLength {
+			t.Errorf("%s should start at the beginning of arm %d, got %d", color, i, start)
+		}
+		if starts[start] {
+			t.Errorf("Start square %d is shared", start)
+		}
+		starts[start] = true
+
+		if !b.IsSafe(start) {
+			t.Errorf("%s start square %d should be safe", color, start)
+		}
+		if lap := b.LapLength(color); lap != b.TrackLength-2 {
+			t.Errorf("%s lap should be %d squares, got %d", color, b.TrackLength-2, lap)
+		}
+	}
+
+	if len(b.SafeSquares()) != 2*b.Arms {
+		t.Errorf("Expected two safe squares per arm, got %v", b.SafeSquares())
+	}
+	if b.HasColor(Yellow) {
+		t.Error("Yellow has no seat on the hex board")
+	}
+}
+
+func TestHexSeatColors(t *testing.T) {
+	game, byColor := newHexTestGame(t)
+	for _, color := range HexBoard.Colors {
+		if byColor[color] == nil {
+			t.Errorf("No player seated as %s", color)
+		}
+	}
+	if game.board() != HexBoard {
+		t.Error("Six-player game should use the hex board")
+	}
+}
+
+func TestHexEnterFromHome(t *testing.T) {
+	game, byColor := newHexTestGame(t)
+	for _, color := range HexBoard.Colors {
+		player := byColor[color]
+		if err := hexMove(game, player, 0, 6); err != nil {
+			t.Fatalf("%s failed to leave home: %v", color, err)
+		}
+		piece := player.Pieces[0]
+		if piece.IsHome || piece.Position != HexBoard.Start(color) || !piece.IsSafe {
+			t.Errorf("%s should be on its start square, got %+v", color, piece)
+		}
+	}
+}
+
+func TestHexTrackWrapsAround(t *testing.T) {
+	game, byColor := newHexTestGame(t)
+	red := byColor[Red] // Starts at 12, so passes square 71 → 0 mid-lap
+	red.Pieces[0].IsHome = false
+	red.Pieces[0].Position = 69
+
+	if err := hexMove(game, red, 0, 5); err != nil {
+		t.Fatalf("Failed to move: %v", err)
+	}
+	if red.Pieces[0].Position != 2 || red.Pieces[0].HomeStretchPosition != 0 {
+		t.Errorf("Expected red to wrap to square 2, got %+v", red.Pieces[0])
+	}
+}
+
+func TestHexHomeStretchEntryPerArm(t *testing.T) {
+	game, byColor := newHexTestGame(t)
+	for _, color := range HexBoard.Colors {
+		player := byColor[color]
+		entry := HexBoard.HomeStretchEntry(color)
+
+		// Three squares before the entry, a 5 goes two squares into the stretch
+		player.Pieces[1].IsHome = false
+		player.Pieces[1].Position = (entry - 3 + HexBoardSize) % HexBoardSize
+		if err := hexMove(game, player, 1, 5); err != nil {
+			t.Fatalf("%s failed to enter home stretch: %v", color, err)
+		}
+		if player.Pieces[1].HomeStretchPosition != 2 || !player.Pieces[1].IsSafe {
+			t.Errorf("%s should be 2 squares into the home stretch, got %+v", color, player.Pieces[1])
+		}
+
+		// Landing exactly on the entry keeps the piece on the track
+		player.Pieces[2].IsHome = false
+		player.Pieces[2].Position = (entry - 4 + HexBoardSize) % HexBoardSize
+		if err := hexMove(game, player, 2, 4); err != nil {
+			t.Fatalf("%s failed to reach entry: %v", color, err)
+		}
+		if player.Pieces[2].Position != entry || player.Pieces[2].HomeStretchPosition != 0 {
+			t.Errorf("%s should stop on its entry square %d, got %+v", color, entry, player.Pieces[2])
+		}
+	}
+}
+
+func TestHexPassesOtherEntries(t *testing.T) {
+	game, byColor := newHexTestGame(t)
+	blue := byColor[Blue] // Starts at 0, passes every other color's entry
+
+	for _, color := range HexBoard.Colors {
+		if color == Blue {
+			continue
+		}
+		entry := HexBoard.HomeStretchEntry(color)
+		blue.Pieces[0].IsHome = false
+		blue.Pieces[0].HomeStretchPosition = 0
+		blue.Pieces[0].Position = entry - 1
+		if err := hexMove(game, blue, 0, 3); err != nil {
+			t.Fatalf("Failed to move past %s entry: %v", color, err)
+		}
+		if blue.Pieces[0].HomeStretchPosition != 0 || blue.Pieces[0].Position != entry+2 {
+			t.Errorf("Blue should pass %s's entry on the track, got %+v", color, blue.Pieces[0])
+		}
+	}
+}
+
+func TestHexFinishRequiresExactRoll(t *testing.T) {
+	game, byColor := newHexTestGame(t)
+	olive := byColor[Olive]
+	entry := HexBoard.HomeStretchEntry(Olive)
+
+	olive.Pieces[0].IsHome = false
+	olive.Pieces[0].Position = entry - 1
+
+	// One square to the entry plus six in the stretch: a 6 lands on stretch square 5
+	if err := hexMove(game, olive, 0, 6); err != nil {
+		t.Fatalf("Failed to move into stretch: %v", err)
+	}
+	if olive.Pieces[0].HomeStretchPosition != 5 {
+		t.Fatalf("Expected stretch square 5, got %+v", olive.Pieces[0])
+	}
+
+	if err := hexMove(game, olive, 0, 2); err != ErrInvalidMove {
+		t.Errorf("Expected overshoot to be rejected, got %v", err)
+	}
+	game.LastDiceRoll = 2
+	for _, id := range game.GetValidMoves(olive.ID) {
+		if id == 0 {
+			t.Error("Overshooting piece should not be a valid move")
+		}
+	}
+
+	if err := hexMove(game, olive, 0, 1); err != nil {
+		t.Fatalf("Failed to finish: %v", err)
+	}
+	if !olive.Pieces[0].IsFinished {
+		t.Errorf("Expected piece to finish, got %+v", olive.Pieces[0])
+	}
+}
+
+func TestHexCaptureAndSafeSquares(t *testing.T) {
+	game, byColor := newHexTestGame(t)
+	green, purple := byColor[Green], byColor[Purple]
+
+	// Capture on an ordinary square
+	green.Pieces[0].IsHome = false
+	green.Pieces[0].Position = 28
+	purple.Pieces[0].IsHome = false
+	purple.Pieces[0].Position = 31
+
+	if err := hexMove(game, green, 0, 3); err != nil {
+		t.Fatalf("Failed to move: %v", err)
+	}
+	if !purple.Pieces[0].IsHome {
+		t.Error("Purple piece should be captured on square 31")
+	}
+
+	// No capture on a safe square
+	purple.Pieces[1].IsHome = false
+	purple.Pieces[1].Position = 39
+	green.Pieces[1].IsHome = false
+	green.Pieces[1].Position = 35
+	if err := hexMove(game, green, 1, 4); err != nil {
+		t.Fatalf("Failed to move: %v", err)
+	}
+	if purple.Pieces[1].IsHome {
+		t.Error("Purple piece on safe square 39 should not be captured")
+	}
+}
+
+func TestHexProgressIncreasesAlongLap(t *testing.T) {
+	for _, color := range HexBoard.Colors {
+		prev := HexBoard.Progress(color, Piece{IsHome: true, Position: HomePosition})
+		start := HexBoard.Start(color)
+		for step := 0; step <= HexBoard.LapLength(color); step++ {
+			piece := Piece{Position: (start + step) % HexBoardSize}
+			progress := HexBoard.Progress(color, piece)
+			if progress <= prev {
+				t.Fatalf("%s progress should increase at step %d: %d <= %d", color, step, progress, prev)
+			}
+			prev = progress
+		}
+		stretch := HexBoard.Progress(color, Piece{Position: -2, HomeStretchPosition: 1})
+		finished := HexBoard.Progress(color, Piece{IsFinished: true, HomeStretchPosition: HomeStretchSize})
+		if stretch <= prev || finished <= stretch {
+			t.Errorf("%s progress should keep increasing into the stretch: %d, %d, %d", color, prev, stretch, finished)
+		}
+	}
+}
+
+func TestSquareBoardAdvanceMatchesRules(t *testing.T) {
+	// Blue starts at 13 and enters its stretch after square 11
+	if pos, entered, _ := SquareBoard.Advance(Blue, 49, 5); entered || pos != 2 {
+		t.Errorf("Blue should wrap to square 2, got %d (entered=%v)", pos, entered)
+	}
+	if _, entered, hs := SquareBoard.Advance(Blue, 9, 4); !entered || hs != 2 {
+		t.Errorf("Blue should enter its stretch at 2, got entered=%v hs=%d", entered, hs)
+	}
+	if pos, entered, _ := SquareBoard.Advance(Red, 45, 5); entered || pos != 50 {
+		t.Errorf("Red should stop on its entry square, got %d (entered=%v)", pos, entered)
+	}
+}
+
+func TestBoardGeometry(t *testing.T) {
+	for _, b := range []*Board{SquareBoard, HexBoard} {
+		geo := b.Geometry()
+		if len(geo.Track) != b.TrackLength {
+			t.Errorf("%s: expected %d track squares, got %d", b.Type, b.TrackLength, len(geo.Track))
+		}
+		if len(geo.Seats) != len(b.Colors) {
+			t.Errorf("%s: expected %d seats, got %d", b.Type, len(b.Colors), len(geo.Seats))
+		}
+
+		seen := make(map[Point]bool)
+		for _, sq := range geo.Track {
+			if sq.Point.X < 0 || sq.Point.X > geo.Width || sq.Point.Y < 0 || sq.Point.Y > geo.Height {
+				t.Errorf("%s: square %d outside board: %+v", b.Type, sq.Index, sq.Point)
+			}
+			if seen[sq.Point] {
+				t.Errorf("%s: square %d overlaps another square", b.Type, sq.Index)
+			}
+			seen[sq.Point] = true
+		}
+
+		for _, seat := range geo.Seats {
+			if geo.Track[seat.Start].StartOf != seat.Color || !geo.Track[seat.Start].Safe {
+				t.Errorf("%s: %s start square not marked", b.Type, seat.Color)
+			}
+			if geo.Track[seat.HomeStretchEntry].EntryOf != seat.Color {
+				t.Errorf("%s: %s entry square not marked", b.Type, seat.Color)
+			}
+			if len(seat.Yard) != PiecesPerPlayer || len(seat.HomeStretch) != b.HomeStretchLength {
+				t.Errorf("%s: %s has %d yard spots and %d stretch squares", b.Type, seat.Color, len(seat.Yard), len(seat.HomeStretch))
+			}
+			if seat.Hex == "" {
+				t.Errorf("%s: %s has no display color", b.Type, seat.Color)
+			}
+		}
+	}
+}
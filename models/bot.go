@@ -0,0 +1,111 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+// BotPersonality biases how a bot chooses between valid moves
+type BotPersonality string
+
+const (
+	PersonalityBalanced   BotPersonality = "balanced"   // No preference, picks any valid move
+	PersonalityAggressive BotPersonality = "aggressive" // Prioritizes captures
+	PersonalityDefensive  BotPersonality = "defensive"  // Keeps pieces on safe squares
+	PersonalityRunner     BotPersonality = "runner"     // Races its most advanced piece
+)
+
+// BotDifficulty controls how carefully a bot chooses its moves
+type BotDifficulty string
+
+const (
+	DifficultyEasy   BotDifficulty = "easy"   // Random valid moves
+	DifficultyMedium BotDifficulty = "medium" // Prefers captures and leaving home, then its personality
+	DifficultyHard   BotDifficulty = "hard"   // Weighs captures, safety, finishing and blocking, then its personality
+)
+
+var (
+	ErrInvalidBotPersonality = errors.New("invalid bot personality")
+	ErrInvalidBotDifficulty  = errors.New("invalid bot difficulty")
+	ErrBotNameTaken          = errors.New("bot name already in use")
+)
+
+// BotOptions configures a bot added to a game
+type BotOptions struct {
+	Name        string // Optional display name; a default "Bot X" name is used when empty
+	Personality BotPersonality
+	Difficulty  BotDifficulty
+}
+
+// normalize validates the options and fills in defaults
+func (o *BotOptions) normalize() error {
+	o.Name = strings.TrimSpace(o.Name)
+	if o.Name != "" {
+		if err := ValidatePlayerName(o.Name); err != nil {
+			return err
+		}
+	}
+	personality, err := ParseBotPersonality(string(o.Personality))
+	if err != nil {
+		return err
+	}
+	difficulty, err := ParseBotDifficulty(string(o.Difficulty))
+	if err != nil {
+		return err
+	}
+	o.Personality = personality
+	o.Difficulty = difficulty
+	return nil
+}
+
+// botNameLocked picks the bot's display name, enforcing the bot name policy:
+// names follow the player name rules and must be unique within the game (caller must hold lock)
+func (g *Game) botNameLocked(requested string) (string, error) {
+	if requested == "" {
+		for i := 0; i < len(botNames); i++ {
+			name := botNames[(len(g.Players)+i)%len(botNames)]
+			if !g.nameInUseLocked(name) {
+				return name, nil
+			}
+		}
+		return botNames[len(g.Players)%len(botNames)], nil
+	}
+	if g.nameInUseLocked(requested) {
+		return "", ErrBotNameTaken
+	}
+	return requested, nil
+}
+
+// nameInUseLocked reports whether a seated player already uses the name (caller must hold lock)
+func (g *Game) nameInUseLocked(name string) bool {
+	for _, p := range g.Players {
+		if strings.EqualFold(p.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseBotDifficulty validates a difficulty name, defaulting to medium
+func ParseBotDifficulty(name string) (BotDifficulty, error) {
+	switch d := BotDifficulty(name); d {
+	case "":
+		return DifficultyMedium, nil
+	case DifficultyEasy, DifficultyMedium, DifficultyHard:
+		return d, nil
+	default:
+		return "", ErrInvalidBotDifficulty
+	}
+}
+
+// ParseBotPersonality validates a personality name, defaulting to balanced
+func ParseBotPersonality(name string) (BotPersonality, error) {
+	switch p := BotPersonality(name); p {
+	case "":
+		return PersonalityBalanced, nil
+	case PersonalityBalanced, PersonalityAggressive, PersonalityDefensive, PersonalityRunner:
+		return p, nil
+	default:
+		return "", ErrInvalidBotPersonality
+	}
+}
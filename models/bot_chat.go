@@ -0,0 +1,96 @@
+package models
+
+import "math/rand"
+
+// BotChatChance is the probability that a bot reacts to a chat-worthy event
+const BotChatChance = 0.5
+
+// Canned bot reactions by situation
+var (
+	botCaptureLines     = []string{"Gotcha!", "Back home you go!", "Sorry, not sorry 😄", "That square was mine."}
+	botCapturedLines    = []string{"Ouch!", "Hey, I was going there!", "I'll remember that...", "Noooo!"}
+	botNiceCaptureLines = []string{"Nice capture!", "Ooh, brutal.", "Good move!"}
+	botWinLines         = []string{"GG! 🎉", "Victory! Thanks for the game.", "That was fun, GG!"}
+	botLoseLines        = []string{"Well played!", "GG, you earned it.", "Rematch? 😅"}
+)
+
+// SetBotChat toggles bot chat reactions for the game (host only)
+func (g *Game) SetBotChat(hostID string, enabled bool) error {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.HostID != hostID {
+		return ErrNotHost
+	}
+	g.BotChat = enabled
+	g.LastActivity = Now()
+	return nil
+}
+
+// ConsumeBotChat reports whether bots posted chat since the last call
+func (g *Game) ConsumeBotChat() bool {
+	g.mu.Lock()
+	defer g.unlock()
+
+	pending := g.botChatPending
+	g.botChatPending = false
+	return pending
+}
+
+// botReactToCaptureLocked lets bots comment on a capture (caller must hold lock)
+func (g *Game) botReactToCaptureLocked(capturerID string, victimIDs []string) {
+	if capturer := g.Players[capturerID]; capturer != nil && capturer.IsBot {
+		g.botSayLocked(capturer, botCaptureLines)
+	}
+
+	for _, victimID := range victimIDs {
+		if victim := g.Players[victimID]; victim != nil && victim.IsBot {
+			g.botSayLocked(victim, botCapturedLines)
+			return
+		}
+	}
+
+	// A human captured another human - a bystander bot may applaud
+	if capturer := g.Players[capturerID]; capturer != nil && !capturer.IsBot {
+		if bot := g.anyBotLocked(capturerID); bot != nil {
+			g.botSayLocked(bot, botNiceCaptureLines)
+		}
+	}
+}
+
+// botReactToWinLocked lets a bot comment on the end of the game (caller must hold lock)
+func (g *Game) botReactToWinLocked(winnerID string) {
+	if winner := g.Players[winnerID]; winner != nil && winner.IsBot {
+		g.botSayLocked(winner, botWinLines)
+		return
+	}
+	if bot := g.anyBotLocked(winnerID); bot != nil {
+		g.botSayLocked(bot, botLoseLines)
+	}
+}
+
+// anyBotLocked returns a bot other than excludeID, if there is one (caller must hold lock)
+func (g *Game) anyBotLocked(excludeID string) *Player {
+	for id, player := range g.Players {
+		if id != excludeID && player.IsBot && !player.HasLeft {
+			return player
+		}
+	}
+	return nil
+}
+
+// botSayLocked occasionally posts a random line from a bot (caller must hold lock)
+func (g *Game) botSayLocked(bot *Player, lines []string) {
+	if !g.BotChat || len(lines) == 0 || rand.Float64() >= BotChatChance {
+		return
+	}
+
+	g.appendChatLocked(ChatMessage{
+		PlayerID:   bot.ID,
+		PlayerName: bot.Name,
+		Message:    lines[rand.Intn(len(lines))],
+		Timestamp:  Now(),
+		IsBot:      true,
+	})
+	g.botChatPending = true
+}
@@ -0,0 +1,151 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ExternalBotResponseTimeout is how long an external controller has to act
+// on a prompt before the built-in AI plays in its place
+const ExternalBotResponseTimeout = 10 * time.Second
+
+var (
+	ErrNotBot             = errors.New("player is not a bot")
+	ErrBotAlreadyClaimed  = errors.New("bot seat already claimed")
+	ErrInvalidPluginToken = errors.New("invalid bot controller token")
+)
+
+// BotTurnAction tells the bot loop what to do for the current turn
+type BotTurnAction int
+
+const (
+	BotTurnNone    BotTurnAction = iota // Nothing to do (not a bot turn, or waiting on a controller)
+	BotTurnBuiltIn                      // Play with the built-in AI
+	BotTurnPrompt                       // Prompt the external controller
+)
+
+// BotPrompt is sent to an external controller when its bot must act
+type BotPrompt struct {
+	GameCode    string    `json:"code"`
+	BotID       string    `json:"bot_id"`
+	HasRolled   bool      `json:"has_rolled"`
+	DiceRoll    int       `json:"dice_roll,omitempty"`
+	ValidMoves  []int     `json:"valid_moves,omitempty"`
+	Deadline    Timestamp `json:"deadline"`
+	CallbackURL string    `json:"-"`
+}
+
+// botController tracks an external process controlling a bot seat
+type botController struct {
+	token       string
+	callbackURL string
+	promptKey   string    // Decision point the last prompt was sent for
+	promptedAt  time.Time // When that prompt was sent
+}
+
+// ClaimBotSeat hands control of a bot seat to an external process (host only).
+// The returned token authenticates the controller's actions.
+func (g *Game) ClaimBotSeat(hostID, botID, callbackURL string) (string, error) {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.HostID != hostID {
+		return "", ErrNotHost
+	}
+
+	bot, exists := g.Players[botID]
+	if !exists {
+		return "", ErrPlayerNotFound
+	}
+	if !bot.IsBot {
+		return "", ErrNotBot
+	}
+	if bot.ExternallyControlled {
+		return "", ErrBotAlreadyClaimed
+	}
+
+	if g.botControllers == nil {
+		g.botControllers = make(map[string]*botController)
+	}
+	token := GenerateSessionSecret()
+	g.botControllers[botID] = &botController{token: token, callbackURL: callbackURL}
+	bot.ExternallyControlled = true
+	g.LastActivity = Now()
+	return token, nil
+}
+
+// ReleaseBotSeat returns a claimed bot seat to the built-in AI
+func (g *Game) ReleaseBotSeat(botID, token string) error {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if err := g.authorizeBotControllerLocked(botID, token); err != nil {
+		return err
+	}
+	delete(g.botControllers, botID)
+	g.Players[botID].ExternallyControlled = false
+	return nil
+}
+
+// AuthorizeBotController checks that a token controls the given bot seat
+func (g *Game) AuthorizeBotController(botID, token string) error {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.authorizeBotControllerLocked(botID, token)
+}
+
+// authorizeBotControllerLocked validates a controller token (caller must hold lock)
+func (g *Game) authorizeBotControllerLocked(botID, token string) error {
+	controller, exists := g.botControllers[botID]
+	if !exists || token == "" || controller.token != token {
+		return ErrInvalidPluginToken
+	}
+	return nil
+}
+
+// NextBotTurnAction decides how the current bot turn should be handled.
+// Externally controlled bots are prompted once per decision point and fall
+// back to the built-in AI if they don't act within ExternalBotResponseTimeout.
+func (g *Game) NextBotTurnAction() (BotTurnAction, *BotPrompt) {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.State != Playing {
+		return BotTurnNone, nil
+	}
+
+	player, exists := g.Players[g.CurrentTurn]
+	if !exists || !player.botPlays() {
+		return BotTurnNone, nil
+	}
+
+	controller, external := g.botControllers[player.ID]
+	if !external {
+		return BotTurnBuiltIn, nil
+	}
+
+	key := fmt.Sprintf("%s:%v:%d:%d", player.ID, g.HasRolled, len(g.MoveHistory), player.Stats.Rolls)
+	if controller.promptKey != key {
+		controller.promptKey = key
+		controller.promptedAt = time.Now()
+
+		prompt := &BotPrompt{
+			GameCode:    g.Code,
+			BotID:       player.ID,
+			HasRolled:   g.HasRolled,
+			Deadline:    At(controller.promptedAt.Add(ExternalBotResponseTimeout)),
+			CallbackURL: controller.callbackURL,
+		}
+		if g.HasRolled {
+			prompt.DiceRoll = g.LastDiceRoll
+			prompt.ValidMoves = g.getValidMovesInternal(player.ID)
+		}
+		return BotTurnPrompt, prompt
+	}
+
+	if time.Since(controller.promptedAt) > ExternalBotResponseTimeout {
+		return BotTurnBuiltIn, nil
+	}
+	return BotTurnNone, nil
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExternalBotPromptAndFallback(t *testing.T) {
+	game, human, bot := setupBotGame(t, BotOptions{})
+
+	if _, err := game.ClaimBotSeat("host1", human.ID, ""); err != ErrNotBot {
+		t.Errorf("Expected ErrNotBot when claiming a human seat, got %v", err)
+	}
+
+	token, err := game.ClaimBotSeat("host1", bot.ID, "")
+	if err != nil {
+		t.Fatalf("Failed to claim bot seat: %v", err)
+	}
+	if err := game.AuthorizeBotController(bot.ID, "wrong"); err != ErrInvalidPluginToken {
+		t.Errorf("Expected ErrInvalidPluginToken, got %v", err)
+	}
+
+	game.CurrentTurn = bot.ID
+	game.HasRolled = false
+
+	action, prompt := game.NextBotTurnAction()
+	if action != BotTurnPrompt || prompt == nil || prompt.BotID != bot.ID {
+		t.Fatalf("Expected a prompt for %s, got %v %+v", bot.ID, action, prompt)
+	}
+
+	// Same decision point: wait for the controller rather than re-prompting
+	if action, _ := game.NextBotTurnAction(); action != BotTurnNone {
+		t.Errorf("Expected to wait for the controller, got %v", action)
+	}
+
+	// Controller missed its window - the built-in AI takes over
+	game.botControllers[bot.ID].promptedAt = time.Now().Add(-2 * ExternalBotResponseTimeout)
+	if action, _ := game.NextBotTurnAction(); action != BotTurnBuiltIn {
+		t.Errorf("Expected built-in fallback, got %v", action)
+	}
+
+	if err := game.ReleaseBotSeat(bot.ID, token); err != nil {
+		t.Fatalf("Failed to release bot seat: %v", err)
+	}
+	if action, _ := game.NextBotTurnAction(); action != BotTurnBuiltIn {
+		t.Errorf("Expected released seat to use the built-in AI, got %v", action)
+	}
+}
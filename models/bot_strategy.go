@@ -0,0 +1,273 @@
+package models
+
+import "math/rand"
+
+// BotMove describes what one valid move would do, for a strategy to weigh
+type BotMove struct {
+	PieceID      int
+	Captures     bool // Sends at least one opponent piece home
+	CaptureValue int  // Total progress of the opponent pieces sent home
+	LeavesHome   bool // Brings a piece out onto the track
+	LandsSafe    bool // Ends on a safe square, in the home stretch, or finished
+	LeavesSafe   bool // Moves off a safe square onto an unsafe one
+	Finishes     bool
+	Blocks       bool // Joins another of the player's pieces on the track
+	Progress     int  // Distance the piece had already traveled before the move
+	Gain         int  // Distance the move adds
+	ThreatsAfter int  // Opponent pieces that could capture the piece on their next roll
+	ThreatsNow   int  // Opponent pieces that could capture the piece where it stands
+}
+
+// BotStrategy chooses a bot's move from its valid moves. The personality
+// biases strategies that score moves; easy ignores it.
+type BotStrategy interface {
+	ChooseMove(moves []BotMove, personality BotPersonality) (pieceID int, ok bool)
+}
+
+// EasyStrategy plays any valid move
+type EasyStrategy struct{}
+
+// MediumStrategy takes captures first, then brings pieces out, then follows its personality
+type MediumStrategy struct{}
+
+// HardStrategy scores every move on capture value, safety, finishing and
+// blocking, and uses its personality to break close calls
+type HardStrategy struct{}
+
+// StrategyFor returns the strategy bots of a difficulty play with
+func StrategyFor(d BotDifficulty) BotStrategy {
+	switch d {
+	case DifficultyEasy:
+		return EasyStrategy{}
+	case DifficultyHard:
+		return HardStrategy{}
+	default:
+		return MediumStrategy{}
+	}
+}
+
+// ChooseMove picks a random valid move
+func (EasyStrategy) ChooseMove(moves []BotMove, _ BotPersonality) (int, bool) {
+	if len(moves) == 0 {
+		return -1, false
+	}
+	return moves[rand.Intn(len(moves))].PieceID, true
+}
+
+// ChooseMove picks a capture, else a move out of home, else the personality's favorite
+func (MediumStrategy) ChooseMove(moves []BotMove, personality BotPersonality) (int, bool) {
+	return bestMove(moves, func(m BotMove) int {
+		score := personality.scoreMove(m)
+		if m.Captures {
+			score += 200
+		}
+		if m.LeavesHome {
+			score += 150
+		}
+		return score
+	})
+}
+
+// ChooseMove picks the move with the best heuristic score
+func (HardStrategy) ChooseMove(moves []BotMove, personality BotPersonality) (int, bool) {
+	return bestMove(moves, func(m BotMove) int {
+		score := personality.scoreMove(m) / 4
+		if m.Finishes {
+			score += 120
+		}
+		if m.Captures {
+			score += 60 + m.CaptureValue
+		}
+		if m.LeavesHome {
+			score += 40
+		}
+		if m.LandsSafe {
+			score += 10
+		}
+		if m.Blocks {
+			score += 8
+		}
+		// Rescue pieces in danger, the further along the more it's worth
+		if m.ThreatsNow > 0 && m.ThreatsAfter == 0 {
+			score += 25 + m.Progress/2
+		}
+		if m.ThreatsAfter > 0 {
+			score -= 15*m.ThreatsAfter + (m.Progress+m.Gain)/2
+		}
+		return score + m.Gain
+	})
+}
+
+// bestMove picks the best-scoring move, breaking ties randomly
+func bestMove(moves []BotMove, score func(BotMove) int) (int, bool) {
+	if len(moves) == 0 {
+		return -1, false
+	}
+
+	var best []int
+	bestScore := 0
+	for _, m := range moves {
+		s := score(m)
+		if len(best) == 0 || s > bestScore {
+			best = []int{m.PieceID}
+			bestScore = s
+		} else if s == bestScore {
+			best = append(best, m.PieceID)
+		}
+	}
+	return best[rand.Intn(len(best))], true
+}
+
+// scoreMove rates a move for a personality; higher is better
+func (p BotPersonality) scoreMove(m BotMove) int {
+	score := 0
+	switch p {
+	case PersonalityAggressive:
+		if m.Captures {
+			score += 100
+		}
+		score += m.Progress / 4
+	case PersonalityDefensive:
+		if m.LandsSafe {
+			score += 60
+		}
+		if m.LeavesSafe {
+			score -= 40
+		}
+		if m.Finishes {
+			score += 30
+		}
+	case PersonalityRunner:
+		score += m.Progress
+		if m.Finishes {
+			score += 100
+		}
+	}
+	return score
+}
+
+// evaluateMovesLocked simulates every valid move for a player (caller must hold lock)
+func (g *Game) evaluateMovesLocked(playerID string) []BotMove {
+	player, exists := g.Players[playerID]
+	if !exists {
+		return nil
+	}
+
+	board := BoardFor(g.MaxPlayers)
+	state := g.engineStateLocked()
+	opponentsHome := countOpponentPiecesHome(state.Players, playerID)
+
+	var moves []BotMove
+	for _, pieceID := range g.getValidMovesInternal(playerID) {
+		next, err := ApplyEvent(state, EngineEvent{Type: EventMove, PlayerID: playerID, PieceID: pieceID})
+		if err != nil {
+			continue
+		}
+
+		before := player.Pieces[pieceID]
+		after := next.Players[playerID].Pieces[pieceID]
+		progress := board.Progress(player.Color, before)
+		moves = append(moves, BotMove{
+			PieceID:      pieceID,
+			Captures:     countOpponentPiecesHome(next.Players, playerID) > opponentsHome,
+			CaptureValue: capturedProgress(board, state.Players, next.Players, playerID),
+			LeavesHome:   before.IsHome && !after.IsHome,
+			LandsSafe:    after.IsSafe || after.IsFinished || after.HomeStretchPosition > 0,
+			LeavesSafe:   before.IsSafe && !before.IsHome && !after.IsSafe,
+			Finishes:     after.IsFinished,
+			Blocks:       joinsOwnPiece(next.Players[playerID], pieceID),
+			Progress:     progress,
+			Gain:         board.Progress(player.Color, after) - progress,
+			ThreatsAfter: threatsTo(board, next.Players, playerID, after),
+			ThreatsNow:   threatsTo(board, state.Players, playerID, before),
+		})
+	}
+	return moves
+}
+
+// countOpponentPiecesHome counts opponent pieces sitting at home
+func countOpponentPiecesHome(players map[string]*Player, playerID string) int {
+	count := 0
+	for id, player := range players {
+		if id == playerID {
+			continue
+		}
+		for _, piece := range player.Pieces {
+			if piece.IsHome {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// pieceProgress returns how far a piece has traveled from its home
+func pieceProgress(color PlayerColor, piece Piece, maxPlayers int) int {
+	return BoardFor(maxPlayers).Progress(color, piece)
+}
+
+// capturedProgress totals the progress of the opponent pieces a move sent home
+func capturedProgress(board *Board, before, after map[string]*Player, playerID string) int {
+	total := 0
+	for id, player := range before {
+		if id == playerID {
+			continue
+		}
+		for i, piece := range player.Pieces {
+			if !piece.IsHome && after[id].Pieces[i].IsHome {
+				total += board.Progress(player.Color, piece)
+			}
+		}
+	}
+	return total
+}
+
+// joinsOwnPiece reports whether a piece shares its track square with another of the player's pieces
+func joinsOwnPiece(player *Player, pieceID int) bool {
+	moved := player.Pieces[pieceID]
+	if !onTrack(moved) {
+		return false
+	}
+	for i, piece := range player.Pieces {
+		if i != pieceID && onTrack(piece) && piece.Position == moved.Position {
+			return true
+		}
+	}
+	return false
+}
+
+// threatsTo counts opponent pieces that could land on a piece with their next
+// roll. Pieces on safe squares, in the home stretch or at home can't be captured.
+func threatsTo(board *Board, players map[string]*Player, playerID string, piece Piece) int {
+	if !onTrack(piece) || piece.IsSafe || board.IsSafe(piece.Position) {
+		return 0
+	}
+
+	threats := 0
+	for id, opponent := range players {
+		if id == playerID {
+			continue
+		}
+		for _, other := range opponent.Pieces {
+			if other.IsHome {
+				if piece.Position == board.Start(opponent.Color) {
+					threats++ // Comes out onto it with a six
+				}
+				continue
+			}
+			if !onTrack(other) {
+				continue
+			}
+			ahead := (piece.Position - other.Position + board.TrackLength) % board.TrackLength
+			if ahead >= 1 && ahead <= 6 && board.Distance(opponent.Color, other.Position)+ahead <= board.LapLength(opponent.Color) {
+				threats++
+			}
+		}
+	}
+	return threats
+}
+
+// onTrack reports whether a piece is on the shared track
+func onTrack(piece Piece) bool {
+	return !piece.IsHome && !piece.IsFinished && piece.HomeStretchPosition == 0
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStrategyFor(t *testing.T) {
+	cases := map[BotDifficulty]BotStrategy{
+		DifficultyEasy:   EasyStrategy{},
+		DifficultyMedium: MediumStrategy{},
+		DifficultyHard:   HardStrategy{},
+		"":               MediumStrategy{},
+	}
+	for difficulty, want := range cases {
+		if got := StrategyFor(difficulty); got != want {
+			t.Errorf("StrategyFor(%q) = %T, want %T", difficulty, got, want)
+		}
+	}
+	if _, ok := (HardStrategy{}).ChooseMove(nil, PersonalityBalanced); ok {
+		t.Error("Expected no move without valid moves")
+	}
+}
+
+func TestMediumBotLeavesHome(t *testing.T) {
+	game, _, bot := setupBotGame(t, BotOptions{Difficulty: DifficultyMedium})
+
+	start := GetStartPosition(bot.Color, game.MaxPlayers)
+	bot.Pieces[1].IsHome = false
+	bot.Pieces[1].Position = start + 10
+
+	game.CurrentTurn = bot.ID
+	game.HasRolled = true
+	game.LastDiceRoll = 6
+
+	for i := 0; i < 20; i++ {
+		if pieceID, _ := game.GetBotMove(); pieceID == 1 {
+			t.Fatal("Expected medium bot to bring a piece out rather than advance")
+		}
+	}
+}
+
+func TestHumanSeatGetsRecommendedMove(t *testing.T) {
+	game, human, _ := setupBotGame(t, BotOptions{Difficulty: DifficultyEasy})
+
+	// A timed-out or stood-in human plays like a medium bot, not at random
+	start := GetStartPosition(human.Color, game.MaxPlayers)
+	human.Pieces[1].IsHome = false
+	human.Pieces[1].Position = start + 10
+
+	game.CurrentTurn = human.ID
+	game.HasRolled = true
+	game.LastDiceRoll = 6
+
+	game.mu.Lock()
+	defer game.mu.Unlock()
+	for i := 0; i < 20; i++ {
+		if pieceID, _ := game.pickMoveLocked(human.ID); pieceID == 1 {
+			t.Fatal("Expected the human's seat to bring a piece out rather than advance")
+		}
+	}
+}
+
+func TestHardBotRescuesThreatenedPiece(t *testing.T) {
+	game, human, bot := setupBotGame(t, BotOptions{Difficulty: DifficultyHard})
+	board := BoardFor(game.MaxPlayers)
+
+	// Piece 0 has a human piece two squares behind it; piece 1 is in no danger
+	start := GetStartPosition(bot.Color, game.MaxPlayers)
+	bot.Pieces[0].IsHome = false
+	bot.Pieces[0].Position = start + 20
+	bot.Pieces[1].IsHome = false
+	bot.Pieces[1].Position = start + 40
+	human.Pieces[0].IsHome = false
+	human.Pieces[0].Position = start + 18
+	if board.IsSafe(start+20) || board.IsSafe(start+18) {
+		t.Fatal("Test squares must not be safe")
+	}
+
+	game.CurrentTurn = bot.ID
+	game.HasRolled = true
+	game.LastDiceRoll = 5
+
+	for i := 0; i < 20; i++ {
+		if pieceID, _ := game.GetBotMove(); pieceID != 0 {
+			t.Fatalf("Expected hard bot to move its threatened piece, got %d", pieceID)
+		}
+	}
+}
+
+func TestHardBotBeatsEasyBot(t *testing.T) {
+	if testing.Short() {
+		t.Skip("plays many games")
+	}
+
+	gm := NewGameManager()
+	const games = 200
+	hardWins := 0
+	for i := 0; i < games; i++ {
+		// Alternate who moves first
+		players := []FixturePlayer{
+			{ID: "hard", IsBot: true, BotDifficulty: string(DifficultyHard)},
+			{ID: "easy", IsBot: true, BotDifficulty: string(DifficultyEasy)},
+		}
+		if i%2 == 1 {
+			players[0], players[1] = players[1], players[0]
+		}
+		game, err := gm.CreateFixtureGame(context.Background(), Fixture{MaxPlayers: 2, Players: players})
+		if err != nil {
+			t.Fatalf("Failed to create game: %v", err)
+		}
+		result, err := game.AdminFastForward()
+		if err != nil || !result.Finished {
+			t.Fatalf("Game didn't finish: %+v (%v)", result, err)
+		}
+		if result.Winner == "hard" {
+			hardWins++
+		}
+		gm.RemoveGame(game.Code)
+	}
+
+	if hardWins < games*3/5 {
+		t.Errorf("Expected hard bots to win most games against easy ones, won %d of %d", hardWins, games)
+	}
+}
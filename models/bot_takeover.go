@@ -0,0 +1,101 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// MaxBotTakeoverDelay is the longest a game may wait before a bot plays for a
+// disconnected player
+const MaxBotTakeoverDelay = 10 * time.Minute
+
+var (
+	ErrInvalidBotTakeover = errors.New("invalid bot takeover delay")
+	ErrPlayerConnected    = errors.New("player is still connected")
+)
+
+// botPlays reports whether the built-in bot plays this seat's turns, because it
+// is a bot or its human is away
+func (p *Player) botPlays() bool {
+	return p.IsBot || p.ControlledByBot
+}
+
+// SetBotTakeover has a bot play for any human who has been disconnected for
+// delay, until they reconnect; zero leaves their turns to time out (host only)
+func (g *Game) SetBotTakeover(hostID string, delay time.Duration) error {
+	if delay < 0 || delay > MaxBotTakeoverDelay {
+		return ErrInvalidBotTakeover
+	}
+
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.HostID != hostID {
+		return ErrNotHost
+	}
+	g.BotTakeoverDelay = delay
+	g.LastActivity = Now()
+	return nil
+}
+
+// HandToBot has a bot play for a disconnected player straight away, until they
+// reconnect (host only, once the game has started)
+func (g *Game) HandToBot(hostID, playerID string) error {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.HostID != hostID {
+		return ErrNotHost
+	}
+	if g.State != Playing && g.State != Paused {
+		return ErrGameNotPlaying
+	}
+	player, exists := g.Players[playerID]
+	if !exists || player.IsBot || player.HasLeft {
+		return ErrPlayerNotFound
+	}
+	if player.DisconnectedAt.IsZero() {
+		return ErrPlayerConnected
+	}
+	player.ControlledByBot = true
+	g.LastActivity = Now()
+	return nil
+}
+
+// HandOverDisconnected has a bot take over every human who has been
+// disconnected for the game's BotTakeoverDelay, returning those newly handed over
+func (g *Game) HandOverDisconnected() []string {
+	// Checked under the read lock first, as this runs for every game every second
+	g.mu.RLock()
+	due := len(g.takeoversDueLocked()) > 0
+	g.mu.RUnlock()
+	if !due {
+		return nil
+	}
+
+	g.mu.Lock()
+	defer g.unlock()
+
+	ids := g.takeoversDueLocked()
+	for _, id := range ids {
+		g.Players[id].ControlledByBot = true
+	}
+	return ids
+}
+
+// takeoversDueLocked returns the humans a bot should now play for (caller must hold lock)
+func (g *Game) takeoversDueLocked() []string {
+	if g.BotTakeoverDelay <= 0 || g.State != Playing {
+		return nil
+	}
+	var ids []string
+	for id, player := range g.Players {
+		if player.botPlays() || player.HasLeft || player.DisconnectedAt.IsZero() {
+			continue
+		}
+		if time.Since(player.DisconnectedAt.Time) >= g.BotTakeoverDelay {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
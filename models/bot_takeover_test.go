@@ -0,0 +1,79 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBotTakeover(t *testing.T) {
+	game := newPauseTestGame(t)
+
+	if err := game.SetBotTakeover("p2", time.Minute); err != ErrNotHost {
+		t.Errorf("Expected ErrNotHost, got %v", err)
+	}
+	if err := game.SetBotTakeover("p1", time.Hour); err != ErrInvalidBotTakeover {
+		t.Errorf("Expected ErrInvalidBotTakeover, got %v", err)
+	}
+	if ids := game.HandOverDisconnected(); len(ids) != 0 {
+		t.Fatalf("Expected nobody handed over while takeover is off, got %v", ids)
+	}
+	if err := game.SetBotTakeover("p1", time.Minute); err != nil {
+		t.Fatalf("Failed to set the takeover delay: %v", err)
+	}
+
+	game.CurrentTurn = "p2"
+	game.SetConnected("p2", false)
+	if ids := game.HandOverDisconnected(); len(ids) != 0 {
+		t.Fatalf("Expected p2 given time to come back, got %v", ids)
+	}
+	if action, _ := game.NextBotTurnAction(); action != BotTurnNone {
+		t.Errorf("Expected the bot to wait, got %v", action)
+	}
+
+	game.Players["p2"].DisconnectedAt = At(time.Now().Add(-time.Minute))
+	if ids := game.HandOverDisconnected(); len(ids) != 1 || ids[0] != "p2" {
+		t.Fatalf("Expected the bot to take over for p2, got %v", ids)
+	}
+	if !game.Players["p2"].ControlledByBot || !game.IsCurrentPlayerBot() {
+		t.Error("Expected p2's seat controlled by the bot")
+	}
+	if action, _ := game.NextBotTurnAction(); action != BotTurnBuiltIn {
+		t.Errorf("Expected the built-in bot to play p2's turn, got %v", action)
+	}
+	if ids := game.HandOverDisconnected(); len(ids) != 0 {
+		t.Errorf("Expected each takeover reported once, got %v", ids)
+	}
+
+	// Reconnecting reclaims the seat
+	game.SetConnected("p2", true)
+	if game.Players["p2"].ControlledByBot || game.IsCurrentPlayerBot() {
+		t.Error("Expected p2 back in control after reconnecting")
+	}
+}
+
+func TestHandToBot(t *testing.T) {
+	game := newPauseTestGame(t)
+
+	if err := game.HandToBot("p2", "p3"); err != ErrNotHost {
+		t.Errorf("Expected ErrNotHost, got %v", err)
+	}
+	if err := game.HandToBot("p1", "p3"); err != ErrPlayerConnected {
+		t.Errorf("Expected ErrPlayerConnected, got %v", err)
+	}
+	game.SetConnected("p3", false)
+	if err := game.HandToBot("p1", "p3"); err != nil {
+		t.Fatalf("Failed to hand p3's seat to the bot: %v", err)
+	}
+	if state := game.GetGameState(); !state["players"].(map[string]*Player)["p3"].ControlledByBot {
+		t.Error("Expected the game state to show p3 controlled by the bot")
+	}
+
+	// The stand-in plays p3's whole turn
+	game.CurrentTurn = "p3"
+	game.HasRolled = false
+	game.TurnStartTime = Now()
+	game.RollDice("p3")
+	if _, hasMove := game.GetBotMove(); hasMove != (len(game.GetValidMoves("p3")) > 0) {
+		t.Error("Expected the bot to pick p3's move")
+	}
+}
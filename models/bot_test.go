@@ -0,0 +1,181 @@
+package models
+
+import (
+	"context"
+	"testing"
+)
+
+// setupBotGame starts a 2-player game between a human host and one bot
+func setupBotGame(t *testing.T, opts BotOptions) (*Game, *Player, *Player) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 2)
+	_, bot, err := gm.AddBot(context.Background(), game.Code, "host1", opts)
+	if err != nil {
+		t.Fatalf("Failed to add bot: %v", err)
+	}
+	game.SetPlayerReady("host1", true)
+	game.StartGame("host1")
+	return game, game.Players["host1"], bot
+}
+
+func TestAddBotPersonality(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+
+	if _, _, err := gm.AddBot(context.Background(), game.Code, "host1", BotOptions{Personality: "reckless"}); err != ErrInvalidBotPersonality {
+		t.Errorf("Expected ErrInvalidBotPersonality, got %v", err)
+	}
+
+	_, bot, err := gm.AddBot(context.Background(), game.Code, "host1", BotOptions{})
+	if err != nil {
+		t.Fatalf("Failed to add bot: %v", err)
+	}
+	if bot.BotPersonality != PersonalityBalanced {
+		t.Errorf("Expected default personality balanced, got %s", bot.BotPersonality)
+	}
+}
+
+func TestAddBotOptions(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+
+	if _, _, err := gm.AddBot(context.Background(), game.Code, "host1", BotOptions{Name: "host"}); err != ErrBotNameTaken {
+		t.Errorf("Expected ErrBotNameTaken, got %v", err)
+	}
+	if _, _, err := gm.AddBot(context.Background(), game.Code, "host1", BotOptions{Name: "   "}); err != nil {
+		t.Errorf("Expected blank name to fall back to default, got %v", err)
+	}
+
+	_, bot, err := gm.AddBot(context.Background(), game.Code, "host1", BotOptions{Name: " Robo ", Difficulty: DifficultyEasy, Personality: PersonalityRunner})
+	if err != nil {
+		t.Fatalf("Failed to add bot: %v", err)
+	}
+	if bot.Name != "Robo" || bot.BotDifficulty != DifficultyEasy || bot.BotPersonality != PersonalityRunner {
+		t.Errorf("Unexpected bot: %s %s %s", bot.Name, bot.BotDifficulty, bot.BotPersonality)
+	}
+	if len(game.Players) != 3 {
+		t.Errorf("Expected 3 players, got %d", len(game.Players))
+	}
+}
+
+func TestFillWithBots(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+	gm.JoinGame(context.Background(), game.Code, "p2", "Player 2", "")
+
+	if _, _, err := gm.FillWithBots(context.Background(), game.Code, "p2", BotOptions{}); err != ErrNotHost {
+		t.Errorf("Expected ErrNotHost, got %v", err)
+	}
+	if _, _, err := gm.FillWithBots(context.Background(), game.Code, "host1", BotOptions{Difficulty: "impossible"}); err != ErrInvalidBotDifficulty {
+		t.Errorf("Expected ErrInvalidBotDifficulty, got %v", err)
+	}
+
+	_, bots, err := gm.FillWithBots(context.Background(), game.Code, "host1", BotOptions{Difficulty: DifficultyHard})
+	if err != nil {
+		t.Fatalf("Failed to fill with bots: %v", err)
+	}
+	if len(bots) != 2 || len(game.Players) != 4 {
+		t.Fatalf("Expected 2 bots and 4 players, got %d bots and %d players", len(bots), len(game.Players))
+	}
+	colors := make(map[PlayerColor]bool)
+	for _, p := range game.Players {
+		colors[p.Color] = true
+	}
+	if len(colors) != 4 {
+		t.Errorf("Expected distinct colors, got %v", colors)
+	}
+	for _, bot := range bots {
+		if bot.BotDifficulty != DifficultyHard || bot.BotPersonality != PersonalityBalanced {
+			t.Errorf("Unexpected bot options: %s/%s", bot.BotDifficulty, bot.BotPersonality)
+		}
+	}
+
+	if _, _, err := gm.FillWithBots(context.Background(), game.Code, "host1", BotOptions{}); err != ErrGameFull {
+		t.Errorf("Expected ErrGameFull, got %v", err)
+	}
+}
+
+func TestAggressiveBotPrefersCapture(t *testing.T) {
+	game, human, bot := setupBotGame(t, BotOptions{Personality: PersonalityAggressive})
+
+	// Bot piece 0 can capture the human piece 3 squares ahead; piece 1 cannot capture anything
+	start := GetStartPosition(bot.Color, game.MaxPlayers)
+	bot.Pieces[0].IsHome = false
+	bot.Pieces[0].Position = start + 2
+	bot.Pieces[1].IsHome = false
+	bot.Pieces[1].Position = start + 20
+
+	human.Pieces[0].IsHome = false
+	human.Pieces[0].Position = start + 5
+
+	game.CurrentTurn = bot.ID
+	game.HasRolled = true
+	game.LastDiceRoll = 3
+
+	for i := 0; i < 20; i++ {
+		pieceID, ok := game.GetBotMove()
+		if !ok || pieceID != 0 {
+			t.Fatalf("Expected aggressive bot to capture with piece 0, got %d (%v)", pieceID, ok)
+		}
+	}
+}
+
+func TestRunnerBotRacesLeadingPiece(t *testing.T) {
+	game, _, bot := setupBotGame(t, BotOptions{Personality: PersonalityRunner})
+
+	start := GetStartPosition(bot.Color, game.MaxPlayers)
+	bot.Pieces[0].IsHome = false
+	bot.Pieces[0].Position = start + 4
+	bot.Pieces[1].IsHome = false
+	bot.Pieces[1].Position = start + 30
+
+	game.CurrentTurn = bot.ID
+	game.HasRolled = true
+	game.LastDiceRoll = 2
+
+	for i := 0; i < 20; i++ {
+		if pieceID, _ := game.GetBotMove(); pieceID != 1 {
+			t.Fatalf("Expected runner bot to move its leading piece, got %d", pieceID)
+		}
+	}
+}
+
+func TestBotChatReactsToCapture(t *testing.T) {
+	game, human, bot := setupBotGame(t, BotOptions{})
+
+	for i := 0; i < 50 && len(game.ChatMessages) == 0; i++ {
+		start := GetStartPosition(human.Color, game.MaxPlayers)
+		human.Pieces[0].IsHome = false
+		human.Pieces[0].Position = start + 2
+		bot.Pieces[0].IsHome = false
+		bot.Pieces[0].Position = start + 5
+
+		game.CurrentTurn = human.ID
+		game.HasRolled = true
+		game.LastDiceRoll = 3
+		if err := game.MovePiece(human.ID, 0); err != nil {
+			t.Fatalf("Failed to move piece: %v", err)
+		}
+	}
+
+	if len(game.ChatMessages) == 0 {
+		t.Fatal("Expected the captured bot to react in chat")
+	}
+	msg := game.ChatMessages[0]
+	if !msg.IsBot || msg.PlayerID != bot.ID {
+		t.Errorf("Expected bot chat from %s, got %+v", bot.ID, msg)
+	}
+	if !game.ConsumeBotChat() || game.ConsumeBotChat() {
+		t.Error("Expected ConsumeBotChat to report pending chat exactly once")
+	}
+
+	// Disabled bot chat stays silent
+	game.SetBotChat("host1", false)
+	game.ChatMessages = nil
+	for i := 0; i < 20; i++ {
+		game.botReactToCaptureLocked(human.ID, []string{bot.ID})
+	}
+	if len(game.ChatMessages) != 0 {
+		t.Errorf("Expected no bot chat when disabled, got %d messages", len(game.ChatMessages))
+	}
+}
@@ -0,0 +1,334 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Leaderboard orderings
+const (
+	SortByWins    = "wins"
+	SortByGames   = "games"
+	SortByWinRate = "win_rate"
+	SortByRating  = "rating"
+)
+
+// Leaderboard page sizes
+const (
+	DefaultLeaderboardLimit = 20
+	MaxLeaderboardLimit     = 100
+)
+
+// StatsNamespace is where player stats are kept in a NamespacedStorage
+const StatsNamespace = "players"
+
+var ErrUnknownLeaderboardSort = errors.New("unknown leaderboard sort")
+
+// CareerStats accumulates how a player has done across every finished game.
+// Bots aren't tracked.
+type CareerStats struct {
+	PlayerID         string    `json:"player_id"`
+	Name             string    `json:"name"` // Name used in the latest game
+	GamesPlayed      int       `json:"games_played"`
+	Wins             int       `json:"wins"`
+	WinRate          float64   `json:"win_rate"`
+	CapturesMade     int       `json:"captures_made"`
+	PiecesLost       int       `json:"pieces_lost"` // Own pieces captured
+	TotalDurationMs  int64     `json:"total_duration_ms"`
+	AvgDurationMs    int64     `json:"avg_game_duration_ms"`
+	WinStreak        int       `json:"win_streak"` // Wins in a row up to the latest game
+	LongestWinStreak int       `json:"longest_win_streak"`
+	Rating           int       `json:"rating"`      // Elo rating from ranked games
+	RatedGames       int       `json:"rated_games"` // Ranked games played
+	LastPlayedAt     Timestamp `json:"last_played_at"`
+}
+
+// LeaderboardQuery selects a page of the leaderboard
+type LeaderboardQuery struct {
+	Sort   string // SortByWins (default), SortByGames, SortByWinRate or SortByRating
+	Offset int
+	Limit  int // Default DefaultLeaderboardLimit, at most MaxLeaderboardLimit
+}
+
+// Leaderboard is one page of players, best first
+type Leaderboard struct {
+	Sort    string        `json:"sort"`
+	Offset  int           `json:"offset"`
+	Limit   int           `json:"limit"`
+	Total   int           `json:"total"` // Players ranked; by rating, only those with ranked games
+	Players []CareerStats `json:"players"`
+}
+
+// StatsStore keeps career stats keyed by player ID. With storage attached, each
+// change is saved in the background like games are.
+type StatsStore struct {
+	careers map[string]*CareerStats
+	storage Storage
+	onError StorageErrorHook
+	dirty   map[string]bool
+	wake    chan struct{}
+	mu      sync.RWMutex
+	io      sync.Mutex // Held while flushing
+}
+
+// NewStatsStore creates an empty stats store
+func NewStatsStore() *StatsStore {
+	return &StatsStore{
+		careers: make(map[string]*CareerStats),
+		dirty:   make(map[string]bool),
+	}
+}
+
+// Stats returns the player stats store
+func (gm *GameManager) Stats() *StatsStore {
+	return gm.stats
+}
+
+// Record adds a finished game to the stats of every human seat in it. For a
+// ranked game, returns how much each human's rating changed.
+func (s *StatsStore) Record(game ArchivedGame) map[string]int {
+	if game.Winner == "" {
+		return nil
+	}
+
+	s.mu.Lock()
+	for _, seat := range game.Seats {
+		if seat.IsBot {
+			continue
+		}
+		c, exists := s.careers[seat.PlayerID]
+		if !exists {
+			c = &CareerStats{PlayerID: seat.PlayerID, Rating: DefaultRating}
+			s.careers[seat.PlayerID] = c
+		}
+		c.Name = seat.Name
+		c.GamesPlayed++
+		c.CapturesMade += seat.Captures
+		c.PiecesLost += seat.CapturesTaken
+		c.TotalDurationMs += game.DurationMs
+		c.LastPlayedAt = game.EndedAt
+		if seat.PlayerID == game.Winner {
+			c.Wins++
+			c.WinStreak++
+			c.LongestWinStreak = max(c.LongestWinStreak, c.WinStreak)
+		} else {
+			c.WinStreak = 0
+		}
+		c.finish()
+		s.dirty[seat.PlayerID] = true
+	}
+	var changes map[string]int
+	if game.Ranked {
+		changes = s.rateLocked(game.Seats)
+	}
+	wake := s.wake
+	s.mu.Unlock()
+
+	if wake != nil {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+	return changes
+}
+
+// finish works out the averages
+func (c *CareerStats) finish() {
+	if c.GamesPlayed == 0 {
+		return
+	}
+	c.WinRate = float64(c.Wins) / float64(c.GamesPlayed)
+	c.AvgDurationMs = c.TotalDurationMs / int64(c.GamesPlayed)
+}
+
+// Get returns a player's stats
+func (s *StatsStore) Get(playerID string) (CareerStats, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, exists := s.careers[playerID]
+	if !exists {
+		return CareerStats{}, false
+	}
+	return *c, true
+}
+
+// All returns every player's stats, by player ID
+func (s *StatsStore) All() []CareerStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	all := make([]CareerStats, 0, len(s.careers))
+	for _, c := range s.careers {
+		all = append(all, *c)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].PlayerID < all[j].PlayerID })
+	return all
+}
+
+// Restore adds saved stats for players not already tracked
+func (s *StatsStore) Restore(careers []CareerStats) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range careers {
+		if _, exists := s.careers[c.PlayerID]; exists || c.PlayerID == "" {
+			continue
+		}
+		saved := c
+		if saved.Rating == 0 {
+			saved.Rating = DefaultRating
+		}
+		saved.finish()
+		s.careers[c.PlayerID] = &saved
+	}
+}
+
+// Leaderboard ranks players by the query's sort, breaking ties by wins, then
+// games played, then player ID
+func (s *StatsStore) Leaderboard(q LeaderboardQuery) (*Leaderboard, error) {
+	if q.Sort == "" {
+		q.Sort = SortByWins
+	}
+	key, err := leaderboardKey(q.Sort)
+	if err != nil {
+		return nil, err
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultLeaderboardLimit
+	}
+	q.Limit = min(q.Limit, MaxLeaderboardLimit)
+	q.Offset = max(q.Offset, 0)
+
+	players := s.All()
+	if q.Sort == SortByRating {
+		rated := players[:0]
+		for _, c := range players {
+			if c.RatedGames > 0 {
+				rated = append(rated, c)
+			}
+		}
+		players = rated
+	}
+	sort.SliceStable(players, func(i, j int) bool {
+		a, b := players[i], players[j]
+		if ka, kb := key(a), key(b); ka != kb {
+			return ka > kb
+		}
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		return a.GamesPlayed > b.GamesPlayed
+	})
+
+	board := &Leaderboard{Sort: q.Sort, Offset: q.Offset, Limit: q.Limit, Total: len(players), Players: []CareerStats{}}
+	if q.Offset < len(players) {
+		board.Players = players[q.Offset:min(q.Offset+q.Limit, len(players))]
+	}
+	return board, nil
+}
+
+// leaderboardKey returns the value players are ranked by, highest first
+func leaderboardKey(sortBy string) (func(CareerStats) float64, error) {
+	switch sortBy {
+	case SortByWins:
+		return func(c CareerStats) float64 { return float64(c.Wins) }, nil
+	case SortByGames:
+		return func(c CareerStats) float64 { return float64(c.GamesPlayed) }, nil
+	case SortByWinRate:
+		return func(c CareerStats) float64 { return c.WinRate }, nil
+	case SortByRating:
+		return func(c CareerStats) float64 { return float64(c.Rating) }, nil
+	}
+	return nil, ErrUnknownLeaderboardSort
+}
+
+// setStorage saves every change to storage from now on
+func (s *StatsStore) setStorage(storage Storage, onError StorageErrorHook) {
+	s.mu.Lock()
+	s.storage = storage
+	s.onError = onError
+	s.wake = make(chan struct{}, 1)
+	wake := s.wake
+	s.mu.Unlock()
+
+	go func() {
+		for range wake {
+			s.flush(context.Background())
+		}
+	}()
+}
+
+// flush saves every changed player's stats, returning the first error
+func (s *StatsStore) flush(ctx context.Context) error {
+	s.io.Lock()
+	defer s.io.Unlock()
+
+	s.mu.Lock()
+	storage, onError := s.storage, s.onError
+	if storage == nil {
+		s.mu.Unlock()
+		return nil
+	}
+	saves := make(map[string][]byte, len(s.dirty))
+	for id := range s.dirty {
+		if data, err := json.Marshal(s.careers[id]); err == nil {
+			saves[id] = data
+		}
+	}
+	s.dirty = make(map[string]bool)
+	s.mu.Unlock()
+
+	var first error
+	for id, data := range saves {
+		saveCtx, cancel := context.WithTimeout(ctx, StorageSaveTimeout)
+		err := storage.Save(saveCtx, id, data)
+		cancel()
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			if onError != nil {
+				onError(StatsNamespace+"/"+id, err)
+			}
+		}
+	}
+	return first
+}
+
+// load restores every player's stats from storage
+func (s *StatsStore) load(ctx context.Context) error {
+	s.mu.RLock()
+	storage := s.storage
+	s.mu.RUnlock()
+	if storage == nil {
+		return nil
+	}
+
+	ids, err := storage.List(ctx)
+	if err != nil {
+		return err
+	}
+	var careers []CareerStats
+	var errs []error
+	for _, id := range ids {
+		data, err := storage.Load(ctx, id)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s/%s: %w", StatsNamespace, id, err))
+			continue
+		}
+		var c CareerStats
+		if err := json.Unmarshal(data, &c); err != nil {
+			errs = append(errs, fmt.Errorf("%s/%s: %w", StatsNamespace, id, err))
+			continue
+		}
+		careers = append(careers, c)
+	}
+	s.Restore(careers)
+	return errors.Join(errs...)
+}
@@ -0,0 +1,142 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestCareerStats(t *testing.T) {
+	stats := NewStatsStore()
+	ended := Now()
+	game := func(winner string, durationMs int64) ArchivedGame {
+		return ArchivedGame{Winner: winner, DurationMs: durationMs, EndedAt: ended, Seats: []ArchivedSeat{
+			{PlayerID: "alice", Name: "Alice", Captures: 2, CapturesTaken: 1},
+			{PlayerID: "bob", Name: "Bob", Captures: 1, CapturesTaken: 2},
+			{PlayerID: "bot_1", Name: "Bot", IsBot: true},
+		}}
+	}
+	stats.Record(game("alice", 1000))
+	stats.Record(game("alice", 2000))
+	stats.Record(game("bob", 3000))
+	stats.Record(game("alice", 6000))
+	stats.Record(ArchivedGame{Seats: []ArchivedSeat{{PlayerID: "alice"}}}) // Abandoned
+
+	alice, exists := stats.Get("alice")
+	if !exists {
+		t.Fatal("Expected alice to have stats")
+	}
+	if alice.GamesPlayed != 4 || alice.Wins != 3 || alice.WinRate != 0.75 {
+		t.Errorf("Expected 3 wins in 4 games, got %+v", alice)
+	}
+	if alice.CapturesMade != 8 || alice.PiecesLost != 4 || alice.AvgDurationMs != 3000 {
+		t.Errorf("Unexpected totals %+v", alice)
+	}
+	if alice.WinStreak != 1 || alice.LongestWinStreak != 2 {
+		t.Errorf("Expected a streak of 1 and a longest of 2, got %d and %d", alice.WinStreak, alice.LongestWinStreak)
+	}
+	if _, exists := stats.Get("bot_1"); exists {
+		t.Error("Bots shouldn't be tracked")
+	}
+}
+
+func TestLeaderboard(t *testing.T) {
+	stats := NewStatsStore()
+	stats.Restore([]CareerStats{
+		{PlayerID: "alice", GamesPlayed: 10, Wins: 4},
+		{PlayerID: "bob", GamesPlayed: 3, Wins: 3},
+		{PlayerID: "carol", GamesPlayed: 20, Wins: 5},
+	})
+
+	board, err := stats.Leaderboard(LeaderboardQuery{})
+	if err != nil || board.Sort != SortByWins || board.Total != 3 || board.Limit != DefaultLeaderboardLimit {
+		t.Fatalf("Unexpected leaderboard %+v (%v)", board, err)
+	}
+	if board.Players[0].PlayerID != "carol" || board.Players[2].PlayerID != "bob" {
+		t.Errorf("Expected carol first and bob last by wins, got %+v", board.Players)
+	}
+
+	board, _ = stats.Leaderboard(LeaderboardQuery{Sort: SortByWinRate, Offset: 1, Limit: 1})
+	if len(board.Players) != 1 || board.Players[0].PlayerID != "alice" {
+		t.Errorf("Expected alice second by win rate, got %+v", board.Players)
+	}
+	if board, _ = stats.Leaderboard(LeaderboardQuery{Offset: 5}); len(board.Players) != 0 {
+		t.Errorf("Expected an empty page past the end, got %+v", board.Players)
+	}
+	if board, _ = stats.Leaderboard(LeaderboardQuery{Limit: 1000}); board.Limit != MaxLeaderboardLimit {
+		t.Errorf("Expected the limit capped at %d, got %d", MaxLeaderboardLimit, board.Limit)
+	}
+	if _, err := stats.Leaderboard(LeaderboardQuery{Sort: "captures"}); err != ErrUnknownLeaderboardSort {
+		t.Errorf("Expected ErrUnknownLeaderboardSort, got %v", err)
+	}
+}
+
+func TestCareerStatsPersist(t *testing.T) {
+	storage := NewMemoryStorage()
+	gm := NewGameManager()
+	gm.SetStorage(storage)
+	game, _ := gm.CreateGame(context.Background(), "p1", "Player 1", 4)
+	gm.JoinGame(context.Background(), game.Code, "p2", "Player 2", "")
+	for id := range game.Players {
+		game.SetPlayerReady(id, true)
+	}
+	game.StartGame("p1")
+	endTestGame(game, "p2")
+
+	if err := gm.FlushStorage(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if codes, _ := storage.List(context.Background()); len(codes) != 1 || codes[0] != game.Code {
+		t.Errorf("Expected player stats kept apart from games, got %v", codes)
+	}
+
+	restored := NewGameManager()
+	restored.SetStorage(storage)
+	restored.LoadFromStorage(context.Background())
+	if p2, exists := restored.Stats().Get("p2"); !exists || p2.Wins != 1 || p2.GamesPlayed != 1 {
+		t.Errorf("Expected p2's win restored from storage, got %+v", p2)
+	}
+
+	// Snapshots carry stats too
+	var buf bytes.Buffer
+	if _, err := gm.WriteSnapshot(context.Background(), &buf); err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	fresh := NewGameManager()
+	fresh.LoadSnapshot(context.Background(), &buf)
+	if p1, exists := fresh.Stats().Get("p1"); !exists || p1.GamesPlayed != 1 || p1.Wins != 0 {
+		t.Errorf("Expected p1's loss restored from the snapshot, got %+v", p1)
+	}
+}
+
+func TestStorageNamespaces(t *testing.T) {
+	ctx := context.Background()
+	bolt, err := NewBoltStorage(t.TempDir() + "/games.db")
+	if err != nil {
+		t.Fatalf("Failed to open bolt storage: %v", err)
+	}
+	defer bolt.Close()
+	file, err := NewFileStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open file storage: %v", err)
+	}
+
+	for _, storage := range []NamespacedStorage{NewMemoryStorage(), file, bolt} {
+		players, err := storage.Namespace(StatsNamespace)
+		if err != nil {
+			t.Fatalf("%T: failed to open namespace: %v", storage, err)
+		}
+		storage.Save(ctx, "ABC123", []byte(`{}`))
+		players.Save(ctx, "alice", []byte(`{"wins":1}`))
+
+		if codes, _ := storage.List(ctx); len(codes) != 1 || codes[0] != "ABC123" {
+			t.Errorf("%T: expected only the game at the top level, got %v", storage, codes)
+		}
+		if ids, _ := players.List(ctx); len(ids) != 1 || ids[0] != "alice" {
+			t.Errorf("%T: expected only alice in the namespace, got %v", storage, ids)
+		}
+		if _, err := storage.Namespace("../escape"); err != ErrInvalidNamespace {
+			t.Errorf("%T: expected ErrInvalidNamespace, got %v", storage, err)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// MarkChatRead records that a participant has read chat up to readAt.
+// A zero readAt means now; markers never move backwards, so a stale
+// device can't resurrect messages already read elsewhere.
+func (g *Game) MarkChatRead(playerID string, readAt time.Time) (int, error) {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if !g.isParticipantLocked(playerID) {
+		return 0, ErrPlayerNotFound
+	}
+
+	if readAt.IsZero() || readAt.After(time.Now()) {
+		readAt = time.Now()
+	}
+	if g.chatReadAt == nil {
+		g.chatReadAt = make(map[string]time.Time)
+	}
+	if readAt.After(g.chatReadAt[playerID]) {
+		g.chatReadAt[playerID] = readAt
+	}
+	return g.unreadChatForLocked(playerID), nil
+}
+
+// UnreadChat returns how many chat messages a participant hasn't read yet
+func (g *Game) UnreadChat(playerID string) int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.unreadChatForLocked(playerID)
+}
+
+// isParticipantLocked reports whether the ID belongs to a player or spectator (caller must hold lock)
+func (g *Game) isParticipantLocked(playerID string) bool {
+	if _, exists := g.Players[playerID]; exists {
+		return true
+	}
+	_, exists := g.Spectators[playerID]
+	return exists
+}
+
+// unreadChatForLocked counts messages from others posted after the participant's
+// read marker (caller must hold lock)
+func (g *Game) unreadChatForLocked(playerID string) int {
+	readAt := g.chatReadAt[playerID]
+	unread := 0
+	for i := len(g.ChatMessages) - 1; i >= 0; i-- {
+		msg := g.ChatMessages[i]
+		if !msg.Timestamp.After(readAt) {
+			break
+		}
+		if msg.PlayerID != playerID {
+			unread++
+		}
+	}
+	return unread
+}
+
+// unreadChatLocked returns unread chat counts keyed by participant ID (caller must hold lock)
+func (g *Game) unreadChatLocked() map[string]int {
+	unread := make(map[string]int, len(g.Players)+len(g.Spectators))
+	for id := range g.Players {
+		unread[id] = g.unreadChatForLocked(id)
+	}
+	for id := range g.Spectators {
+		unread[id] = g.unreadChatForLocked(id)
+	}
+	return unread
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestChatReadMarkers(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+	gm.JoinGame(context.Background(), game.Code, "player2", "Player 2", "")
+
+	game.SendChatMessage("host1", "hello")
+	game.SendChatMessage("host1", "anyone?")
+	game.SendChatMessage("player2", "hi")
+	// Timestamps only carry milliseconds; keep the messages apart
+	sent := game.ChatMessages[2].Timestamp
+	for i := range game.ChatMessages {
+		game.ChatMessages[i].Timestamp = At(sent.Add(time.Duration(i-2) * time.Millisecond))
+	}
+
+	if n := game.UnreadChat("player2"); n != 2 {
+		t.Errorf("Expected 2 unread for player2 (own messages excluded), got %d", n)
+	}
+	if n := game.UnreadChat("host1"); n != 1 {
+		t.Errorf("Expected 1 unread for host1, got %d", n)
+	}
+
+	first := game.ChatMessages[0].Timestamp
+	unread, err := game.MarkChatRead("player2", first.Time)
+	if err != nil {
+		t.Fatalf("Failed to mark chat read: %v", err)
+	}
+	if unread != 1 {
+		t.Errorf("Expected 1 unread after reading the first message, got %d", unread)
+	}
+
+	if unread, _ := game.MarkChatRead("player2", time.Time{}); unread != 0 {
+		t.Errorf("Expected 0 unread after reading everything, got %d", unread)
+	}
+	// Markers never move backwards
+	if unread, _ := game.MarkChatRead("player2", first.Time); unread != 0 {
+		t.Errorf("Stale marker should not resurrect unread messages, got %d", unread)
+	}
+
+	state := game.GetGameState()
+	counts := state["unread_chat"].(map[string]int)
+	if counts["player2"] != 0 || counts["host1"] != 1 {
+		t.Errorf("Unexpected unread counts in state: %v", counts)
+	}
+
+	if _, err := game.MarkChatRead("stranger", time.Time{}); err != ErrPlayerNotFound {
+		t.Errorf("Expected ErrPlayerNotFound, got %v", err)
+	}
+}
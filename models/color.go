@@ -0,0 +1,71 @@
+package models
+
+import (
+	"errors"
+	"sort"
+)
+
+var ErrColorTaken = errors.New("color is taken by another player")
+
+// ChangeColor moves a player to another color while the game waits for players.
+// The color is checked and taken under the game lock, so two players asking for
+// the same one can't both get it.
+func (g *Game) ChangeColor(playerID string, color PlayerColor) error {
+	g.mu.Lock()
+	defer g.unlock()
+
+	player, exists := g.Players[playerID]
+	if !exists {
+		return ErrPlayerNotFound
+	}
+	if g.State != Waiting {
+		return ErrGameStarted
+	}
+	if !g.board().HasColor(color) {
+		return ErrUnknownColor
+	}
+	for id, other := range g.Players {
+		if id != playerID && other.Color == color {
+			return ErrColorTaken
+		}
+	}
+
+	player.Color = color
+	g.LastActivity = Now()
+	return nil
+}
+
+// seatColorLocked picks the color for a new seat: the preferred one if the board
+// has it and it's free, otherwise the first free color from the next seat on
+// (caller must hold lock)
+func (g *Game) seatColorLocked(preferred PlayerColor) PlayerColor {
+	board := g.board()
+	taken := make(map[PlayerColor]bool, len(g.Players))
+	for _, player := range g.Players {
+		taken[player.Color] = true
+	}
+	if preferred != "" && board.HasColor(preferred) && !taken[preferred] {
+		return preferred
+	}
+	for i := range board.Colors {
+		if color := board.SeatColor(len(g.Players) + i); !taken[color] {
+			return color
+		}
+	}
+	return board.SeatColor(len(g.Players))
+}
+
+// closeSeatGapLocked renumbers the join order after a player leaves a game that
+// hasn't started. The others keep their colors. (caller must hold lock)
+func (g *Game) closeSeatGapLocked() {
+	players := make([]*Player, 0, len(g.Players))
+	for _, player := range g.Players {
+		players = append(players, player)
+	}
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].Order < players[j].Order
+	})
+	for order, player := range players {
+		player.Order = order
+	}
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"context"
+	"testing"
+)
+
+func TestJoinWithPreferredColor(t *testing.T) {
+	ctx := context.Background()
+	gm := NewGameManager()
+	game, err := gm.CreateGame(ctx, "host1", "Host", 4)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	if _, err := gm.JoinGameWithColor(ctx, game.Code, "player2", "Bob", "", Yellow); err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+	if color := game.Players["player2"].Color; color != Yellow {
+		t.Errorf("Expected yellow, got %s", color)
+	}
+
+	if _, err := gm.JoinGameWithColor(ctx, game.Code, "player3", "Carol", "", Yellow); err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+	if color := game.Players["player3"].Color; color == Yellow || color == game.Players["host1"].Color {
+		t.Errorf("Expected a free color, got %s", color)
+	}
+
+	if _, err := gm.JoinGameWithColor(ctx, game.Code, "player4", "Dan", "", Olive); err != ErrUnknownColor {
+		t.Errorf("Expected ErrUnknownColor, got %v", err)
+	}
+}
+
+func TestChangeColor(t *testing.T) {
+	ctx := context.Background()
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(ctx, "host1", "Host", 4)
+	gm.JoinGameWithColor(ctx, game.Code, "player2", "Bob", "", Green)
+
+	if err := game.ChangeColor("host1", Green); err != ErrColorTaken {
+		t.Errorf("Expected ErrColorTaken, got %v", err)
+	}
+	if err := game.ChangeColor("host1", Purple); err != ErrUnknownColor {
+		t.Errorf("Expected ErrUnknownColor, got %v", err)
+	}
+	if err := game.ChangeColor("nobody", Yellow); err != ErrPlayerNotFound {
+		t.Errorf("Expected ErrPlayerNotFound, got %v", err)
+	}
+	if err := game.ChangeColor("host1", Yellow); err != nil {
+		t.Fatalf("ChangeColor failed: %v", err)
+	}
+	if color := game.Players["host1"].Color; color != Yellow {
+		t.Errorf("Expected yellow, got %s", color)
+	}
+
+	// Colors stay put when someone leaves the lobby
+	gm.JoinGame(ctx, game.Code, "player3", "Carol", "")
+	game.LeaveGame("player2")
+	if game.Players["host1"].Color != Yellow {
+		t.Errorf("Expected the host to keep yellow, got %s", game.Players["host1"].Color)
+	}
+	if order := game.Players["player3"].Order; order != 1 {
+		t.Errorf("Expected the gap in the join order closed, got %d", order)
+	}
+
+	game.SetPlayerReady("host1", true)
+	game.SetPlayerReady("player3", true)
+	if err := game.StartGame("host1"); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := game.ChangeColor("host1", Red); err != ErrGameStarted {
+		t.Errorf("Expected ErrGameStarted, got %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxCommentary is how many commentary lines a game keeps for late spectators
+const MaxCommentary = 50
+
+// CommentaryKind classifies a commentary line
+type CommentaryKind string
+
+const (
+	CommentaryCapture   CommentaryKind = "capture"    // A piece was sent home
+	CommentaryFinish    CommentaryKind = "finish"     // A piece reached the finish
+	CommentaryCloseCall CommentaryKind = "close_call" // A player is one roll from winning
+	CommentaryWin       CommentaryKind = "win"        // The game is over
+)
+
+// Commentary is a human-readable line describing a notable play
+type Commentary struct {
+	ID        int            `json:"id"`
+	Kind      CommentaryKind `json:"kind"`
+	Text      string         `json:"text"`
+	PlayerID  string         `json:"player_id"`
+	Timestamp Timestamp      `json:"timestamp"`
+}
+
+// GetCommentary returns the most recent commentary lines
+func (g *Game) GetCommentary() []Commentary {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return append([]Commentary{}, g.commentary...)
+}
+
+// ConsumeCommentary returns commentary added since the last call
+func (g *Game) ConsumeCommentary() []Commentary {
+	g.mu.Lock()
+	defer g.unlock()
+
+	var fresh []Commentary
+	for _, c := range g.commentary {
+		if c.ID > g.commentarySent {
+			fresh = append(fresh, c)
+		}
+	}
+	if len(fresh) > 0 {
+		g.commentarySent = fresh[len(fresh)-1].ID
+	}
+	return fresh
+}
+
+// commentOnMoveLocked derives commentary from the latest move (caller must hold lock)
+func (g *Game) commentOnMoveLocked(player *Player, move MoveRecord, victims []string) {
+	name := colorName(player.Color)
+
+	if len(victims) > 0 {
+		var names []string
+		for _, id := range victims {
+			if victim := g.Players[id]; victim != nil {
+				names = append(names, colorName(victim.Color))
+			}
+		}
+		text := fmt.Sprintf("%s captured %s", name, strings.Join(names, " and "))
+		if g.nearSafeSquareLocked(move.ToPos) {
+			text += " near the safe star"
+		}
+		g.commentLocked(CommentaryCapture, player.ID, text+"!")
+	}
+
+	piece := player.Pieces[move.PieceID]
+	remaining := 0
+	for _, p := range player.Pieces {
+		if !p.IsFinished {
+			remaining++
+		}
+	}
+
+	if piece.IsFinished && remaining > 0 {
+		g.commentLocked(CommentaryFinish, player.ID, fmt.Sprintf("%s brings a piece home - %d to go", name, remaining))
+	}
+
+	// One piece left within a single roll of the finish
+	if remaining == 1 {
+		board := g.board()
+		for _, p := range player.Pieces {
+			if p.IsFinished || p.IsHome {
+				continue
+			}
+			need := board.Progress(player.Color, Piece{IsFinished: true}) - board.Progress(player.Color, p)
+			if need >= 1 && need <= 6 {
+				g.commentLocked(CommentaryCloseCall, player.ID, fmt.Sprintf("%s needs a %d to finish", name, need))
+			}
+		}
+	}
+}
+
+// commentOnWinLocked announces the winner (caller must hold lock)
+func (g *Game) commentOnWinLocked(winnerID string) {
+	if winner := g.Players[winnerID]; winner != nil {
+		g.commentLocked(CommentaryWin, winnerID, fmt.Sprintf("%s wins the game!", colorName(winner.Color)))
+	}
+}
+
+// commentLocked records a commentary line (caller must hold lock)
+func (g *Game) commentLocked(kind CommentaryKind, playerID, text string) {
+	g.commentarySeq++
+	g.commentary = append(g.commentary, Commentary{
+		ID:        g.commentarySeq,
+		Kind:      kind,
+		Text:      text,
+		PlayerID:  playerID,
+		Timestamp: Now(),
+	})
+	if len(g.commentary) > MaxCommentary {
+		g.commentary = g.commentary[len(g.commentary)-MaxCommentary:]
+	}
+}
+
+// nearSafeSquareLocked reports whether a track square is next to a safe square (caller must hold lock)
+func (g *Game) nearSafeSquareLocked(position int) bool {
+	board := g.board()
+	if position < 0 {
+		return false
+	}
+	before := (position - 1 + board.TrackLength) % board.TrackLength
+	after := (position + 1) % board.TrackLength
+	return board.IsSafe(before) || board.IsSafe(after)
+}
+
+// colorName returns a display name for a player color, e.g. "Red"
+func colorName(color PlayerColor) string {
+	s := string(color)
+	if s == "" {
+		return "Someone"
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCommentaryOnCapture(t *testing.T) {
+	game, human, bot := setupBotGame(t, BotOptions{})
+
+	start := GetStartPosition(human.Color, game.MaxPlayers)
+	human.Pieces[0].IsHome = false
+	human.Pieces[0].Position = start + 2
+	bot.Pieces[0].IsHome = false
+	bot.Pieces[0].Position = start + 5
+
+	game.CurrentTurn = human.ID
+	game.HasRolled = true
+	game.LastDiceRoll = 3
+	if err := game.MovePiece(human.ID, 0); err != nil {
+		t.Fatalf("Failed to move piece: %v", err)
+	}
+
+	lines := game.ConsumeCommentary()
+	if len(lines) != 1 || lines[0].Kind != CommentaryCapture {
+		t.Fatalf("Expected one capture line, got %+v", lines)
+	}
+	want := colorName(human.Color) + " captured " + colorName(bot.Color)
+	if !strings.HasPrefix(lines[0].Text, want) {
+		t.Errorf("Expected %q, got %q", want, lines[0].Text)
+	}
+	if again := game.ConsumeCommentary(); len(again) != 0 {
+		t.Errorf("Commentary should only be consumed once, got %+v", again)
+	}
+	if len(game.GetCommentary()) != 1 {
+		t.Error("Consumed commentary should stay available for late spectators")
+	}
+}
+
+func TestCommentaryCloseCallAndWin(t *testing.T) {
+	game, human, _ := setupBotGame(t, BotOptions{})
+
+	for i := 1; i < PiecesPerPlayer; i++ {
+		human.Pieces[i].IsHome = false
+		human.Pieces[i].IsFinished = true
+	}
+	human.Pieces[0].IsHome = false
+	human.Pieces[0].Position = -2
+	human.Pieces[0].HomeStretchPosition = 1
+
+	game.CurrentTurn = human.ID
+	game.HasRolled = true
+	game.LastDiceRoll = 2
+	if err := game.MovePiece(human.ID, 0); err != nil {
+		t.Fatalf("Failed to move piece: %v", err)
+	}
+
+	need := HomeStretchSize - 3
+	lines := game.ConsumeCommentary()
+	if len(lines) != 1 || lines[0].Kind != CommentaryCloseCall || !strings.Contains(lines[0].Text, fmt.Sprintf("needs a %d", need)) {
+		t.Fatalf("Expected a close-call line needing %d, got %+v", need, lines)
+	}
+
+	game.CurrentTurn = human.ID
+	game.HasRolled = true
+	game.LastDiceRoll = need
+	if err := game.MovePiece(human.ID, 0); err != nil {
+		t.Fatalf("Failed to finish: %v", err)
+	}
+	lines = game.ConsumeCommentary()
+	if len(lines) != 1 || lines[0].Kind != CommentaryWin {
+		t.Errorf("Expected a win line, got %+v", lines)
+	}
+}
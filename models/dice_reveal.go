@@ -0,0 +1,135 @@
+package models
+
+import (
+	crypto_rand "crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// MaxDiceRevealDelay is the longest a game may hold a roll back for its animation
+const MaxDiceRevealDelay = 5 * time.Second
+
+var (
+	ErrInstantDice        = errors.New("game reveals rolls at once")
+	ErrRollPending        = errors.New("dice are already rolling")
+	ErrNoPendingRoll      = errors.New("no such roll in progress")
+	ErrInvalidRevealDelay = errors.New("invalid dice reveal delay")
+)
+
+// PendingRoll is a roll whose value has been decided but not yet shown, so
+// every client's dice animation can land on it at the same moment
+type PendingRoll struct {
+	RollID   string    `json:"roll_id"`
+	PlayerID string    `json:"player_id"`
+	RevealAt Timestamp `json:"reveal_at"` // Latest the value will be revealed
+	value    int
+	acks     map[string]bool // Participants whose animation has finished
+}
+
+// SetDiceReveal holds each roll back for up to delay so clients can animate it
+// together; zero reveals rolls at once (host only, before the game starts)
+func (g *Game) SetDiceReveal(hostID string, delay time.Duration) error {
+	if delay < 0 || delay > MaxDiceRevealDelay {
+		return ErrInvalidRevealDelay
+	}
+
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.HostID != hostID {
+		return ErrNotHost
+	}
+	if g.State != Waiting {
+		return ErrGameStarted
+	}
+	g.DiceRevealDelay = delay
+	g.LastActivity = Now()
+	return nil
+}
+
+// BeginRoll decides a player's roll without revealing it. Returns ErrInstantDice
+// if the game doesn't hold rolls back, in which case the caller should RollDice.
+func (g *Game) BeginRoll(playerID string) (PendingRoll, error) {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.DiceRevealDelay <= 0 {
+		return PendingRoll{}, ErrInstantDice
+	}
+	if g.State == Paused {
+		return PendingRoll{}, ErrGamePaused
+	}
+	if g.State != Playing {
+		return PendingRoll{}, ErrGameNotPlaying
+	}
+	if g.CurrentTurn != playerID {
+		return PendingRoll{}, ErrNotPlayerTurn
+	}
+	if g.HasRolled {
+		return PendingRoll{}, ErrAlreadyRolled
+	}
+	if g.pendingRoll != nil {
+		return PendingRoll{}, ErrRollPending
+	}
+
+	g.pendingRoll = &PendingRoll{
+		RollID:   newRollID(),
+		PlayerID: playerID,
+		RevealAt: At(time.Now().Add(g.DiceRevealDelay).Truncate(time.Millisecond)),
+		value:    SecureRollDice(),
+		acks:     make(map[string]bool),
+	}
+	g.recordPlayerAction(playerID)
+	g.LastActivity = Now()
+	return *g.pendingRoll, nil
+}
+
+// AckRoll records that a participant's dice animation has finished. Returns true
+// once everyone connected, and the roller, has acknowledged, meaning the roll can
+// be revealed early.
+func (g *Game) AckRoll(participantID, rollID string, connected map[string]int) (bool, error) {
+	g.mu.Lock()
+	defer g.unlock()
+
+	pending := g.pendingRoll
+	if pending == nil || pending.RollID != rollID {
+		return false, ErrNoPendingRoll
+	}
+	if !g.isParticipantLocked(participantID) {
+		return false, ErrPlayerNotFound
+	}
+	pending.acks[participantID] = true
+
+	if !pending.acks[pending.PlayerID] {
+		return false, nil
+	}
+	for id := range connected {
+		if !pending.acks[id] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// RevealRoll applies a pending roll. Returns the player it was for and the value,
+// with ErrThreeSixes if it cost them the turn.
+func (g *Game) RevealRoll(rollID string) (string, int, error) {
+	g.mu.Lock()
+	defer g.unlock()
+
+	pending := g.pendingRoll
+	if pending == nil || pending.RollID != rollID {
+		return "", 0, ErrNoPendingRoll
+	}
+	g.pendingRoll = nil
+	roll, err := g.applyRollLocked(pending.PlayerID, pending.value)
+	return pending.PlayerID, roll, err
+}
+
+// newRollID returns a random ID for a pending roll
+func newRollID() string {
+	var b [8]byte
+	crypto_rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiceReveal(t *testing.T) {
+	game := newPauseTestGame(t)
+	turn := game.CurrentTurn
+
+	if _, err := game.BeginRoll(turn); err != ErrInstantDice {
+		t.Fatalf("Expected ErrInstantDice by default, got %v", err)
+	}
+	if err := game.SetDiceReveal("p1", time.Second); err != ErrGameStarted {
+		t.Errorf("Expected ErrGameStarted, got %v", err)
+	}
+	game.DiceRevealDelay = time.Second
+
+	pending, err := game.BeginRoll(turn)
+	if err != nil {
+		t.Fatalf("Failed to begin roll: %v", err)
+	}
+	if game.HasRolled || game.GetGameState()["rolling"] == nil {
+		t.Error("The roll should be pending, not applied")
+	}
+	if _, err := game.BeginRoll(turn); err != ErrRollPending {
+		t.Errorf("Expected ErrRollPending, got %v", err)
+	}
+	if _, err := game.RollDice(turn); err != ErrRollPending {
+		t.Errorf("Expected ErrRollPending for an instant roll, got %v", err)
+	}
+
+	// Reveals early only once the roller and every connected client have acked
+	connected := map[string]int{"p1": 1, "p2": 1, "p3": 1}
+	for id := range connected {
+		if id == turn {
+			continue
+		}
+		if ready, err := game.AckRoll(id, pending.RollID, connected); err != nil || ready {
+			t.Fatalf("Should wait for the roller, got %v, %v", ready, err)
+		}
+	}
+	if ready, _ := game.AckRoll(turn, pending.RollID, connected); !ready {
+		t.Error("Expected the roll to be ready once everyone acked")
+	}
+
+	playerID, roll, err := game.RevealRoll(pending.RollID)
+	if err != nil && err != ErrThreeSixes {
+		t.Fatalf("Failed to reveal: %v", err)
+	}
+	if playerID != turn || roll < 1 || roll > 6 || !game.HasRolled {
+		t.Errorf("Expected %s's roll applied, got %q rolled %d", turn, playerID, roll)
+	}
+	if _, _, err := game.RevealRoll(pending.RollID); err != ErrNoPendingRoll {
+		t.Errorf("A roll should only be revealed once, got %v", err)
+	}
+}
+
+func TestDiceRevealDroppedOnPause(t *testing.T) {
+	game := newPauseTestGame(t)
+	game.DiceRevealDelay = time.Second
+	pending, _ := game.BeginRoll(game.CurrentTurn)
+
+	game.PauseGame(game.CurrentTurn)
+	if _, _, err := game.RevealRoll(pending.RollID); err != ErrNoPendingRoll {
+		t.Errorf("Expected the roll dropped on pause, got %v", err)
+	}
+}
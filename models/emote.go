@@ -0,0 +1,95 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Emote rate limit: at most EmoteBurst emotes per participant in any EmoteWindow
+const (
+	EmoteBurst  = 3
+	EmoteWindow = 5 * time.Second
+)
+
+var (
+	ErrUnknownEmote     = errors.New("unknown emote")
+	ErrEmoteRateLimited = errors.New("too many emotes, slow down")
+)
+
+// EmoteDef is one quick-chat emote. Clients render it by ID, with Text as a fallback.
+type EmoteDef struct {
+	ID       string `json:"id"`
+	Text     string `json:"text"`
+	Category string `json:"category"` // praise, banter or dice
+}
+
+// Emotes is the catalogue of emotes players and spectators can send
+var Emotes = []EmoteDef{
+	{ID: "nice_move", Text: "Nice move!", Category: "praise"},
+	{ID: "well_played", Text: "Well played", Category: "praise"},
+	{ID: "good_game", Text: "Good game", Category: "praise"},
+	{ID: "hurry_up", Text: "Hurry up!", Category: "banter"},
+	{ID: "oops", Text: "Oops", Category: "banter"},
+	{ID: "gotcha", Text: "Gotcha!", Category: "banter"},
+	{ID: "thinking", Text: "Hmm...", Category: "banter"},
+	{ID: "six_please", Text: "Come on, six!", Category: "dice"},
+	{ID: "lucky_roll", Text: "Lucky roll!", Category: "dice"},
+	{ID: "unlucky_roll", Text: "Not again...", Category: "dice"},
+}
+
+// Emote is an emote someone sent to the game
+type Emote struct {
+	PlayerID    string    `json:"player_id"`
+	PlayerName  string    `json:"player_name"`
+	Emote       string    `json:"emote"`
+	IsSpectator bool      `json:"is_spectator"`
+	Timestamp   Timestamp `json:"timestamp"`
+}
+
+// EmoteByID looks up an emote in the catalogue
+func EmoteByID(id string) (EmoteDef, bool) {
+	for _, e := range Emotes {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return EmoteDef{}, false
+}
+
+// SendEmote sends a predefined emote from a player or spectator. Emotes aren't
+// kept in the chat log or moderated, but each participant is rate limited.
+func (g *Game) SendEmote(playerID, emoteID string) (Emote, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock() // Nothing in the game state changes
+
+	emote := Emote{PlayerID: playerID, Emote: emoteID, Timestamp: Now()}
+	if player, exists := g.Players[playerID]; exists {
+		emote.PlayerName = player.Name
+	} else if spec, exists := g.Spectators[playerID]; exists {
+		emote.PlayerName = spec.Name
+		emote.IsSpectator = true
+	} else {
+		return Emote{}, ErrPlayerNotFound
+	}
+	if _, ok := EmoteByID(emoteID); !ok {
+		return Emote{}, ErrUnknownEmote
+	}
+
+	// Keep only the sends still inside the window
+	now := time.Now()
+	recent := g.emoteTimes[playerID][:0]
+	for _, sent := range g.emoteTimes[playerID] {
+		if now.Sub(sent) < EmoteWindow {
+			recent = append(recent, sent)
+		}
+	}
+	if len(recent) >= EmoteBurst {
+		g.emoteTimes[playerID] = recent
+		return Emote{}, ErrEmoteRateLimited
+	}
+	if g.emoteTimes == nil {
+		g.emoteTimes = make(map[string][]time.Time)
+	}
+	g.emoteTimes[playerID] = append(recent, now)
+	return emote, nil
+}
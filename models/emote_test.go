@@ -0,0 +1,48 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSendEmote(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+	gm.JoinAsSpectator(context.Background(), game.Code, "viewer", "Viewer", "")
+
+	if _, err := game.SendEmote("host1", "shrug"); err != ErrUnknownEmote {
+		t.Errorf("Expected ErrUnknownEmote, got %v", err)
+	}
+	if _, err := game.SendEmote("stranger", "nice_move"); err != ErrPlayerNotFound {
+		t.Errorf("Expected ErrPlayerNotFound, got %v", err)
+	}
+	emote, err := game.SendEmote("viewer", "hurry_up")
+	if err != nil || !emote.IsSpectator || emote.PlayerName != "Viewer" {
+		t.Fatalf("Expected the spectator's emote, got %+v (%v)", emote, err)
+	}
+	if len(game.GetRecentChat(0)) != 0 {
+		t.Error("Emotes shouldn't be kept in the chat log")
+	}
+
+	// Rate limited per participant
+	for i := 0; i < EmoteBurst; i++ {
+		if _, err := game.SendEmote("host1", "six_please"); err != nil {
+			t.Fatalf("Emote %d failed: %v", i, err)
+		}
+	}
+	if _, err := game.SendEmote("host1", "six_please"); err != ErrEmoteRateLimited {
+		t.Errorf("Expected ErrEmoteRateLimited, got %v", err)
+	}
+	if _, err := game.SendEmote("viewer", "oops"); err != nil {
+		t.Errorf("Expected the spectator unaffected by the host's limit, got %v", err)
+	}
+
+	// Sends age out of the window
+	for i := range game.emoteTimes["host1"] {
+		game.emoteTimes["host1"][i] = time.Now().Add(-EmoteWindow)
+	}
+	if _, err := game.SendEmote("host1", "good_game"); err != nil {
+		t.Errorf("Expected the limit to reset after the window, got %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package models
+
+import (
+	"errors"
+)
+
+// EventType identifies an input to the rules engine
+type EventType string
+
+const (
+	EventRoll  EventType = "roll"  // Player rolled the given dice value
+	EventMove  EventType = "move"  // Player moved a piece with the current roll
+	EventSkip  EventType = "skip"  // Player passed after a roll with no move
+	EventPass  EventType = "pass"  // Player's turn passed without a move: timed out or forced
+	EventLeave EventType = "leave" // Player left mid-game; Action says what happened to the pieces
+	EventEnd   EventType = "end"   // An operator ended the game; PlayerID is the declared winner
+)
+
+// ErrUnknownEvent is returned by ApplyEvent for unsupported event types
+var ErrUnknownEvent = errors.New("unknown engine event")
+
+// EngineEvent is a deterministic game action; dice values are supplied by the caller
+type EngineEvent struct {
+	Type     EventType       `json:"type"`
+	PlayerID string          `json:"player_id"`
+	Roll     int             `json:"roll,omitempty"`     // Dice value for roll events
+	PieceID  int             `json:"piece_id,omitempty"` // Piece for move events
+	Action   DepartureAction `json:"action,omitempty"`   // Departure action for leave events
+}
+
+// EngineState is a self-contained snapshot of the rules-relevant game state.
+// ApplyEvent never mutates its input, so states can be kept for replay and undo.
+// A zero Rules means the standard rules; CaptureGrantsTurn overrides the rule set's.
+type EngineState struct {
+	MaxPlayers        int                `json:"max_players"`
+	CaptureGrantsTurn bool               `json:"capture_grants_turn"`
+	Rules             RuleSet            `json:"rules"`
+	State             GameState          `json:"state"`
+	Players           map[string]*Player `json:"players"`
+	CurrentTurn       string             `json:"current_turn"`
+	LastDiceRoll      int                `json:"last_dice_roll"`
+	HasRolled         bool               `json:"has_rolled"`
+	ConsecutiveSixes  int                `json:"consecutive_sixes"`
+	Winner            string             `json:"winner,omitempty"`
+}
+
+// ApplyEvent applies an event to a state and returns the resulting state.
+// It runs the same rule code as live play. A roll of three sixes returns the
+// new state (turn passed) together with ErrThreeSixes.
+func ApplyEvent(state EngineState, event EngineEvent) (EngineState, error) {
+	g := state.toGame()
+
+	var err error
+	switch event.Type {
+	case EventRoll:
+		_, err = g.applyRollLocked(event.PlayerID, event.Roll)
+	case EventMove:
+		err = g.movePieceLocked(event.PlayerID, event.PieceID)
+	case EventSkip:
+		err = g.skipTurnLocked(event.PlayerID)
+	case EventPass:
+		err = g.passTurnLocked(event.PlayerID)
+	case EventLeave:
+		err = g.leaveEventLocked(event.PlayerID, event.Action)
+	case EventEnd:
+		err = g.endGameLocked(event.PlayerID)
+	default:
+		return state, ErrUnknownEvent
+	}
+
+	if err != nil && err != ErrThreeSixes {
+		return state, err
+	}
+	return g.engineStateLocked(), err
+}
+
+// leaveEventLocked takes a departing player out of a scratch game (caller must hold lock)
+func (g *Game) leaveEventLocked(playerID string, action DepartureAction) error {
+	if player, exists := g.Players[playerID]; !exists || player.HasLeft {
+		return ErrPlayerNotFound
+	}
+	switch action {
+	case DepartureRemove, DepartureHome, DepartureFreeze:
+	default:
+		return ErrInvalidDepartureAction // Bots taking a seat over leave the board as it is
+	}
+	g.departLocked(playerID, action)
+	return nil
+}
+
+// EngineState returns a snapshot of the game suitable for ApplyEvent
+func (g *Game) EngineState() EngineState {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.engineStateLocked()
+}
+
+// engineStateLocked copies the rules-relevant fields of a game (caller must hold lock)
+func (g *Game) engineStateLocked() EngineState {
+	return EngineState{
+		MaxPlayers:        g.MaxPlayers,
+		CaptureGrantsTurn: g.CaptureGrantsTurn,
+		Rules:             g.rulesLocked(),
+		State:             g.State,
+		Players:           clonePlayers(g.Players),
+		CurrentTurn:       g.CurrentTurn,
+		LastDiceRoll:      g.LastDiceRoll,
+		HasRolled:         g.HasRolled,
+		ConsecutiveSixes:  g.ConsecutiveSixes,
+		Winner:            g.Winner,
+	}
+}
+
+// toGame builds a private scratch game from a state for the engine to mutate
+func (s EngineState) toGame() *Game {
+	rules := s.Rules
+	if rules == (RuleSet{}) {
+		rules = DefaultRuleSet()
+	}
+	return &Game{
+		Players:           clonePlayers(s.Players),
+		Spectators:        make(map[string]*Spectator),
+		State:             s.State,
+		CurrentTurn:       s.CurrentTurn,
+		MaxPlayers:        s.MaxPlayers,
+		LastDiceRoll:      s.LastDiceRoll,
+		HasRolled:         s.HasRolled,
+		ConsecutiveSixes:  s.ConsecutiveSixes,
+		Winner:            s.Winner,
+		CaptureGrantsTurn: s.CaptureGrantsTurn,
+		Rules:             rules,
+		TurnTimeout:       rules.TurnTimeout(),
+	}
+}
+
+// clonePlayers deep-copies a player map including piece slices
+func clonePlayers(players map[string]*Player) map[string]*Player {
+	clone := make(map[string]*Player, len(players))
+	for id, player := range players {
+		p := *player
+		p.Pieces = make([]Piece, len(player.Pieces))
+		copy(p.Pieces, player.Pieces)
+		clone[id] = &p
+	}
+	return clone
+}
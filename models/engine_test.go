@@ -0,0 +1,70 @@
+package models
+
+import (
+	"context"
+	"testing"
+)
+
+func newEngineTestGame(t *testing.T) *Game {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 2)
+	gm.JoinGame(context.Background(), game.Code, "player2", "Bob", "")
+	game.SetPlayerReady("host1", true)
+	game.SetPlayerReady("player2", true)
+	if err := game.StartGame("host1"); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+	return game
+}
+
+func TestApplyEventRollAndMove(t *testing.T) {
+	game := newEngineTestGame(t)
+	start := game.EngineState()
+	mover := start.CurrentTurn
+
+	rolled, err := ApplyEvent(start, EngineEvent{Type: EventRoll, PlayerID: mover, Roll: 6})
+	if err != nil {
+		t.Fatalf("Failed to apply roll: %v", err)
+	}
+	if !rolled.HasRolled || rolled.LastDiceRoll != 6 {
+		t.Errorf("Expected rolled state with 6, got %+v", rolled)
+	}
+
+	moved, err := ApplyEvent(rolled, EngineEvent{Type: EventMove, PlayerID: mover, PieceID: 0})
+	if err != nil {
+		t.Fatalf("Failed to apply move: %v", err)
+	}
+
+	piece := moved.Players[mover].Pieces[0]
+	if piece.IsHome || piece.Position != GetStartPosition(moved.Players[mover].Color, moved.MaxPlayers) {
+		t.Errorf("Expected piece on start square, got %+v", piece)
+	}
+
+	// Inputs are never mutated
+	if !start.Players[mover].Pieces[0].IsHome || start.HasRolled {
+		t.Error("ApplyEvent must not mutate the input state")
+	}
+	if !game.Players[mover].Pieces[0].IsHome {
+		t.Error("ApplyEvent must not mutate the live game")
+	}
+
+	// A six grants another roll to the same player
+	if moved.CurrentTurn != mover {
+		t.Errorf("Expected extra turn for %s, got %s", mover, moved.CurrentTurn)
+	}
+}
+
+func TestApplyEventRejectsInvalidEvents(t *testing.T) {
+	game := newEngineTestGame(t)
+	state := game.EngineState()
+
+	if _, err := ApplyEvent(state, EngineEvent{Type: EventRoll, PlayerID: state.CurrentTurn, Roll: 7}); err != ErrInvalidDiceValue {
+		t.Errorf("Expected ErrInvalidDiceValue, got %v", err)
+	}
+	if _, err := ApplyEvent(state, EngineEvent{Type: EventMove, PlayerID: state.CurrentTurn}); err != ErrMustRollFirst {
+		t.Errorf("Expected ErrMustRollFirst, got %v", err)
+	}
+	if _, err := ApplyEvent(state, EngineEvent{Type: "teleport"}); err != ErrUnknownEvent {
+		t.Errorf("Expected ErrUnknownEvent, got %v", err)
+	}
+}
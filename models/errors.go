@@ -0,0 +1,136 @@
+package models
+
+import (
+	"errors"
+	"sort"
+)
+
+// errorCodes gives every model error a stable, machine-readable code so API
+// clients can branch on it; messages are for people and may change
+var errorCodes = map[error]string{
+	ErrGameNotFound:           "GAME_NOT_FOUND",
+	ErrGameFull:               "GAME_FULL",
+	ErrGameStarted:            "GAME_STARTED",
+	ErrGamePaused:             "GAME_PAUSED",
+	ErrGameNotPaused:          "GAME_NOT_PAUSED",
+	ErrInvalidCode:            "INVALID_GAME_CODE",
+	ErrPlayerExists:           "PLAYER_EXISTS",
+	ErrNotPlayerTurn:          "NOT_YOUR_TURN",
+	ErrInvalidMove:            "INVALID_MOVE",
+	ErrTurnTimeout:            "TURN_TIMED_OUT",
+	ErrNotHost:                "NOT_HOST",
+	ErrPlayersNotReady:        "PLAYERS_NOT_READY",
+	ErrInvalidPlayerName:      "INVALID_PLAYER_NAME",
+	ErrInvalidPlayerID:        "INVALID_PLAYER_ID",
+	ErrMustRollFirst:          "MUST_ROLL_FIRST",
+	ErrAlreadyRolled:          "ALREADY_ROLLED",
+	ErrThreeSixes:             "THREE_SIXES",
+	ErrPlayerNotFound:         "PLAYER_NOT_FOUND",
+	ErrCannotKickSelf:         "CANNOT_KICK_SELF",
+	ErrChatTooLong:            "CHAT_TOO_LONG",
+	ErrNotEnoughPlayers:       "NOT_ENOUGH_PLAYERS",
+	ErrGameNotDeleted:         "GAME_NOT_DELETED",
+	ErrInvalidTimeoutAction:   "INVALID_TIMEOUT_ACTION",
+	ErrInvalidDiceValue:       "INVALID_DICE_VALUE",
+	ErrGameNotPlaying:         "GAME_NOT_PLAYING",
+	ErrInvalidPieceID:         "INVALID_PIECE_ID",
+	ErrInvalidAFKPolicy:       "INVALID_AFK_POLICY",
+	ErrUnknownGroupBy:         "UNKNOWN_GROUP_BY",
+	ErrInvalidBotPersonality:  "INVALID_BOT_PERSONALITY",
+	ErrInvalidBotDifficulty:   "INVALID_BOT_DIFFICULTY",
+	ErrBotNameTaken:           "BOT_NAME_TAKEN",
+	ErrNotBot:                 "NOT_A_BOT",
+	ErrBotAlreadyClaimed:      "BOT_ALREADY_CLAIMED",
+	ErrInvalidPluginToken:     "INVALID_PLUGIN_TOKEN",
+	ErrInvalidBotTakeover:     "INVALID_BOT_TAKEOVER",
+	ErrPlayerConnected:        "PLAYER_CONNECTED",
+	ErrUnknownLeaderboardSort: "UNKNOWN_LEADERBOARD_SORT",
+	ErrInstantDice:            "INSTANT_DICE",
+	ErrRollPending:            "ROLL_PENDING",
+	ErrNoPendingRoll:          "NO_PENDING_ROLL",
+	ErrInvalidRevealDelay:     "INVALID_REVEAL_DELAY",
+	ErrUnknownEmote:           "UNKNOWN_EMOTE",
+	ErrEmoteRateLimited:       "EMOTE_RATE_LIMITED",
+	ErrUnknownEvent:           "UNKNOWN_EVENT",
+	ErrInvalidExperiment:      "INVALID_EXPERIMENT",
+	ErrExperimentRunning:      "EXPERIMENT_RUNNING",
+	ErrNoExperiment:           "NO_EXPERIMENT",
+	ErrInvalidFixture:         "INVALID_FIXTURE",
+	ErrFriendSelf:             "CANNOT_FRIEND_SELF",
+	ErrTooManyFriends:         "TOO_MANY_FRIENDS",
+	ErrFriendCodeNotFound:     "FRIEND_CODE_NOT_FOUND",
+	ErrInvalidAvatar:          "INVALID_AVATAR",
+	ErrUnknownColor:           "UNKNOWN_COLOR",
+	ErrColorTaken:             "COLOR_TAKEN",
+	ErrInvalidLocale:          "INVALID_LOCALE",
+	ErrInviteNotFound:         "INVITE_NOT_FOUND",
+	ErrNotFriends:             "NOT_FRIENDS",
+	ErrNoHistory:              "NO_HISTORY",
+	ErrInvalidReplayMove:      "INVALID_REPLAY_MOVE",
+	ErrInvalidNewHost:         "INVALID_NEW_HOST",
+	ErrMaintenance:            "MAINTENANCE",
+	ErrMaintenancePause:       "MAINTENANCE_PAUSED",
+	ErrChatMuted:              "CHAT_MUTED",
+	ErrInvalidMute:            "INVALID_MUTE",
+	ErrCannotMuteHost:         "CANNOT_MUTE_HOST",
+	ErrChatMessageNotFound:    "CHAT_MESSAGE_NOT_FOUND",
+	ErrUnknownPalette:         "UNKNOWN_PALETTE",
+	ErrWrongPassword:          "WRONG_PASSWORD",
+	ErrPasswordTooLong:        "PASSWORD_TOO_LONG",
+	ErrPauseBudgetExhausted:   "PAUSE_BUDGET_EXHAUSTED",
+	ErrPauseTooLong:           "PAUSE_TOO_LONG",
+	ErrNoPauseVote:            "NO_PAUSE_VOTE",
+	ErrInvalidPauseRules:      "INVALID_PAUSE_RULES",
+	ErrWaitingForPlayers:      "WAITING_FOR_PLAYERS",
+	ErrPreMoveOwnTurn:         "PREMOVE_ON_OWN_TURN",
+	ErrInvalidPreMove:         "INVALID_PREMOVE",
+	ErrUnknownPreset:          "UNKNOWN_PRESET",
+	ErrReplayNotFound:         "REPLAY_NOT_FOUND",
+	ErrArchiveNotFound:        "ARCHIVE_NOT_FOUND",
+	ErrArchiveDisabled:        "ARCHIVE_DISABLED",
+	ErrInvalidArchiveURL:      "INVALID_ARCHIVE_URL",
+	ErrInvalidRules:           "INVALID_RULES",
+	ErrGameEnded:              "GAME_ENDED",
+	ErrInvalidDepartureAction: "INVALID_DEPARTURE_ACTION",
+	ErrHostNotActive:          "HOST_NOT_ACTIVE",
+	ErrTakeoverNotFound:       "TAKEOVER_NOT_FOUND",
+	ErrTooManyTakeovers:       "TOO_MANY_TAKEOVERS",
+	ErrMissingSignature:       "MISSING_SIGNATURE",
+	ErrInvalidSignature:       "INVALID_SIGNATURE",
+	ErrNonceReused:            "NONCE_REUSED",
+	ErrStaleNonce:             "STALE_NONCE",
+	ErrHumansRemaining:        "HUMANS_REMAINING",
+	ErrSnapshotVersion:        "UNSUPPORTED_SNAPSHOT",
+	ErrUnknownStorage:         "UNKNOWN_STORAGE",
+	ErrInvalidNamespace:       "INVALID_NAMESPACE",
+	ErrInvalidStorageDir:      "INVALID_STORAGE_DIR",
+	ErrUnknownTheme:           "UNKNOWN_THEME",
+	ErrWrongThemeKind:         "WRONG_THEME_KIND",
+	ErrInvalidTurnTimeout:     "INVALID_TURN_TIMEOUT",
+	ErrTooManyGames:           "TOO_MANY_GAMES",
+	ErrInvalidManagerConfig:   "INVALID_MANAGER_CONFIG",
+}
+
+// ErrorCode returns the machine-readable code of a model error, or of the model
+// error it wraps; empty for any other error
+func ErrorCode(err error) string {
+	if code, ok := errorCodes[err]; ok {
+		return code
+	}
+	for known, code := range errorCodes {
+		if errors.Is(err, known) {
+			return code
+		}
+	}
+	return ""
+}
+
+// ErrorCodes lists every model error code, sorted
+func ErrorCodes() []string {
+	codes := make([]string, 0, len(errorCodes))
+	for _, code := range errorCodes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
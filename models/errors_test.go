@@ -0,0 +1,40 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrorCode(t *testing.T) {
+	game := newPauseTestGame(t)
+	waiting := "p1"
+	if game.CurrentTurn == waiting {
+		waiting = "p2"
+	}
+
+	_, err := game.RollDice(waiting)
+	if code := ErrorCode(err); code != "NOT_YOUR_TURN" {
+		t.Errorf("Expected NOT_YOUR_TURN, got %q for %v", code, err)
+	}
+
+	// Wrapped errors keep their code
+	if code := ErrorCode(RuleSet{PiecesPerPlayer: 99}.Validate()); code != "INVALID_RULES" {
+		t.Errorf("Expected INVALID_RULES for a wrapped error, got %q", code)
+	}
+	if code := ErrorCode(errors.New("something else")); code != "" {
+		t.Errorf("Expected no code for an unknown error, got %q", code)
+	}
+}
+
+func TestErrorCodesUnique(t *testing.T) {
+	seen := make(map[string]error)
+	for err, code := range errorCodes {
+		if other, ok := seen[code]; ok {
+			t.Errorf("%q and %q share the code %s", err, other, code)
+		}
+		seen[code] = err
+	}
+	if len(ErrorCodes()) != len(errorCodes) {
+		t.Errorf("Expected %d codes, got %d", len(errorCodes), len(ErrorCodes()))
+	}
+}
@@ -0,0 +1,208 @@
+package models
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// Experiment arms. While an experiment runs, every casual game is tagged with one:
+// treatment games get the experimental rules, control games the defaults.
+const (
+	ArmControl   = "control"
+	ArmTreatment = "treatment"
+)
+
+var (
+	ErrInvalidExperiment = errors.New("invalid experiment")
+	ErrExperimentRunning = errors.New("an experiment is already running")
+	ErrNoExperiment      = errors.New("no such experiment")
+)
+
+// ExperimentRules are the rule changes a treatment game gets
+type ExperimentRules struct {
+	CaptureGrantsTurn  *bool         `json:"capture_grants_turn,omitempty"`
+	TurnTimeoutSeconds int           `json:"turn_timeout_seconds,omitempty"`
+	TimeoutAction      TimeoutAction `json:"timeout_action,omitempty"`
+}
+
+// Experiment places a fraction of new casual games into an experimental ruleset
+type Experiment struct {
+	ID        string          `json:"id"`
+	Fraction  float64         `json:"fraction"` // Share of casual games given the treatment
+	Rules     ExperimentRules `json:"rules"`
+	StartedAt Timestamp       `json:"started_at"`
+	StoppedAt Timestamp       `json:"stopped_at"` // Null while running
+	Assigned  map[string]int  `json:"assigned"`   // Games tagged, by arm
+}
+
+// ExperimentArmStats compares how one arm's games have gone. Completion counts
+// games that finished at least once, against games assigned to the arm.
+type ExperimentArmStats struct {
+	Arm            string  `json:"arm"`
+	Assigned       int     `json:"assigned"`
+	Completed      int     `json:"completed"`
+	CompletionRate float64 `json:"completion_rate"`
+	AnalyticsGroup
+}
+
+// ExperimentReport is an experiment with the comparative stats of its arms
+type ExperimentReport struct {
+	Experiment
+	Arms []ExperimentArmStats `json:"arms"`
+}
+
+// validate checks an experiment's settings
+func (e *Experiment) validate() error {
+	if e.ID == "" || e.Fraction <= 0 || e.Fraction >= 1 {
+		return ErrInvalidExperiment
+	}
+	r := e.Rules
+	if r.CaptureGrantsTurn == nil && r.TurnTimeoutSeconds == 0 && r.TimeoutAction == "" {
+		return ErrInvalidExperiment
+	}
+	if r.TurnTimeoutSeconds < 0 || time.Duration(r.TurnTimeoutSeconds)*time.Second > 10*time.Minute {
+		return ErrInvalidExperiment
+	}
+	switch r.TimeoutAction {
+	case "", TimeoutSkip, TimeoutAutoPlay:
+	default:
+		return ErrInvalidTimeoutAction
+	}
+	return nil
+}
+
+// StartExperiment begins assigning casual games to an experiment. Only one runs at a time.
+func (gm *GameManager) StartExperiment(id string, fraction float64, rules ExperimentRules) (Experiment, error) {
+	exp := &Experiment{
+		ID:        id,
+		Fraction:  fraction,
+		Rules:     rules,
+		StartedAt: Now(),
+		Assigned:  map[string]int{ArmControl: 0, ArmTreatment: 0},
+	}
+	if err := exp.validate(); err != nil {
+		return Experiment{}, err
+	}
+
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	if gm.activeExperiment != nil {
+		return Experiment{}, ErrExperimentRunning
+	}
+	if _, exists := gm.experiments[id]; exists {
+		return Experiment{}, ErrInvalidExperiment
+	}
+	if gm.experiments == nil {
+		gm.experiments = make(map[string]*Experiment)
+	}
+	gm.experiments[id] = exp
+	gm.activeExperiment = exp
+	return *exp, nil
+}
+
+// StopExperiment stops assigning games to the running experiment. Games already
+// assigned keep their rules and tags.
+func (gm *GameManager) StopExperiment() (Experiment, error) {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	exp := gm.activeExperiment
+	if exp == nil {
+		return Experiment{}, ErrNoExperiment
+	}
+	exp.StoppedAt = Now()
+	gm.activeExperiment = nil
+	return *exp, nil
+}
+
+// AssignExperiment tags a new casual game with an arm of the running experiment,
+// giving treatment games the experimental rules. Returns the arm, or "" if no
+// experiment is running.
+func (gm *GameManager) AssignExperiment(game *Game) string {
+	gm.mu.Lock()
+	exp := gm.activeExperiment
+	if exp == nil {
+		gm.mu.Unlock()
+		return ""
+	}
+	arm := ArmControl
+	if rand.Float64() < exp.Fraction {
+		arm = ArmTreatment
+	}
+	exp.Assigned[arm]++
+	id, rules := exp.ID, exp.Rules
+	gm.mu.Unlock()
+
+	game.mu.Lock()
+	defer game.unlock()
+
+	game.Experiment = id
+	game.ExperimentArm = arm
+	if arm == ArmTreatment {
+		if rules.CaptureGrantsTurn != nil {
+			game.CaptureGrantsTurn = *rules.CaptureGrantsTurn
+		}
+		if rules.TurnTimeoutSeconds > 0 {
+			game.TurnTimeout = time.Duration(rules.TurnTimeoutSeconds) * time.Second
+		}
+		if rules.TimeoutAction != "" {
+			game.TimeoutAction = rules.TimeoutAction
+		}
+	}
+	return arm
+}
+
+// ExperimentReports compares the arms of every experiment, newest first
+func (gm *GameManager) ExperimentReports() []ExperimentReport {
+	gm.mu.RLock()
+	experiments := make([]Experiment, 0, len(gm.experiments))
+	for _, exp := range gm.experiments {
+		copied := *exp
+		copied.Assigned = map[string]int{ArmControl: exp.Assigned[ArmControl], ArmTreatment: exp.Assigned[ArmTreatment]}
+		experiments = append(experiments, copied)
+	}
+	gm.mu.RUnlock()
+
+	sort.Slice(experiments, func(i, j int) bool {
+		return experiments[i].StartedAt.After(experiments[j].StartedAt.Time)
+	})
+
+	archived := gm.archive.Games()
+	reports := make([]ExperimentReport, 0, len(experiments))
+	for _, exp := range experiments {
+		reports = append(reports, experimentReport(exp, archived))
+	}
+	return reports
+}
+
+// experimentReport aggregates an experiment's archived games by arm
+func experimentReport(exp Experiment, archived []ArchivedGame) ExperimentReport {
+	report := ExperimentReport{Experiment: exp}
+	for _, arm := range []string{ArmControl, ArmTreatment} {
+		stats := ExperimentArmStats{
+			Arm:            arm,
+			Assigned:       exp.Assigned[arm],
+			AnalyticsGroup: AnalyticsGroup{Key: arm, ByKind: make(map[string]*WinRate)},
+		}
+		completed := make(map[string]bool)
+		for _, game := range archived {
+			if game.Experiment != exp.ID || game.ExperimentArm != arm {
+				continue
+			}
+			completed[game.Code] = true
+			stats.add(game)
+		}
+		stats.Completed = len(completed)
+		if stats.Assigned > 0 {
+			stats.CompletionRate = float64(stats.Completed) / float64(stats.Assigned)
+		}
+		if stats.Games > 0 {
+			stats.finish()
+		}
+		report.Arms = append(report.Arms, stats)
+	}
+	return report
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"context"
+	"testing"
+)
+
+func TestExperimentAssignment(t *testing.T) {
+	gm := NewGameManager()
+	noBonus := false
+
+	if _, err := gm.StartExperiment("no-bonus", 1.5, ExperimentRules{CaptureGrantsTurn: &noBonus}); err != ErrInvalidExperiment {
+		t.Errorf("Expected ErrInvalidExperiment for a fraction above 1, got %v", err)
+	}
+	if _, err := gm.StartExperiment("empty", 0.5, ExperimentRules{}); err != ErrInvalidExperiment {
+		t.Errorf("Expected ErrInvalidExperiment without rules, got %v", err)
+	}
+
+	game, _ := gm.CreateGame(context.Background(), "host", "Host", 4)
+	if arm := gm.AssignExperiment(game); arm != "" || game.Experiment != "" {
+		t.Errorf("Expected no assignment without an experiment, got %q", arm)
+	}
+
+	if _, err := gm.StartExperiment("no-bonus", 0.5, ExperimentRules{CaptureGrantsTurn: &noBonus, TurnTimeoutSeconds: 20}); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if _, err := gm.StartExperiment("other", 0.5, ExperimentRules{TurnTimeoutSeconds: 20}); err != ErrExperimentRunning {
+		t.Errorf("Expected ErrExperimentRunning, got %v", err)
+	}
+
+	arms := map[string]int{}
+	for i := 0; i < 200; i++ {
+		game, _ := gm.CreateGame(context.Background(), "host", "Host", 4)
+		arm := gm.AssignExperiment(game)
+		arms[arm]++
+		if game.Experiment != "no-bonus" || game.ExperimentArm != arm {
+			t.Fatalf("Expected the game tagged with its arm, got %q/%q", game.Experiment, game.ExperimentArm)
+		}
+		treated := arm == ArmTreatment
+		if game.CaptureGrantsTurn == treated || (game.TurnTimeout.Seconds() == 20) != treated {
+			t.Fatalf("Expected %s rules, got capture bonus %v and timeout %v", arm, game.CaptureGrantsTurn, game.TurnTimeout)
+		}
+	}
+	if arms[ArmControl] == 0 || arms[ArmTreatment] == 0 {
+		t.Errorf("Expected games in both arms, got %v", arms)
+	}
+
+	if _, err := gm.StopExperiment(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if _, err := gm.StopExperiment(); err != ErrNoExperiment {
+		t.Errorf("Expected ErrNoExperiment, got %v", err)
+	}
+	game, _ = gm.CreateGame(context.Background(), "host", "Host", 4)
+	if arm := gm.AssignExperiment(game); arm != "" {
+		t.Errorf("Expected no assignment after stopping, got %q", arm)
+	}
+}
+
+func TestExperimentReport(t *testing.T) {
+	exp := Experiment{ID: "no-bonus", Assigned: map[string]int{ArmControl: 4, ArmTreatment: 2}}
+	archived := []ArchivedGame{
+		{Code: "A", Experiment: "no-bonus", ExperimentArm: ArmControl, DurationMs: 600000, Moves: 100},
+		{Code: "B", Experiment: "no-bonus", ExperimentArm: ArmControl, DurationMs: 1200000, Moves: 200},
+		{Code: "B", Experiment: "no-bonus", ExperimentArm: ArmControl, DurationMs: 900000, Moves: 150}, // Rematch
+		{Code: "C", Experiment: "no-bonus", ExperimentArm: ArmTreatment, DurationMs: 300000, Moves: 80},
+		{Code: "D", Experiment: "other", ExperimentArm: ArmTreatment, DurationMs: 100000, Moves: 10},
+	}
+
+	report := experimentReport(exp, archived)
+	control, treatment := report.Arms[0], report.Arms[1]
+	if control.Completed != 2 || control.CompletionRate != 0.5 || control.Games != 3 || control.AvgDurationMs != 900000 {
+		t.Errorf("Unexpected control stats %+v", control)
+	}
+	if treatment.Completed != 1 || treatment.CompletionRate != 0.5 || treatment.AvgMoves != 80 {
+		t.Errorf("Unexpected treatment stats %+v", treatment)
+	}
+}
@@ -0,0 +1,283 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Exports name their format and version, bumped on incompatible changes, so
+// tools can tell them apart from other JSON
+const (
+	ExportFormat  = "ludo-nadwa-game"
+	ExportVersion = 1
+)
+
+// ExportChat is the type of chat messages in an export's events
+const ExportChat = "chat"
+
+// ExportedPlayer is a seat in an exported game
+type ExportedPlayer struct {
+	ID      string      `json:"id"`
+	Name    string      `json:"name"`
+	Color   PlayerColor `json:"color"`
+	Order   int         `json:"order"` // Turn order, 0 moves first
+	IsBot   bool        `json:"is_bot"`
+	HasLeft bool        `json:"has_left"`
+}
+
+// ExportedEvent is a roll, move, skip, pass, departure, forced end or chat
+// message. Moves say where the piece went and whose pieces it sent home.
+type ExportedEvent struct {
+	Seq        int             `json:"seq"` // 1 for the first event
+	At         Timestamp       `json:"at"`
+	Type       string          `json:"type"` // An engine event type, or chat
+	PlayerID   string          `json:"player_id"`
+	PlayerName string          `json:"player_name,omitempty"` // Chat only; spectators aren't seated
+	Roll       int             `json:"roll,omitempty"`
+	PieceID    *int            `json:"piece_id,omitempty"`
+	From       string          `json:"from,omitempty"` // home, square N, home stretch N or finished
+	To         string          `json:"to,omitempty"`
+	Captured   []string        `json:"captured,omitempty"`
+	Action     DepartureAction `json:"action,omitempty"` // What happened to a departing player's pieces
+	Message    string          `json:"message,omitempty"`
+	Spectator  bool            `json:"spectator,omitempty"`
+}
+
+// GameExport is a portable record of a whole game. Start and the engine events
+// replay with ReplayHistory.
+type GameExport struct {
+	Format     string           `json:"format"`
+	Version    int              `json:"version"`
+	Code       string           `json:"code"`
+	State      GameState        `json:"state"`
+	CreatedAt  Timestamp        `json:"created_at"`
+	StartedAt  Timestamp        `json:"started_at"`
+	ExportedAt Timestamp        `json:"exported_at"`
+	Winner     string           `json:"winner,omitempty"`
+	MaxPlayers int              `json:"max_players"`
+	Preset     string           `json:"preset,omitempty"`
+	Ranked     bool             `json:"ranked"`
+	Rules      RuleSet          `json:"rules"`
+	Players    []ExportedPlayer `json:"players"`
+	Start      *EngineState     `json:"start,omitempty"` // Position play started from; null before it starts
+	Events     []ExportedEvent  `json:"events"`
+}
+
+// Export returns a record of the game so far: its players, rules, and every
+// roll, move, skip and chat message in order
+func (g *Game) Export() *GameExport {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.exportLocked()
+}
+
+// exportLocked records the game so far (caller must hold lock)
+func (g *Game) exportLocked() *GameExport {
+	export := &GameExport{
+		Format:     ExportFormat,
+		Version:    ExportVersion,
+		Code:       g.Code,
+		State:      g.State,
+		CreatedAt:  g.CreatedAt,
+		StartedAt:  g.StartedAt,
+		ExportedAt: Now(),
+		Winner:     g.Winner,
+		MaxPlayers: g.MaxPlayers,
+		Preset:     g.Preset,
+		Ranked:     g.Ranked,
+		Rules:      g.rulesLocked(),
+	}
+	export.Rules.CaptureGrantsTurn = g.CaptureGrantsTurn
+	for _, player := range g.Players {
+		export.Players = append(export.Players, ExportedPlayer{
+			ID:      player.ID,
+			Name:    player.Name,
+			Color:   player.Color,
+			Order:   player.Order,
+			IsBot:   player.IsBot,
+			HasLeft: player.HasLeft,
+		})
+	}
+	if g.historyStart != nil {
+		start := *g.historyStart
+		start.Players = clonePlayers(start.Players)
+		export.Start = &start
+	}
+
+	sort.Slice(export.Players, func(i, j int) bool {
+		a, b := export.Players[i], export.Players[j]
+		if a.Order != b.Order {
+			return a.Order < b.Order
+		}
+		return a.ID < b.ID
+	})
+
+	var events []ExportedEvent
+	if export.Start != nil {
+		events = exportHistory(*export.Start, g.history)
+	}
+	for _, msg := range g.ChatMessages {
+		events = append(events, ExportedEvent{
+			At:         msg.Timestamp,
+			Type:       ExportChat,
+			PlayerID:   msg.PlayerID,
+			PlayerName: msg.PlayerName,
+			Message:    msg.Message,
+			Spectator:  msg.IsSpectator,
+		})
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].At.Before(events[j].At.Time)
+	})
+	for i := range events {
+		events[i].Seq = i + 1
+	}
+	export.Events = events
+	return export
+}
+
+// exportHistory replays a game's history to describe each event, with where
+// moved pieces went and whose pieces they sent home
+func exportHistory(start EngineState, history []HistoryEvent) []ExportedEvent {
+	events := make([]ExportedEvent, 0, len(history))
+	state := start
+	for _, event := range history {
+		exported := ExportedEvent{
+			At:       event.At,
+			Type:     string(event.Type),
+			PlayerID: event.PlayerID,
+			Roll:     event.Roll,
+			Action:   event.Action,
+		}
+		next, err := ApplyEvent(state, event.EngineEvent)
+		if err != nil && err != ErrThreeSixes {
+			next = state // Described as recorded; the board stays as it was
+		}
+
+		if event.Type == EventMove {
+			pieceID := event.PieceID
+			exported.PieceID = &pieceID
+			before, after := state.Players[event.PlayerID], next.Players[event.PlayerID]
+			if before != nil && after != nil && pieceID >= 0 && pieceID < len(before.Pieces) && pieceID < len(after.Pieces) {
+				exported.From = describePiece(before.Pieces[pieceID])
+				exported.To = describePiece(after.Pieces[pieceID])
+			}
+			exported.Captured = capturedBetween(state, next, event.PlayerID)
+		}
+		events = append(events, exported)
+		state = next
+	}
+	return events
+}
+
+// capturedBetween lists the opponents with a piece sent home from the board
+// between two states, in ID order
+func capturedBetween(before, after EngineState, moverID string) []string {
+	var captured []string
+	for id, player := range before.Players {
+		if id == moverID || after.Players[id] == nil {
+			continue
+		}
+		for i, piece := range player.Pieces {
+			if i < len(after.Players[id].Pieces) && !piece.IsHome && after.Players[id].Pieces[i].IsHome {
+				captured = append(captured, id)
+				break
+			}
+		}
+	}
+	sort.Strings(captured)
+	return captured
+}
+
+// Text formats the export in a PGN-like form: bracketed tags for the game and
+// its players, then one numbered line per event, then the result
+func (e *GameExport) Text() string {
+	var b strings.Builder
+	tag := func(name, value string) {
+		fmt.Fprintf(&b, "[%s %s]\n", name, strconv.Quote(value))
+	}
+
+	tag("Format", fmt.Sprintf("%s %d", e.Format, e.Version))
+	tag("Game", e.Code)
+	tag("State", string(e.State))
+	tag("Created", e.CreatedAt.String())
+	if !e.StartedAt.IsZero() {
+		tag("Started", e.StartedAt.String())
+	}
+	tag("Exported", e.ExportedAt.String())
+	tag("Players", strconv.Itoa(len(e.Players)))
+	tag("MaxPlayers", strconv.Itoa(e.MaxPlayers))
+	if e.Preset != "" {
+		tag("Preset", e.Preset)
+	}
+	tag("Ranked", strconv.FormatBool(e.Ranked))
+	tag("Rules", rulesText(e.Rules))
+	for _, player := range e.Players {
+		seat := colorTag(player.Color)
+		tag(seat, player.Name)
+		tag(seat+"ID", player.ID)
+		if player.IsBot {
+			tag(seat+"Bot", "true")
+		}
+		if player.HasLeft {
+			tag(seat+"Left", "true")
+		}
+	}
+	result := "*"
+	if e.Winner != "" {
+		result = e.Winner
+	}
+	tag("Result", result)
+	b.WriteString("\n")
+
+	for _, event := range e.Events {
+		fmt.Fprintf(&b, "%d. %s %s %s", event.Seq, event.At.String(), event.PlayerID, event.Type)
+		switch EventType(event.Type) {
+		case EventRoll:
+			fmt.Fprintf(&b, " %d", event.Roll)
+		case EventMove:
+			if event.PieceID != nil {
+				fmt.Fprintf(&b, " %d: %s -> %s", *event.PieceID, event.From, event.To)
+			}
+			if len(event.Captured) > 0 {
+				fmt.Fprintf(&b, " x %s", strings.Join(event.Captured, ","))
+			}
+		case EventLeave:
+			fmt.Fprintf(&b, " %s", event.Action)
+		}
+		if event.Type == ExportChat {
+			if event.Spectator {
+				b.WriteString(" (spectator)")
+			}
+			fmt.Fprintf(&b, " %s", strconv.Quote(event.Message))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(result + "\n")
+	return b.String()
+}
+
+// rulesText lists a rule set as sorted name=value pairs
+func rulesText(rules RuleSet) string {
+	data, _ := json.Marshal(rules)
+	var fields map[string]interface{}
+	json.Unmarshal(data, &fields)
+
+	pairs := make([]string, 0, len(fields))
+	for name, value := range fields {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", name, value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
+
+// colorTag names a seat's tags after its color, e.g. Red, RedID
+func colorTag(color PlayerColor) string {
+	if color == "" {
+		return "Seat"
+	}
+	return strings.ToUpper(string(color[:1])) + string(color[1:])
+}
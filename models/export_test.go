@@ -0,0 +1,71 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExport(t *testing.T) {
+	game := newEngineTestGame(t)
+	mover := game.CurrentTurn
+	game.SendChatMessage("player2", "good luck")
+	game.ChatMessages[0].Timestamp = At(time.Now().Add(-time.Second)) // Sent well before the roll
+
+	game.mu.Lock()
+	game.applyRollLocked(mover, 6)
+	game.unlock()
+	if err := game.MovePiece(mover, 0); err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+
+	export := game.Export()
+	if export.Format != ExportFormat || export.Code != game.Code || len(export.Players) != 2 || export.Start == nil {
+		t.Fatalf("Unexpected export %+v", export)
+	}
+	if len(export.Events) != 3 {
+		t.Fatalf("Expected chat, roll and move, got %+v", export.Events)
+	}
+	chat, roll, move := export.Events[0], export.Events[1], export.Events[2]
+	if chat.Type != ExportChat || chat.Message != "good luck" || chat.PlayerName != "Bob" {
+		t.Errorf("Unexpected chat event %+v", chat)
+	}
+	if roll.Type != string(EventRoll) || roll.Roll != 6 || roll.PlayerID != mover {
+		t.Errorf("Unexpected roll event %+v", roll)
+	}
+	to := describePiece(game.Players[mover].Pieces[0])
+	if move.Type != string(EventMove) || move.PieceID == nil || *move.PieceID != 0 || move.From != "home" || move.To != to || move.Seq != 3 {
+		t.Errorf("Unexpected move event %+v", move)
+	}
+
+	text := export.Text()
+	for _, want := range []string{
+		`[Game "` + game.Code + `"]`,
+		`[Result "*"]`,
+		"require_six_to_leave=true",
+		"1. " + chat.At.String() + ` player2 chat "good luck"`,
+		"2. " + roll.At.String() + " " + mover + " roll 6",
+		"3. " + move.At.String() + " " + mover + " move 0: home -> " + to,
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in text export:\n%s", want, text)
+		}
+	}
+}
+
+func TestExportCaptures(t *testing.T) {
+	before := EngineState{Players: map[string]*Player{
+		"a": {ID: "a", Pieces: []Piece{{Position: 5}}},
+		"b": {ID: "b", Pieces: []Piece{{Position: 9}, {Position: -1, IsHome: true}}},
+	}}
+	after := EngineState{Players: clonePlayers(before.Players)}
+	after.Players["a"].Pieces[0].Position = 9
+	after.Players["b"].Pieces[0] = Piece{Position: -1, IsHome: true}
+
+	if captured := capturedBetween(before, after, "a"); len(captured) != 1 || captured[0] != "b" {
+		t.Errorf("Expected b captured, got %v", captured)
+	}
+	if captured := capturedBetween(before, before, "a"); captured != nil {
+		t.Errorf("Expected no captures, got %v", captured)
+	}
+}
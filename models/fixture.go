@@ -0,0 +1,285 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var ErrInvalidFixture = errors.New("invalid fixture")
+
+// FixturePiece places one piece. Set at most one field; the zero value leaves the
+// piece at home.
+type FixturePiece struct {
+	Position    *int `json:"position,omitempty"`     // Track square
+	HomeStretch int  `json:"home_stretch,omitempty"` // Square of the home stretch, 1 to HomeStretchSize-1
+	Finished    bool `json:"finished,omitempty"`
+}
+
+// FixturePlayer is one seat of a fixture. Pieces not listed stay at home.
+type FixturePlayer struct {
+	ID             string         `json:"id"`
+	Name           string         `json:"name,omitempty"`  // Defaults to the ID
+	Color          PlayerColor    `json:"color,omitempty"` // Defaults to the seat's color
+	IsBot          bool           `json:"is_bot,omitempty"`
+	BotPersonality string         `json:"bot_personality,omitempty"`
+	BotDifficulty  string         `json:"bot_difficulty,omitempty"`
+	Pieces         []FixturePiece `json:"pieces,omitempty"`
+}
+
+// Fixture describes a game in progress for tests and bug reproductions, so a
+// scenario can be set up directly instead of by scripting rolls to reach it.
+// Players take turns in the order listed, by default taking the board's colors
+// in turn, and the first one hosts.
+type Fixture struct {
+	MaxPlayers        int             `json:"max_players,omitempty"` // Board size, default 4
+	Rules             *RuleSet        `json:"rules,omitempty"`       // Defaults to the standard rules
+	CaptureGrantsTurn *bool           `json:"capture_grants_turn,omitempty"`
+	Players           []FixturePlayer `json:"players"`
+	CurrentTurn       string          `json:"current_turn,omitempty"`      // Defaults to the first player
+	Dice              int             `json:"dice,omitempty"`              // Roll awaiting a move; 0 before rolling
+	ConsecutiveSixes  int             `json:"consecutive_sixes,omitempty"` // Sixes already rolled this turn
+}
+
+// fixtureErr wraps ErrInvalidFixture with what is wrong
+func fixtureErr(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidFixture, fmt.Sprintf(format, args...))
+}
+
+// EngineState builds the rules state a fixture describes, for use with ApplyEvent
+func (f Fixture) EngineState() (EngineState, error) {
+	maxPlayers := f.MaxPlayers
+	if maxPlayers == 0 {
+		maxPlayers = 4
+	}
+	if maxPlayers < 2 || maxPlayers > 6 {
+		return EngineState{}, fixtureErr("max_players must be between 2 and 6")
+	}
+	if len(f.Players) < 2 || len(f.Players) > maxPlayers {
+		return EngineState{}, fixtureErr("needs between 2 and %d players", maxPlayers)
+	}
+	if f.Dice < 0 || f.Dice > 6 {
+		return EngineState{}, fixtureErr("dice must be between 0 and 6")
+	}
+	if f.ConsecutiveSixes < 0 || f.ConsecutiveSixes > 2 {
+		return EngineState{}, fixtureErr("consecutive_sixes must be between 0 and 2")
+	}
+
+	rules := DefaultRuleSet()
+	if f.Rules != nil {
+		rules = *f.Rules
+		if err := rules.Validate(); err != nil {
+			return EngineState{}, fixtureErr("%v", err)
+		}
+	}
+
+	board := BoardFor(maxPlayers)
+	state := EngineState{
+		MaxPlayers:        maxPlayers,
+		CaptureGrantsTurn: rules.CaptureGrantsTurn,
+		Rules:             rules,
+		State:             Playing,
+		Players:           make(map[string]*Player, len(f.Players)),
+		CurrentTurn:       f.CurrentTurn,
+		LastDiceRoll:      f.Dice,
+		HasRolled:         f.Dice > 0,
+		ConsecutiveSixes:  f.ConsecutiveSixes,
+	}
+	if f.CaptureGrantsTurn != nil {
+		state.CaptureGrantsTurn = *f.CaptureGrantsTurn
+	}
+	if state.CurrentTurn == "" {
+		state.CurrentTurn = f.Players[0].ID
+	}
+
+	colors := make(map[PlayerColor]bool)
+	for order, fp := range f.Players {
+		player, err := fp.player(board, rules.PiecesPerPlayer, order)
+		if err != nil {
+			return EngineState{}, err
+		}
+		if _, exists := state.Players[player.ID]; exists {
+			return EngineState{}, fixtureErr("player %q is listed twice", player.ID)
+		}
+		if colors[player.Color] {
+			return EngineState{}, fixtureErr("color %q is taken twice", player.Color)
+		}
+		colors[player.Color] = true
+		state.Players[player.ID] = player
+	}
+	if _, exists := state.Players[state.CurrentTurn]; !exists {
+		return EngineState{}, fixtureErr("current_turn %q is not a player", state.CurrentTurn)
+	}
+	return state, nil
+}
+
+// player builds the seat a fixture player takes
+func (fp FixturePlayer) player(board *Board, pieces, order int) (*Player, error) {
+	if err := ValidatePlayerID(fp.ID); err != nil {
+		return nil, fixtureErr("player %q: %v", fp.ID, err)
+	}
+	name := strings.TrimSpace(fp.Name)
+	if name == "" {
+		name = fp.ID
+	}
+	if err := ValidatePlayerName(name); err != nil {
+		return nil, fixtureErr("player %q: %v", fp.ID, err)
+	}
+	if len(fp.Pieces) > pieces {
+		return nil, fixtureErr("player %q has more than %d pieces", fp.ID, pieces)
+	}
+
+	color := fp.Color
+	if color == "" {
+		color = board.SeatColor(order)
+	}
+	if !board.HasColor(color) {
+		return nil, fixtureErr("player %q: no %q seat on this board", fp.ID, color)
+	}
+
+	player := &Player{
+		ID:           fp.ID,
+		Name:         name,
+		Color:        color,
+		Pieces:       make([]Piece, pieces),
+		Order:        order,
+		LastActivity: Now(),
+		IsReady:      true,
+		IsHost:       order == 0,
+		IsBot:        fp.IsBot,
+	}
+	if fp.IsBot {
+		opts := BotOptions{Personality: BotPersonality(fp.BotPersonality), Difficulty: BotDifficulty(fp.BotDifficulty)}
+		if err := opts.normalize(); err != nil {
+			return nil, fixtureErr("player %q: %v", fp.ID, err)
+		}
+		player.BotPersonality = opts.Personality
+		player.BotDifficulty = opts.Difficulty
+	}
+
+	finished := 0
+	for i := range player.Pieces {
+		piece := Piece{ID: i, Position: HomePosition, IsHome: true}
+		if i < len(fp.Pieces) {
+			fixture := fp.Pieces[i]
+			placed := 0
+			if fixture.Position != nil {
+				placed++
+				if *fixture.Position < 0 || *fixture.Position >= board.TrackLength {
+					return nil, fixtureErr("player %q piece %d: position must be between 0 and %d", fp.ID, i, board.TrackLength-1)
+				}
+				piece = Piece{ID: i, Position: *fixture.Position, IsSafe: board.IsSafe(*fixture.Position)}
+			}
+			if fixture.HomeStretch != 0 {
+				placed++
+				if fixture.HomeStretch < 1 || fixture.HomeStretch >= HomeStretchSize {
+					return nil, fixtureErr("player %q piece %d: home_stretch must be between 1 and %d", fp.ID, i, HomeStretchSize-1)
+				}
+				piece = Piece{ID: i, Position: -2, HomeStretchPosition: fixture.HomeStretch, IsSafe: true}
+			}
+			if fixture.Finished {
+				placed++
+				finished++
+				piece = Piece{ID: i, Position: FinishPosition + i, HomeStretchPosition: HomeStretchSize, IsSafe: true, IsFinished: true}
+			}
+			if placed > 1 {
+				return nil, fixtureErr("player %q piece %d has more than one placement", fp.ID, i)
+			}
+		}
+		player.Pieces[i] = piece
+	}
+	if finished == pieces {
+		return nil, fixtureErr("player %q has already won", fp.ID)
+	}
+	return player, nil
+}
+
+// CreateFixtureGame creates a game in progress exactly as a fixture describes it.
+// Every player gets a session secret, like players who joined normally.
+func (gm *GameManager) CreateFixtureGame(ctx context.Context, f Fixture) (*Game, error) {
+	state, err := f.EngineState()
+	if err != nil {
+		return nil, err
+	}
+	host := state.Players[f.Players[0].ID]
+	game, err := gm.CreateGame(ctx, host.ID, host.Name, state.MaxPlayers)
+	if err != nil {
+		return nil, err
+	}
+
+	game.mu.Lock()
+	defer game.unlock()
+
+	game.Players = state.Players
+	for id := range game.Players {
+		if id != host.ID {
+			game.issueSessionSecret(id)
+		}
+	}
+	game.Rules = state.Rules
+	game.CaptureGrantsTurn = state.CaptureGrantsTurn
+	game.TurnTimeout = state.Rules.TurnTimeout()
+	game.State = state.State
+	game.CurrentTurn = state.CurrentTurn
+	game.LastDiceRoll = state.LastDiceRoll
+	game.HasRolled = state.HasRolled
+	game.ConsecutiveSixes = state.ConsecutiveSixes
+	game.TurnStartTime = Now()
+	game.StartedAt = game.TurnStartTime
+	game.LastActivity = Now()
+	game.beginHistoryLocked()
+	return game, nil
+}
+
+// Fixture captures a game's board as a fixture, so a bug seen in a live game can
+// be reproduced elsewhere
+func (g *Game) Fixture() Fixture {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	captureGrantsTurn := g.CaptureGrantsTurn
+	rules := g.rulesLocked()
+	f := Fixture{
+		MaxPlayers:        g.MaxPlayers,
+		Rules:             &rules,
+		CaptureGrantsTurn: &captureGrantsTurn,
+		CurrentTurn:       g.CurrentTurn,
+		ConsecutiveSixes:  g.ConsecutiveSixes,
+	}
+	if g.HasRolled {
+		f.Dice = g.LastDiceRoll
+	}
+
+	seated := make([]*Player, 0, len(g.Players))
+	for _, p := range g.Players {
+		seated = append(seated, p)
+	}
+	sort.Slice(seated, func(i, j int) bool { return seated[i].Order < seated[j].Order })
+
+	for _, p := range seated {
+		fp := FixturePlayer{
+			ID:             p.ID,
+			Name:           p.Name,
+			Color:          p.Color,
+			IsBot:          p.IsBot,
+			BotPersonality: string(p.BotPersonality),
+			BotDifficulty:  string(p.BotDifficulty),
+			Pieces:         make([]FixturePiece, len(p.Pieces)),
+		}
+		for i, piece := range p.Pieces {
+			switch {
+			case piece.IsFinished:
+				fp.Pieces[i].Finished = true
+			case piece.HomeStretchPosition > 0:
+				fp.Pieces[i].HomeStretch = piece.HomeStretchPosition
+			case !piece.IsHome:
+				position := piece.Position
+				fp.Pieces[i].Position = &position
+			}
+		}
+		f.Players = append(f.Players, fp)
+	}
+	return f
+}
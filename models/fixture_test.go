@@ -0,0 +1,91 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestFixtureCapture(t *testing.T) {
+	// Alice is two squares behind Bob's piece with a two to play
+	fixture := Fixture{
+		MaxPlayers: 2,
+		Dice:       2,
+		Players: []FixturePlayer{
+			{ID: "alice", Pieces: []FixturePiece{{Position: intPtr(3)}, {HomeStretch: 2}, {Finished: true}}},
+			{ID: "bob", IsBot: true, Pieces: []FixturePiece{{Position: intPtr(5)}}},
+		},
+	}
+	state, err := fixture.EngineState()
+	if err != nil {
+		t.Fatalf("Fixture rejected: %v", err)
+	}
+	if state.CurrentTurn != "alice" || !state.HasRolled || state.Players["alice"].Pieces[3].IsHome != true {
+		t.Fatalf("Unexpected state %+v", state)
+	}
+
+	moved, err := ApplyEvent(state, EngineEvent{Type: EventMove, PlayerID: "alice", PieceID: 0})
+	if err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if !moved.Players["bob"].Pieces[0].IsHome || moved.Players["alice"].Stats.CapturesMade != 1 {
+		t.Errorf("Expected bob's piece captured, got %+v", moved.Players["bob"].Pieces[0])
+	}
+}
+
+func TestFixtureValidation(t *testing.T) {
+	tests := map[string]Fixture{
+		"one player":      {Players: []FixturePlayer{{ID: "a"}}},
+		"bad dice":        {Dice: 7, Players: []FixturePlayer{{ID: "a"}, {ID: "b"}}},
+		"duplicate":       {Players: []FixturePlayer{{ID: "a"}, {ID: "a"}}},
+		"unknown turn":    {CurrentTurn: "c", Players: []FixturePlayer{{ID: "a"}, {ID: "b"}}},
+		"off the track":   {Players: []FixturePlayer{{ID: "a", Pieces: []FixturePiece{{Position: intPtr(52)}}}, {ID: "b"}}},
+		"two placements":  {Players: []FixturePlayer{{ID: "a", Pieces: []FixturePiece{{Position: intPtr(1), Finished: true}}}, {ID: "b"}}},
+		"shared color":    {Players: []FixturePlayer{{ID: "a", Color: Red}, {ID: "b", Color: Red}}},
+		"already won":     {Players: []FixturePlayer{{ID: "a", Pieces: []FixturePiece{{Finished: true}, {Finished: true}, {Finished: true}, {Finished: true}}}, {ID: "b"}}},
+		"stretch too far": {Players: []FixturePlayer{{ID: "a", Pieces: []FixturePiece{{HomeStretch: HomeStretchSize}}}, {ID: "b"}}},
+	}
+	for name, fixture := range tests {
+		if _, err := fixture.EngineState(); !errors.Is(err, ErrInvalidFixture) {
+			t.Errorf("%s: expected ErrInvalidFixture, got %v", name, err)
+		}
+	}
+}
+
+func TestCreateFixtureGameRoundTrip(t *testing.T) {
+	gm := NewGameManager()
+	fixture := Fixture{
+		MaxPlayers:  4,
+		CurrentTurn: "bob",
+		Players: []FixturePlayer{
+			{ID: "alice", Color: Green, Pieces: []FixturePiece{{Position: intPtr(20)}}},
+			{ID: "bob", Pieces: []FixturePiece{{}, {HomeStretch: 4}}},
+		},
+	}
+	game, err := gm.CreateFixtureGame(context.Background(), fixture)
+	if err != nil {
+		t.Fatalf("Failed to create game: %v", err)
+	}
+	if game.State != Playing || game.HostID != "alice" || game.CurrentTurn != "bob" || game.SessionSecret("bob") == "" {
+		t.Fatalf("Unexpected game %+v", game)
+	}
+	if _, err := game.RollDice("bob"); err != nil && err != ErrThreeSixes {
+		t.Errorf("Expected bob to be able to roll, got %v", err)
+	}
+
+	exported := game.Fixture()
+	if len(exported.Players) != 2 || exported.Players[0].Color != Green || exported.Players[1].Color != Blue {
+		t.Fatalf("Expected the seats exported in turn order, got %+v", exported.Players)
+	}
+	if p := exported.Players[0].Pieces[0].Position; p == nil || *p != 20 {
+		t.Errorf("Expected alice's piece on 20, got %v", p)
+	}
+	if exported.Players[1].Pieces[1].HomeStretch != 4 {
+		t.Errorf("Expected bob's piece in the home stretch, got %+v", exported.Players[1].Pieces[1])
+	}
+	if _, err := exported.EngineState(); err != nil {
+		t.Errorf("Expected the export to load back, got %v", err)
+	}
+}
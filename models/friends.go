@@ -0,0 +1,223 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MaxFriends caps how many friends one player can add
+const MaxFriends = 200
+
+var (
+	ErrFriendSelf     = errors.New("cannot add yourself as a friend")
+	ErrTooManyFriends = errors.New("friend list is full")
+)
+
+// FriendStore keeps each player's friend list. Adding a player asks them to be
+// friends; they become friends once the other player adds them back, and only
+// then see each other's presence or can invite each other. With storage
+// attached, each change is saved in the background.
+type FriendStore struct {
+	friends map[string]map[string]time.Time // Player ID -> players they added -> when added
+	saver   *recordSaver
+	mu      sync.RWMutex
+}
+
+// NewFriendStore creates an empty friend store
+func NewFriendStore() *FriendStore {
+	s := &FriendStore{
+		friends: make(map[string]map[string]time.Time),
+	}
+	s.saver = newRecordSaver(FriendsNamespace, s.encode)
+	return s
+}
+
+// Friends returns the friend store
+func (gm *GameManager) Friends() *FriendStore {
+	return gm.friends
+}
+
+// Add asks friendID to be playerID's friend, or accepts their request. It
+// reports whether the two are now friends.
+func (s *FriendStore) Add(playerID, friendID string) (bool, error) {
+	if err := ValidatePlayerID(playerID); err != nil {
+		return false, err
+	}
+	if err := ValidatePlayerID(friendID); err != nil {
+		return false, err
+	}
+	if playerID == friendID {
+		return false, ErrFriendSelf
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := s.friends[playerID]
+	if list == nil {
+		list = make(map[string]time.Time)
+		s.friends[playerID] = list
+	}
+	if _, exists := list[friendID]; !exists {
+		if len(list) >= MaxFriends {
+			return false, ErrTooManyFriends
+		}
+		list[friendID] = time.Now()
+		s.saver.mark(playerID)
+	}
+	return s.mutualLocked(playerID, friendID), nil
+}
+
+// Remove ends a friendship, withdraws a request or declines one, whichever
+// way it goes between the two players
+func (s *FriendStore) Remove(playerID, friendID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, pair := range [][2]string{{playerID, friendID}, {friendID, playerID}} {
+		list := s.friends[pair[0]]
+		if _, exists := list[pair[1]]; !exists {
+			continue
+		}
+		delete(list, pair[1])
+		if len(list) == 0 {
+			delete(s.friends, pair[0])
+		}
+		s.saver.mark(pair[0])
+	}
+}
+
+// IsFriend reports whether two players have added each other
+func (s *FriendStore) IsFriend(playerID, friendID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.mutualLocked(playerID, friendID)
+}
+
+// List returns playerID's friends in the order they were added
+func (s *FriendStore) List(playerID string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := s.friends[playerID]
+	ids := make([]string, 0, len(list))
+	for id := range list {
+		if s.mutualLocked(playerID, id) {
+			ids = append(ids, id)
+		}
+	}
+	sortByAdded(ids, list)
+	return ids
+}
+
+// Requests returns the players asking to be playerID's friend, oldest first
+func (s *FriendStore) Requests(playerID string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	asked := make(map[string]time.Time)
+	for requesterID, list := range s.friends {
+		if at, ok := list[playerID]; ok && !s.mutualLocked(playerID, requesterID) {
+			asked[requesterID] = at
+		}
+	}
+	ids := make([]string, 0, len(asked))
+	for id := range asked {
+		ids = append(ids, id)
+	}
+	sortByAdded(ids, asked)
+	return ids
+}
+
+// Followers returns the friends of friendID, who hear about their presence
+func (s *FriendStore) Followers(friendID string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var ids []string
+	for id := range s.friends[friendID] {
+		if s.mutualLocked(friendID, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// mutualLocked reports whether both players added each other (caller must hold lock)
+func (s *FriendStore) mutualLocked(a, b string) bool {
+	_, ab := s.friends[a][b]
+	_, ba := s.friends[b][a]
+	return ab && ba
+}
+
+// sortByAdded orders player IDs by when they were added, then by ID
+func sortByAdded(ids []string, added map[string]time.Time) {
+	sort.Slice(ids, func(i, j int) bool {
+		if !added[ids[i]].Equal(added[ids[j]]) {
+			return added[ids[i]].Before(added[ids[j]])
+		}
+		return ids[i] < ids[j]
+	})
+}
+
+// encode serializes a player's friend list for storage; an empty list is deleted
+func (s *FriendStore) encode(playerID string) ([]byte, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := s.friends[playerID]
+	if len(list) == 0 {
+		return nil, false
+	}
+	data, _ := json.Marshal(list)
+	return data, true
+}
+
+// load restores every stored friend list
+func (s *FriendStore) load(ctx context.Context) error {
+	return s.saver.load(ctx, func(playerID string, data []byte) error {
+		var list map[string]time.Time
+		if err := json.Unmarshal(data, &list); err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if len(list) > 0 {
+			s.friends[playerID] = list
+		}
+		return nil
+	})
+}
+
+// PresenceInfo returns the name a player or spectator goes by in the game, and the game's state
+func (g *Game) PresenceInfo(playerID string) (name string, state GameState, ok bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if player, exists := g.Players[playerID]; exists {
+		return player.Name, g.State, true
+	}
+	if spectator, exists := g.Spectators[playerID]; exists {
+		return spectator.Name, g.State, true
+	}
+	return "", g.State, false
+}
+
+// HumanPlayerIDs returns the IDs of the game's human players
+func (g *Game) HumanPlayerIDs() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var ids []string
+	for id, player := range g.Players {
+		if !player.IsBot {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
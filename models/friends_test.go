@@ -0,0 +1,122 @@
+package models
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestFriendStore(t *testing.T) {
+	friends := NewGameManager().Friends()
+
+	if _, err := friends.Add("player1", "player1"); err != ErrFriendSelf {
+		t.Errorf("Expected ErrFriendSelf, got %v", err)
+	}
+	if _, err := friends.Add("player1", "bad id!"); err == nil {
+		t.Error("Expected an invalid friend ID to be rejected")
+	}
+
+	if mutual, err := friends.Add("player1", "player2"); err != nil || mutual {
+		t.Errorf("Expected a request waiting for player2, got %v, %v", mutual, err)
+	}
+	friends.Add("player1", "player3")
+	friends.Add("player1", "player2") // Asking twice is a no-op
+	friends.Add("player3", "player2")
+
+	// A one-way add is only a request
+	if list := friends.List("player1"); len(list) != 0 {
+		t.Errorf("Expected no friends before anyone accepts, got %v", list)
+	}
+	if friends.IsFriend("player1", "player2") || len(friends.Followers("player2")) != 0 {
+		t.Error("Expected a request not to share presence")
+	}
+	if requests := friends.Requests("player2"); len(requests) != 2 || requests[0] != "player1" || requests[1] != "player3" {
+		t.Errorf("Expected requests from [player1 player3], got %v", requests)
+	}
+
+	if mutual, _ := friends.Add("player2", "player1"); !mutual {
+		t.Error("Expected adding back to accept the request")
+	}
+	friends.Add("player3", "player1")
+	if list := friends.List("player1"); len(list) != 2 || list[0] != "player2" || list[1] != "player3" {
+		t.Errorf("Expected [player2 player3], got %v", list)
+	}
+	if followers := friends.Followers("player2"); len(followers) != 1 || followers[0] != "player1" {
+		t.Errorf("Expected player1 to follow player2, got %v", followers)
+	}
+	if requests := friends.Requests("player2"); len(requests) != 1 || requests[0] != "player3" {
+		t.Errorf("Expected player3 still asking, got %v", requests)
+	}
+
+	// Removing ends the friendship both ways, and declines a request
+	friends.Remove("player2", "player1")
+	if friends.IsFriend("player1", "player2") || len(friends.Requests("player1")) != 0 || len(friends.Requests("player2")) != 1 {
+		t.Error("Expected the friendship gone without leaving a request")
+	}
+	friends.Remove("player2", "player3")
+	if requests := friends.Requests("player2"); len(requests) != 0 {
+		t.Errorf("Expected the request declined, got %v", requests)
+	}
+}
+
+func TestFriendCodes(t *testing.T) {
+	profiles := NewGameManager().Profiles()
+
+	code, err := profiles.FriendCode("player1")
+	if err != nil || len(code) != FriendCodeLength {
+		t.Fatalf("Expected a friend code, got %q, %v", code, err)
+	}
+	if again, _ := profiles.FriendCode("player1"); again != code {
+		t.Errorf("Expected the same code again, got %q and %q", code, again)
+	}
+	if profiles.Get("player1").FriendCode != code {
+		t.Error("Expected the code saved to the profile")
+	}
+
+	shared := strings.ToLower(code[:4] + "-" + code[4:])
+	if id, err := profiles.ByFriendCode(shared); err != nil || id != "player1" {
+		t.Errorf("Expected player1 for %q, got %q, %v", shared, id, err)
+	}
+	if _, err := profiles.ByFriendCode("NOPE"); err != ErrFriendCodeNotFound {
+		t.Errorf("Expected ErrFriendCodeNotFound, got %v", err)
+	}
+}
+
+func TestFriendsAndProfilesPersist(t *testing.T) {
+	ctx := context.Background()
+	storage := NewMemoryStorage()
+	gm := NewGameManager()
+	gm.SetStorage(storage)
+
+	gm.Friends().Add("player1", "player2")
+	gm.Friends().Add("player2", "player1")
+	gm.Friends().Add("player3", "player2")
+	gm.Friends().Remove("player3", "player2")
+	gm.Friends().Add("player4", "player1")
+	gm.Profiles().SetDisplayName("player1", "Amine")
+	code, _ := gm.Profiles().FriendCode("player1")
+	if err := gm.FlushStorage(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	restored := NewGameManager()
+	restored.SetStorage(storage)
+	if _, err := restored.LoadFromStorage(ctx); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if list := restored.Friends().List("player1"); len(list) != 1 || list[0] != "player2" {
+		t.Errorf("Expected player1's friends restored, got %v", list)
+	}
+	if list := restored.Friends().List("player3"); len(list) != 0 {
+		t.Errorf("Expected player3's emptied list gone, got %v", list)
+	}
+	if requests := restored.Friends().Requests("player1"); len(requests) != 1 || requests[0] != "player4" {
+		t.Errorf("Expected player4's request restored, got %v", requests)
+	}
+	if profile := restored.Profiles().Get("player1"); profile.DisplayName != "Amine" || profile.FriendCode != code {
+		t.Errorf("Expected the profile restored, got %+v", profile)
+	}
+	if id, _ := restored.Profiles().ByFriendCode(code); id != "player1" {
+		t.Errorf("Expected the friend code to still find player1, got %q", id)
+	}
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNoHistory         = errors.New("game has not started")
+	ErrInvalidReplayMove = errors.New("move out of range")
+)
+
+// HistoryEvent is an engine event as it happened in a game
+type HistoryEvent struct {
+	EngineEvent
+	At Timestamp `json:"at"`
+}
+
+// ReplayFrame is a game's board after the first Move events of its history
+type ReplayFrame struct {
+	Move  int           `json:"move"`            // Events applied, 0 for the opening position
+	Total int           `json:"total"`           // Events in the history
+	Event *HistoryEvent `json:"event,omitempty"` // Last event applied
+	State EngineState   `json:"state"`
+}
+
+// beginHistoryLocked starts recording the game's events from its current
+// position (caller must hold lock)
+func (g *Game) beginHistoryLocked() {
+	start := g.engineStateLocked()
+	g.historyStart = &start
+	g.history = nil
+}
+
+// recordEventLocked adds an event to the game's history. Scratch games built by
+// the engine keep none (caller must hold lock).
+func (g *Game) recordEventLocked(event EngineEvent) {
+	if g.historyStart == nil {
+		return
+	}
+	g.history = append(g.history, HistoryEvent{EngineEvent: event, At: Now()})
+}
+
+// renameInHistoryLocked rewrites a seat's player ID throughout the history when
+// someone new takes the seat (caller must hold lock)
+func (g *Game) renameInHistoryLocked(oldID, newID string) {
+	for i := range g.history {
+		if g.history[i].PlayerID == oldID {
+			g.history[i].PlayerID = newID
+		}
+	}
+
+	start := g.historyStart
+	if start == nil {
+		return
+	}
+	if player, exists := start.Players[oldID]; exists {
+		renamed := *player
+		renamed.ID = newID
+		delete(start.Players, oldID)
+		start.Players[newID] = &renamed
+	}
+	if start.CurrentTurn == oldID {
+		start.CurrentTurn = newID
+	}
+}
+
+// History returns the position play started from and every roll, move, skip,
+// pass and departure since, in order
+func (g *Game) History() (EngineState, []HistoryEvent, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if g.historyStart == nil {
+		return EngineState{}, nil, ErrNoHistory
+	}
+	start := *g.historyStart
+	start.Players = clonePlayers(start.Players)
+	return start, append([]HistoryEvent(nil), g.history...), nil
+}
+
+// ReplayAt reconstructs the game's board after the first move events of its history
+func (g *Game) ReplayAt(move int) (*ReplayFrame, error) {
+	start, events, err := g.History()
+	if err != nil {
+		return nil, err
+	}
+	return ReplayHistory(start, events, move)
+}
+
+// ReplayHistory applies the first move events to a starting position
+func ReplayHistory(start EngineState, events []HistoryEvent, move int) (*ReplayFrame, error) {
+	if move < 0 || move > len(events) {
+		return nil, fmt.Errorf("%w: 0 to %d", ErrInvalidReplayMove, len(events))
+	}
+
+	frame := &ReplayFrame{Move: move, Total: len(events), State: start}
+	for i := 0; i < move; i++ {
+		next, err := ApplyEvent(frame.State, events[i].EngineEvent)
+		if err != nil && err != ErrThreeSixes {
+			return nil, fmt.Errorf("event %d (%s by %s): %w", i, events[i].Type, events[i].PlayerID, err)
+		}
+		frame.State = next
+	}
+	if move > 0 {
+		frame.Event = &events[move-1]
+	}
+	return frame, nil
+}
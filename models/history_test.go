@@ -0,0 +1,92 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestReplayHistory(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "p1", "Player 1", 4)
+	if _, err := game.ReplayAt(0); err != ErrNoHistory {
+		t.Errorf("Expected ErrNoHistory before the start, got %v", err)
+	}
+	gm.JoinGame(context.Background(), game.Code, "p2", "Player 2", "")
+	gm.JoinGame(context.Background(), game.Code, "p3", "Player 3", "")
+	for id := range game.Players {
+		game.SetPlayerReady(id, true)
+	}
+	game.StartGame("p1")
+
+	// Play rolls and moves, passing some turns, then have a player leave
+	for turn := 0; turn < 300 && game.State == Playing; turn++ {
+		player := game.CurrentTurn
+		if turn%7 == 6 {
+			game.mu.Lock()
+			game.passTurnLocked(player)
+			game.unlock()
+			continue
+		}
+		if _, err := game.RollDice(player); err != nil {
+			continue // Three sixes passed the turn
+		}
+		if moves := game.GetValidMoves(player); len(moves) > 0 {
+			if err := game.MovePiece(player, moves[len(moves)-1]); err != nil {
+				t.Fatalf("Move failed: %v", err)
+			}
+		} else {
+			game.SkipTurn(player)
+		}
+		if turn == 150 {
+			game.mu.Lock()
+			game.departLocked("p3", DepartureHome)
+			game.unlock()
+		}
+	}
+
+	frame, err := game.ReplayAt(len(game.history))
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	for id, player := range game.Players {
+		for i, piece := range player.Pieces {
+			if !samePiecePlacement(piece, frame.State.Players[id].Pieces[i]) {
+				t.Errorf("%s piece %d: live %s, replayed %s", id, i, describePiece(piece), describePiece(frame.State.Players[id].Pieces[i]))
+			}
+		}
+	}
+	if frame.State.CurrentTurn != game.CurrentTurn || frame.State.Winner != game.Winner {
+		t.Errorf("Expected turn %q and winner %q, got %q and %q", game.CurrentTurn, game.Winner, frame.State.CurrentTurn, frame.State.Winner)
+	}
+
+	opening, _ := game.ReplayAt(0)
+	if opening.Event != nil || opening.Total != len(game.history) || !opening.State.Players["p1"].Pieces[0].IsHome {
+		t.Errorf("Unexpected opening frame %+v", opening)
+	}
+	if _, err := game.ReplayAt(len(game.history) + 1); !errors.Is(err, ErrInvalidReplayMove) {
+		t.Errorf("Expected ErrInvalidReplayMove, got %v", err)
+	}
+}
+
+func TestHistorySurvivesSnapshot(t *testing.T) {
+	game := newPauseTestGame(t)
+	game.RollDice(game.CurrentTurn)
+
+	data, err := game.marshalSnapshot()
+	if err != nil {
+		t.Fatalf("Failed to snapshot: %v", err)
+	}
+	restored := NewGameManager()
+	restored.mu.Lock()
+	saved, err := restored.unmarshalSnapshotLocked(data)
+	restored.mu.Unlock()
+	if err != nil {
+		t.Fatalf("Failed to restore: %v", err)
+	}
+
+	frame, err := saved.ReplayAt(1)
+	if err != nil || frame.Event.Type != EventRoll || frame.State.LastDiceRoll != game.LastDiceRoll {
+		t.Errorf("Expected the roll in the restored history, got %+v, %v", frame, err)
+	}
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"errors"
+	"sort"
+)
+
+var ErrInvalidNewHost = errors.New("new host must be a human player still in the game")
+
+// HostChange records the host role passing to another player
+type HostChange struct {
+	HostID         string `json:"host_id"`
+	PreviousHostID string `json:"previous_host_id"`
+	Reason         string `json:"reason"` // "transferred" by the host, or "host_left"
+}
+
+// TransferHost hands the host role to another human player still in the game (host only)
+func (g *Game) TransferHost(hostID, newHostID string) error {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.HostID != hostID {
+		return ErrNotHost
+	}
+	if g.State == Ended {
+		return ErrGameEnded
+	}
+	player, exists := g.Players[newHostID]
+	if !exists || newHostID == hostID || player.IsBot || player.HasLeft {
+		return ErrInvalidNewHost
+	}
+	g.setHostLocked(newHostID, "transferred")
+	g.LastActivity = Now()
+	return nil
+}
+
+// ConsumeHostChanges returns the host changes since the last call, so each is
+// announced once
+func (g *Game) ConsumeHostChanges() []HostChange {
+	g.mu.Lock()
+	defer g.unlock()
+
+	changes := g.hostChanges
+	g.hostChanges = nil
+	return changes
+}
+
+// migrateHostLocked hands the host role on if the host is no longer a human in
+// the game: to the next human in turn order after the seat at order after,
+// preferring those who are connected. Keeps the old host if nobody can take
+// over. (caller must hold lock)
+func (g *Game) migrateHostLocked(after int) {
+	if host, exists := g.Players[g.HostID]; exists && !host.IsBot && !host.HasLeft {
+		return
+	}
+
+	var candidates []*Player
+	for _, p := range g.Players {
+		if !p.IsBot && !p.HasLeft {
+			candidates = append(candidates, p)
+		}
+	}
+	if len(candidates) == 0 {
+		return
+	}
+
+	// Turn order starting after the old host's seat, connected players first
+	seats := max(g.MaxPlayers, len(g.Players))
+	rank := func(p *Player) int {
+		r := (p.Order - after - 1 + seats) % seats
+		if !p.DisconnectedAt.IsZero() {
+			r += seats
+		}
+		return r
+	}
+	sort.Slice(candidates, func(i, j int) bool { return rank(candidates[i]) < rank(candidates[j]) })
+	g.setHostLocked(candidates[0].ID, "host_left")
+}
+
+// setHostLocked makes a player the host and records the change (caller must hold lock)
+func (g *Game) setHostLocked(newHostID, reason string) {
+	previous := g.HostID
+	if old, exists := g.Players[previous]; exists {
+		old.IsHost = false
+	}
+	g.Players[newHostID].IsHost = true
+	g.HostID = newHostID
+	g.hostChanges = append(g.hostChanges, HostChange{HostID: newHostID, PreviousHostID: previous, Reason: reason})
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTransferHost(t *testing.T) {
+	game := newPauseTestGame(t)
+
+	if err := game.TransferHost("p2", "p3"); err != ErrNotHost {
+		t.Errorf("Expected ErrNotHost, got %v", err)
+	}
+	for _, id := range []string{"p1", "stranger"} {
+		if err := game.TransferHost("p1", id); err != ErrInvalidNewHost {
+			t.Errorf("Expected ErrInvalidNewHost for %s, got %v", id, err)
+		}
+	}
+	if err := game.TransferHost("p1", "p3"); err != nil {
+		t.Fatalf("Failed to transfer host: %v", err)
+	}
+	if game.HostID != "p3" || !game.Players["p3"].IsHost || game.Players["p1"].IsHost {
+		t.Errorf("Expected p3 the only host, got %s", game.HostID)
+	}
+	changes := game.ConsumeHostChanges()
+	if len(changes) != 1 || changes[0] != (HostChange{HostID: "p3", PreviousHostID: "p1", Reason: "transferred"}) {
+		t.Errorf("Unexpected host changes %+v", changes)
+	}
+	if err := game.RequestPause("p3", 0); err != nil {
+		t.Errorf("Expected the new host to be able to pause, got %v", err)
+	}
+}
+
+func TestHostMigration(t *testing.T) {
+	game := newPauseTestGame(t)
+	next := func(order int) string {
+		for id, p := range game.Players {
+			if p.Order == order%3 {
+				return id
+			}
+		}
+		return ""
+	}
+	host := game.Players["p1"]
+	first, second := next(host.Order+1), next(host.Order+2)
+
+	// The next player in turn order takes over, unless they're disconnected
+	game.SetConnected(first, false)
+	if err := game.LeaveGame("p1"); err != nil {
+		t.Fatalf("Failed to leave: %v", err)
+	}
+	if game.HostID != second || !game.Players[second].IsHost || host.IsHost {
+		t.Fatalf("Expected %s to host, got %s", second, game.HostID)
+	}
+	changes := game.ConsumeHostChanges()
+	if len(changes) != 1 || changes[0].PreviousHostID != "p1" || changes[0].Reason != "host_left" {
+		t.Errorf("Unexpected host changes %+v", changes)
+	}
+
+	// A bot taking over the seat can't host either
+	game.SetDeparturePolicy(second, DepartureBot)
+	game.LeaveGame(second)
+	if game.HostID != first || !game.Players[first].IsHost {
+		t.Errorf("Expected %s to host after the bot took %s's seat, got %s", first, second, game.HostID)
+	}
+	if changes := game.ConsumeHostChanges(); len(changes) != 1 || changes[0].PreviousHostID != second {
+		t.Errorf("Unexpected host changes %+v", changes)
+	}
+}
+
+func TestHostMigrationInLobby(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "p1", "Player 1", 4)
+	gm.AddBot(context.Background(), game.Code, "p1", BotOptions{})
+	gm.JoinGame(context.Background(), game.Code, "p2", "Player 2", "")
+
+	game.LeaveGame("p1")
+	if game.HostID != "p2" || !game.Players["p2"].IsHost {
+		t.Errorf("Expected the human p2 to host rather than the bot, got %s", game.HostID)
+	}
+}
@@ -0,0 +1,216 @@
+package models
+
+import (
+	"context"
+	crypto_rand "crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// InviteTTL is how long a game invite can be accepted
+const InviteTTL = 10 * time.Minute
+
+// MaxPendingInvites caps the invites waiting for one player; the oldest are dropped
+const MaxPendingInvites = 20
+
+var (
+	ErrInviteNotFound = errors.New("invite not found or expired")
+	ErrNotFriends     = errors.New("can only invite players on your friend list")
+)
+
+// Invite asks a friend to join a game waiting for players
+type Invite struct {
+	ID        string    `json:"id"`
+	GameCode  string    `json:"game_code"`
+	FromID    string    `json:"from_id"`
+	FromName  string    `json:"from_name"` // As seated in the game
+	FromHost  bool      `json:"from_host"` // Sent by the host, so accepting skips the game's password
+	ToID      string    `json:"to_id"`
+	CreatedAt Timestamp `json:"created_at"`
+	ExpiresAt Timestamp `json:"expires_at"`
+}
+
+// InviteStore keeps pending game invites in memory until they are accepted,
+// declined or expire
+type InviteStore struct {
+	invites map[string]*Invite // Invite ID -> invite
+	mu      sync.Mutex
+}
+
+// NewInviteStore creates an empty invite store
+func NewInviteStore() *InviteStore {
+	return &InviteStore{
+		invites: make(map[string]*Invite),
+	}
+}
+
+// Invites returns the game invite store
+func (gm *GameManager) Invites() *InviteStore {
+	return gm.invites
+}
+
+// InvitePlayer invites a friend of a seated player to their game while it waits
+// for players. Only the host's invites let the friend in without the password.
+func (gm *GameManager) InvitePlayer(ctx context.Context, code, fromID, toID string) (Invite, error) {
+	if err := ValidatePlayerID(toID); err != nil {
+		return Invite{}, err
+	}
+	if !gm.friends.IsFriend(fromID, toID) {
+		return Invite{}, ErrNotFriends
+	}
+	game, err := gm.GetGame(ctx, code)
+	if err != nil {
+		return Invite{}, err
+	}
+
+	game.mu.RLock()
+	inviter, seated := game.Players[fromID]
+	_, invitedSeated := game.Players[toID]
+	state, full := game.State, len(game.Players) >= game.MaxPlayers
+	var fromName string
+	if seated {
+		fromName = inviter.Name
+	}
+	fromHost := fromID == game.HostID
+	game.mu.RUnlock()
+
+	switch {
+	case !seated:
+		return Invite{}, ErrPlayerNotFound
+	case state != Waiting:
+		return Invite{}, ErrGameStarted
+	case invitedSeated:
+		return Invite{}, ErrPlayerExists
+	case full:
+		return Invite{}, ErrGameFull
+	}
+
+	now := time.Now()
+	invite := Invite{
+		ID:        newInviteID(),
+		GameCode:  game.Code,
+		FromID:    fromID,
+		FromName:  fromName,
+		FromHost:  fromHost,
+		ToID:      toID,
+		CreatedAt: At(now),
+		ExpiresAt: At(now.Add(InviteTTL)),
+	}
+	gm.invites.add(invite)
+	return invite, nil
+}
+
+// AcceptInvite joins the game an invite is for, without the lobby. The game's
+// password is needed unless the host sent the invite and is still the host. As
+// when joining, the name defaults to the player's profile name.
+func (gm *GameManager) AcceptInvite(ctx context.Context, inviteID, playerID, playerName, password string) (*Game, Invite, error) {
+	invite, err := gm.invites.get(inviteID, playerID)
+	if err != nil {
+		return nil, Invite{}, err
+	}
+
+	var invitedBy string
+	if invite.FromHost {
+		invitedBy = invite.FromID
+	}
+	game, err := gm.joinGame(ctx, invite.GameCode, playerID, playerName, password, "", invitedBy)
+	if err != nil {
+		return nil, invite, err
+	}
+	gm.invites.remove(inviteID)
+	return game, invite, nil
+}
+
+// Pending returns the unexpired invites waiting for a player, newest first
+func (s *InviteStore) Pending(playerID string) []Invite {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneLocked()
+	invites := []Invite{}
+	for _, invite := range s.invites {
+		if invite.ToID == playerID {
+			invites = append(invites, *invite)
+		}
+	}
+	sortInvites(invites)
+	return invites
+}
+
+// Decline drops an invite sent to playerID and returns it, so the sender can be told
+func (s *InviteStore) Decline(inviteID, playerID string) (Invite, error) {
+	invite, err := s.get(inviteID, playerID)
+	if err != nil {
+		return Invite{}, err
+	}
+	s.remove(inviteID)
+	return invite, nil
+}
+
+// add keeps an invite, dropping the recipient's oldest past MaxPendingInvites
+func (s *InviteStore) add(invite Invite) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneLocked()
+	s.invites[invite.ID] = &invite
+
+	var pending []Invite
+	for _, other := range s.invites {
+		if other.ToID == invite.ToID {
+			pending = append(pending, *other)
+		}
+	}
+	sortInvites(pending)
+	for _, old := range pending[min(len(pending), MaxPendingInvites):] {
+		delete(s.invites, old.ID)
+	}
+}
+
+// get returns an unexpired invite sent to playerID
+func (s *InviteStore) get(inviteID, playerID string) (Invite, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	invite, exists := s.invites[inviteID]
+	if !exists || invite.ToID != playerID || time.Now().After(invite.ExpiresAt.Time) {
+		return Invite{}, ErrInviteNotFound
+	}
+	return *invite, nil
+}
+
+func (s *InviteStore) remove(inviteID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.invites, inviteID)
+}
+
+// pruneLocked drops expired invites (caller must hold lock)
+func (s *InviteStore) pruneLocked() {
+	now := time.Now()
+	for id, invite := range s.invites {
+		if now.After(invite.ExpiresAt.Time) {
+			delete(s.invites, id)
+		}
+	}
+}
+
+// sortInvites orders invites newest first
+func sortInvites(invites []Invite) {
+	sort.Slice(invites, func(i, j int) bool {
+		if !invites[i].CreatedAt.Equal(invites[j].CreatedAt.Time) {
+			return invites[i].CreatedAt.After(invites[j].CreatedAt.Time)
+		}
+		return invites[i].ID < invites[j].ID
+	})
+}
+
+// newInviteID returns a random, unguessable invite ID
+func newInviteID() string {
+	var b [8]byte
+	crypto_rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
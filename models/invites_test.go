@@ -0,0 +1,118 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// befriend makes two players friends
+func befriend(gm *GameManager, a, b string) {
+	gm.Friends().Add(a, b)
+	gm.Friends().Add(b, a)
+}
+
+func TestInvites(t *testing.T) {
+	ctx := context.Background()
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(ctx, "host1", "Host", 4)
+	game.SetPassword("host1", "secret")
+
+	if _, err := gm.InvitePlayer(ctx, game.Code, "host1", "friend1"); err != ErrNotFriends {
+		t.Errorf("Expected ErrNotFriends, got %v", err)
+	}
+	gm.Friends().Add("host1", "friend1")
+	if _, err := gm.InvitePlayer(ctx, game.Code, "host1", "friend1"); err != ErrNotFriends {
+		t.Errorf("Expected a friend request not to allow invites, got %v", err)
+	}
+	befriend(gm, "host1", "friend1")
+	befriend(gm, "stranger", "friend1")
+	if _, err := gm.InvitePlayer(ctx, game.Code, "stranger", "friend1"); err != ErrPlayerNotFound {
+		t.Errorf("Expected only seated players to invite, got %v", err)
+	}
+
+	invite, err := gm.InvitePlayer(ctx, game.Code, "host1", "friend1")
+	if err != nil {
+		t.Fatalf("Invite failed: %v", err)
+	}
+	if invite.FromName != "Host" || invite.GameCode != game.Code || !invite.FromHost {
+		t.Errorf("Unexpected invite %+v", invite)
+	}
+	if pending := gm.Invites().Pending("friend1"); len(pending) != 1 || pending[0].ID != invite.ID {
+		t.Errorf("Expected the invite pending, got %+v", pending)
+	}
+
+	if _, _, err := gm.AcceptInvite(ctx, invite.ID, "someone-else", "Eve", ""); err != ErrInviteNotFound {
+		t.Errorf("Expected only the invitee to accept, got %v", err)
+	}
+	gm.Profiles().SetDisplayName("friend1", "Fatima")
+	joined, _, err := gm.AcceptInvite(ctx, invite.ID, "friend1", "", "")
+	if err != nil {
+		t.Fatalf("Accept failed: %v", err)
+	}
+	if player := joined.Players["friend1"]; player == nil || player.Name != "Fatima" {
+		t.Errorf("Expected friend1 seated without the password under their profile name, got %+v", player)
+	}
+	if pending := gm.Invites().Pending("friend1"); len(pending) != 0 {
+		t.Errorf("Expected the invite used up, got %+v", pending)
+	}
+}
+
+func TestInviteDeclineAndExpiry(t *testing.T) {
+	ctx := context.Background()
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(ctx, "host1", "Host", 4)
+	befriend(gm, "host1", "friend1")
+
+	invite, _ := gm.InvitePlayer(ctx, game.Code, "host1", "friend1")
+	if declined, err := gm.Invites().Decline(invite.ID, "friend1"); err != nil || declined.FromID != "host1" {
+		t.Errorf("Expected the invite declined, got %+v, %v", declined, err)
+	}
+	if _, _, err := gm.AcceptInvite(ctx, invite.ID, "friend1", "Fatima", ""); err != ErrInviteNotFound {
+		t.Errorf("Expected a declined invite gone, got %v", err)
+	}
+
+	invite, _ = gm.InvitePlayer(ctx, game.Code, "host1", "friend1")
+	gm.invites.invites[invite.ID].ExpiresAt = At(time.Now().Add(-time.Second))
+	if pending := gm.Invites().Pending("friend1"); len(pending) != 0 {
+		t.Errorf("Expected the expired invite dropped, got %+v", pending)
+	}
+
+	for i := 0; i < MaxPendingInvites+5; i++ {
+		gm.InvitePlayer(ctx, game.Code, "host1", "friend1")
+	}
+	if pending := gm.Invites().Pending("friend1"); len(pending) != MaxPendingInvites {
+		t.Errorf("Expected %d invites kept, got %d", MaxPendingInvites, len(pending))
+	}
+}
+
+func TestPlayerInviteNeedsPassword(t *testing.T) {
+	ctx := context.Background()
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(ctx, "host1", "Host", 4)
+	game.SetPassword("host1", "secret")
+	gm.JoinGame(ctx, game.Code, "p2", "Player 2", "secret")
+	befriend(gm, "p2", "friend1")
+
+	invite, err := gm.InvitePlayer(ctx, game.Code, "p2", "friend1")
+	if err != nil {
+		t.Fatalf("Invite failed: %v", err)
+	}
+	if invite.FromHost {
+		t.Error("Expected the invite marked as not from the host")
+	}
+	if _, _, err := gm.AcceptInvite(ctx, invite.ID, "friend1", "Fatima", ""); err != ErrWrongPassword {
+		t.Errorf("Expected ErrWrongPassword, got %v", err)
+	}
+	if _, _, err := gm.AcceptInvite(ctx, invite.ID, "friend1", "Fatima", "secret"); err != nil {
+		t.Errorf("Expected the password to let friend1 in, got %v", err)
+	}
+
+	// An invite from a host who has since handed over the game needs it too
+	befriend(gm, "host1", "friend2")
+	invite, _ = gm.InvitePlayer(ctx, game.Code, "host1", "friend2")
+	game.TransferHost("host1", "p2")
+	if _, _, err := gm.AcceptInvite(ctx, invite.ID, "friend2", "Farid", ""); err != ErrWrongPassword {
+		t.Errorf("Expected ErrWrongPassword after the host changed, got %v", err)
+	}
+}
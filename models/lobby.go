@@ -0,0 +1,81 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// LobbyGame is a public game as shown in the lobby's game browser
+type LobbyGame struct {
+	Code               string    `json:"code"`
+	HostName           string    `json:"host_name"`
+	Players            int       `json:"players"`
+	MaxPlayers         int       `json:"max_players"`
+	HasPassword        bool      `json:"has_password"` // Joining needs the game's password
+	Ranked             bool      `json:"ranked"`
+	Rating             int       `json:"rating"`               // Average rating of the seated players
+	TurnTimeoutSeconds int       `json:"turn_timeout_seconds"` // 0 for untimed turns
+	CreatedAt          Timestamp `json:"created_at"`
+}
+
+// SetPublic lists or unlists the game in the lobby's game browser (host only)
+func (g *Game) SetPublic(hostID string, public bool) error {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.HostID != hostID {
+		return ErrNotHost
+	}
+	g.Public = public
+	g.LastActivity = Now()
+	return nil
+}
+
+// LobbyListing returns the game's lobby entry. Only public games still waiting
+// for players are listed.
+func (g *Game) LobbyListing() (LobbyGame, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.Public || g.State != Waiting || !g.DeletedAt.IsZero() {
+		return LobbyGame{}, false
+	}
+
+	listing := LobbyGame{
+		Code:               g.Code,
+		Players:            len(g.Players),
+		MaxPlayers:         g.MaxPlayers,
+		HasPassword:        g.passwordHash != nil,
+		Ranked:             g.Ranked,
+		CreatedAt:          g.CreatedAt,
+		TurnTimeoutSeconds: int(g.TurnTimeout / time.Second),
+	}
+	var total, rated int
+	for _, player := range g.Players {
+		if !player.IsBot {
+			total += player.Rating
+			rated++
+		}
+	}
+	if rated > 0 {
+		listing.Rating = total / rated
+	}
+	if host, ok := g.Players[g.HostID]; ok {
+		listing.HostName = host.Name
+	}
+	return listing, true
+}
+
+// ListPublicGames returns the lobby entry of every listed game, oldest first
+func (gm *GameManager) ListPublicGames() []LobbyGame {
+	games := []LobbyGame{}
+	for _, game := range gm.GetAllGames() {
+		if listing, ok := game.LobbyListing(); ok {
+			games = append(games, listing)
+		}
+	}
+	sort.Slice(games, func(i, j int) bool {
+		return games[i].CreatedAt.Before(games[j].CreatedAt.Time)
+	})
+	return games
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"context"
+	"testing"
+)
+
+func TestListPublicGames(t *testing.T) {
+	gm := NewGameManager()
+	public, _ := gm.CreateGame(context.Background(), "host1", "Host", 2)
+	gm.CreateGame(context.Background(), "host2", "Private", 2)
+
+	if games := gm.ListPublicGames(); len(games) != 0 {
+		t.Fatalf("Expected no public games, got %d", len(games))
+	}
+
+	if err := public.SetPublic("player1", true); err != ErrNotHost {
+		t.Errorf("Expected ErrNotHost, got %v", err)
+	}
+	if err := public.SetPublic("host1", true); err != nil {
+		t.Fatalf("Failed to make game public: %v", err)
+	}
+
+	games := gm.ListPublicGames()
+	if len(games) != 1 || games[0].Code != public.Code || games[0].HostName != "Host" || games[0].Players != 1 {
+		t.Fatalf("Expected the public game in the lobby, got %+v", games)
+	}
+
+	// Started games leave the lobby
+	gm.JoinGame(context.Background(), public.Code, "player2", "Bob", "")
+	public.SetPlayerReady("host1", true)
+	public.SetPlayerReady("player2", true)
+	if err := public.StartGame("host1"); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+	if games := gm.ListPublicGames(); len(games) != 0 {
+		t.Errorf("Expected started game to be unlisted, got %+v", games)
+	}
+}
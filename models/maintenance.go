@@ -0,0 +1,91 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// MaintenancePausedBy marks games paused by maintenance rather than by a player
+const MaintenancePausedBy = "maintenance"
+
+var (
+	ErrMaintenance      = errors.New("server is in maintenance mode; new games and joins are disabled")
+	ErrMaintenancePause = errors.New("game is paused for server maintenance")
+)
+
+// Maintenance describes a planned maintenance window
+type Maintenance struct {
+	Enabled    bool      `json:"enabled"`
+	Message    string    `json:"message,omitempty"`
+	StartedAt  Timestamp `json:"started_at,omitempty"`
+	Deadline   Timestamp `json:"deadline,omitempty"`    // When games stop; zero if open-ended
+	PauseGames bool      `json:"pause_games,omitempty"` // Pause games in progress at the deadline
+}
+
+// StartMaintenance blocks new games and joins until EndMaintenance is called
+func (gm *GameManager) StartMaintenance(message string, deadline time.Time, pauseGames bool) Maintenance {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	gm.maintenance = Maintenance{
+		Enabled:    true,
+		Message:    message,
+		StartedAt:  Now(),
+		Deadline:   At(deadline),
+		PauseGames: pauseGames,
+	}
+	return gm.maintenance
+}
+
+// EndMaintenance reopens the server and resumes games paused for maintenance.
+// Returns the codes of the resumed games.
+func (gm *GameManager) EndMaintenance() []string {
+	gm.mu.Lock()
+	gm.maintenance = Maintenance{}
+	gm.mu.Unlock()
+
+	var resumed []string
+	for _, game := range gm.GetAllGames() {
+		if game.resumeFromMaintenance() {
+			resumed = append(resumed, game.Code)
+		}
+	}
+	return resumed
+}
+
+// MaintenanceStatus returns the current maintenance window, if any
+func (gm *GameManager) MaintenanceStatus() Maintenance {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	return gm.maintenance
+}
+
+// PauseAllForMaintenance pauses every game in progress and returns their codes
+func (gm *GameManager) PauseAllForMaintenance() []string {
+	var paused []string
+	for _, game := range gm.GetAllGames() {
+		if err := game.PauseGame(MaintenancePausedBy); err == nil {
+			paused = append(paused, game.Code)
+		}
+	}
+	return paused
+}
+
+// inMaintenance reports whether new games and joins are blocked
+func (gm *GameManager) inMaintenance() bool {
+	gm.mu.RLock()
+	defer gm.mu.RUnlock()
+	return gm.maintenance.Enabled
+}
+
+// resumeFromMaintenance resumes the game if maintenance paused it
+func (g *Game) resumeFromMaintenance() bool {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.State != Paused || g.PausedBy != MaintenancePausedBy {
+		return false
+	}
+	g.resumeLocked()
+	return true
+}
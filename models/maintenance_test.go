@@ -0,0 +1,47 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMaintenanceMode(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+	gm.JoinGame(context.Background(), game.Code, "player2", "Player 2", "")
+	waiting, _ := gm.CreateGame(context.Background(), "host2", "Host 2", 4)
+	game.SetPlayerReady("host1", true)
+	game.SetPlayerReady("player2", true)
+	if err := game.StartGame("host1"); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+
+	status := gm.StartMaintenance("Upgrading", time.Now().Add(time.Minute), true)
+	if !status.Enabled || !gm.MaintenanceStatus().Enabled {
+		t.Fatal("Expected maintenance to be enabled")
+	}
+
+	if _, err := gm.CreateGame(context.Background(), "host3", "Host 3", 4); err != ErrMaintenance {
+		t.Errorf("Expected ErrMaintenance on create, got %v", err)
+	}
+	if _, err := gm.JoinGame(context.Background(), waiting.Code, "player3", "Player 3", ""); err != ErrMaintenance {
+		t.Errorf("Expected ErrMaintenance on join, got %v", err)
+	}
+
+	paused := gm.PauseAllForMaintenance()
+	if len(paused) != 1 || paused[0] != game.Code {
+		t.Fatalf("Expected only the playing game to pause, got %v", paused)
+	}
+	if err := game.ResumeGame("host1", nil); err != ErrMaintenancePause {
+		t.Errorf("Players shouldn't resume a maintenance pause, got %v", err)
+	}
+
+	resumed := gm.EndMaintenance()
+	if len(resumed) != 1 || game.State != Playing {
+		t.Errorf("Expected the game to resume after maintenance, got %v (state %s)", resumed, game.State)
+	}
+	if _, err := gm.JoinGame(context.Background(), waiting.Code, "player3", "Player 3", ""); err != nil {
+		t.Errorf("Joins should work after maintenance, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Seats a game can have
+const (
+	MinPlayers = 2
+	MaxPlayers = 6
+)
+
+var (
+	ErrTooManyGames         = errors.New("server has reached its game limit; try again later")
+	ErrInvalidManagerConfig = errors.New("invalid game manager config")
+)
+
+// ManagerConfig sets how long games live and how many the server holds
+type ManagerConfig struct {
+	TurnTimeout   time.Duration // Time allowed per turn in new games
+	GameTTL       time.Duration // Longest a game lives, from creation or its last restore
+	InactivityTTL time.Duration // How long a waiting or ended game may sit idle
+	RestoreWindow time.Duration // How long a cleaned-up game can still be restored
+	MaxGames      int           // Games held at once, cleaned-up ones included (0 = no limit)
+	MaxPlayers    int           // Most seats a new game may have
+}
+
+// DefaultManagerConfig returns the default lifetimes and limits
+func DefaultManagerConfig() ManagerConfig {
+	return ManagerConfig{
+		TurnTimeout:   DefaultTurnTimeout,
+		GameTTL:       DefaultGameTTL,
+		InactivityTTL: DefaultInactivityTTL,
+		RestoreWindow: DefaultRestoreWindow,
+		MaxPlayers:    MaxPlayers,
+	}
+}
+
+// SetConfig sets game lifetimes and limits. Zero fields keep their defaults.
+// Call before the server starts.
+func (gm *GameManager) SetConfig(cfg ManagerConfig) error {
+	defaults := DefaultManagerConfig()
+	if cfg.TurnTimeout == 0 {
+		cfg.TurnTimeout = defaults.TurnTimeout
+	}
+	if cfg.GameTTL == 0 {
+		cfg.GameTTL = defaults.GameTTL
+	}
+	if cfg.InactivityTTL == 0 {
+		cfg.InactivityTTL = defaults.InactivityTTL
+	}
+	if cfg.RestoreWindow == 0 {
+		cfg.RestoreWindow = defaults.RestoreWindow
+	}
+	if cfg.MaxPlayers == 0 {
+		cfg.MaxPlayers = defaults.MaxPlayers
+	}
+
+	if !validTurnTimeout(cfg.TurnTimeout) {
+		return ErrInvalidTurnTimeout
+	}
+	if cfg.GameTTL < 0 || cfg.InactivityTTL < 0 || cfg.RestoreWindow < 0 {
+		return fmt.Errorf("%w: lifetimes must not be negative", ErrInvalidManagerConfig)
+	}
+	if cfg.MaxGames < 0 {
+		return fmt.Errorf("%w: max games must not be negative", ErrInvalidManagerConfig)
+	}
+	if cfg.MaxPlayers < MinPlayers || cfg.MaxPlayers > MaxPlayers {
+		return fmt.Errorf("%w: max players must be between %d and %d", ErrInvalidManagerConfig, MinPlayers, MaxPlayers)
+	}
+
+	gm.mu.Lock()
+	gm.config = cfg
+	gm.mu.Unlock()
+	return nil
+}
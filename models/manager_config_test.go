@@ -0,0 +1,47 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestManagerConfig(t *testing.T) {
+	gm := NewGameManager()
+	if err := gm.SetConfig(ManagerConfig{MaxPlayers: 7}); !errors.Is(err, ErrInvalidManagerConfig) {
+		t.Errorf("Expected ErrInvalidManagerConfig, got %v", err)
+	}
+	if err := gm.SetConfig(ManagerConfig{TurnTimeout: time.Second}); err != ErrInvalidTurnTimeout {
+		t.Errorf("Expected ErrInvalidTurnTimeout, got %v", err)
+	}
+	if err := gm.SetConfig(ManagerConfig{TurnTimeout: 30 * time.Second, MaxGames: 1, MaxPlayers: 3}); err != nil {
+		t.Fatalf("Failed to set config: %v", err)
+	}
+
+	game, err := gm.CreateGame(context.Background(), "host1", "Host", 4)
+	if err != nil {
+		t.Fatalf("Failed to create game: %v", err)
+	}
+	if game.MaxPlayers != 3 {
+		t.Errorf("Expected seats capped at 3, got %d", game.MaxPlayers)
+	}
+	if game.TurnTimeout != 30*time.Second || game.Rules.TurnTimeoutSeconds != 30 {
+		t.Errorf("Expected 30s turns, got %v (rules %ds)", game.TurnTimeout, game.Rules.TurnTimeoutSeconds)
+	}
+
+	if _, err := gm.CreateGame(context.Background(), "host2", "Host", 4); err != ErrTooManyGames {
+		t.Errorf("Expected ErrTooManyGames, got %v", err)
+	}
+}
+
+func TestManagerConfigInactivityTTL(t *testing.T) {
+	gm := NewGameManager()
+	gm.SetConfig(ManagerConfig{InactivityTTL: time.Minute})
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 2)
+
+	game.LastActivity = At(time.Now().Add(-2 * time.Minute))
+	if removed := gm.CleanupAbandonedGames(); len(removed) != 1 || removed[0] != game.Code {
+		t.Errorf("Expected the idle game to be cleaned up, got %v", removed)
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// maxPendingTurns caps the turn durations a game keeps between metric samples
+const maxPendingTurns = 256
+
+// TurnTimings describes how a game's turns are progressing
+type TurnTimings struct {
+	State     GameState       // Current game state
+	Completed []time.Duration // Turns finished since the last call, excluding paused time
+	Current   time.Duration   // How long the current turn has been running; 0 when not playing
+}
+
+// ConsumeTurnTimings returns turns completed since the last call along with the running turn
+func (g *Game) ConsumeTurnTimings() TurnTimings {
+	g.mu.Lock()
+	defer g.unlock()
+
+	timings := TurnTimings{
+		State:     g.State,
+		Completed: g.turnDurations,
+	}
+	g.turnDurations = nil
+	if g.State == Playing && !g.TurnStartTime.IsZero() {
+		timings.Current = time.Since(g.TurnStartTime.Time)
+	}
+	return timings
+}
+
+// IsPlayer reports whether the ID belongs to a seated player
+func (g *Game) IsPlayer(playerID string) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	_, ok := g.Players[playerID]
+	return ok
+}
+
+// recordTurnLocked notes how long the current turn took (caller must hold lock)
+func (g *Game) recordTurnLocked() {
+	if g.TurnStartTime.IsZero() {
+		return
+	}
+	if len(g.turnDurations) >= maxPendingTurns {
+		g.turnDurations = g.turnDurations[1:]
+	}
+	g.turnDurations = append(g.turnDurations, time.Since(g.TurnStartTime.Time))
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestConsumeTurnTimings(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+	gm.JoinGame(context.Background(), game.Code, "player2", "Player 2", "")
+	game.SetPlayerReady("host1", true)
+	game.SetPlayerReady("player2", true)
+	if err := game.StartGame("host1"); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+
+	if !game.IsPlayer("player2") || game.IsPlayer("spectator") {
+		t.Error("IsPlayer should only match seated players")
+	}
+
+	timings := game.ConsumeTurnTimings()
+	if timings.State != Playing || len(timings.Completed) != 0 {
+		t.Fatalf("Expected no completed turns yet, got %+v", timings)
+	}
+
+	game.TurnStartTime = At(time.Now().Add(-2 * game.TurnTimeout)) // Let ForceSkipTurn pass the turn straight away
+	if skipped := game.ForceSkipTurn(); skipped == "" {
+		t.Fatal("Expected the current turn to be skipped")
+	}
+	timings = game.ConsumeTurnTimings()
+	if len(timings.Completed) != 1 {
+		t.Fatalf("Expected one completed turn, got %d", len(timings.Completed))
+	}
+	if again := game.ConsumeTurnTimings(); len(again.Completed) != 0 {
+		t.Errorf("Completed turns should only be returned once, got %d", len(again.Completed))
+	}
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// MaxChatMute is the longest a host can mute someone in chat
+const MaxChatMute = 24 * time.Hour
+
+var (
+	ErrChatMuted           = errors.New("you are muted in this game's chat")
+	ErrInvalidMute         = errors.New("mute must be between 0 and 1440 minutes")
+	ErrCannotMuteHost      = errors.New("the host can't mute themselves")
+	ErrChatMessageNotFound = errors.New("chat message not found")
+)
+
+// ChatFilter masks blocked words in chat messages. Words match whole and
+// ignoring case, so blocking "ass" doesn't touch "class".
+type ChatFilter struct {
+	words map[string]bool
+}
+
+// NewChatFilter creates a filter for the given words; nil if there are none
+func NewChatFilter(words []string) *ChatFilter {
+	f := &ChatFilter{words: make(map[string]bool)}
+	for _, word := range words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			f.words[word] = true
+		}
+	}
+	if len(f.words) == 0 {
+		return nil
+	}
+	return f
+}
+
+// Clean returns the message with every blocked word replaced by asterisks
+func (f *ChatFilter) Clean(message string) string {
+	if f == nil {
+		return message
+	}
+	runes := []rune(message)
+	start := -1
+	for i := 0; i <= len(runes); i++ {
+		if i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 && f.words[strings.ToLower(string(runes[start:i]))] {
+			for j := start; j < i; j++ {
+				runes[j] = '*'
+			}
+		}
+		start = -1
+	}
+	return string(runes)
+}
+
+// SetChatFilter masks blocked words in every game's chat from now on; nil turns
+// filtering off
+func (gm *GameManager) SetChatFilter(f *ChatFilter) {
+	gm.mu.Lock()
+	gm.chatFilter = f
+	games := make([]*Game, 0, len(gm.games))
+	for _, game := range gm.games {
+		games = append(games, game)
+	}
+	gm.mu.Unlock()
+
+	for _, game := range games {
+		game.mu.Lock()
+		game.chatFilter = f
+		game.mu.Unlock()
+	}
+}
+
+// MuteChat stops a player or spectator sending chat for the given time; zero
+// unmutes them (host only). Returns when the mute ends.
+func (g *Game) MuteChat(hostID, targetID string, d time.Duration) (Timestamp, error) {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.HostID != hostID {
+		return Timestamp{}, ErrNotHost
+	}
+	if !g.isParticipantLocked(targetID) {
+		return Timestamp{}, ErrPlayerNotFound
+	}
+	if targetID == hostID {
+		return Timestamp{}, ErrCannotMuteHost
+	}
+	if d < 0 || d > MaxChatMute {
+		return Timestamp{}, ErrInvalidMute
+	}
+
+	if d == 0 {
+		delete(g.chatMutes, targetID)
+		return Timestamp{}, nil
+	}
+	until := time.Now().Add(d)
+	if g.chatMutes == nil {
+		g.chatMutes = make(map[string]time.Time)
+	}
+	g.chatMutes[targetID] = until
+	g.LastActivity = Now()
+	return At(until), nil
+}
+
+// mutedLocked reports whether a participant is muted in chat (caller must hold lock)
+func (g *Game) mutedLocked(playerID string) bool {
+	return time.Now().Before(g.chatMutes[playerID])
+}
+
+// chatMutesLocked returns when each muted participant can chat again (caller must hold lock)
+func (g *Game) chatMutesLocked() map[string]Timestamp {
+	mutes := make(map[string]Timestamp, len(g.chatMutes))
+	for id, until := range g.chatMutes {
+		if time.Now().Before(until) {
+			mutes[id] = At(until)
+		}
+	}
+	return mutes
+}
+
+// DeleteChatMessage removes a message from the chat log by its ID (host only)
+func (g *Game) DeleteChatMessage(hostID string, id int) (ChatMessage, error) {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.HostID != hostID {
+		return ChatMessage{}, ErrNotHost
+	}
+	for i, msg := range g.ChatMessages {
+		if msg.ID == id {
+			// Copy rather than shift in place; earlier readers may still hold the old slice
+			g.ChatMessages = append(g.ChatMessages[:i:i], g.ChatMessages[i+1:]...)
+			g.LastActivity = Now()
+			return msg, nil
+		}
+	}
+	return ChatMessage{}, ErrChatMessageNotFound
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestChatFilter(t *testing.T) {
+	f := NewChatFilter([]string{" Darn ", "heck", ""})
+	if got := f.Clean("Darn it, what the HECK. Checked!"); got != "**** it, what the ****. Checked!" {
+		t.Errorf("Expected whole words masked, got %q", got)
+	}
+	if NewChatFilter(nil) != nil {
+		t.Error("Expected no filter without words")
+	}
+	var off *ChatFilter
+	if got := off.Clean("heck"); got != "heck" {
+		t.Errorf("Expected a nil filter to pass messages through, got %q", got)
+	}
+
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+	gm.SetChatFilter(f)
+	msg, _ := game.SendChatMessage("host1", "oh heck")
+	if msg.Message != "oh ****" {
+		t.Errorf("Expected the filter applied to existing games, got %q", msg.Message)
+	}
+}
+
+func TestMuteChat(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+	gm.JoinGame(context.Background(), game.Code, "player2", "Player 2", "")
+	gm.JoinAsSpectator(context.Background(), game.Code, "viewer", "Viewer", "")
+
+	if _, err := game.MuteChat("player2", "viewer", time.Minute); err != ErrNotHost {
+		t.Errorf("Expected ErrNotHost, got %v", err)
+	}
+	if _, err := game.MuteChat("host1", "host1", time.Minute); err != ErrCannotMuteHost {
+		t.Errorf("Expected ErrCannotMuteHost, got %v", err)
+	}
+	if _, err := game.MuteChat("host1", "player2", MaxChatMute+time.Minute); err != ErrInvalidMute {
+		t.Errorf("Expected ErrInvalidMute, got %v", err)
+	}
+
+	for _, id := range []string{"player2", "viewer"} {
+		until, err := game.MuteChat("host1", id, 5*time.Minute)
+		if err != nil || until.IsZero() {
+			t.Fatalf("Failed to mute %s: %v", id, err)
+		}
+		if _, err := game.SendChatMessage(id, "hello"); err != ErrChatMuted {
+			t.Errorf("Expected %s muted, got %v", id, err)
+		}
+	}
+	if mutes := game.GetGameState()["chat_muted_until"].(map[string]Timestamp); len(mutes) != 2 {
+		t.Errorf("Expected 2 mutes in the state, got %v", mutes)
+	}
+
+	// Unmuting, or the mute running out, lets them chat again
+	game.MuteChat("host1", "player2", 0)
+	if _, err := game.SendChatMessage("player2", "hello"); err != nil {
+		t.Errorf("Expected player2 unmuted, got %v", err)
+	}
+	game.chatMutes["viewer"] = time.Now().Add(-time.Second)
+	if _, err := game.SendChatMessage("viewer", "hello"); err != nil {
+		t.Errorf("Expected the mute to have run out, got %v", err)
+	}
+}
+
+func TestDeleteChatMessage(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+	gm.JoinGame(context.Background(), game.Code, "player2", "Player 2", "")
+	game.SendChatMessage("host1", "hello")
+	rude, _ := game.SendChatMessage("player2", "something rude")
+	game.SendChatMessage("host1", "please don't")
+	before := game.GetRecentChat(0)
+
+	if _, err := game.DeleteChatMessage("player2", rude.ID); err != ErrNotHost {
+		t.Errorf("Expected ErrNotHost, got %v", err)
+	}
+	deleted, err := game.DeleteChatMessage("host1", rude.ID)
+	if err != nil || deleted.PlayerID != "player2" {
+		t.Fatalf("Failed to delete the message: %+v, %v", deleted, err)
+	}
+	if chat := game.GetRecentChat(0); len(chat) != 2 || chat[1].Message != "please don't" {
+		t.Errorf("Expected the message removed from chat, got %+v", chat)
+	}
+	if before[1].ID != rude.ID {
+		t.Error("Expected earlier reads of the chat left untouched")
+	}
+	if _, err := game.DeleteChatMessage("host1", rude.ID); err != ErrChatMessageNotFound {
+		t.Errorf("Expected ErrChatMessageNotFound, got %v", err)
+	}
+}
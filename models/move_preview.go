@@ -0,0 +1,125 @@
+package models
+
+import "sort"
+
+// Square areas a piece can be in
+const (
+	AreaYard        = "yard"
+	AreaTrack       = "track"
+	AreaHomeStretch = "home_stretch"
+	AreaFinished    = "finished"
+)
+
+// Square is where a piece stands: its yard, a track square by index, a home
+// stretch square counted from 1, or finished
+type Square struct {
+	Area  string `json:"area"`
+	Index int    `json:"index"` // Track index or home stretch step; 0 in the yard and finished
+}
+
+// squareOf returns the square a piece stands on
+func squareOf(p Piece) Square {
+	switch {
+	case p.IsFinished:
+		return Square{Area: AreaFinished}
+	case p.IsHome:
+		return Square{Area: AreaYard}
+	case p.HomeStretchPosition > 0:
+		return Square{Area: AreaHomeStretch, Index: p.HomeStretchPosition}
+	default:
+		return Square{Area: AreaTrack, Index: p.Position}
+	}
+}
+
+// CapturedPiece identifies an opponent piece a move sends back to its yard
+type CapturedPiece struct {
+	PlayerID string `json:"player_id"`
+	PieceID  int    `json:"piece_id"`
+}
+
+// MovePreview is what moving a piece with the current roll would do
+type MovePreview struct {
+	PieceID           int             `json:"piece_id"`
+	From              Square          `json:"from"`
+	To                Square          `json:"to"`
+	LeavesYard        bool            `json:"leaves_yard"`
+	EntersHomeStretch bool            `json:"enters_home_stretch"`
+	Finishes          bool            `json:"finishes"`
+	LandsSafe         bool            `json:"lands_safe"`
+	WouldCapture      bool            `json:"would_capture"`
+	Captures          []CapturedPiece `json:"captures"`
+	ExtraTurn         bool            `json:"extra_turn"` // The player rolls again afterwards
+	Wins              bool            `json:"wins"`
+}
+
+// PreviewMove reports what moving a piece with the current roll would do,
+// without changing the game. An illegal move returns the error MovePiece would.
+func (g *Game) PreviewMove(playerID string, pieceID int) (MovePreview, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.previewMoveLocked(playerID, pieceID)
+}
+
+// previewMoveLocked plays a move on a copy of the game (caller must hold lock)
+func (g *Game) previewMoveLocked(playerID string, pieceID int) (MovePreview, error) {
+	state := g.engineStateLocked()
+	next, err := ApplyEvent(state, EngineEvent{Type: EventMove, PlayerID: playerID, PieceID: pieceID})
+	if err != nil {
+		return MovePreview{}, err
+	}
+
+	before := state.Players[playerID].Pieces[pieceID]
+	after := next.Players[playerID].Pieces[pieceID]
+	preview := MovePreview{
+		PieceID:           pieceID,
+		From:              squareOf(before),
+		To:                squareOf(after),
+		LeavesYard:        before.IsHome,
+		EntersHomeStretch: before.HomeStretchPosition == 0 && after.HomeStretchPosition > 0 && !after.IsFinished,
+		Finishes:          after.IsFinished,
+		LandsSafe:         after.IsSafe,
+		Captures:          []CapturedPiece{},
+		ExtraTurn:         next.State == Playing && next.CurrentTurn == playerID,
+		Wins:              next.Winner == playerID,
+	}
+
+	for id, opponent := range state.Players {
+		if id == playerID {
+			continue
+		}
+		for i, piece := range opponent.Pieces {
+			if !piece.IsHome && next.Players[id].Pieces[i].IsHome {
+				preview.Captures = append(preview.Captures, CapturedPiece{PlayerID: id, PieceID: i})
+			}
+		}
+	}
+	sort.Slice(preview.Captures, func(i, j int) bool {
+		ci, cj := preview.Captures[i], preview.Captures[j]
+		if ci.PlayerID != cj.PlayerID {
+			return ci.PlayerID < cj.PlayerID
+		}
+		return ci.PieceID < cj.PieceID
+	})
+	preview.WouldCapture = len(preview.Captures) > 0
+	return preview, nil
+}
+
+// GetValidMoveDetails previews every move the current roll allows a player
+func (g *Game) GetValidMoveDetails(playerID string) []MovePreview {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.validMoveDetailsLocked(playerID)
+}
+
+// validMoveDetailsLocked previews each valid move (caller must hold lock)
+func (g *Game) validMoveDetailsLocked(playerID string) []MovePreview {
+	moves := []MovePreview{}
+	for _, pieceID := range g.getValidMovesInternal(playerID) {
+		if preview, err := g.previewMoveLocked(playerID, pieceID); err == nil {
+			moves = append(moves, preview)
+		}
+	}
+	return moves
+}
@@ -0,0 +1,83 @@
+package models
+
+import "testing"
+
+func TestPreviewMove(t *testing.T) {
+	game := newEngineTestGame(t)
+	mover := game.CurrentTurn
+	opponent := "player2"
+	if mover == opponent {
+		opponent = "host1"
+	}
+
+	if _, err := game.PreviewMove(mover, 0); err != ErrMustRollFirst {
+		t.Errorf("Expected ErrMustRollFirst, got %v", err)
+	}
+
+	// Leaving the yard with a six
+	game.LastDiceRoll, game.HasRolled = 6, true
+	preview, err := game.PreviewMove(mover, 0)
+	if err != nil {
+		t.Fatalf("Failed to preview: %v", err)
+	}
+	start := GetStartPosition(game.Players[mover].Color, game.MaxPlayers)
+	if !preview.LeavesYard || preview.From.Area != AreaYard || preview.To != (Square{Area: AreaTrack, Index: start}) {
+		t.Errorf("Expected the piece to leave its yard for square %d, got %+v", start, preview)
+	}
+	if !preview.ExtraTurn || preview.WouldCapture {
+		t.Errorf("Expected an extra turn and no capture, got %+v", preview)
+	}
+	if !game.Players[mover].Pieces[0].IsHome {
+		t.Error("Previewing must not move the piece")
+	}
+
+	// Landing on an opponent off the safe squares
+	position := start + 1
+	for IsSafeZone(position+3, game.MaxPlayers) {
+		position++
+	}
+	game.Players[mover].Pieces[0].IsHome = false
+	game.Players[mover].Pieces[0].Position = position
+	game.Players[opponent].Pieces[2].IsHome = false
+	game.Players[opponent].Pieces[2].Position = position + 3
+	game.LastDiceRoll = 3
+
+	preview, err = game.PreviewMove(mover, 0)
+	if err != nil {
+		t.Fatalf("Failed to preview: %v", err)
+	}
+	if preview.To != (Square{Area: AreaTrack, Index: position + 3}) || !preview.WouldCapture {
+		t.Fatalf("Expected a capture on square %d, got %+v", position+3, preview)
+	}
+	if len(preview.Captures) != 1 || preview.Captures[0] != (CapturedPiece{PlayerID: opponent, PieceID: 2}) {
+		t.Errorf("Expected the opponent's piece 2 captured, got %+v", preview.Captures)
+	}
+	if game.Players[opponent].Pieces[2].IsHome {
+		t.Error("Previewing must not capture the piece")
+	}
+
+	if _, err := game.PreviewMove(opponent, 0); err != ErrNotPlayerTurn {
+		t.Errorf("Expected ErrNotPlayerTurn, got %v", err)
+	}
+}
+
+func TestGetValidMoveDetails(t *testing.T) {
+	game := newEngineTestGame(t)
+	mover := game.CurrentTurn
+
+	game.LastDiceRoll, game.HasRolled = 3, true
+	if moves := game.GetValidMoveDetails(mover); len(moves) != 0 {
+		t.Errorf("Expected no moves out of the yard with a 3, got %+v", moves)
+	}
+
+	game.LastDiceRoll = 6
+	moves := game.GetValidMoveDetails(mover)
+	if len(moves) != len(game.GetValidMoves(mover)) || len(moves) != PiecesPerPlayer {
+		t.Fatalf("Expected a preview for every valid move, got %+v", moves)
+	}
+	for i, move := range moves {
+		if move.PieceID != i || !move.LeavesYard || move.To.Area != AreaTrack {
+			t.Errorf("Expected piece %d to leave its yard, got %+v", i, move)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package models
+
+import "errors"
+
+// DefaultPalette is used by games and players that haven't picked one
+const DefaultPalette = "standard"
+
+var ErrUnknownPalette = errors.New("unknown palette")
+
+// PaletteSwatch is how one player color is drawn under a palette
+type PaletteSwatch struct {
+	Hex     string `json:"hex"`
+	Label   string `json:"label"`   // Name to show for the color, e.g. "Vermillion"
+	Pattern string `json:"pattern"` // Shape marked on pieces so color is never the only cue
+}
+
+// Palette maps every player color to a display color. Seat colors never change
+// with the palette; only how they are drawn does.
+type Palette struct {
+	ID             string                        `json:"id"`
+	Name           string                        `json:"name"`
+	ColorBlindSafe bool                          `json:"color_blind_safe"`
+	Colors         map[PlayerColor]PaletteSwatch `json:"colors"`
+}
+
+// colorPatterns gives each player color the same shape in every palette
+var colorPatterns = map[PlayerColor]string{
+	Red:    "circle",
+	Blue:   "square",
+	Green:  "triangle",
+	Yellow: "diamond",
+	Purple: "star",
+	Orange: "cross",
+	Olive:  "hexagon",
+	Indigo: "ring",
+}
+
+// Palettes is the built-in palette catalogue. Okabe-Ito and Paul Tol's muted set
+// stay distinguishable under the common forms of color blindness.
+var Palettes = []Palette{
+	newPalette(DefaultPalette, "Standard", false, map[PlayerColor][2]string{
+		Red:    {PlayerColorHex[Red], "Red"},
+		Blue:   {PlayerColorHex[Blue], "Blue"},
+		Green:  {PlayerColorHex[Green], "Green"},
+		Yellow: {PlayerColorHex[Yellow], "Yellow"},
+		Purple: {PlayerColorHex[Purple], "Purple"},
+		Orange: {PlayerColorHex[Orange], "Orange"},
+		Olive:  {PlayerColorHex[Olive], "Olive"},
+		Indigo: {PlayerColorHex[Indigo], "Indigo"},
+	}),
+	newPalette("okabe-ito", "Okabe-Ito", true, map[PlayerColor][2]string{
+		Red:    {"#D55E00", "Vermillion"},
+		Blue:   {"#0072B2", "Blue"},
+		Green:  {"#009E73", "Bluish green"},
+		Yellow: {"#F0E442", "Yellow"},
+		Purple: {"#CC79A7", "Reddish purple"},
+		Orange: {"#E69F00", "Orange"},
+		Olive:  {"#56B4E9", "Sky blue"},
+		Indigo: {"#000000", "Black"},
+	}),
+	newPalette("tol-muted", "Tol Muted", true, map[PlayerColor][2]string{
+		Red:    {"#CC6677", "Rose"},
+		Blue:   {"#332288", "Indigo"},
+		Green:  {"#117733", "Green"},
+		Yellow: {"#DDCC77", "Sand"},
+		Purple: {"#AA4499", "Purple"},
+		Orange: {"#882255", "Wine"},
+		Olive:  {"#999933", "Olive"},
+		Indigo: {"#88CCEE", "Cyan"},
+	}),
+}
+
+// newPalette builds a palette from hex/label pairs
+func newPalette(id, name string, colorBlindSafe bool, colors map[PlayerColor][2]string) Palette {
+	p := Palette{ID: id, Name: name, ColorBlindSafe: colorBlindSafe, Colors: make(map[PlayerColor]PaletteSwatch)}
+	for color, swatch := range colors {
+		p.Colors[color] = PaletteSwatch{Hex: swatch[0], Label: swatch[1], Pattern: colorPatterns[color]}
+	}
+	return p
+}
+
+// PaletteByID looks up a palette in the catalogue
+func PaletteByID(id string) (Palette, bool) {
+	for _, p := range Palettes {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return Palette{}, false
+}
+
+// SetPalette sets the palette the game is shown in by default (host only)
+func (g *Game) SetPalette(hostID, paletteID string) error {
+	if _, ok := PaletteByID(paletteID); !ok {
+		return ErrUnknownPalette
+	}
+
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.HostID != hostID {
+		return ErrNotHost
+	}
+	g.Palette = paletteID
+	g.LastActivity = Now()
+	return nil
+}
+
+// SetPlayerPalette sets the palette one player or spectator sees the game in,
+// overriding the game's. An empty ID goes back to the game's palette.
+func (g *Game) SetPlayerPalette(participantID, paletteID string) error {
+	if paletteID != "" {
+		if _, ok := PaletteByID(paletteID); !ok {
+			return ErrUnknownPalette
+		}
+	}
+
+	g.mu.Lock()
+	defer g.unlock()
+
+	if player, ok := g.Players[participantID]; ok {
+		player.Palette = paletteID
+	} else if spectator, ok := g.Spectators[participantID]; ok {
+		spectator.Palette = paletteID
+	} else {
+		return ErrPlayerNotFound
+	}
+	g.LastActivity = Now()
+	return nil
+}
+
+// SeatColors returns the palette a participant sees the game in and the swatch for
+// each of the board's colors. An empty or unknown ID gets the game's palette.
+func (g *Game) SeatColors(participantID string) (string, map[PlayerColor]PaletteSwatch) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.seatColorsLocked(participantID)
+}
+
+// seatColorsLocked backs SeatColors (caller must hold lock)
+func (g *Game) seatColorsLocked(participantID string) (string, map[PlayerColor]PaletteSwatch) {
+	paletteID := g.Palette
+	if player, ok := g.Players[participantID]; ok && player.Palette != "" {
+		paletteID = player.Palette
+	} else if spectator, ok := g.Spectators[participantID]; ok && spectator.Palette != "" {
+		paletteID = spectator.Palette
+	}
+	palette, ok := PaletteByID(paletteID)
+	if !ok {
+		palette, _ = PaletteByID(DefaultPalette)
+	}
+
+	swatches := make(map[PlayerColor]PaletteSwatch)
+	for _, color := range g.board().Colors {
+		swatches[color] = palette.Colors[color]
+	}
+	return palette.ID, swatches
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPaletteCatalogue(t *testing.T) {
+	if _, ok := PaletteByID(DefaultPalette); !ok {
+		t.Fatal("Default palette missing from catalogue")
+	}
+	for _, palette := range Palettes {
+		hexes := make(map[string]bool)
+		for color := range PlayerColorHex {
+			swatch, ok := palette.Colors[color]
+			if !ok || swatch.Hex == "" || swatch.Pattern == "" {
+				t.Errorf("Palette %s has no swatch for %s", palette.ID, color)
+			}
+			if hexes[swatch.Hex] {
+				t.Errorf("Palette %s reuses %s", palette.ID, swatch.Hex)
+			}
+			hexes[swatch.Hex] = true
+		}
+	}
+}
+
+func TestGamePalettes(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "player1", "Player 1", 4)
+	gm.JoinGame(context.Background(), game.Code, "player2", "Player 2", "")
+
+	palette, colors := game.SeatColors("")
+	if palette != DefaultPalette || len(colors) != 4 {
+		t.Errorf("Expected 4 default seat colors, got %s with %d", palette, len(colors))
+	}
+
+	if err := game.SetPalette("player2", "okabe-ito"); err != ErrNotHost {
+		t.Errorf("Expected ErrNotHost, got %v", err)
+	}
+	if err := game.SetPalette("player1", "rainbow"); err != ErrUnknownPalette {
+		t.Errorf("Expected ErrUnknownPalette, got %v", err)
+	}
+	if err := game.SetPalette("player1", "okabe-ito"); err != nil {
+		t.Fatalf("Failed to set game palette: %v", err)
+	}
+	if err := game.SetPlayerPalette("player2", "tol-muted"); err != nil {
+		t.Fatalf("Failed to set player palette: %v", err)
+	}
+
+	if palette, colors := game.SeatColors("player1"); palette != "okabe-ito" || colors[Red].Hex != "#D55E00" {
+		t.Errorf("Expected game palette for player1, got %s %+v", palette, colors[Red])
+	}
+	if palette, _ := game.SeatColors("player2"); palette != "tol-muted" {
+		t.Errorf("Expected player2's own palette, got %s", palette)
+	}
+	if state := game.GetGameState(); state["palette"] != "okabe-ito" {
+		t.Errorf("Expected state palette okabe-ito, got %v", state["palette"])
+	}
+
+	game.SetPlayerPalette("player2", "")
+	if palette, _ := game.SeatColors("player2"); palette != "okabe-ito" {
+		t.Errorf("Clearing should fall back to the game palette, got %s", palette)
+	}
+	if err := game.SetPlayerPalette("stranger", "okabe-ito"); err != ErrPlayerNotFound {
+		t.Errorf("Expected ErrPlayerNotFound, got %v", err)
+	}
+}
+
+func TestGeometryPalette(t *testing.T) {
+	board, _ := BoardByType(BoardSquare)
+	geo := board.Geometry()
+	if geo.Palette != DefaultPalette || geo.Seats[0].Hex != PlayerColorHex[geo.Seats[0].Color] {
+		t.Errorf("Expected default palette colors, got %s %s", geo.Palette, geo.Seats[0].Hex)
+	}
+
+	palette, _ := PaletteByID("okabe-ito")
+	geo.UsePalette(palette)
+	for _, seat := range geo.Seats {
+		if seat.Hex != palette.Colors[seat.Color].Hex || seat.Pattern == "" {
+			t.Errorf("Seat %s not redrawn: %+v", seat.Color, seat)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MaxPasswordLength is the longest game password; bcrypt ignores anything past 72 bytes
+const MaxPasswordLength = 72
+
+var (
+	ErrWrongPassword   = errors.New("incorrect game password")
+	ErrPasswordTooLong = errors.New("game password is too long")
+)
+
+// SetPassword requires a password to join the game as a player or spectator,
+// so its code can be shared publicly. An empty password removes it (host only).
+func (g *Game) SetPassword(hostID, password string) error {
+	if len(password) > MaxPasswordLength {
+		return ErrPasswordTooLong
+	}
+	// Hash before locking; bcrypt is deliberately slow
+	var hash []byte
+	if password != "" {
+		var err error
+		if hash, err = bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost); err != nil {
+			return err
+		}
+	}
+
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.HostID != hostID {
+		return ErrNotHost
+	}
+	g.passwordHash = hash
+	g.LastActivity = Now()
+	return nil
+}
+
+// HasPassword reports whether joining the game needs a password
+func (g *Game) HasPassword() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.passwordHash != nil
+}
+
+// checkPasswordLocked verifies a join password (caller must hold lock). Games
+// without a password accept any.
+func (g *Game) checkPasswordLocked(password string) error {
+	if g.passwordHash == nil {
+		return nil
+	}
+	if bcrypt.CompareHashAndPassword(g.passwordHash, []byte(password)) != nil {
+		return ErrWrongPassword
+	}
+	return nil
+}
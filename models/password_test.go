@@ -0,0 +1,72 @@
+package models
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestPasswordProtectedGame(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+
+	if err := game.SetPassword("player2", "secret"); err != ErrNotHost {
+		t.Errorf("Expected ErrNotHost, got %v", err)
+	}
+	if err := game.SetPassword("host1", strings.Repeat("x", MaxPasswordLength+1)); err != ErrPasswordTooLong {
+		t.Errorf("Expected ErrPasswordTooLong, got %v", err)
+	}
+	if err := game.SetPassword("host1", "secret"); err != nil {
+		t.Fatalf("Failed to set password: %v", err)
+	}
+	if state := game.GetGameState(); state["has_password"] != true {
+		t.Error("Expected the state to show the game has a password")
+	}
+
+	if _, err := gm.JoinGame(context.Background(), game.Code, "player2", "Bob", ""); err != ErrWrongPassword {
+		t.Errorf("Expected ErrWrongPassword without a password, got %v", err)
+	}
+	if _, err := gm.JoinGame(context.Background(), game.Code, "player2", "Bob", "Secret"); err != ErrWrongPassword {
+		t.Errorf("Expected ErrWrongPassword, got %v", err)
+	}
+	if _, err := gm.JoinAsSpectator(context.Background(), game.Code, "viewer", "Viewer", "nope"); err != ErrWrongPassword {
+		t.Errorf("Expected spectators to need the password too, got %v", err)
+	}
+	if _, err := gm.JoinGame(context.Background(), game.Code, "player2", "Bob", "secret"); err != nil {
+		t.Errorf("Expected the right password to work, got %v", err)
+	}
+	if _, err := gm.JoinAsSpectator(context.Background(), game.Code, "viewer", "Viewer", "secret"); err != nil {
+		t.Errorf("Expected the right password to work for spectators, got %v", err)
+	}
+
+	// Removing the password opens the game again
+	game.SetPassword("host1", "")
+	if _, err := gm.JoinGame(context.Background(), game.Code, "player3", "Carol", ""); err != nil || game.HasPassword() {
+		t.Errorf("Expected an open game after removing the password, got %v", err)
+	}
+}
+
+func TestPasswordSurvivesSnapshot(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+	game.SetPassword("host1", "secret")
+
+	data, err := game.marshalSnapshot()
+	if err != nil {
+		t.Fatalf("Failed to snapshot: %v", err)
+	}
+	restored := NewGameManager()
+	restored.mu.Lock()
+	saved, err := restored.unmarshalSnapshotLocked(data)
+	if err == nil {
+		restored.games[saved.Code] = saved
+	}
+	restored.mu.Unlock()
+	if err != nil {
+		t.Fatalf("Failed to restore: %v", err)
+	}
+
+	if _, err := restored.JoinGame(context.Background(), game.Code, "player2", "Bob", "wrong"); err != ErrWrongPassword {
+		t.Errorf("Expected the password to survive a restart, got %v", err)
+	}
+}
@@ -0,0 +1,293 @@
+package models
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// Pause limits. A player may pause a few times per game for a short while each;
+// pausing for longer than the limit needs a majority of the players to agree.
+const (
+	DefaultPauseBudget    = 2                // Pauses each player may call per game
+	DefaultMaxPauseLength = 3 * time.Minute  // Longest pause without a vote
+	MaxVotedPauseLength   = 30 * time.Minute // Longest pause a vote can grant
+)
+
+var (
+	ErrPauseBudgetExhausted = errors.New("no pauses left")
+	ErrPauseTooLong         = errors.New("pause is longer than allowed")
+	ErrNoPauseVote          = errors.New("no pause vote in progress")
+	ErrInvalidPauseRules    = errors.New("invalid pause rules")
+	ErrWaitingForPlayers    = errors.New("waiting for players to reconnect before resuming")
+)
+
+// DisconnectPausedBy marks games paused because all their human players disconnected
+const DisconnectPausedBy = "disconnected"
+
+// Pause vote outcomes
+const (
+	PauseVotePending  = "pending"
+	PauseVoteApproved = "approved"
+	PauseVoteRejected = "rejected"
+)
+
+// PauseVote is a request to pause for longer than the game's limit. The pause
+// runs for the normal length while the players vote.
+type PauseVote struct {
+	ProposedBy string          `json:"proposed_by"`
+	Seconds    int             `json:"seconds"` // Requested pause length
+	Votes      map[string]bool `json:"votes"`   // Player ID to approval
+}
+
+// SetPauseRules sets how many pauses each player gets and how long each may last
+// (host only, before the game starts)
+func (g *Game) SetPauseRules(hostID string, budget int, maxLength time.Duration) error {
+	if budget < 0 || maxLength <= 0 || maxLength > MaxVotedPauseLength {
+		return ErrInvalidPauseRules
+	}
+
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.HostID != hostID {
+		return ErrNotHost
+	}
+	if g.State != Waiting {
+		return ErrGameStarted
+	}
+	g.PauseBudget = budget
+	g.MaxPauseLength = maxLength
+	g.LastActivity = Now()
+	return nil
+}
+
+// SetResumeQuorum sets how many human players must be connected to resume a
+// paused game; 0 means all of them (host only, before the game starts)
+func (g *Game) SetResumeQuorum(hostID string, quorum int) error {
+	if quorum < 0 {
+		return ErrInvalidPauseRules
+	}
+
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.HostID != hostID {
+		return ErrNotHost
+	}
+	if g.State != Waiting {
+		return ErrGameStarted
+	}
+	g.ResumeQuorum = quorum
+	g.LastActivity = Now()
+	return nil
+}
+
+// WaitingFor returns the human players who aren't connected, sorted, and how many
+// more must connect before the game can resume. playerID, if set, counts as present.
+func (g *Game) WaitingFor(playerID string, connected map[string]int) ([]string, int) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.waitingForLocked(playerID, connected)
+}
+
+// waitingForLocked backs WaitingFor (caller must hold lock)
+func (g *Game) waitingForLocked(playerID string, connected map[string]int) ([]string, int) {
+	humans := g.pauseVotersLocked()
+	missing := []string{}
+	for id := range humans {
+		if id != playerID && connected[id] == 0 {
+			missing = append(missing, id)
+		}
+	}
+	sort.Strings(missing)
+
+	quorum := g.ResumeQuorum
+	if quorum == 0 || quorum > len(humans) {
+		quorum = len(humans)
+	}
+	needed := quorum - (len(humans) - len(missing))
+	if needed < 0 {
+		needed = 0
+	}
+	return missing, needed
+}
+
+// RequestPause pauses the game on a player's behalf, using one of their pauses.
+// A zero length means the game's limit. A longer pause starts at the limit and
+// opens a vote; a majority of the players still in the game extends it.
+func (g *Game) RequestPause(playerID string, length time.Duration) error {
+	if length > MaxVotedPauseLength {
+		return ErrPauseTooLong
+	}
+
+	g.mu.Lock()
+	defer g.unlock()
+
+	player, ok := g.Players[playerID]
+	if !ok || player.IsBot || player.HasLeft {
+		return ErrPlayerNotFound
+	}
+	if g.State != Playing {
+		return errors.New("can only pause a playing game")
+	}
+	if player.PausesUsed >= g.PauseBudget {
+		return ErrPauseBudgetExhausted
+	}
+
+	if length <= 0 || length > g.MaxPauseLength {
+		g.pauseLocked(playerID, g.MaxPauseLength)
+	} else {
+		g.pauseLocked(playerID, length)
+	}
+	player.PausesUsed++
+
+	if length > g.MaxPauseLength {
+		g.PauseVote = &PauseVote{
+			ProposedBy: playerID,
+			Seconds:    int(length / time.Second),
+			Votes:      map[string]bool{playerID: true},
+		}
+		g.tallyPauseVoteLocked()
+	}
+	return nil
+}
+
+// VotePause records a player's vote on a longer pause. Returns the vote's outcome;
+// an approved vote extends the pause right away.
+func (g *Game) VotePause(playerID string, approve bool) (string, error) {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.State != Paused || g.PauseVote == nil {
+		return "", ErrNoPauseVote
+	}
+	if _, ok := g.pauseVotersLocked()[playerID]; !ok {
+		return "", ErrPlayerNotFound
+	}
+	g.PauseVote.Votes[playerID] = approve
+	g.LastActivity = Now()
+	return g.tallyPauseVoteLocked(), nil
+}
+
+// pauseVotersLocked returns the humans still playing, who get a say in pauses
+// (caller must hold lock)
+func (g *Game) pauseVotersLocked() map[string]bool {
+	voters := make(map[string]bool)
+	for id, player := range g.Players {
+		if !player.IsBot && !player.HasLeft {
+			voters[id] = true
+		}
+	}
+	return voters
+}
+
+// tallyPauseVoteLocked settles the vote once the outcome is certain, extending the
+// pause if a majority approved (caller must hold lock)
+func (g *Game) tallyPauseVoteLocked() string {
+	voters := g.pauseVotersLocked()
+	yes, no := 0, 0
+	for id, approve := range g.PauseVote.Votes {
+		if !voters[id] {
+			continue
+		}
+		if approve {
+			yes++
+		} else {
+			no++
+		}
+	}
+
+	switch {
+	case yes*2 > len(voters):
+		g.PauseDeadline = At(g.PausedAt.Add(time.Duration(g.PauseVote.Seconds) * time.Second))
+		g.PauseVote = nil
+		return PauseVoteApproved
+	case no*2 >= len(voters):
+		g.PauseVote = nil
+		return PauseVoteRejected
+	}
+	return PauseVotePending
+}
+
+// IsPausedBy reports whether the game is paused by the given player or reason
+func (g *Game) IsPausedBy(pausedBy string) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.State == Paused && g.PausedBy == pausedBy
+}
+
+// pauseLocked pauses the game. A zero length pauses until someone resumes it
+// (caller must hold lock).
+func (g *Game) pauseLocked(pausedBy string, length time.Duration) {
+	g.State = Paused
+	g.PausedBy = pausedBy
+	g.PausedAt = Now()
+	g.PauseDeadline = Timestamp{}
+	if length > 0 {
+		g.PauseDeadline = At(g.PausedAt.Add(length))
+	}
+	g.PauseVote = nil
+	g.pendingRoll = nil // The player rolls again after the pause
+	g.LastActivity = Now()
+}
+
+// ResumeExpiredPause resumes the game if its pause has run out. Returns true if it did.
+func (g *Game) ResumeExpiredPause() bool {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.State != Paused || g.PauseDeadline.IsZero() || time.Now().Before(g.PauseDeadline.Time) {
+		return false
+	}
+	g.resumeLocked()
+	return true
+}
+
+// PauseIfAbandoned pauses a game in play once none of its human players is
+// connected, so nobody's turns time out while they are all gone. connected holds
+// the connection count of each player present. Returns true if it paused the game.
+func (g *Game) PauseIfAbandoned(connected map[string]int) bool {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.State != Playing || !g.abandonedLocked(connected) {
+		return false
+	}
+	g.pauseLocked(DisconnectPausedBy, 0)
+	return true
+}
+
+// Abandoned reports whether the game has human players and none is connected
+func (g *Game) Abandoned(connected map[string]int) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.abandonedLocked(connected)
+}
+
+// abandonedLocked backs Abandoned (caller must hold lock)
+func (g *Game) abandonedLocked(connected map[string]int) bool {
+	humans := g.pauseVotersLocked()
+	for id := range humans {
+		if connected[id] > 0 {
+			return false
+		}
+	}
+	return len(humans) > 0
+}
+
+// ResumeIfReconnected resumes a game paused by PauseIfAbandoned once its resume
+// quorum of human players is connected again. Returns true if it resumed the game.
+func (g *Game) ResumeIfReconnected(connected map[string]int) bool {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.State != Paused || g.PausedBy != DisconnectPausedBy {
+		return false
+	}
+	if _, needed := g.waitingForLocked("", connected); needed > 0 {
+		return false
+	}
+	g.resumeLocked()
+	return true
+}
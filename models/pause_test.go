@@ -0,0 +1,175 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// allConnected has every player of a pause test game connected
+var allConnected = map[string]int{"p1": 1, "p2": 1, "p3": 1}
+
+// newPauseTestGame starts a game between three humans
+func newPauseTestGame(t *testing.T) *Game {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "p1", "Player 1", 4)
+	gm.JoinGame(context.Background(), game.Code, "p2", "Player 2", "")
+	gm.JoinGame(context.Background(), game.Code, "p3", "Player 3", "")
+	for id := range game.Players {
+		game.SetPlayerReady(id, true)
+	}
+	if err := game.StartGame("p1"); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+	return game
+}
+
+func TestPauseBudget(t *testing.T) {
+	game := newPauseTestGame(t)
+
+	if err := game.RequestPause("stranger", 0); err != ErrPlayerNotFound {
+		t.Errorf("Expected ErrPlayerNotFound, got %v", err)
+	}
+
+	for i := 0; i < DefaultPauseBudget; i++ {
+		if err := game.RequestPause("p1", 0); err != nil {
+			t.Fatalf("Pause %d failed: %v", i+1, err)
+		}
+		if want := game.PausedAt.Add(DefaultMaxPauseLength); !game.PauseDeadline.Equal(want) {
+			t.Errorf("Expected pause deadline %v, got %v", want, game.PauseDeadline)
+		}
+		game.ResumeGame("p1", allConnected)
+	}
+	if err := game.RequestPause("p1", 0); err != ErrPauseBudgetExhausted {
+		t.Errorf("Expected ErrPauseBudgetExhausted, got %v", err)
+	}
+	if err := game.RequestPause("p2", time.Minute); err != nil {
+		t.Errorf("Other players keep their own budget: %v", err)
+	}
+	if want := game.PausedAt.Add(time.Minute); !game.PauseDeadline.Equal(want) {
+		t.Errorf("Expected a one minute pause, got deadline %v", game.PauseDeadline)
+	}
+}
+
+func TestPauseExpires(t *testing.T) {
+	game := newPauseTestGame(t)
+	game.RequestPause("p1", 0)
+
+	if game.ResumeExpiredPause() {
+		t.Error("Pause should not expire early")
+	}
+	game.PauseDeadline = At(time.Now().Add(-time.Second))
+	if !game.ResumeExpiredPause() || game.State != Playing {
+		t.Error("Expected the game to resume once the pause ran out")
+	}
+
+	// Open-ended pauses, such as maintenance, never run out
+	game.PauseGame(MaintenancePausedBy)
+	if game.ResumeExpiredPause() {
+		t.Error("Open-ended pause should not expire")
+	}
+}
+
+func TestPauseVote(t *testing.T) {
+	game := newPauseTestGame(t)
+
+	if err := game.RequestPause("p1", MaxVotedPauseLength+time.Minute); err != ErrPauseTooLong {
+		t.Errorf("Expected ErrPauseTooLong, got %v", err)
+	}
+	if _, err := game.VotePause("p2", true); err != ErrNoPauseVote {
+		t.Errorf("Expected ErrNoPauseVote, got %v", err)
+	}
+
+	if err := game.RequestPause("p1", 10*time.Minute); err != nil {
+		t.Fatalf("Failed to request long pause: %v", err)
+	}
+	if game.PauseVote == nil {
+		t.Fatal("Expected a vote for a pause over the limit")
+	}
+	if want := game.PausedAt.Add(DefaultMaxPauseLength); !game.PauseDeadline.Equal(want) {
+		t.Errorf("Pause should run at the limit during the vote, got %v", game.PauseDeadline)
+	}
+
+	result, err := game.VotePause("p2", true)
+	if err != nil || result != PauseVoteApproved {
+		t.Fatalf("Expected approval with 2 of 3 votes, got %s %v", result, err)
+	}
+	if want := game.PausedAt.Add(10 * time.Minute); !game.PauseDeadline.Equal(want) {
+		t.Errorf("Expected the pause extended to 10 minutes, got %v", game.PauseDeadline)
+	}
+
+	game.ResumeGame("p1", allConnected)
+	game.RequestPause("p2", 10*time.Minute)
+	if result, _ := game.VotePause("p1", false); result != PauseVotePending {
+		t.Errorf("Expected vote pending at 1-1, got %s", result)
+	}
+	if result, _ := game.VotePause("p3", false); result != PauseVoteRejected {
+		t.Errorf("Expected rejection, got %s", result)
+	}
+	if game.State != Paused || game.PauseVote != nil {
+		t.Error("A rejected vote should leave the normal pause running")
+	}
+}
+
+func TestPauseWhenAbandoned(t *testing.T) {
+	game := newPauseTestGame(t)
+
+	if game.PauseIfAbandoned(map[string]int{"p2": 1}) {
+		t.Error("Should not pause while a player is connected")
+	}
+	if !game.PauseIfAbandoned(map[string]int{"spectator": 1}) || game.PausedBy != DisconnectPausedBy {
+		t.Fatal("Expected a pause once every player disconnected")
+	}
+	if !game.PauseDeadline.IsZero() {
+		t.Error("Disconnect pauses should not run out")
+	}
+
+	if game.ResumeIfReconnected(map[string]int{"p1": 2, "p3": 1}) {
+		t.Error("Every player must be back by default")
+	}
+	if !game.ResumeIfReconnected(allConnected) || game.State != Playing {
+		t.Error("Expected resume once every player is back")
+	}
+
+	// Pauses called by players are left alone
+	game.RequestPause("p1", 0)
+	if game.ResumeIfReconnected(map[string]int{"p1": 1, "p2": 1, "p3": 1}) {
+		t.Error("A player's pause should not be resumed by reconnecting")
+	}
+}
+
+func TestResumeQuorum(t *testing.T) {
+	game := newPauseTestGame(t)
+	game.RequestPause("p1", 0)
+
+	// p1 is resuming, so counts as present
+	if err := game.ResumeGame("p1", map[string]int{"p2": 1}); err != ErrWaitingForPlayers {
+		t.Errorf("Expected ErrWaitingForPlayers, got %v", err)
+	}
+	missing, needed := game.WaitingFor("p1", map[string]int{"p2": 1})
+	if len(missing) != 1 || missing[0] != "p3" || needed != 1 {
+		t.Errorf("Expected to wait for p3, got %v (%d needed)", missing, needed)
+	}
+	if err := game.ResumeGame("p1", map[string]int{"p2": 1, "p3": 1}); err != nil {
+		t.Errorf("Failed to resume with everyone present: %v", err)
+	}
+
+	gm := NewGameManager()
+	game, _ = gm.CreateGame(context.Background(), "p1", "Player 1", 4)
+	if err := game.SetResumeQuorum("p2", 2); err != ErrNotHost {
+		t.Errorf("Expected ErrNotHost, got %v", err)
+	}
+	if err := game.SetResumeQuorum("p1", 2); err != nil {
+		t.Fatalf("Failed to set quorum: %v", err)
+	}
+	gm.JoinGame(context.Background(), game.Code, "p2", "Player 2", "")
+	gm.JoinGame(context.Background(), game.Code, "p3", "Player 3", "")
+	for id := range game.Players {
+		game.SetPlayerReady(id, true)
+	}
+	game.StartGame("p1")
+	game.PauseIfAbandoned(nil)
+	if !game.ResumeIfReconnected(map[string]int{"p2": 1, "p3": 1}) {
+		t.Error("Two players should meet a quorum of 2")
+	}
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Where player profiles and friend lists are kept in a NamespacedStorage
+const (
+	ProfilesNamespace = "profiles"
+	FriendsNamespace  = "friends"
+)
+
+// recordSaver saves one kind of per-player record to storage in the background,
+// shortly after each change, the way games and stats are saved. Changes made
+// before storage is attached are saved once it is.
+type recordSaver struct {
+	namespace string
+	encode    func(id string) ([]byte, bool) // A record to save, or false to delete it
+	storage   Storage
+	onError   StorageErrorHook
+	dirty     map[string]bool
+	wake      chan struct{}
+	mu        sync.Mutex
+	io        sync.Mutex // Held while flushing
+}
+
+// newRecordSaver creates a saver for a namespace. encode is called without the
+// saver's lock held, so it may take its store's lock.
+func newRecordSaver(namespace string, encode func(id string) ([]byte, bool)) *recordSaver {
+	return &recordSaver{
+		namespace: namespace,
+		encode:    encode,
+		dirty:     make(map[string]bool),
+	}
+}
+
+// mark queues a player's record to be saved
+func (r *recordSaver) mark(id string) {
+	r.mu.Lock()
+	r.dirty[id] = true
+	wake := r.wake
+	r.mu.Unlock()
+
+	if wake != nil {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// setStorage saves every change to storage from now on
+func (r *recordSaver) setStorage(storage Storage, onError StorageErrorHook) {
+	r.mu.Lock()
+	r.storage = storage
+	r.onError = onError
+	r.wake = make(chan struct{}, 1)
+	wake := r.wake
+	r.mu.Unlock()
+
+	go func() {
+		for range wake {
+			r.flush(context.Background())
+		}
+	}()
+	wake <- struct{}{}
+}
+
+// flush saves or deletes every changed record, returning the first error
+func (r *recordSaver) flush(ctx context.Context) error {
+	r.io.Lock()
+	defer r.io.Unlock()
+
+	r.mu.Lock()
+	storage, onError := r.storage, r.onError
+	if storage == nil {
+		r.mu.Unlock()
+		return nil
+	}
+	ids := make([]string, 0, len(r.dirty))
+	for id := range r.dirty {
+		ids = append(ids, id)
+	}
+	r.dirty = make(map[string]bool)
+	r.mu.Unlock()
+
+	var first error
+	for _, id := range ids {
+		saveCtx, cancel := context.WithTimeout(ctx, StorageSaveTimeout)
+		var err error
+		if data, ok := r.encode(id); ok {
+			err = storage.Save(saveCtx, id, data)
+		} else {
+			err = storage.Delete(saveCtx, id)
+		}
+		cancel()
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			if onError != nil {
+				onError(r.namespace+"/"+id, err)
+			}
+		}
+	}
+	return first
+}
+
+// load reads every stored record, handing each to decode. Records that can't
+// be read are skipped and reported in the error.
+func (r *recordSaver) load(ctx context.Context, decode func(id string, data []byte) error) error {
+	r.mu.Lock()
+	storage := r.storage
+	r.mu.Unlock()
+	if storage == nil {
+		return nil
+	}
+
+	ids, err := storage.List(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, id := range ids {
+		data, err := storage.Load(ctx, id)
+		if err == nil {
+			err = decode(id, data)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s/%s: %w", r.namespace, id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,66 @@
+package models
+
+import "errors"
+
+var (
+	ErrPreMoveOwnTurn = errors.New("it is already your turn, move instead")
+	ErrInvalidPreMove = errors.New("invalid piece for a pre-move")
+)
+
+// QueueMove records the piece a player wants to move on their next turn, or
+// clears it when pieceID is negative. The dice roll as soon as that turn starts
+// and the piece moves if the roll allows it; otherwise the player moves as
+// usual. Only the player sees what they queued.
+func (g *Game) QueueMove(playerID string, pieceID int) error {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.State != Playing && g.State != Paused {
+		return ErrGameNotPlaying
+	}
+	player, exists := g.Players[playerID]
+	if !exists || player.IsBot || player.HasLeft {
+		return ErrPlayerNotFound
+	}
+	if pieceID < 0 {
+		player.PreMove = nil
+		return nil
+	}
+	if pieceID >= len(player.Pieces) || player.Pieces[pieceID].IsFinished {
+		return ErrInvalidPreMove
+	}
+	if g.CurrentTurn == playerID {
+		return ErrPreMoveOwnTurn
+	}
+	player.PreMove = &pieceID
+	return nil
+}
+
+// QueuedMove returns the piece a player has queued to move next turn
+func (g *Game) QueuedMove(playerID string) (int, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	player, exists := g.Players[playerID]
+	if !exists || player.PreMove == nil {
+		return 0, false
+	}
+	return *player.PreMove, true
+}
+
+// takePreMoveLocked returns the current player's queued piece if the roll lets it
+// move. The queue is cleared either way: a pre-move is only for the turn after
+// it was made (caller must hold lock).
+func (g *Game) takePreMoveLocked(player *Player, moves []int) (int, bool) {
+	if player.PreMove == nil {
+		return 0, false
+	}
+	pieceID := *player.PreMove
+	player.PreMove = nil
+	for _, id := range moves {
+		if id == pieceID {
+			return pieceID, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,74 @@
+package models
+
+import "testing"
+
+func TestQueueMove(t *testing.T) {
+	game := newPauseTestGame(t)
+	turn := game.CurrentTurn
+	var waiting *Player
+	for _, player := range game.Players {
+		if player.ID != turn {
+			waiting = player
+			break
+		}
+	}
+
+	if err := game.QueueMove(turn, 0); err != ErrPreMoveOwnTurn {
+		t.Errorf("Expected ErrPreMoveOwnTurn, got %v", err)
+	}
+	if err := game.QueueMove(waiting.ID, PiecesPerPlayer); err != ErrInvalidPreMove {
+		t.Errorf("Expected ErrInvalidPreMove, got %v", err)
+	}
+	if err := game.QueueMove(waiting.ID, 0); err != nil {
+		t.Fatalf("Failed to queue move: %v", err)
+	}
+	if pieceID, ok := game.QueuedMove(waiting.ID); !ok || pieceID != 0 {
+		t.Errorf("Expected piece 0 queued, got %d, %v", pieceID, ok)
+	}
+
+	// Start the waiting player's turn with piece 0 out on the board
+	start := GetStartPosition(waiting.Color, game.MaxPlayers)
+	waiting.Pieces[0].IsHome = false
+	waiting.Pieces[0].Position = start
+	game.CurrentTurn = waiting.ID
+	game.HasRolled = false
+
+	if _, action := game.PlayAutoStep(); action != AutoRolled {
+		t.Fatalf("A pre-move should roll the turn, got %q", action)
+	}
+	if !game.HasRolled {
+		t.Skip("Rolled three sixes")
+	}
+	roll := game.LastDiceRoll
+	if _, action := game.PlayAutoStep(); action != AutoPreMoved {
+		t.Fatalf("Expected the queued move played, got %q", action)
+	}
+	if want := (start + roll) % BoardSize; waiting.Pieces[0].Position != want {
+		t.Errorf("Expected piece at %d, got %d", want, waiting.Pieces[0].Position)
+	}
+	if _, ok := game.QueuedMove(waiting.ID); ok {
+		t.Error("A pre-move should only be played once")
+	}
+}
+
+func TestQueueMoveInvalidOnRoll(t *testing.T) {
+	game := newPauseTestGame(t)
+	turn := game.CurrentTurn
+	for id := range game.Players {
+		if id != turn {
+			game.QueueMove(id, 1)
+			game.CurrentTurn = id
+			break
+		}
+	}
+
+	// Every piece is at home and a three can't bring one out
+	game.HasRolled = true
+	game.LastDiceRoll = 3
+	if _, action := game.PlayAutoStep(); action != AutoNone {
+		t.Errorf("Expected the player to be left to move, got %q", action)
+	}
+	if _, ok := game.QueuedMove(game.CurrentTurn); ok {
+		t.Error("An unplayable pre-move should be dropped")
+	}
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Speed presets. Blitz gives short turns and takes the clicks with no decision
+// behind them off the players: the dice roll themselves at the start of a turn
+// and a roll with no move passes the turn.
+const (
+	PresetStandard   = "standard"
+	PresetBlitz      = "blitz"
+	BlitzTurnTimeout = 15 * time.Second
+)
+
+var ErrUnknownPreset = errors.New("unknown speed preset")
+
+// SetPreset applies a speed preset's turn length and automation
+// (host only, before the game starts)
+func (g *Game) SetPreset(hostID, preset string) error {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.HostID != hostID {
+		return ErrNotHost
+	}
+	if g.State != Waiting {
+		return ErrGameStarted
+	}
+
+	switch preset {
+	case PresetStandard:
+		g.TurnTimeout = DefaultTurnTimeout
+		g.AutoRoll, g.AutoSkip = false, false
+	case PresetBlitz:
+		g.TurnTimeout = BlitzTurnTimeout
+		g.AutoRoll, g.AutoSkip = true, true
+	default:
+		return ErrUnknownPreset
+	}
+	g.Preset = preset
+	g.LastActivity = Now()
+	return nil
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBlitzPreset(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "p1", "Player 1", 4)
+	gm.JoinGame(context.Background(), game.Code, "p2", "Player 2", "")
+
+	if err := game.SetPreset("p2", PresetBlitz); err != ErrNotHost {
+		t.Errorf("Expected ErrNotHost, got %v", err)
+	}
+	if err := game.SetPreset("p1", "bullet"); err != ErrUnknownPreset {
+		t.Errorf("Expected ErrUnknownPreset, got %v", err)
+	}
+	if err := game.SetPreset("p1", PresetBlitz); err != nil {
+		t.Fatalf("Failed to set preset: %v", err)
+	}
+	if game.TurnTimeout != BlitzTurnTimeout || !game.AutoRoll || !game.AutoSkip {
+		t.Errorf("Blitz settings not applied: timeout %v, auto roll %v, auto skip %v", game.TurnTimeout, game.AutoRoll, game.AutoSkip)
+	}
+
+	game.SetPlayerReady("p1", true)
+	game.SetPlayerReady("p2", true)
+	if err := game.StartGame("p1"); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+	if err := game.SetPreset("p1", PresetStandard); err != ErrGameStarted {
+		t.Errorf("Expected ErrGameStarted, got %v", err)
+	}
+
+	// Every piece is at home, so only a six gives a move
+	for i := 0; i < 20; i++ {
+		turn := game.CurrentTurn
+		playerID, action := game.PlayAutoAction()
+		if playerID != turn || action != AutoRolled {
+			t.Fatalf("Expected an automatic roll for %s, got %q for %q", turn, action, playerID)
+		}
+		if !game.HasRolled {
+			continue // Three sixes passed the turn
+		}
+		if len(game.GetValidMoves(turn)) > 0 {
+			if _, action := game.PlayAutoAction(); action != AutoNone {
+				t.Errorf("A roll with a move should wait for the player, got %q", action)
+			}
+			return
+		}
+		if _, action := game.PlayAutoAction(); action != AutoSkipped {
+			t.Fatalf("Expected the turn to be skipped, got %q", action)
+		}
+		if game.CurrentTurn == turn {
+			t.Fatal("Skipping should pass the turn")
+		}
+	}
+}
+
+func TestNoAutoActionByDefault(t *testing.T) {
+	game := newPauseTestGame(t)
+	if _, action := game.PlayAutoAction(); action != AutoNone {
+		t.Errorf("Expected no automatic action, got %q", action)
+	}
+}
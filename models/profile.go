@@ -0,0 +1,312 @@
+package models
+
+import (
+	"context"
+	crypto_rand "crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// friendCodeAlphabet leaves out letters and digits that are easily confused
+const friendCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// FriendCodeLength is how many characters a friend code has
+const FriendCodeLength = 8
+
+// Avatar limits: an emoji, or a reference to an image uploaded elsewhere
+const (
+	MaxAvatarRunes  = 8
+	MaxAvatarURLLen = 512
+)
+
+var (
+	ErrFriendCodeNotFound = errors.New("no player has that friend code")
+	ErrInvalidAvatar      = errors.New("avatar must be an emoji or an https image URL")
+	ErrUnknownColor       = errors.New("unknown color")
+	ErrInvalidLocale      = errors.New("invalid locale")
+)
+
+// Profile holds per-player preferences that outlive a single game. The name,
+// avatar and color are applied when the player creates or joins a game.
+type Profile struct {
+	PlayerID    string      `json:"player_id"`
+	DisplayName string      `json:"display_name,omitempty"` // Used when no name is given; also set by RenamePlayer
+	Avatar      string      `json:"avatar,omitempty"`       // An emoji or https image URL
+	Color       PlayerColor `json:"color,omitempty"`        // Seated in this color when it's free
+	Locale      string      `json:"locale,omitempty"`       // Chat language when a client doesn't say
+	BoardTheme  string      `json:"board_theme"`
+	PieceSkin   string      `json:"piece_skin"`
+	FriendCode  string      `json:"friend_code,omitempty"` // Shared so others can add the player without knowing their ID
+	UpdatedAt   Timestamp   `json:"updated_at"`
+}
+
+// ProfileUpdate changes some of a profile's fields. Nil fields are left as
+// they are; empty strings clear them.
+type ProfileUpdate struct {
+	DisplayName *string
+	Avatar      *string
+	Color       *PlayerColor
+	Locale      *string
+}
+
+// ProfileStore keeps player profiles keyed by player ID. With storage attached,
+// each change is saved in the background like stats are.
+type ProfileStore struct {
+	profiles map[string]*Profile
+	codes    map[string]string // Friend code -> player ID
+	saver    *recordSaver
+	mu       sync.RWMutex
+}
+
+// NewProfileStore creates an empty profile store
+func NewProfileStore() *ProfileStore {
+	s := &ProfileStore{
+		profiles: make(map[string]*Profile),
+		codes:    make(map[string]string),
+	}
+	s.saver = newRecordSaver(ProfilesNamespace, s.encode)
+	return s
+}
+
+// Get returns a copy of a player's profile, with defaults if none is saved
+func (s *ProfileStore) Get(playerID string) Profile {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if profile, exists := s.profiles[playerID]; exists {
+		return *profile
+	}
+	return defaultProfile(playerID)
+}
+
+// SetTheme saves a player's board theme and piece skin; empty values are left unchanged
+func (s *ProfileStore) SetTheme(playerID, boardTheme, pieceSkin string) (Profile, error) {
+	if err := ValidatePlayerID(playerID); err != nil {
+		return Profile{}, err
+	}
+	if boardTheme != "" {
+		if err := validateTheme(boardTheme, ThemeBoard); err != nil {
+			return Profile{}, err
+		}
+	}
+	if pieceSkin != "" {
+		if err := validateTheme(pieceSkin, ThemePiece); err != nil {
+			return Profile{}, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	profile := s.profileLocked(playerID)
+	if boardTheme != "" {
+		profile.BoardTheme = boardTheme
+	}
+	if pieceSkin != "" {
+		profile.PieceSkin = pieceSkin
+	}
+	profile.UpdatedAt = Now()
+	s.saver.mark(playerID)
+	return *profile, nil
+}
+
+// Update validates and saves changes to a player's profile
+func (s *ProfileStore) Update(playerID string, update ProfileUpdate) (Profile, error) {
+	if err := ValidatePlayerID(playerID); err != nil {
+		return Profile{}, err
+	}
+	if update.DisplayName != nil && *update.DisplayName != "" {
+		if err := ValidatePlayerName(*update.DisplayName); err != nil {
+			return Profile{}, err
+		}
+	}
+	if update.Avatar != nil {
+		if err := ValidateAvatar(*update.Avatar); err != nil {
+			return Profile{}, err
+		}
+	}
+	if update.Color != nil && *update.Color != "" && !SquareBoard.HasColor(*update.Color) && !HexBoard.HasColor(*update.Color) {
+		return Profile{}, ErrUnknownColor
+	}
+	var locale string
+	if update.Locale != nil {
+		locale = NormalizeLocale(*update.Locale)
+		if !validLocale(locale) {
+			return Profile{}, ErrInvalidLocale
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	profile := s.profileLocked(playerID)
+	if update.DisplayName != nil {
+		profile.DisplayName = strings.TrimSpace(*update.DisplayName)
+	}
+	if update.Avatar != nil {
+		profile.Avatar = strings.TrimSpace(*update.Avatar)
+	}
+	if update.Color != nil {
+		profile.Color = *update.Color
+	}
+	if update.Locale != nil {
+		profile.Locale = locale
+	}
+	profile.UpdatedAt = Now()
+	s.saver.mark(playerID)
+	return *profile, nil
+}
+
+// ValidateAvatar accepts an empty avatar, a short emoji, or an https URL of an
+// image uploaded elsewhere
+func ValidateAvatar(avatar string) error {
+	avatar = strings.TrimSpace(avatar)
+	if avatar == "" {
+		return nil
+	}
+	if strings.HasPrefix(avatar, "https://") {
+		u, err := url.Parse(avatar)
+		if err != nil || u.Host == "" || len(avatar) > MaxAvatarURLLen {
+			return ErrInvalidAvatar
+		}
+		return nil
+	}
+	if utf8.RuneCountInString(avatar) > MaxAvatarRunes {
+		return ErrInvalidAvatar
+	}
+	for _, r := range avatar {
+		if r < utf8.RuneSelf || unicode.IsLetter(r) || unicode.IsSpace(r) {
+			return ErrInvalidAvatar // Emoji only, not text
+		}
+	}
+	return nil
+}
+
+// validLocale reports whether a normalized locale is empty or a language code
+func validLocale(locale string) bool {
+	if len(locale) > 3 || len(locale) == 1 {
+		return false
+	}
+	for _, r := range locale {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// SetDisplayName saves the name a player last chose
+func (s *ProfileStore) SetDisplayName(playerID, name string) Profile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	profile := s.profileLocked(playerID)
+	profile.DisplayName = name
+	profile.UpdatedAt = Now()
+	s.saver.mark(playerID)
+	return *profile
+}
+
+// FriendCode returns a player's friend code, giving them one if they have none
+func (s *ProfileStore) FriendCode(playerID string) (string, error) {
+	if err := ValidatePlayerID(playerID); err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	profile := s.profileLocked(playerID)
+	if profile.FriendCode == "" {
+		code := newFriendCode()
+		for s.codes[code] != "" {
+			code = newFriendCode()
+		}
+		profile.FriendCode = code
+		profile.UpdatedAt = Now()
+		s.codes[code] = playerID
+		s.saver.mark(playerID)
+	}
+	return profile.FriendCode, nil
+}
+
+// ByFriendCode returns the player a friend code belongs to. Case, spaces and
+// dashes are ignored, so codes can be shared as ABCD-2345.
+func (s *ProfileStore) ByFriendCode(code string) (string, error) {
+	code = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if playerID, ok := s.codes[code]; ok {
+		return playerID, nil
+	}
+	return "", ErrFriendCodeNotFound
+}
+
+// newFriendCode returns a random friend code
+func newFriendCode() string {
+	var b [FriendCodeLength]byte
+	crypto_rand.Read(b[:])
+	for i := range b {
+		b[i] = friendCodeAlphabet[int(b[i])%len(friendCodeAlphabet)]
+	}
+	return string(b[:])
+}
+
+// encode serializes a profile for storage
+func (s *ProfileStore) encode(playerID string) ([]byte, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	profile, exists := s.profiles[playerID]
+	if !exists {
+		return nil, false
+	}
+	data, _ := json.Marshal(profile)
+	return data, true
+}
+
+// load restores every stored profile
+func (s *ProfileStore) load(ctx context.Context) error {
+	return s.saver.load(ctx, func(playerID string, data []byte) error {
+		var profile Profile
+		if err := json.Unmarshal(data, &profile); err != nil {
+			return err
+		}
+		profile.PlayerID = playerID
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.profiles[playerID] = &profile
+		if profile.FriendCode != "" {
+			s.codes[profile.FriendCode] = playerID
+		}
+		return nil
+	})
+}
+
+// profileLocked returns the stored profile, creating it if needed (caller must hold lock)
+func (s *ProfileStore) profileLocked(playerID string) *Profile {
+	profile, exists := s.profiles[playerID]
+	if !exists {
+		p := defaultProfile(playerID)
+		profile = &p
+		s.profiles[playerID] = profile
+	}
+	return profile
+}
+
+// defaultProfile returns the profile used for players without saved preferences
+func defaultProfile(playerID string) Profile {
+	return Profile{
+		PlayerID:   playerID,
+		BoardTheme: DefaultBoardTheme,
+		PieceSkin:  DefaultPieceSkin,
+	}
+}
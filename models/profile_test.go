@@ -0,0 +1,85 @@
+package models
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestProfileUpdate(t *testing.T) {
+	profiles := NewProfileStore()
+	blue := Blue
+
+	profile, err := profiles.Update("player1", ProfileUpdate{
+		DisplayName: strPtr(" Amine "),
+		Avatar:      strPtr("🦊"),
+		Color:       &blue,
+		Locale:      strPtr("ar-MA"),
+	})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if profile.DisplayName != "Amine" || profile.Avatar != "🦊" || profile.Color != Blue || profile.Locale != "ar" {
+		t.Errorf("Unexpected profile %+v", profile)
+	}
+
+	// Fields left out are kept; empty ones are cleared
+	profile, _ = profiles.Update("player1", ProfileUpdate{Avatar: strPtr("")})
+	if profile.Avatar != "" || profile.DisplayName != "Amine" {
+		t.Errorf("Expected only the avatar cleared, got %+v", profile)
+	}
+
+	pink := PlayerColor("pink")
+	for _, update := range []ProfileUpdate{
+		{DisplayName: strPtr(strings.Repeat("x", MaxPlayerNameLength+1))},
+		{Avatar: strPtr("not an emoji")},
+		{Avatar: strPtr("http://example.com/me.png")},
+		{Color: &pink},
+		{Locale: strPtr("english")},
+	} {
+		if _, err := profiles.Update("player1", update); err == nil {
+			t.Errorf("Expected %+v to be rejected", update)
+		}
+	}
+	if err := ValidateAvatar("https://cdn.example.com/avatars/1.png"); err != nil {
+		t.Errorf("Expected an image URL accepted, got %v", err)
+	}
+}
+
+func TestProfileAppliedToGames(t *testing.T) {
+	ctx := context.Background()
+	gm := NewGameManager()
+	blue := Blue
+	gm.Profiles().Update("host1", ProfileUpdate{DisplayName: strPtr("Amine"), Avatar: strPtr("🦊"), Color: &blue})
+	gm.Profiles().Update("player2", ProfileUpdate{DisplayName: strPtr("Fatima"), Color: &blue})
+
+	game, err := gm.CreateGame(ctx, "host1", "", 4)
+	if err != nil {
+		t.Fatalf("Create without a name failed: %v", err)
+	}
+	host := game.Players["host1"]
+	if host.Name != "Amine" || host.Avatar != "🦊" || host.Color != Blue {
+		t.Errorf("Expected the profile applied to the host, got %+v", host)
+	}
+
+	gm.AddBot(ctx, game.Code, "host1", BotOptions{})
+	if _, err := gm.JoinGame(ctx, game.Code, "player2", "", ""); err != nil {
+		t.Fatalf("Join without a name failed: %v", err)
+	}
+	seen := make(map[PlayerColor]bool)
+	for _, player := range game.Players {
+		if seen[player.Color] {
+			t.Errorf("Expected distinct colors, got %s twice", player.Color)
+		}
+		seen[player.Color] = true
+	}
+	if player := game.Players["player2"]; player.Name != "Fatima" || player.Color == Blue {
+		t.Errorf("Expected the profile name and another color than the host's, got %+v", player)
+	}
+
+	if _, err := gm.JoinGame(ctx, game.Code, "player3", "", ""); err != ErrInvalidPlayerName {
+		t.Errorf("Expected a name to be required without a profile, got %v", err)
+	}
+}
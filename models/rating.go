@@ -0,0 +1,100 @@
+package models
+
+import "math"
+
+// Elo rating parameters
+const (
+	DefaultRating = 1500 // Rating before a player's first ranked game
+	RatingK       = 32   // Most a player can gain or lose in one ranked game
+)
+
+// SetRanked marks the game as ranked, so its result moves the players' ratings
+// (host only, before the game starts)
+func (g *Game) SetRanked(hostID string, ranked bool) error {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.HostID != hostID {
+		return ErrNotHost
+	}
+	if g.State != Waiting {
+		return ErrGameStarted
+	}
+	g.Ranked = ranked
+	g.LastActivity = Now()
+	return nil
+}
+
+// Rating returns a player's rating, DefaultRating before their first ranked game
+func (s *StatsStore) Rating(playerID string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if c, exists := s.careers[playerID]; exists {
+		return c.Rating
+	}
+	return DefaultRating
+}
+
+// rateLocked applies a ranked game to the ratings of its human seats and returns
+// each player's change (caller must hold lock). The result counts as a match
+// between every pair of players, won by whoever finished higher, and a player's
+// change is the average over their opponents so it doesn't grow with the table.
+func (s *StatsStore) rateLocked(seats []ArchivedSeat) map[string]int {
+	var rated []ArchivedSeat
+	for _, seat := range seats {
+		if !seat.IsBot {
+			rated = append(rated, seat)
+		}
+	}
+	if len(rated) < 2 {
+		return nil
+	}
+
+	before := make([]float64, len(rated))
+	for i, seat := range rated {
+		before[i] = float64(s.careers[seat.PlayerID].Rating)
+	}
+	changes := make(map[string]int, len(rated))
+	for i, seat := range rated {
+		var gain float64
+		for j, opponent := range rated {
+			if i == j {
+				continue
+			}
+			score := 0.5
+			if seat.Place < opponent.Place {
+				score = 1
+			} else if seat.Place > opponent.Place {
+				score = 0
+			}
+			expected := 1 / (1 + math.Pow(10, (before[j]-before[i])/400))
+			gain += score - expected
+		}
+		change := int(math.Round(RatingK * gain / float64(len(rated)-1)))
+		c := s.careers[seat.PlayerID]
+		c.Rating += change
+		c.RatedGames++
+		changes[seat.PlayerID] = change
+	}
+	return changes
+}
+
+// applyRatingsLocked shows the new ratings on the players after a ranked game
+// (caller must hold lock)
+func (g *Game) applyRatingsLocked(changes map[string]int) {
+	for id, change := range changes {
+		if player, exists := g.Players[id]; exists {
+			player.Rating = g.stats.Rating(id)
+			player.RatingChange = change
+		}
+	}
+}
+
+// seatRating returns the rating to show on a newly seated human
+func (g *Game) seatRating(playerID string) int {
+	if g.stats == nil {
+		return DefaultRating
+	}
+	return g.stats.Rating(playerID)
+}
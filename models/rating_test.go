@@ -0,0 +1,75 @@
+package models
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRankedGameRatings(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "p1", "Player 1", 4)
+	gm.JoinGame(context.Background(), game.Code, "p2", "Player 2", "")
+	gm.JoinGame(context.Background(), game.Code, "p3", "Player 3", "")
+	gm.AddBot(context.Background(), game.Code, "p1", BotOptions{})
+	if err := game.SetRanked("p2", true); err != ErrNotHost {
+		t.Errorf("Expected ErrNotHost, got %v", err)
+	}
+	if err := game.SetRanked("p1", true); err != nil {
+		t.Fatalf("Failed to rank the game: %v", err)
+	}
+	if game.Players["p2"].Rating != DefaultRating {
+		t.Errorf("Expected new players at %d, got %d", DefaultRating, game.Players["p2"].Rating)
+	}
+	for id := range game.Players {
+		game.SetPlayerReady(id, true)
+	}
+	game.StartGame("p1")
+	if err := game.SetRanked("p1", false); err != ErrGameStarted {
+		t.Errorf("Expected ErrGameStarted, got %v", err)
+	}
+
+	// p3 is further along than p1, so finishes second
+	game.Players["p3"].Pieces[0].IsHome = false
+	game.Players["p3"].Pieces[0].Position = 20
+	endTestGame(game, "p2")
+
+	// Even ratings: the winner beats both, second place splits its two matches
+	for id, want := range map[string]int{"p2": 16, "p3": 0, "p1": -16} {
+		player := game.Players[id]
+		if player.RatingChange != want || player.Rating != DefaultRating+want {
+			t.Errorf("Expected %s to change by %d, got %d to %d", id, want, player.RatingChange, player.Rating)
+		}
+		if career, _ := gm.Stats().Get(id); career.Rating != DefaultRating+want || career.RatedGames != 1 {
+			t.Errorf("Expected %s's career rating %d after 1 ranked game, got %+v", id, DefaultRating+want, career)
+		}
+	}
+
+	// An upset moves ratings further than an expected result
+	changes := gm.Stats().Record(ArchivedGame{Ranked: true, Winner: "p1", Seats: []ArchivedSeat{
+		{PlayerID: "p1", Place: 1},
+		{PlayerID: "p2", Place: 2},
+	}})
+	if changes["p1"] <= 16 || changes["p1"] != -changes["p2"] {
+		t.Errorf("Expected p1 to gain more than 16 from p2, got %v", changes)
+	}
+
+	board, _ := gm.Stats().Leaderboard(LeaderboardQuery{Sort: SortByRating})
+	if board.Total != 3 || board.Players[0].PlayerID != "p1" || board.Players[2].PlayerID != "p2" {
+		t.Errorf("Expected p1 top and p2 bottom of 3 rated players, got %+v", board.Players)
+	}
+}
+
+func TestUnrankedGamesKeepRatings(t *testing.T) {
+	game := newPauseTestGame(t)
+	endTestGame(game, "p1")
+
+	if game.Players["p1"].RatingChange != 0 {
+		t.Errorf("Expected no rating change, got %d", game.Players["p1"].RatingChange)
+	}
+	if career, _ := game.stats.Get("p1"); career.Rating != DefaultRating || career.RatedGames != 0 {
+		t.Errorf("Expected p1 unrated, got %+v", career)
+	}
+	if board, _ := game.stats.Leaderboard(LeaderboardQuery{Sort: SortByRating}); board.Total != 0 {
+		t.Errorf("Expected nobody on the rating leaderboard, got %+v", board.Players)
+	}
+}
@@ -0,0 +1,82 @@
+package models
+
+import "time"
+
+// DefaultReconnectGrace is how long a disconnected player's turn waits for them
+// before it times out, if that is sooner than the turn timeout
+const DefaultReconnectGrace = 30 * time.Second
+
+// SetReconnectGrace sets how long a disconnected player has to come back before
+// their turns time out early, for every game; zero waits out the full turn timeout
+func (gm *GameManager) SetReconnectGrace(grace time.Duration) {
+	if grace < 0 {
+		grace = 0
+	}
+
+	gm.mu.Lock()
+	gm.reconnectGrace = grace
+	games := make([]*Game, 0, len(gm.games))
+	for _, game := range gm.games {
+		games = append(games, game)
+	}
+	gm.mu.Unlock()
+
+	for _, game := range games {
+		game.mu.Lock()
+		game.ReconnectGrace = grace
+		game.unlock()
+	}
+}
+
+// SetConnected records a player's last connection to the game closing, or their
+// first one opening. Spectators and unknown IDs are ignored.
+func (g *Game) SetConnected(playerID string, connected bool) {
+	g.mu.Lock()
+	defer g.unlock()
+
+	player, exists := g.Players[playerID]
+	if !exists {
+		return
+	}
+	if connected {
+		player.DisconnectedAt = Timestamp{}
+		player.ControlledByBot = false // Back in control
+	} else if player.DisconnectedAt.IsZero() {
+		player.DisconnectedAt = Now()
+	}
+}
+
+// turnDeadlineLocked returns when the current turn times out: after the turn
+// timeout, or sooner once the player has been disconnected for the reconnect
+// grace. Zero if the game's turns are untimed and the player is connected
+// (caller must hold lock).
+func (g *Game) turnDeadlineLocked() time.Time {
+	var deadline time.Time
+	if g.TurnTimeout > 0 {
+		deadline = g.TurnStartTime.Add(g.TurnTimeout)
+	}
+	player, exists := g.Players[g.CurrentTurn]
+	if !exists || player.DisconnectedAt.IsZero() || g.ReconnectGrace <= 0 {
+		return deadline
+	}
+
+	// The grace runs from the disconnect, or from the start of the turn if the
+	// player was already gone
+	gone := player.DisconnectedAt.Time
+	if gone.Before(g.TurnStartTime.Time) {
+		gone = g.TurnStartTime.Time
+	}
+	if early := gone.Add(g.ReconnectGrace); deadline.IsZero() || early.Before(deadline) {
+		return early
+	}
+	return deadline
+}
+
+// IsParticipant reports whether the ID belongs to a player or spectator of the game
+func (g *Game) IsParticipant(id string) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	_, isPlayer := g.Players[id]
+	_, isSpectator := g.Spectators[id]
+	return isPlayer || isSpectator
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDisconnectedTurnTimesOutAfterGrace(t *testing.T) {
+	game := newPauseTestGame(t)
+	current := game.CurrentTurn
+
+	// Connected players get the full turn timeout
+	game.TurnStartTime = At(time.Now().Add(-DefaultReconnectGrace - time.Second))
+	if player, _ := game.HandleTurnTimeout(); player != "" {
+		t.Fatalf("Expected a connected player to keep their turn, but %s timed out", player)
+	}
+
+	// A player gone longer than the grace loses the turn early
+	game.SetConnected(current, false)
+	if game.Players[current].DisconnectedAt.IsZero() {
+		t.Fatal("Expected the disconnect to be recorded")
+	}
+	if clock, _ := game.TurnClock(); !clock.Deadline.Before(game.TurnStartTime.Add(game.TurnTimeout)) {
+		t.Errorf("Expected the turn clock to show the early deadline, got %v", clock.Deadline)
+	}
+	game.Players[current].DisconnectedAt = At(time.Now().Add(-DefaultReconnectGrace - time.Second))
+	if player, action := game.HandleTurnTimeout(); player != current || action != TimeoutSkip {
+		t.Fatalf("Expected %s's turn to be skipped, got %q (%s)", current, player, action)
+	}
+
+	// Coming back clears the disconnect
+	game.SetConnected(current, true)
+	if !game.Players[current].DisconnectedAt.IsZero() {
+		t.Error("Expected reconnecting to clear the disconnect")
+	}
+}
+
+func TestReconnectGraceRunsFromTurnStart(t *testing.T) {
+	game := newPauseTestGame(t)
+	current := game.CurrentTurn
+
+	// Gone since long before the turn began: the grace starts with the turn
+	game.Players[current].DisconnectedAt = At(time.Now().Add(-time.Hour))
+	game.TurnStartTime = Now()
+	if game.IsTurnTimedOut() {
+		t.Fatal("Expected the grace to run from the start of the turn")
+	}
+	if remaining := game.GetTurnTimeRemaining(); remaining > DefaultReconnectGrace {
+		t.Errorf("Expected at most the grace remaining, got %v", remaining)
+	}
+}
+
+func TestSetReconnectGrace(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+
+	gm.SetReconnectGrace(-time.Second)
+	if game.ReconnectGrace != 0 {
+		t.Errorf("Expected a negative grace to turn it off for existing games, got %v", game.ReconnectGrace)
+	}
+
+	gm.SetReconnectGrace(10 * time.Second)
+	later, _ := gm.CreateGame(context.Background(), "host2", "Host", 4)
+	if later.ReconnectGrace != 10*time.Second || game.ReconnectGrace != 10*time.Second {
+		t.Errorf("Expected every game to use the new grace, got %v and %v", game.ReconnectGrace, later.ReconnectGrace)
+	}
+
+	// Without a grace a disconnected player keeps the full turn
+	gm.SetReconnectGrace(-1)
+	game.SetConnected("host1", false)
+	game.mu.Lock()
+	game.State = Playing
+	game.CurrentTurn = "host1"
+	game.TurnStartTime = Now()
+	deadline := game.turnDeadlineLocked()
+	game.unlock()
+	if want := game.TurnStartTime.Add(game.TurnTimeout); !deadline.Equal(want) {
+		t.Errorf("Expected the full turn timeout, got %v", deadline)
+	}
+}
+
+func TestIsParticipant(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+	gm.JoinAsSpectator(context.Background(), game.Code, "viewer", "Viewer", "")
+
+	if !game.IsParticipant("host1") || !game.IsParticipant("viewer") || game.IsParticipant("stranger") {
+		t.Error("Expected players and spectators to be participants, and nobody else")
+	}
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"context"
+	"strings"
+)
+
+// RenamePlayer changes a player's display name in every active game they play or
+// watch, and in their profile. Chat sent from now on carries the new name; earlier
+// messages and move history keep the old one. Returns the codes of the games that changed.
+func (gm *GameManager) RenamePlayer(ctx context.Context, playerID, name string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := ValidatePlayerID(playerID); err != nil {
+		return nil, err
+	}
+	if err := ValidatePlayerName(name); err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+
+	renamed := []string{}
+	for _, game := range gm.GetAllGames() {
+		if game.renameParticipant(playerID, name) {
+			renamed = append(renamed, game.Code)
+		}
+	}
+	gm.profiles.SetDisplayName(playerID, name)
+	return renamed, nil
+}
+
+// renameParticipant renames a player or spectator. Returns false if they aren't in
+// the game, already use the name, or the game is over or deleted.
+func (g *Game) renameParticipant(playerID, name string) bool {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.State == Ended || !g.DeletedAt.IsZero() {
+		return false
+	}
+
+	if player, ok := g.Players[playerID]; ok && !player.HasLeft && player.Name != name {
+		player.Name = name
+		g.LastActivity = Now()
+		return true
+	}
+	if spectator, ok := g.Spectators[playerID]; ok && spectator.Name != name {
+		spectator.Name = name
+		g.LastActivity = Now()
+		return true
+	}
+	return false
+}
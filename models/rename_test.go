@@ -0,0 +1,54 @@
+package models
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRenamePlayer(t *testing.T) {
+	gm := NewGameManager()
+	game1, _ := gm.CreateGame(context.Background(), "player1", "Player 1", 4)
+	game2, _ := gm.CreateGame(context.Background(), "host2", "Host 2", 4)
+	gm.JoinGame(context.Background(), game2.Code, "player1", "Player 1", "")
+	game3, _ := gm.CreateGame(context.Background(), "host3", "Host 3", 4)
+	gm.JoinAsSpectator(context.Background(), game3.Code, "player1", "Player 1", "")
+	other, _ := gm.CreateGame(context.Background(), "host4", "Host 4", 4)
+
+	game1.SendChatMessage("player1", "before")
+
+	if _, err := gm.RenamePlayer(context.Background(), "player1", "   "); err == nil {
+		t.Error("Expected error for blank name")
+	}
+
+	codes, err := gm.RenamePlayer(context.Background(), "player1", "  Amine ")
+	if err != nil {
+		t.Fatalf("Failed to rename: %v", err)
+	}
+	if len(codes) != 3 {
+		t.Errorf("Expected 3 renamed games, got %v", codes)
+	}
+	for _, code := range codes {
+		if code == other.Code {
+			t.Error("Game without the player should not be renamed")
+		}
+	}
+	if game1.Players["player1"].Name != "Amine" || game2.Players["player1"].Name != "Amine" {
+		t.Error("Player name should be updated in every game")
+	}
+	if game3.Spectators["player1"].Name != "Amine" {
+		t.Error("Spectator name should be updated")
+	}
+	if p := gm.Profiles().Get("player1"); p.DisplayName != "Amine" {
+		t.Errorf("Expected profile display name Amine, got %q", p.DisplayName)
+	}
+
+	game1.SendChatMessage("player1", "after")
+	chat := game1.GetRecentChat(2)
+	if chat[0].PlayerName != "Player 1" || chat[1].PlayerName != "Amine" {
+		t.Errorf("Expected old messages to keep the old name, got %q and %q", chat[0].PlayerName, chat[1].PlayerName)
+	}
+
+	if codes, _ := gm.RenamePlayer(context.Background(), "player1", "Amine"); len(codes) != 0 {
+		t.Errorf("Renaming to the same name should change nothing, got %v", codes)
+	}
+}
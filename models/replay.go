@@ -0,0 +1,260 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ReplayResult describes the outcome of re-simulating a game from its move history
+type ReplayResult struct {
+	Code          string   `json:"code"`
+	MovesReplayed int      `json:"moves_replayed"`
+	Winner        string   `json:"winner,omitempty"`
+	Valid         bool     `json:"valid"`
+	Mismatches    []string `json:"mismatches,omitempty"`
+}
+
+// VerifyReplay re-simulates a game by code and checks it against the live state
+func (gm *GameManager) VerifyReplay(ctx context.Context, code string) (*ReplayResult, error) {
+	game, err := gm.GetGame(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	return game.VerifyReplay(), nil
+}
+
+// VerifyReplay replays MoveHistory through the engine on a fresh board and
+// verifies that the final piece positions and winner match the recorded game
+func (g *Game) VerifyReplay() *ReplayResult {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	result := &ReplayResult{Code: g.Code}
+	replay := g.initialEngineStateLocked()
+
+	for i, record := range g.MoveHistory {
+		// History only records moves, so seat the mover with their recorded roll
+		replay.CurrentTurn = record.PlayerID
+		replay.HasRolled = true
+		replay.LastDiceRoll = record.DiceRoll
+
+		next, err := ApplyEvent(replay, EngineEvent{Type: EventMove, PlayerID: record.PlayerID, PieceID: record.PieceID})
+		if err != nil {
+			result.Mismatches = append(result.Mismatches,
+				fmt.Sprintf("move %d (%s piece %d, roll %d) rejected: %v", i, record.PlayerID, record.PieceID, record.DiceRoll, err))
+			break
+		}
+		replay = next
+		result.MovesReplayed++
+	}
+
+	result.Winner = replay.Winner
+	if replay.Winner != g.Winner {
+		result.Mismatches = append(result.Mismatches,
+			fmt.Sprintf("winner mismatch: recorded %q, replayed %q", g.Winner, replay.Winner))
+	}
+
+	for id, player := range g.Players {
+		replayed, exists := replay.Players[id]
+		if !exists || player.HasLeft {
+			// Departed players' pieces may have been moved by a kick policy, not a move
+			continue
+		}
+		for i, piece := range player.Pieces {
+			if i >= len(replayed.Pieces) {
+				break
+			}
+			if !samePiecePlacement(piece, replayed.Pieces[i]) {
+				result.Mismatches = append(result.Mismatches,
+					fmt.Sprintf("%s piece %d: recorded %s, replayed %s", id, i, describePiece(piece), describePiece(replayed.Pieces[i])))
+			}
+		}
+	}
+
+	result.Valid = len(result.Mismatches) == 0
+	return result
+}
+
+// initialEngineStateLocked builds the opening position with the same seats (caller must hold lock)
+func (g *Game) initialEngineStateLocked() EngineState {
+	replay := EngineState{
+		MaxPlayers:        g.MaxPlayers,
+		CaptureGrantsTurn: g.CaptureGrantsTurn,
+		Rules:             g.rulesLocked(),
+		State:             Playing,
+		Players:           make(map[string]*Player, len(g.Players)),
+	}
+
+	for id, player := range g.Players {
+		pieces := make([]Piece, len(player.Pieces))
+		for i := range pieces {
+			pieces[i] = Piece{ID: i, Position: HomePosition, IsHome: true}
+		}
+		replay.Players[id] = &Player{
+			ID:     player.ID,
+			Name:   player.Name,
+			Color:  player.Color,
+			Pieces: pieces,
+			Order:  player.Order,
+			IsBot:  player.IsBot,
+		}
+	}
+
+	return replay
+}
+
+// samePiecePlacement compares the board placement of two pieces
+func samePiecePlacement(a, b Piece) bool {
+	return a.Position == b.Position &&
+		a.HomeStretchPosition == b.HomeStretchPosition &&
+		a.IsHome == b.IsHome &&
+		a.IsFinished == b.IsFinished
+}
+
+// describePiece formats a piece placement for mismatch reports
+func describePiece(p Piece) string {
+	switch {
+	case p.IsFinished:
+		return "finished"
+	case p.IsHome:
+		return "home"
+	case p.HomeStretchPosition > 0:
+		return fmt.Sprintf("home stretch %d", p.HomeStretchPosition)
+	default:
+		return fmt.Sprintf("square %d", p.Position)
+	}
+}
+
+// ErrReplayNotFound is returned for unknown replay IDs and games too old to replay
+var ErrReplayNotFound = errors.New("replay not found")
+
+// ReplayPiece is where a piece ended up after a replay event
+type ReplayPiece struct {
+	PlayerID string `json:"player_id"`
+	PieceID  int    `json:"piece_id"`
+	Point    Point  `json:"point"`
+}
+
+// ReplayEvent is a recorded move with its pieces placed on the board geometry
+type ReplayEvent struct {
+	MoveRecord
+	Color    PlayerColor   `json:"color"`
+	Piece    ReplayPiece   `json:"piece"`
+	Captured []ReplayPiece `json:"captured,omitempty"` // Sent back to their yards
+}
+
+// Replay is a self-contained account of a finished game: who played, the board
+// they played on and every move, so it can be shared and viewed anywhere
+type Replay struct {
+	ArchivedGame
+	Geometry *BoardGeometry `json:"board_geometry"`
+	Events   []ReplayEvent  `json:"events"`
+}
+
+// Replay builds the public replay of an archived game
+func (a *Archive) Replay(id string) (*Replay, error) {
+	game, ok := a.Get(id)
+	if !ok || id == "" || (game.Events == nil && game.Moves > 0) {
+		return nil, ErrReplayNotFound
+	}
+	board, ok := BoardByType(game.Board)
+	if !ok {
+		return nil, ErrReplayNotFound
+	}
+	return buildReplay(game, board), nil
+}
+
+// buildReplay walks a game's moves, tracking every piece so each event can say
+// where its piece landed and which pieces it captured
+func buildReplay(game ArchivedGame, board *Board) *Replay {
+	geometry := board.Geometry()
+	replay := &Replay{ArchivedGame: game, Geometry: geometry, Events: []ReplayEvent{}}
+	replay.ArchivedGame.Events = nil
+
+	seats := make(map[PlayerColor]SeatGeometry)
+	for _, seat := range geometry.Seats {
+		seats[seat.Color] = seat
+	}
+	colors := make(map[string]PlayerColor)
+	for _, seat := range game.Seats {
+		colors[seat.PlayerID] = seat.Color
+	}
+
+	// Track square, or -1 in the yard; stretch squares are counted separately
+	type pieceState struct{ position, stretch int }
+	pieces := make(map[string]map[int]*pieceState)
+	pieceAt := func(playerID string, pieceID int) *pieceState {
+		if pieces[playerID] == nil {
+			pieces[playerID] = make(map[int]*pieceState)
+		}
+		p, exists := pieces[playerID][pieceID]
+		if !exists {
+			p = &pieceState{position: HomePosition}
+			pieces[playerID][pieceID] = p
+		}
+		return p
+	}
+	yardPoint := func(color PlayerColor, pieceID int) Point {
+		yard := seats[color].Yard
+		if pieceID >= 0 && pieceID < len(yard) {
+			return yard[pieceID]
+		}
+		return geometry.Center
+	}
+
+	for _, move := range game.Events {
+		color := colors[move.PlayerID]
+		piece := pieceAt(move.PlayerID, move.PieceID)
+		switch {
+		case move.WasFromHome:
+			piece.position = move.ToPos
+		case piece.stretch > 0:
+			piece.stretch += move.DiceRoll
+		default:
+			position, entered, stretch := board.Advance(color, piece.position, move.DiceRoll)
+			if entered {
+				piece.position, piece.stretch = -2, stretch
+			} else {
+				piece.position = position
+			}
+		}
+
+		event := ReplayEvent{MoveRecord: move, Color: color}
+		event.Piece = ReplayPiece{PlayerID: move.PlayerID, PieceID: move.PieceID}
+		switch stretch := seats[color].HomeStretch; {
+		case piece.stretch > 0 && len(stretch) > 0:
+			event.Piece.Point = stretch[min(piece.stretch, len(stretch))-1]
+		case piece.position >= 0 && piece.position < len(geometry.Track):
+			event.Piece.Point = geometry.Track[piece.position].Point
+		}
+
+		if move.WasCapture {
+			for victimID, victims := range pieces {
+				if victimID == move.PlayerID {
+					continue
+				}
+				for pieceID, victim := range victims {
+					if victim.stretch == 0 && victim.position == piece.position {
+						victim.position = HomePosition
+						event.Captured = append(event.Captured, ReplayPiece{
+							PlayerID: victimID,
+							PieceID:  pieceID,
+							Point:    yardPoint(colors[victimID], pieceID),
+						})
+					}
+				}
+			}
+			sort.Slice(event.Captured, func(i, j int) bool {
+				ci, cj := event.Captured[i], event.Captured[j]
+				if ci.PlayerID != cj.PlayerID {
+					return ci.PlayerID < cj.PlayerID
+				}
+				return ci.PieceID < cj.PieceID
+			})
+		}
+		replay.Events = append(replay.Events, event)
+	}
+	return replay
+}
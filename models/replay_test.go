@@ -0,0 +1,85 @@
+package models
+
+import (
+	"context"
+	"testing"
+)
+
+func TestVerifyReplay(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 2)
+	gm.JoinGame(context.Background(), game.Code, "player2", "Bob", "")
+	game.SetPlayerReady("host1", true)
+	game.SetPlayerReady("player2", true)
+	game.StartGame("host1")
+
+	current := game.CurrentTurn
+	game.HasRolled = true
+	game.LastDiceRoll = 6
+	if err := game.MovePiece(current, 0); err != nil {
+		t.Fatalf("Failed to move piece: %v", err)
+	}
+	game.HasRolled = true
+	game.LastDiceRoll = 4
+	if err := game.MovePiece(current, 0); err != nil {
+		t.Fatalf("Failed to move piece: %v", err)
+	}
+
+	result, err := gm.VerifyReplay(context.Background(), game.Code)
+	if err != nil {
+		t.Fatalf("Failed to verify replay: %v", err)
+	}
+	if !result.Valid || result.MovesReplayed != 2 {
+		t.Fatalf("Expected valid replay of 2 moves, got %+v", result)
+	}
+
+	// Corrupt the live board; the replay must catch it
+	game.Players[current].Pieces[0].Position += 1
+	if result := game.VerifyReplay(); result.Valid {
+		t.Error("Expected corrupted board to fail verification")
+	}
+}
+
+func TestArchivedReplay(t *testing.T) {
+	board := SquareBoard
+	redStart, blueStart := board.Start(Red), board.Start(Blue)
+	archive := NewArchive()
+	archive.Add(ArchivedGame{
+		ID:    "abc",
+		Board: BoardSquare,
+		Moves: 4,
+		Seats: []ArchivedSeat{{PlayerID: "p1", Color: Red}, {PlayerID: "p2", Color: Blue}},
+		Events: []MoveRecord{
+			{PlayerID: "p2", PieceID: 1, DiceRoll: 6, WasFromHome: true, ToPos: blueStart},
+			{PlayerID: "p2", PieceID: 1, DiceRoll: 1, FromPos: blueStart, ToPos: blueStart + 1},
+			{PlayerID: "p1", PieceID: 0, DiceRoll: 6, WasFromHome: true, ToPos: redStart},
+			{PlayerID: "p1", PieceID: 0, DiceRoll: blueStart + 1 - redStart, WasCapture: true, CapturedPID: "p2"},
+		},
+	})
+
+	if _, err := archive.Replay("missing"); err != ErrReplayNotFound {
+		t.Errorf("Expected ErrReplayNotFound, got %v", err)
+	}
+	replay, err := archive.Replay("abc")
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if len(replay.Events) != 4 || replay.Geometry == nil || replay.ArchivedGame.Events != nil {
+		t.Fatalf("Expected 4 events with the board geometry, got %+v", replay)
+	}
+
+	geometry := board.Geometry()
+	capture := replay.Events[3]
+	if capture.Color != Red || capture.Piece.Point != geometry.Track[blueStart+1].Point {
+		t.Errorf("Expected red to land next to blue's start, got %+v", capture.Piece)
+	}
+	var blueYard []Point
+	for _, seat := range geometry.Seats {
+		if seat.Color == Blue {
+			blueYard = seat.Yard
+		}
+	}
+	if len(capture.Captured) != 1 || capture.Captured[0].PieceID != 1 || capture.Captured[0].Point != blueYard[1] {
+		t.Errorf("Expected blue's piece 1 sent back to its yard, got %+v", capture.Captured)
+	}
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Turn length limits for a rule set
+const (
+	MinRuleTurnTimeout = 5 * time.Second
+	MaxRuleTurnTimeout = 10 * time.Minute
+)
+
+var ErrInvalidRules = errors.New("invalid rules")
+
+// RuleSet is the house rules a game is played by, chosen by the host before it
+// starts. Fields left out of a JSON rule set keep their standard values.
+type RuleSet struct {
+	RequireSixToLeave  bool `json:"require_six_to_leave"` // Only a 6 brings a piece out of the yard
+	ExactRollToFinish  bool `json:"exact_roll_to_finish"` // A roll overshooting the finish can't be played
+	CaptureGrantsTurn  bool `json:"capture_grants_turn"`  // Capturing earns another roll
+	SixGrantsTurn      bool `json:"six_grants_turn"`      // Rolling a 6 earns another roll
+	ThreeSixesForfeit  bool `json:"three_sixes_forfeit"`  // A third 6 in a row passes the turn
+	PiecesPerPlayer    int  `json:"pieces_per_player"`    // 1 to PiecesPerPlayer
+	TurnTimeoutSeconds int  `json:"turn_timeout_seconds"` // Time allowed per turn; 0 for unlimited
+	Blockades          bool `json:"blockades"`            // Two pieces of one color on a square block opponents
+}
+
+// DefaultRuleSet returns the standard rules
+func DefaultRuleSet() RuleSet {
+	return RuleSet{
+		RequireSixToLeave:  true,
+		ExactRollToFinish:  true,
+		CaptureGrantsTurn:  true,
+		SixGrantsTurn:      true,
+		ThreeSixesForfeit:  true,
+		PiecesPerPlayer:    PiecesPerPlayer,
+		TurnTimeoutSeconds: int(DefaultTurnTimeout / time.Second),
+	}
+}
+
+// UnmarshalJSON fills fields missing from the JSON with the standard rules
+func (r *RuleSet) UnmarshalJSON(data []byte) error {
+	type plain RuleSet
+	rules := plain(DefaultRuleSet())
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return err
+	}
+	*r = RuleSet(rules)
+	return nil
+}
+
+// Validate checks that a rule set can be played
+func (r RuleSet) Validate() error {
+	if r.PiecesPerPlayer < 1 || r.PiecesPerPlayer > PiecesPerPlayer {
+		return fmt.Errorf("%w: pieces_per_player must be between 1 and %d", ErrInvalidRules, PiecesPerPlayer)
+	}
+	if !validTurnTimeout(r.TurnTimeout()) {
+		return fmt.Errorf("%w: turn_timeout_seconds must be 0 (unlimited) or between %d and %d", ErrInvalidRules,
+			int(MinRuleTurnTimeout/time.Second), int(MaxRuleTurnTimeout/time.Second))
+	}
+	return nil
+}
+
+// TurnTimeout returns the time allowed per turn, 0 for unlimited
+func (r RuleSet) TurnTimeout() time.Duration {
+	return time.Duration(r.TurnTimeoutSeconds) * time.Second
+}
+
+// SetRules replaces the game's rules (host only, before the game starts).
+// Seated players get the rule set's number of pieces.
+func (g *Game) SetRules(hostID string, rules RuleSet) error {
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.HostID != hostID {
+		return ErrNotHost
+	}
+	if g.State != Waiting {
+		return ErrGameStarted
+	}
+
+	g.Rules = rules
+	g.CaptureGrantsTurn = rules.CaptureGrantsTurn
+	g.TurnTimeout = rules.TurnTimeout()
+	for _, player := range g.Players {
+		player.Pieces = homePieces(rules.PiecesPerPlayer)
+	}
+	g.LastActivity = Now()
+	return nil
+}
+
+// GetRules returns the rules the game is played by
+func (g *Game) GetRules() RuleSet {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.rulesLocked()
+}
+
+// rulesLocked returns the game's rules, with the capture bonus and turn length
+// as presets and experiments left them (caller must hold lock)
+func (g *Game) rulesLocked() RuleSet {
+	rules := g.Rules
+	rules.CaptureGrantsTurn = g.CaptureGrantsTurn
+	rules.TurnTimeoutSeconds = int(g.TurnTimeout / time.Second)
+	return rules
+}
+
+// canLeaveHome reports whether a roll brings a piece out of the yard
+func (r RuleSet) canLeaveHome(roll int) bool {
+	return roll == 6 || !r.RequireSixToLeave
+}
+
+// homePieces returns a full set of pieces in the yard
+func homePieces(count int) []Piece {
+	pieces := make([]Piece, count)
+	for i := range pieces {
+		pieces[i] = Piece{ID: i, Position: HomePosition, IsHome: true}
+	}
+	return pieces
+}
@@ -0,0 +1,161 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRuleSetJSONDefaults(t *testing.T) {
+	var rules RuleSet
+	if err := json.Unmarshal([]byte(`{"pieces_per_player": 2, "six_grants_turn": false}`), &rules); err != nil {
+		t.Fatalf("Failed to decode rules: %v", err)
+	}
+	want := DefaultRuleSet()
+	want.PiecesPerPlayer = 2
+	want.SixGrantsTurn = false
+	if rules != want {
+		t.Errorf("Expected %+v, got %+v", want, rules)
+	}
+
+	for _, bad := range []RuleSet{
+		{PiecesPerPlayer: 0, TurnTimeoutSeconds: 60},
+		{PiecesPerPlayer: PiecesPerPlayer + 1, TurnTimeoutSeconds: 60},
+		{PiecesPerPlayer: 4, TurnTimeoutSeconds: 1},
+		{PiecesPerPlayer: 4, TurnTimeoutSeconds: 3600},
+	} {
+		if err := bad.Validate(); !errors.Is(err, ErrInvalidRules) {
+			t.Errorf("Expected ErrInvalidRules for %+v, got %v", bad, err)
+		}
+	}
+}
+
+func TestSetRules(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+	rules := DefaultRuleSet()
+	rules.PiecesPerPlayer = 2
+	rules.CaptureGrantsTurn = false
+	rules.TurnTimeoutSeconds = 30
+
+	if err := game.SetRules("player2", rules); err != ErrNotHost {
+		t.Errorf("Expected ErrNotHost, got %v", err)
+	}
+	if err := game.SetRules("host1", rules); err != nil {
+		t.Fatalf("Failed to set rules: %v", err)
+	}
+	gm.JoinGame(context.Background(), game.Code, "player2", "Bob", "")
+
+	for id, player := range game.Players {
+		if len(player.Pieces) != 2 {
+			t.Errorf("Expected %s to have 2 pieces, got %d", id, len(player.Pieces))
+		}
+	}
+	if game.CaptureGrantsTurn || game.TurnTimeout != 30*time.Second {
+		t.Errorf("Expected the capture bonus off and 30s turns, got %v and %v", game.CaptureGrantsTurn, game.TurnTimeout)
+	}
+	if state := game.GetGameState(); state["rules"] != rules {
+		t.Errorf("Expected the state to show %+v, got %+v", rules, state["rules"])
+	}
+
+	game.SetPlayerReady("host1", true)
+	game.SetPlayerReady("player2", true)
+	if err := game.StartGame("host1"); err != nil {
+		t.Fatalf("Failed to start: %v", err)
+	}
+	if err := game.SetRules("host1", DefaultRuleSet()); err != ErrGameStarted {
+		t.Errorf("Expected ErrGameStarted, got %v", err)
+	}
+}
+
+func TestRuleSetVariants(t *testing.T) {
+	relaxed := RuleSet{PiecesPerPlayer: 4, TurnTimeoutSeconds: 60}
+	fixture := func(rules RuleSet, dice, sixes int, pieces ...FixturePiece) EngineState {
+		t.Helper()
+		state, err := Fixture{
+			MaxPlayers:       2,
+			Rules:            &rules,
+			Dice:             dice,
+			ConsecutiveSixes: sixes,
+			Players:          []FixturePlayer{{ID: "alice", Pieces: pieces}, {ID: "bob"}},
+		}.EngineState()
+		if err != nil {
+			t.Fatalf("Fixture rejected: %v", err)
+		}
+		return state
+	}
+
+	// Any roll brings a piece out of the yard
+	state := fixture(relaxed, 3, 0)
+	if moves := state.toGame().getValidMovesInternal("alice"); len(moves) != 4 {
+		t.Errorf("Expected every piece to leave home on a 3, got %v", moves)
+	}
+	if moves := fixture(DefaultRuleSet(), 3, 0).toGame().getValidMovesInternal("alice"); len(moves) != 0 {
+		t.Errorf("Expected no piece to leave home on a 3 under the standard rules, got %v", moves)
+	}
+
+	// Overshooting the finish still finishes
+	state = fixture(relaxed, 5, 0, FixturePiece{HomeStretch: 4})
+	next, err := ApplyEvent(state, EngineEvent{Type: EventMove, PlayerID: "alice", PieceID: 0})
+	if err != nil || !next.Players["alice"].Pieces[0].IsFinished {
+		t.Errorf("Expected the overshooting piece to finish, got %v and %+v", err, next.Players["alice"].Pieces[0])
+	}
+
+	// A six doesn't earn another roll
+	state = fixture(relaxed, 6, 1, FixturePiece{Position: intPtr(10)})
+	if next, err = ApplyEvent(state, EngineEvent{Type: EventMove, PlayerID: "alice", PieceID: 0}); err != nil || next.CurrentTurn != "bob" {
+		t.Errorf("Expected the turn to pass after a six, got %v and %q", err, next.CurrentTurn)
+	}
+
+	// A third six is played like any other roll
+	rules := DefaultRuleSet()
+	rules.ThreeSixesForfeit = false
+	state = fixture(rules, 0, 2, FixturePiece{Position: intPtr(10)})
+	if next, err = ApplyEvent(state, EngineEvent{Type: EventRoll, PlayerID: "alice", Roll: 6}); err != nil || next.CurrentTurn != "alice" {
+		t.Errorf("Expected alice to keep the turn after a third six, got %v and %q", err, next.CurrentTurn)
+	}
+}
+
+func TestBlockades(t *testing.T) {
+	rules := DefaultRuleSet()
+	rules.Blockades = true
+	fixture := func(dice int, alice ...FixturePiece) EngineState {
+		t.Helper()
+		state, err := Fixture{
+			MaxPlayers: 2,
+			Rules:      &rules,
+			Dice:       dice,
+			Players: []FixturePlayer{
+				{ID: "alice", Pieces: alice},
+				{ID: "bob", Pieces: []FixturePiece{{Position: intPtr(5)}, {Position: intPtr(5)}}},
+			},
+		}.EngineState()
+		if err != nil {
+			t.Fatalf("Fixture rejected: %v", err)
+		}
+		return state
+	}
+
+	game := fixture(4, FixturePiece{Position: intPtr(3)}).toGame()
+	if moves := game.getValidMovesInternal("alice"); len(moves) != 0 {
+		t.Errorf("Expected passing bob's blockade to be invalid, got %v", moves)
+	}
+	if err := game.movePieceLocked("alice", 0); err != ErrInvalidMove {
+		t.Errorf("Expected ErrInvalidMove, got %v", err)
+	}
+	if blockades := game.blockadesLocked(); len(blockades) != 1 || blockades[0].Position != 5 || blockades[0].PlayerID != "bob" {
+		t.Errorf("Expected bob's blockade on square 5, got %+v", blockades)
+	}
+
+	if moves := fixture(2, FixturePiece{Position: intPtr(2)}).toGame().getValidMovesInternal("alice"); len(moves) != 1 {
+		t.Errorf("Expected a move stopping short of the blockade, got %v", moves)
+	}
+
+	// Without the rule the pieces are just two pieces
+	rules.Blockades = false
+	if moves := fixture(4, FixturePiece{Position: intPtr(3)}).toGame().getValidMovesInternal("alice"); len(moves) != 1 {
+		t.Errorf("Expected no blockade without the rule, got %v", moves)
+	}
+}
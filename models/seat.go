@@ -0,0 +1,398 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DepartureAction decides what happens to a player's seat and pieces when they leave mid-game
+type DepartureAction string
+
+const (
+	DepartureRemove DepartureAction = "remove" // Pieces are taken off the board
+	DepartureHome   DepartureAction = "home"   // Pieces return to the yard
+	DepartureFreeze DepartureAction = "freeze" // Pieces stay where they are
+	DepartureBot    DepartureAction = "bot"    // A bot takes over the seat
+)
+
+var (
+	ErrGameEnded              = errors.New("game has ended")
+	ErrInvalidDepartureAction = errors.New("invalid departure action")
+	ErrHostNotActive          = errors.New("host must be an active player to kick mid-game")
+	ErrTakeoverNotFound       = errors.New("no request to take over a bot seat from this player")
+	ErrTooManyTakeovers       = errors.New("too many requests for bot seats are waiting for the host")
+)
+
+// ParseDepartureAction validates a departure action name
+func ParseDepartureAction(name string) (DepartureAction, error) {
+	switch a := DepartureAction(name); a {
+	case DepartureRemove, DepartureHome, DepartureFreeze, DepartureBot:
+		return a, nil
+	default:
+		return "", ErrInvalidDepartureAction
+	}
+}
+
+// MaxTakeoverRequests caps the requests for bot seats waiting for a host's answer
+const MaxTakeoverRequests = 10
+
+// TakeoverRequest is a player asking the host for a bot's seat
+type TakeoverRequest struct {
+	PlayerID    string    `json:"player_id"`
+	PlayerName  string    `json:"player_name"`
+	BotID       string    `json:"bot_id"`
+	RequestedAt Timestamp `json:"requested_at"`
+}
+
+// RequestBotTakeover asks the host to let a human replace a bot, inheriting its
+// color, order and pieces. Works in the lobby and mid-game, so short-handed games
+// can be filled later. Spectators need the game's password too, since the
+// request may not be signed. A player new to the game gets a session secret now,
+// returned only this once, so they can act once the host approves.
+func (gm *GameManager) RequestBotTakeover(ctx context.Context, code, botID, playerID, playerName, password string) (*Game, string, error) {
+	if err := ValidatePlayerID(playerID); err != nil {
+		return nil, "", err
+	}
+	if err := ValidatePlayerName(playerName); err != nil {
+		return nil, "", err
+	}
+	if gm.inMaintenance() {
+		return nil, "", ErrMaintenance
+	}
+
+	game, err := gm.GetGame(ctx, code)
+	if err != nil {
+		return nil, "", err
+	}
+
+	game.mu.Lock()
+	defer game.unlock()
+
+	if err := game.checkPasswordLocked(password); err != nil {
+		return nil, "", err
+	}
+	if err := game.checkTakeoverLocked(botID, playerID); err != nil {
+		return nil, "", err
+	}
+	if _, asked := game.takeovers[playerID]; !asked && len(game.takeovers) >= MaxTakeoverRequests {
+		return nil, "", ErrTooManyTakeovers
+	}
+
+	if game.takeovers == nil {
+		game.takeovers = make(map[string]TakeoverRequest)
+	}
+	game.takeovers[playerID] = TakeoverRequest{PlayerID: playerID, PlayerName: playerName, BotID: botID, RequestedAt: Now()}
+	secret := ""
+	if game.sessionSecrets[playerID] == "" {
+		secret = game.issueSessionSecret(playerID)
+	}
+	game.LastActivity = Now()
+	return game, secret, nil
+}
+
+// ApproveBotTakeover seats a player in the bot seat they asked for (host only)
+func (g *Game) ApproveBotTakeover(hostID, playerID string) (*Player, error) {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.HostID != hostID {
+		return nil, ErrNotHost
+	}
+	request, exists := g.takeovers[playerID]
+	if !exists {
+		return nil, ErrTakeoverNotFound
+	}
+	if err := g.checkTakeoverLocked(request.BotID, playerID); err != nil {
+		g.dropTakeoverLocked(playerID)
+		return nil, err
+	}
+
+	bot := g.Players[request.BotID]
+	player := &Player{
+		ID:           playerID,
+		Name:         request.PlayerName,
+		Color:        bot.Color,
+		Pieces:       bot.Pieces,
+		Order:        bot.Order,
+		LastActivity: Now(),
+		IsReady:      true,
+		Stats:        bot.Stats,
+		Rating:       g.seatRating(playerID),
+	}
+	g.replaceSeatLocked(request.BotID, player)
+
+	// Anyone else asking for the seat is too late
+	for id, other := range g.takeovers {
+		if other.BotID == request.BotID {
+			delete(g.takeovers, id)
+		}
+	}
+	// A spectator stepping in no longer watches from the sidelines
+	delete(g.Spectators, playerID)
+	return player, nil
+}
+
+// DeclineBotTakeover turns down a player's request for a bot seat (host only)
+func (g *Game) DeclineBotTakeover(hostID, playerID string) error {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.HostID != hostID {
+		return ErrNotHost
+	}
+	if _, exists := g.takeovers[playerID]; !exists {
+		return ErrTakeoverNotFound
+	}
+	g.dropTakeoverLocked(playerID)
+	g.LastActivity = Now()
+	return nil
+}
+
+// checkTakeoverLocked checks that a player may take a bot's seat (caller must hold lock)
+func (g *Game) checkTakeoverLocked(botID, playerID string) error {
+	if g.State == Ended {
+		return ErrGameEnded
+	}
+	if _, exists := g.Players[playerID]; exists {
+		return ErrPlayerExists
+	}
+	bot, exists := g.Players[botID]
+	if !exists {
+		return ErrPlayerNotFound
+	}
+	if !bot.IsBot {
+		return ErrNotBot
+	}
+	if bot.ExternallyControlled {
+		return ErrBotAlreadyClaimed
+	}
+	return nil
+}
+
+// dropTakeoverLocked forgets a request, and the secret it was issued unless the
+// player is still watching (caller must hold lock)
+func (g *Game) dropTakeoverLocked(playerID string) {
+	delete(g.takeovers, playerID)
+	if _, watching := g.Spectators[playerID]; !watching {
+		delete(g.sessionSecrets, playerID)
+		delete(g.usedNonces, playerID)
+	}
+}
+
+// takeoverRequestsLocked lists the requests waiting for the host, oldest first
+// (caller must hold lock)
+func (g *Game) takeoverRequestsLocked() []TakeoverRequest {
+	requests := make([]TakeoverRequest, 0, len(g.takeovers))
+	for _, request := range g.takeovers {
+		requests = append(requests, request)
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		if !requests[i].RequestedAt.Equal(requests[j].RequestedAt.Time) {
+			return requests[i].RequestedAt.Before(requests[j].RequestedAt.Time)
+		}
+		return requests[i].PlayerID < requests[j].PlayerID
+	})
+	return requests
+}
+
+// kickMidGameLocked removes a player from a game in progress (caller must hold lock).
+// The host must still be seated, and must say explicitly what happens to the pieces.
+func (g *Game) kickMidGameLocked(hostID, playerID string, action DepartureAction) error {
+	if host, exists := g.Players[hostID]; !exists || host.HasLeft {
+		return ErrHostNotActive
+	}
+
+	if _, err := ParseDepartureAction(string(action)); err != nil {
+		return err
+	}
+
+	player, exists := g.Players[playerID]
+	if !exists || player.HasLeft {
+		return ErrPlayerNotFound
+	}
+
+	g.departLocked(playerID, action)
+	g.LastActivity = Now()
+	return nil
+}
+
+// departLocked takes a player out of the rotation mid-game (caller must hold lock)
+func (g *Game) departLocked(playerID string, action DepartureAction) {
+	if action == DepartureBot {
+		g.replaceWithBotLocked(playerID)
+		return
+	}
+
+	g.recordEventLocked(EngineEvent{Type: EventLeave, PlayerID: playerID, Action: action})
+	player := g.Players[playerID]
+	player.HasLeft = true
+	g.applyDepartureLocked(player, action)
+	delete(g.sessionSecrets, playerID)
+	if g.HostID == playerID {
+		g.migrateHostLocked(player.Order)
+	}
+
+	if g.CurrentTurn == playerID {
+		g.nextTurn()
+		if g.State == Paused {
+			// Resuming adds the pause back, so the next player gets a full turn
+			g.TurnStartTime = g.PausedAt
+		}
+	}
+	g.endIfLastStandingLocked()
+}
+
+// endIfLastStandingLocked ends the game once at most one seat is still in play (caller must hold lock).
+// The remaining player wins; if nobody remains, the player furthest along wins.
+func (g *Game) endIfLastStandingLocked() {
+	var active []*Player
+	for _, p := range g.Players {
+		if !p.HasLeft {
+			active = append(active, p)
+		}
+	}
+	if len(active) > 1 {
+		return
+	}
+
+	var winner *Player
+	if len(active) == 1 {
+		winner = active[0]
+	} else {
+		best := -1
+		for _, p := range g.Players {
+			if progress := g.playerProgressLocked(p); progress > best || (progress == best && p.Order < winner.Order) {
+				best = progress
+				winner = p
+			}
+		}
+	}
+	if winner == nil {
+		return
+	}
+
+	g.State = Ended
+	g.Winner = winner.ID
+	g.HasRolled = false
+	g.recordResultLocked()
+	g.botReactToWinLocked(winner.ID)
+	g.commentOnWinLocked(winner.ID)
+}
+
+// playerProgressLocked sums how far a player's pieces have travelled (caller must hold lock)
+func (g *Game) playerProgressLocked(p *Player) int {
+	total := 0
+	for _, piece := range p.Pieces {
+		total += pieceProgress(p.Color, piece, g.MaxPlayers)
+	}
+	return total
+}
+
+// HasEnded reports whether the game is over
+func (g *Game) HasEnded() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.State == Ended
+}
+
+// applyDepartureLocked applies a departure action to a player's pieces (caller must hold lock)
+func (g *Game) applyDepartureLocked(player *Player, action DepartureAction) {
+	switch action {
+	case DepartureRemove, DepartureHome:
+		for i := range player.Pieces {
+			if player.Pieces[i].IsFinished {
+				continue
+			}
+			player.Pieces[i].Position = HomePosition
+			player.Pieces[i].HomeStretchPosition = 0
+			player.Pieces[i].IsHome = true
+			player.Pieces[i].IsSafe = false
+		}
+		player.PiecesRemoved = action == DepartureRemove
+	}
+}
+
+// SetDeparturePolicy sets what happens when a player leaves mid-game (host only)
+func (g *Game) SetDeparturePolicy(hostID string, policy DepartureAction) error {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.HostID != hostID {
+		return ErrNotHost
+	}
+	if _, err := ParseDepartureAction(string(policy)); err != nil {
+		return err
+	}
+	g.DeparturePolicy = policy
+	g.LastActivity = Now()
+	return nil
+}
+
+// replaceWithBotLocked hands a departing player's seat to a new bot (caller must hold lock).
+// The bot keeps playing from the same position so the remaining players aren't short-handed.
+func (g *Game) replaceWithBotLocked(playerID string) *Player {
+	player := g.Players[playerID]
+	wasHost := player.IsHost
+
+	name, _ := g.botNameLocked("")
+	bot := &Player{
+		ID:             fmt.Sprintf("bot_%d_%d", time.Now().UnixNano(), player.Order),
+		Name:           name,
+		Color:          player.Color,
+		Pieces:         player.Pieces,
+		Order:          player.Order,
+		LastActivity:   Now(),
+		IsReady:        true,
+		IsBot:          true,
+		BotPersonality: PersonalityBalanced,
+		BotDifficulty:  DifficultyMedium,
+		Stats:          player.Stats,
+	}
+	g.replaceSeatLocked(playerID, bot)
+
+	// Bots can't host; hand the role to a remaining human if there is one
+	if wasHost {
+		bot.IsHost = false
+		g.HostID = playerID
+		g.migrateHostLocked(bot.Order)
+	}
+	return bot
+}
+
+// replaceSeatLocked puts a new occupant in an existing seat and rewrites
+// every reference to the old ID so turns and replays stay consistent (caller must hold lock)
+func (g *Game) replaceSeatLocked(oldID string, occupant *Player) {
+	delete(g.Players, oldID)
+	g.Players[occupant.ID] = occupant
+
+	if g.CurrentTurn == oldID {
+		g.CurrentTurn = occupant.ID
+		g.TurnStartTime = Now()
+	}
+	if g.Winner == oldID {
+		g.Winner = occupant.ID
+	}
+	if g.HostID == oldID {
+		g.HostID = occupant.ID
+		occupant.IsHost = true
+	}
+	for i := range g.MoveHistory {
+		if g.MoveHistory[i].PlayerID == oldID {
+			g.MoveHistory[i].PlayerID = occupant.ID
+		}
+		if g.MoveHistory[i].CapturedPID == oldID {
+			g.MoveHistory[i].CapturedPID = occupant.ID
+		}
+	}
+	g.renameInHistoryLocked(oldID, occupant.ID)
+
+	delete(g.sessionSecrets, oldID)
+	delete(g.usedNonces, oldID)
+	delete(g.botControllers, oldID)
+	delete(g.chatReadAt, oldID)
+	delete(g.chatMutes, oldID)
+	g.LastActivity = Now()
+}
@@ -0,0 +1,341 @@
+package models
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTakeOverBotSeat(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 2)
+	_, bot, err := gm.AddBot(context.Background(), game.Code, "host1", BotOptions{})
+	if err != nil {
+		t.Fatalf("Failed to add bot: %v", err)
+	}
+	game.SetPlayerReady("host1", true)
+	game.StartGame("host1")
+	human := game.Players["host1"]
+
+	bot.Pieces[0].IsHome = false
+	bot.Pieces[0].Position = 7
+	game.MoveHistory = append(game.MoveHistory, MoveRecord{PlayerID: bot.ID, CapturedPID: human.ID})
+	game.CurrentTurn = bot.ID
+
+	game.SetPassword("host1", "secret")
+	if _, _, err := gm.RequestBotTakeover(context.Background(), game.Code, bot.ID, "friend1", "Friend", ""); err != ErrWrongPassword {
+		t.Errorf("Expected ErrWrongPassword, got %v", err)
+	}
+	if _, _, err := gm.RequestBotTakeover(context.Background(), game.Code, human.ID, "friend1", "Friend", "secret"); err != ErrNotBot {
+		t.Errorf("Expected ErrNotBot, got %v", err)
+	}
+	if _, _, err := gm.RequestBotTakeover(context.Background(), game.Code, bot.ID, human.ID, "Host", "secret"); err != ErrPlayerExists {
+		t.Errorf("Expected ErrPlayerExists, got %v", err)
+	}
+
+	if _, _, err := gm.RequestBotTakeover(context.Background(), game.Code, bot.ID, "friend1", "Friend", "secret"); err != nil {
+		t.Fatalf("Failed to ask for the seat: %v", err)
+	}
+	if _, exists := game.Players[bot.ID]; !exists {
+		t.Fatal("Bot should keep its seat until the host approves")
+	}
+	if _, err := game.ApproveBotTakeover("friend1", "friend1"); err != ErrNotHost {
+		t.Errorf("Expected ErrNotHost, got %v", err)
+	}
+	if _, err := game.ApproveBotTakeover("host1", "stranger"); err != ErrTakeoverNotFound {
+		t.Errorf("Expected ErrTakeoverNotFound, got %v", err)
+	}
+
+	player, err := game.ApproveBotTakeover("host1", "friend1")
+	if err != nil {
+		t.Fatalf("Failed to take over seat: %v", err)
+	}
+
+	if _, exists := game.Players[bot.ID]; exists {
+		t.Error("Bot should no longer be seated")
+	}
+	if player.IsBot || player.Color != bot.Color || player.Order != bot.Order {
+		t.Errorf("Seat not inherited: %+v", player)
+	}
+	if player.Pieces[0].Position != 7 {
+		t.Errorf("Expected pieces to transfer, got position %d", player.Pieces[0].Position)
+	}
+	if game.CurrentTurn != "friend1" {
+		t.Errorf("Expected turn to pass to new occupant, got %s", game.CurrentTurn)
+	}
+	if game.MoveHistory[0].PlayerID != "friend1" {
+		t.Errorf("Expected move history to follow the seat, got %s", game.MoveHistory[0].PlayerID)
+	}
+	if game.SessionSecret("friend1") == "" {
+		t.Error("Expected a session secret for the new occupant")
+	}
+	if len(game.takeovers) != 0 {
+		t.Errorf("Expected the request to be answered, got %v", game.takeovers)
+	}
+}
+
+func TestDeclineBotTakeover(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 2)
+	_, bot, _ := gm.AddBot(context.Background(), game.Code, "host1", BotOptions{})
+
+	if _, _, err := gm.RequestBotTakeover(context.Background(), game.Code, bot.ID, "friend1", "Friend", ""); err != nil {
+		t.Fatalf("Failed to ask for the seat: %v", err)
+	}
+	if err := game.DeclineBotTakeover("host1", "friend1"); err != nil {
+		t.Fatalf("Failed to decline: %v", err)
+	}
+	if _, err := game.ApproveBotTakeover("host1", "friend1"); err != ErrTakeoverNotFound {
+		t.Errorf("Expected ErrTakeoverNotFound after declining, got %v", err)
+	}
+	if game.SessionSecret("friend1") != "" {
+		t.Error("Expected the declined player's secret to be dropped")
+	}
+}
+
+func TestDeparturePolicyBot(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 3)
+	gm.JoinGame(context.Background(), game.Code, "p2", "Player 2", "")
+	gm.JoinGame(context.Background(), game.Code, "p3", "Player 3", "")
+	for id := range game.Players {
+		game.SetPlayerReady(id, true)
+	}
+	game.StartGame("host1")
+
+	if err := game.SetDeparturePolicy("p2", DepartureBot); err != ErrNotHost {
+		t.Errorf("Expected ErrNotHost, got %v", err)
+	}
+	if err := game.SetDeparturePolicy("host1", "vanish"); err != ErrInvalidDepartureAction {
+		t.Errorf("Expected ErrInvalidDepartureAction, got %v", err)
+	}
+	game.SetDeparturePolicy("host1", DepartureBot)
+
+	leaver := game.Players["host1"]
+	leaver.Pieces[0].IsHome = false
+	leaver.Pieces[0].Position = 12
+	game.CurrentTurn = "host1"
+
+	if err := game.LeaveGame("host1"); err != nil {
+		t.Fatalf("Failed to leave: %v", err)
+	}
+
+	if _, exists := game.Players["host1"]; exists {
+		t.Fatal("Leaver should have been replaced")
+	}
+	if len(game.Players) != 3 {
+		t.Fatalf("Expected 3 seats, got %d", len(game.Players))
+	}
+
+	bot := game.Players[game.CurrentTurn]
+	if bot == nil || !bot.IsBot {
+		t.Fatalf("Expected a bot to hold the turn, got %v", game.CurrentTurn)
+	}
+	if bot.Color != leaver.Color || bot.Pieces[0].Position != 12 {
+		t.Errorf("Bot did not inherit the seat: %+v", bot)
+	}
+	if bot.IsHost || game.HostID == bot.ID || !game.Players[game.HostID].IsHost {
+		t.Errorf("Expected host to move to a remaining human, got %s", game.HostID)
+	}
+}
+
+func TestLeaveWhilePaused(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 3)
+	gm.JoinGame(context.Background(), game.Code, "p2", "Player 2", "")
+	gm.JoinGame(context.Background(), game.Code, "p3", "Player 3", "")
+	for id := range game.Players {
+		game.SetPlayerReady(id, true)
+	}
+	game.StartGame("host1")
+	game.SetDeparturePolicy("host1", DepartureHome)
+	game.CurrentTurn = "p2"
+	leaver := game.Players["p2"]
+	leaver.Pieces[0].IsHome = false
+	leaver.Pieces[0].Position = 12
+
+	if err := game.PauseGame("host1"); err != nil {
+		t.Fatalf("Failed to pause: %v", err)
+	}
+	if err := game.LeaveGame("p2"); err != nil {
+		t.Fatalf("Failed to leave: %v", err)
+	}
+
+	if !leaver.HasLeft {
+		t.Error("Expected the player to have left")
+	}
+	if !leaver.Pieces[0].IsHome {
+		t.Error("Expected the departure policy to send the pieces home")
+	}
+	if game.CurrentTurn == "p2" {
+		t.Error("Expected the turn to move on from the leaver")
+	}
+	if game.State != Paused {
+		t.Errorf("Expected the game to stay paused, got %s", game.State)
+	}
+}
+
+func TestKickPlayerMidGame(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+	gm.JoinGame(context.Background(), game.Code, "p2", "Player 2", "")
+	gm.JoinGame(context.Background(), game.Code, "p3", "Player 3", "")
+	gm.JoinGame(context.Background(), game.Code, "p4", "Player 4", "")
+	for id := range game.Players {
+		game.SetPlayerReady(id, true)
+	}
+	game.StartGame("host1")
+
+	for _, id := range []string{"p2", "p3", "p4"} {
+		game.Players[id].Pieces[0].IsHome = false
+		game.Players[id].Pieces[0].Position = 20
+	}
+
+	if err := game.KickPlayer("p2", "p3", DepartureHome); err != ErrNotHost {
+		t.Errorf("Expected ErrNotHost, got %v", err)
+	}
+	if err := game.KickPlayer("host1", "p2", ""); err != ErrInvalidDepartureAction {
+		t.Errorf("Expected ErrInvalidDepartureAction, got %v", err)
+	}
+
+	if err := game.KickPlayer("host1", "p2", DepartureRemove); err != nil {
+		t.Fatalf("Failed to kick: %v", err)
+	}
+	p2 := game.Players["p2"]
+	if !p2.HasLeft || !p2.PiecesRemoved || !p2.Pieces[0].IsHome {
+		t.Errorf("Expected p2 removed from board: %+v", p2)
+	}
+
+	game.KickPlayer("host1", "p3", DepartureHome)
+	p3 := game.Players["p3"]
+	if !p3.Pieces[0].IsHome || p3.PiecesRemoved {
+		t.Errorf("Expected p3 pieces back home: %+v", p3)
+	}
+
+	if err := game.KickPlayer("host1", "p3", DepartureHome); err != ErrPlayerNotFound {
+		t.Errorf("Expected ErrPlayerNotFound for departed player, got %v", err)
+	}
+
+	game.Players["host1"].HasLeft = true
+	if err := game.KickPlayer("host1", "p4", DepartureHome); err != ErrHostNotActive {
+		t.Errorf("Expected ErrHostNotActive, got %v", err)
+	}
+	game.Players["host1"].HasLeft = false
+
+	game.KickPlayer("host1", "p4", DepartureFreeze)
+	if p4 := game.Players["p4"]; p4.Pieces[0].IsHome || p4.Pieces[0].Position != 20 {
+		t.Errorf("Expected p4 pieces frozen in place: %+v", p4.Pieces[0])
+	}
+}
+
+func TestDeparturePolicySkipsDepartedSeats(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 3)
+	gm.JoinGame(context.Background(), game.Code, "p2", "Player 2", "")
+	gm.JoinGame(context.Background(), game.Code, "p3", "Player 3", "")
+	for id := range game.Players {
+		game.SetPlayerReady(id, true)
+	}
+	game.StartGame("host1")
+	game.SetDeparturePolicy("host1", DepartureRemove)
+
+	byOrder := make(map[int]string)
+	for id, p := range game.Players {
+		byOrder[p.Order] = id
+	}
+	leaver := game.Players[byOrder[1]]
+	leaver.Pieces[0].IsHome = false
+	leaver.Pieces[0].Position = 30
+
+	game.LeaveGame(leaver.ID)
+	if !leaver.HasLeft || !leaver.PiecesRemoved || !leaver.Pieces[0].IsHome {
+		t.Errorf("Expected departure policy to remove pieces: %+v", leaver)
+	}
+
+	game.CurrentTurn = byOrder[0]
+	game.nextTurn()
+	if game.CurrentTurn != byOrder[2] {
+		t.Errorf("Expected turn to skip departed seat, got %s", game.CurrentTurn)
+	}
+	game.nextTurn()
+	if game.CurrentTurn != byOrder[0] {
+		t.Errorf("Expected turn to wrap around, got %s", game.CurrentTurn)
+	}
+}
+
+func TestLastPlayerStandingWins(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 3)
+	gm.JoinGame(context.Background(), game.Code, "p2", "Player 2", "")
+	gm.JoinGame(context.Background(), game.Code, "p3", "Player 3", "")
+	for id := range game.Players {
+		game.SetPlayerReady(id, true)
+	}
+	game.StartGame("host1")
+
+	game.LeaveGame("p2")
+	if game.HasEnded() {
+		t.Fatal("Game should continue with two players left")
+	}
+
+	game.KickPlayer("host1", "p3", DepartureFreeze)
+	if !game.HasEnded() {
+		t.Fatal("Expected game to end when one player remains")
+	}
+	if game.Winner != "host1" {
+		t.Errorf("Expected host1 to win, got %s", game.Winner)
+	}
+}
+
+func TestLastStandingFallsBackToProgress(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 2)
+	gm.JoinGame(context.Background(), game.Code, "p2", "Player 2", "")
+	game.SetPlayerReady("host1", true)
+	game.SetPlayerReady("p2", true)
+	game.StartGame("host1")
+
+	p2 := game.Players["p2"]
+	p2.Pieces[0].IsHome = false
+	p2.Pieces[0].Position = GetStartPosition(p2.Color, game.MaxPlayers) + 10
+
+	// Both seats empty at once: the player furthest along wins
+	game.mu.Lock()
+	game.Players["host1"].HasLeft = true
+	p2.HasLeft = true
+	game.endIfLastStandingLocked()
+	game.unlock()
+
+	if game.State != Ended || game.Winner != "p2" {
+		t.Errorf("Expected p2 to win on progress, got %s/%s", game.State, game.Winner)
+	}
+}
+
+func TestBotTakeoverSecretIssuedOnce(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 2)
+	_, bot, _ := gm.AddBot(context.Background(), game.Code, "host1", BotOptions{})
+	gm.JoinAsSpectator(context.Background(), game.Code, "viewer", "Viewer", "")
+	game.SetPassword("host1", "secret")
+
+	if _, _, err := gm.RequestBotTakeover(context.Background(), game.Code, bot.ID, "viewer", "Viewer", ""); err != ErrWrongPassword {
+		t.Errorf("Expected spectators to need the password, got %v", err)
+	}
+	_, secret, err := gm.RequestBotTakeover(context.Background(), game.Code, bot.ID, "viewer", "Viewer", "secret")
+	if err != nil {
+		t.Fatalf("Failed to ask for the seat: %v", err)
+	}
+	if secret != "" {
+		t.Error("Expected a spectator's existing secret not to be returned")
+	}
+
+	_, secret, err = gm.RequestBotTakeover(context.Background(), game.Code, bot.ID, "friend1", "Friend", "secret")
+	if err != nil {
+		t.Fatalf("Failed to ask for the seat: %v", err)
+	}
+	if secret == "" || secret != game.SessionSecret("friend1") {
+		t.Error("Expected a new requester to be issued a secret")
+	}
+	if _, secret, _ = gm.RequestBotTakeover(context.Background(), game.Code, bot.ID, "friend1", "Friend", "secret"); secret != "" {
+		t.Error("Expected asking again not to return the secret")
+	}
+}
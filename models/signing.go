@@ -0,0 +1,133 @@
+package models
+
+import (
+	"crypto/hmac"
+	crypto_rand "crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureWindow is how far the time in a signed nonce may be from the server's
+// clock. Nonces are remembered for as long, so each is accepted once at most.
+const SignatureWindow = 5 * time.Minute
+
+var (
+	ErrMissingSignature = errors.New("missing request signature")
+	ErrInvalidSignature = errors.New("invalid request signature")
+	ErrNonceReused      = errors.New("request nonce already used")
+	ErrStaleNonce       = errors.New("request nonce must start with the current time in Unix milliseconds")
+)
+
+// GenerateSessionSecret returns a random hex secret used to sign player actions
+func GenerateSessionSecret() string {
+	var b [32]byte
+	crypto_rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// SignAction computes the hex HMAC-SHA256 of (code, action, nonce) with the given secret
+func SignAction(secret, code, action, nonce string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(code + "|" + action + "|" + nonce))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// issueSessionSecret creates and stores a session secret for a participant (caller must hold lock)
+func (g *Game) issueSessionSecret(id string) string {
+	if g.sessionSecrets == nil {
+		g.sessionSecrets = make(map[string]string)
+	}
+	secret := GenerateSessionSecret()
+	g.sessionSecrets[id] = secret
+	return secret
+}
+
+// SessionSecret returns the session secret issued to a player or spectator
+func (g *Game) SessionSecret(id string) string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.sessionSecrets[id]
+}
+
+// nonceTime reads the Unix milliseconds a nonce starts with, as in "1718000000000-x7k2"
+func nonceTime(nonce string) (time.Time, bool) {
+	millis, _, _ := strings.Cut(nonce, "-")
+	ms, err := strconv.ParseInt(millis, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(ms), true
+}
+
+// CheckRetrySignature checks that a retried action was signed by the participant,
+// without consuming its nonce: the first attempt already used it
+func (g *Game) CheckRetrySignature(id, action, nonce, signature string) error {
+	if nonce == "" || signature == "" {
+		return ErrMissingSignature
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.checkSignatureLocked(id, action, nonce, signature)
+}
+
+// checkSignatureLocked compares a signature with the one the participant's
+// secret gives (caller must hold lock)
+func (g *Game) checkSignatureLocked(id, action, nonce, signature string) error {
+	secret, exists := g.sessionSecrets[id]
+	if !exists {
+		return ErrInvalidSignature
+	}
+
+	expected := SignAction(secret, g.Code, action, nonce)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifyActionSignature checks an action signature and rejects nonces that are
+// reused or whose time is outside SignatureWindow
+func (g *Game) VerifyActionSignature(id, action, nonce, signature string) error {
+	if nonce == "" || signature == "" {
+		return ErrMissingSignature
+	}
+
+	g.mu.Lock()
+	defer g.unlock()
+
+	if err := g.checkSignatureLocked(id, action, nonce, signature); err != nil {
+		return err
+	}
+
+	signedAt, ok := nonceTime(nonce)
+	now := time.Now()
+	if !ok || signedAt.Before(now.Add(-SignatureWindow)) || signedAt.After(now.Add(SignatureWindow)) {
+		return ErrStaleNonce
+	}
+
+	if g.usedNonces == nil {
+		g.usedNonces = make(map[string]map[string]time.Time)
+	}
+	used, exists := g.usedNonces[id]
+	if !exists {
+		used = make(map[string]time.Time)
+		g.usedNonces[id] = used
+	}
+	if _, seen := used[nonce]; seen {
+		return ErrNonceReused
+	}
+
+	// Nonces outside the window are rejected anyway, so they needn't be kept
+	for old, at := range used {
+		if at.Before(now.Add(-SignatureWindow)) {
+			delete(used, old)
+		}
+	}
+	used[nonce] = signedAt
+	return nil
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// testNonce returns a nonce signed at t, as clients make them
+func testNonce(t time.Time, suffix string) string {
+	return fmt.Sprintf("%d-%s", t.UnixMilli(), suffix)
+}
+
+func TestVerifyActionSignature(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+
+	secret := game.SessionSecret("host1")
+	if secret == "" {
+		t.Fatal("Expected host to be issued a session secret")
+	}
+
+	n1 := testNonce(time.Now(), "n1")
+	sig := SignAction(secret, game.Code, "/api/game/roll", n1)
+	if err := game.VerifyActionSignature("host1", "/api/game/roll", n1, sig); err != nil {
+		t.Fatalf("Expected valid signature, got %v", err)
+	}
+
+	if err := game.VerifyActionSignature("host1", "/api/game/roll", n1, sig); err != ErrNonceReused {
+		t.Errorf("Expected ErrNonceReused on replay, got %v", err)
+	}
+
+	n2 := testNonce(time.Now(), "n2")
+	wrongAction := SignAction(secret, game.Code, "/api/game/move", n2)
+	if err := game.VerifyActionSignature("host1", "/api/game/roll", n2, wrongAction); err != ErrInvalidSignature {
+		t.Errorf("Expected ErrInvalidSignature for mismatched action, got %v", err)
+	}
+
+	if err := game.VerifyActionSignature("host1", "/api/game/roll", "", ""); err != ErrMissingSignature {
+		t.Errorf("Expected ErrMissingSignature, got %v", err)
+	}
+
+	for _, nonce := range []string{"n3", testNonce(time.Now().Add(-SignatureWindow-time.Second), "old"), testNonce(time.Now().Add(SignatureWindow+time.Minute), "early")} {
+		if err := game.VerifyActionSignature("host1", "/api/game/roll", nonce, SignAction(secret, game.Code, "/api/game/roll", nonce)); err != ErrStaleNonce {
+			t.Errorf("Expected ErrStaleNonce for %q, got %v", nonce, err)
+		}
+	}
+}
+
+func TestCheckRetrySignature(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+	gm.JoinGame(context.Background(), game.Code, "p2", "Player 2", "")
+
+	nonce := testNonce(time.Now(), "n1")
+	sig := SignAction(game.SessionSecret("host1"), game.Code, "/api/game/roll", nonce)
+	if err := game.VerifyActionSignature("host1", "/api/game/roll", nonce, sig); err != nil {
+		t.Fatalf("Expected valid signature, got %v", err)
+	}
+
+	// A retry resends the used nonce
+	if err := game.CheckRetrySignature("host1", "/api/game/roll", nonce, sig); err != nil {
+		t.Errorf("Expected the retry's signature to check out, got %v", err)
+	}
+	if err := game.CheckRetrySignature("p2", "/api/game/roll", nonce, sig); err != ErrInvalidSignature {
+		t.Errorf("Expected ErrInvalidSignature for another player, got %v", err)
+	}
+	if err := game.CheckRetrySignature("host1", "/api/game/roll", "", ""); err != ErrMissingSignature {
+		t.Errorf("Expected ErrMissingSignature, got %v", err)
+	}
+}
+
+func TestUsedNoncesSurviveRestart(t *testing.T) {
+	ctx := context.Background()
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(ctx, "host1", "Host", 4)
+	secret := game.SessionSecret("host1")
+	nonce := testNonce(time.Now(), "n1")
+	sig := SignAction(secret, game.Code, "/api/game/roll", nonce)
+	if err := game.VerifyActionSignature("host1", "/api/game/roll", nonce, sig); err != nil {
+		t.Fatalf("Expected valid signature, got %v", err)
+	}
+
+	data, err := game.marshalSnapshot()
+	if err != nil {
+		t.Fatalf("marshalSnapshot failed: %v", err)
+	}
+	restarted := NewGameManager()
+	restarted.mu.Lock()
+	restored, err := restarted.unmarshalSnapshotLocked(data)
+	restarted.mu.Unlock()
+	if err != nil || restored == nil {
+		t.Fatalf("unmarshalSnapshotLocked failed: %v", err)
+	}
+	if err := restored.VerifyActionSignature("host1", "/api/game/roll", nonce, sig); err != ErrNonceReused {
+		t.Errorf("Expected the nonce still used after a restart, got %v", err)
+	}
+}
+
+func TestSpectatingAgainKeepsSecret(t *testing.T) {
+	ctx := context.Background()
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(ctx, "host1", "Host", 4)
+	gm.JoinAsSpectator(ctx, game.Code, "viewer", "Viewer", "")
+	secret := game.SessionSecret("viewer")
+
+	if _, err := gm.JoinAsSpectator(ctx, game.Code, "viewer", "Impostor", ""); err != ErrPlayerExists {
+		t.Errorf("Expected ErrPlayerExists, got %v", err)
+	}
+	if game.SessionSecret("viewer") != secret {
+		t.Error("Expected the spectator's secret to be kept")
+	}
+}
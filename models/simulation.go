@@ -0,0 +1,62 @@
+package models
+
+import (
+	"errors"
+)
+
+// MaxFastForwardTurns bounds a fast-forward so a stuck game cannot spin forever
+const MaxFastForwardTurns = 10000
+
+// ErrHumansRemaining is returned when fast-forwarding a game that still has active humans
+var ErrHumansRemaining = errors.New("fast-forward requires all remaining players to be bots")
+
+// FastForwardResult summarizes a synchronous simulation of the rest of a game
+type FastForwardResult struct {
+	TurnsSimulated int    `json:"turns_simulated"`
+	Winner         string `json:"winner,omitempty"`
+	Finished       bool   `json:"finished"`
+}
+
+// FastForward plays out the rest of a game at engine speed when every
+// remaining player is a bot (host only)
+func (g *Game) FastForward(hostID string) (*FastForwardResult, error) {
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.HostID != hostID {
+		return nil, ErrNotHost
+	}
+	return g.fastForwardLocked()
+}
+
+// AdminFastForward plays out a bot-only game without a host check
+func (g *Game) AdminFastForward() (*FastForwardResult, error) {
+	g.mu.Lock()
+	defer g.unlock()
+	return g.fastForwardLocked()
+}
+
+// fastForwardLocked runs bot turns until the game ends (caller must hold lock)
+func (g *Game) fastForwardLocked() (*FastForwardResult, error) {
+	if g.State != Playing {
+		return nil, ErrGameNotPlaying
+	}
+
+	for _, player := range g.Players {
+		if !player.IsBot && !player.HasLeft {
+			return nil, ErrHumansRemaining
+		}
+	}
+
+	result := &FastForwardResult{}
+	for g.State == Playing && result.TurnsSimulated < MaxFastForwardTurns {
+		if err := g.autoPlayTurnLocked(g.CurrentTurn); err != nil {
+			break // Nothing more can be played
+		}
+		result.TurnsSimulated++
+	}
+
+	result.Winner = g.Winner
+	result.Finished = g.State == Ended
+	return result, nil
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFastForwardBotOnlyGame(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+	gm.AddBot(context.Background(), game.Code, "host1", BotOptions{})
+	gm.AddBot(context.Background(), game.Code, "host1", BotOptions{})
+	game.SetPlayerReady("host1", true)
+	game.StartGame("host1")
+
+	if _, err := game.FastForward("host1"); err != ErrHumansRemaining {
+		t.Fatalf("Expected ErrHumansRemaining while host is playing, got %v", err)
+	}
+
+	game.LeaveGame("host1")
+
+	result, err := game.FastForward("host1")
+	if err != nil {
+		t.Fatalf("Failed to fast-forward: %v", err)
+	}
+	if !result.Finished || game.State != Ended {
+		t.Fatalf("Expected game to finish, got %+v", result)
+	}
+	if winner := game.Players[result.Winner]; winner == nil {
+		t.Errorf("Expected a winner from the simulated players, got %q", result.Winner)
+	}
+}
+
+func TestFastForwardRevealsPendingRoll(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+	gm.AddBot(context.Background(), game.Code, "host1", BotOptions{})
+	gm.AddBot(context.Background(), game.Code, "host1", BotOptions{})
+	game.SetPlayerReady("host1", true)
+	game.StartGame("host1")
+	game.LeaveGame("host1")
+
+	// A two-phase roll still animating when the game is fast-forwarded
+	game.pendingRoll = &PendingRoll{RollID: "r1", PlayerID: game.CurrentTurn, value: 3, acks: make(map[string]bool)}
+
+	result, err := game.FastForward("host1")
+	if err != nil {
+		t.Fatalf("Failed to fast-forward: %v", err)
+	}
+	if !result.Finished || result.TurnsSimulated >= MaxFastForwardTurns {
+		t.Fatalf("Expected the pending roll revealed and the game finished, got %+v", result)
+	}
+	if game.pendingRoll != nil {
+		t.Error("Expected no roll left pending")
+	}
+}
@@ -0,0 +1,194 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+)
+
+// SnapshotVersion is bumped whenever the snapshot format changes incompatibly
+const SnapshotVersion = 1
+
+var ErrSnapshotVersion = errors.New("unsupported snapshot version")
+
+// Snapshot holds every live game so a restarted server can pick up where it left off
+type Snapshot struct {
+	Version int               `json:"version"`
+	SavedAt Timestamp         `json:"saved_at"`
+	Games   []json.RawMessage `json:"games"` // One gameSnapshot each
+	Archive []ArchivedGame    `json:"archive,omitempty"`
+	Stats   []CareerStats     `json:"stats,omitempty"`
+}
+
+// gameSnapshot adds the state a game keeps out of its JSON form
+type gameSnapshot struct {
+	Game            *Game                           `json:"game"`
+	TurnTimeout     *time.Duration                  `json:"turn_timeout"` // 0 for unlimited; missing in older saves
+	MaxPauseLength  time.Duration                   `json:"max_pause_length"`
+	DiceRevealDelay time.Duration                   `json:"dice_reveal_delay,omitempty"`
+	BotTakeover     time.Duration                   `json:"bot_takeover,omitempty"`
+	SessionSecrets  map[string]string               `json:"session_secrets,omitempty"`
+	UsedNonces      map[string]map[string]time.Time `json:"used_nonces,omitempty"` // So signed requests can't be replayed after a restart
+	PasswordHash    []byte                          `json:"password_hash,omitempty"`
+	History         []HistoryEvent                  `json:"history,omitempty"`
+	HistoryStart    *EngineState                    `json:"history_start,omitempty"`
+	ChatSeq         int                             `json:"chat_seq"`
+	ChatReadAt      map[string]time.Time            `json:"chat_read_at,omitempty"`
+	ChatMutes       map[string]time.Time            `json:"chat_mutes,omitempty"`
+}
+
+// WriteSnapshot writes every game that hasn't been deleted. Returns how many were saved.
+// Nothing is written if ctx ends before every game has been serialized.
+func (gm *GameManager) WriteSnapshot(ctx context.Context, w io.Writer) (int, error) {
+	snapshot := Snapshot{Version: SnapshotVersion, SavedAt: Now(), Archive: gm.archive.Games(), Stats: gm.stats.All()}
+
+	for _, game := range gm.GetAllGames() {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		data, err := game.marshalSnapshot()
+		if err != nil {
+			return 0, err
+		}
+		snapshot.Games = append(snapshot.Games, data)
+	}
+
+	if err := json.NewEncoder(w).Encode(snapshot); err != nil {
+		return 0, err
+	}
+	return len(snapshot.Games), nil
+}
+
+// LoadSnapshot rehydrates games from a snapshot. Games whose code is already
+// in use are skipped. Returns the codes of the games loaded, which are only
+// some of them if ctx ends part way through.
+func (gm *GameManager) LoadSnapshot(ctx context.Context, r io.Reader) ([]string, error) {
+	var snapshot Snapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, err
+	}
+	if snapshot.Version != SnapshotVersion {
+		return nil, ErrSnapshotVersion
+	}
+
+	for _, game := range snapshot.Archive {
+		gm.archive.Add(game)
+	}
+	gm.stats.Restore(snapshot.Stats)
+
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+
+	var loaded []string
+	for _, data := range snapshot.Games {
+		if err := ctx.Err(); err != nil {
+			return loaded, err
+		}
+		game, err := gm.unmarshalSnapshotLocked(data)
+		if err != nil {
+			return loaded, err
+		}
+		if game == nil {
+			continue
+		}
+		gm.games[game.Code] = game
+		loaded = append(loaded, game.Code)
+	}
+	return loaded, nil
+}
+
+// marshalSnapshot serializes a game with the state it keeps out of its JSON form
+func (g *Game) marshalSnapshot() ([]byte, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return json.Marshal(gameSnapshot{
+		Game:            g,
+		TurnTimeout:     &g.TurnTimeout,
+		MaxPauseLength:  g.MaxPauseLength,
+		DiceRevealDelay: g.DiceRevealDelay,
+		BotTakeover:     g.BotTakeoverDelay,
+		SessionSecrets:  g.sessionSecrets,
+		UsedNonces:      g.usedNonces,
+		PasswordHash:    g.passwordHash,
+		History:         g.history,
+		HistoryStart:    g.historyStart,
+		ChatSeq:         g.chatSeq,
+		ChatReadAt:      g.chatReadAt,
+		ChatMutes:       g.chatMutes,
+	})
+}
+
+// unmarshalSnapshotLocked rebuilds a game serialized by marshalSnapshot. Returns
+// nil without an error if its code is already in use (caller must hold gm.mu).
+func (gm *GameManager) unmarshalSnapshotLocked(data []byte) (*Game, error) {
+	var gs gameSnapshot
+	if err := json.Unmarshal(data, &gs); err != nil {
+		return nil, err
+	}
+	game := gs.Game
+	if game == nil || game.Code == "" || gm.games[game.Code] != nil {
+		return nil, nil
+	}
+
+	game.TurnTimeout = DefaultTurnTimeout
+	if gs.TurnTimeout != nil && *gs.TurnTimeout >= 0 {
+		game.TurnTimeout = *gs.TurnTimeout
+	}
+	if game.Rules == (RuleSet{}) { // Saved before rule sets existed
+		game.Rules = DefaultRuleSet()
+	}
+	game.MaxPauseLength = gs.MaxPauseLength
+	if game.MaxPauseLength <= 0 { // Saved before pause limits existed
+		game.MaxPauseLength = DefaultMaxPauseLength
+		game.PauseBudget = DefaultPauseBudget
+	}
+	if game.AFKAction == "" { // Saved before AFK removal existed; it stays off
+		game.AFKAction = DepartureBot
+	}
+	game.DiceRevealDelay = gs.DiceRevealDelay
+	game.BotTakeoverDelay = gs.BotTakeover
+	game.ReconnectGrace = gm.reconnectGrace
+	game.archive = gm.archive
+	game.stats = gm.stats
+	game.persister = gm.persister
+	game.sessionSecrets = gs.SessionSecrets
+	game.usedNonces = gs.UsedNonces
+	game.passwordHash = gs.PasswordHash
+	game.history = gs.History
+	game.historyStart = gs.HistoryStart
+	game.chatSeq = gs.ChatSeq
+	game.chatReadAt = gs.ChatReadAt
+	game.chatMutes = gs.ChatMutes
+	game.chatFilter = gm.chatFilter
+	if game.Spectators == nil {
+		game.Spectators = make(map[string]*Spectator)
+	}
+
+	// External bot controllers don't survive a restart; the built-in AI takes over
+	for _, p := range game.Players {
+		p.ExternallyControlled = false
+	}
+	return game, nil
+}
+
+// GamesInProgress counts started games that still have a human playing.
+// Bot-only games don't hold up a restart.
+func (gm *GameManager) GamesInProgress() int {
+	count := 0
+	for _, game := range gm.GetAllGames() {
+		game.mu.RLock()
+		if game.State == Playing || game.State == Paused {
+			for _, p := range game.Players {
+				if !p.IsBot && !p.HasLeft {
+					count++
+					break
+				}
+			}
+		}
+		game.mu.RUnlock()
+	}
+	return count
+}
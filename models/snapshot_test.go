@@ -0,0 +1,73 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+	gm.JoinGame(context.Background(), game.Code, "player2", "Player 2", "")
+	game.SetPlayerReady("host1", true)
+	game.SetPlayerReady("player2", true)
+	if err := game.StartGame("host1"); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+	game.SendChatMessage("host1", "brb")
+	gm.PauseAllForMaintenance()
+
+	var buf bytes.Buffer
+	saved, err := gm.WriteSnapshot(context.Background(), &buf)
+	if err != nil || saved != 1 {
+		t.Fatalf("Expected 1 game saved, got %d (%v)", saved, err)
+	}
+
+	restored := NewGameManager()
+	loaded, err := restored.LoadSnapshot(context.Background(), bytes.NewReader(buf.Bytes()))
+	if err != nil || len(loaded) != 1 {
+		t.Fatalf("Expected 1 game loaded, got %v (%v)", loaded, err)
+	}
+
+	got, err := restored.GetGame(context.Background(), game.Code)
+	if err != nil {
+		t.Fatalf("Restored game missing: %v", err)
+	}
+	if got.CurrentTurn != game.CurrentTurn || len(got.Players) != 2 || got.TurnTimeout != game.TurnTimeout {
+		t.Errorf("Restored game differs: turn %s, %d players, timeout %v", got.CurrentTurn, len(got.Players), got.TurnTimeout)
+	}
+	if got.SessionSecret("host1") != game.SessionSecret("host1") {
+		t.Error("Session secrets should survive a restart")
+	}
+	if msg, _ := got.SendChatMessage("player2", "back"); msg.ID != 2 {
+		t.Errorf("Chat IDs should continue after restore, got %d", msg.ID)
+	}
+
+	if resumed := restored.EndMaintenance(); len(resumed) != 1 || got.State != Playing {
+		t.Errorf("Expected the maintenance-paused game to resume, got %v", resumed)
+	}
+
+	// Loading the same snapshot twice doesn't clobber live games
+	if again, _ := restored.LoadSnapshot(context.Background(), bytes.NewReader(buf.Bytes())); len(again) != 0 {
+		t.Errorf("Expected existing games to be skipped, got %v", again)
+	}
+}
+
+func TestGamesInProgressIgnoresBotOnlyGames(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 2)
+	gm.AddBot(context.Background(), game.Code, "host1", BotOptions{})
+	game.SetPlayerReady("host1", true)
+	if err := game.StartGame("host1"); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+	if n := gm.GamesInProgress(); n != 1 {
+		t.Fatalf("Expected 1 game in progress, got %d", n)
+	}
+
+	game.LeaveGame("host1")
+	if n := gm.GamesInProgress(); n != 0 {
+		t.Errorf("Bot-only games shouldn't block a restart, got %d", n)
+	}
+}
@@ -0,0 +1,104 @@
+package models
+
+import "sort"
+
+// Standing is one player's running record across a game and its rematches.
+// Each finished game gives a point for every seat a player finished ahead of.
+type Standing struct {
+	PlayerID string `json:"player_id"`
+	Name     string `json:"name"`
+	Played   int    `json:"played"`
+	Wins     int    `json:"wins"`
+	Points   int    `json:"points"`
+}
+
+// Standings returns the running scoreboard, leader first
+func (g *Game) Standings() []Standing {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.standingsLocked()
+}
+
+// standingsLocked sorts the scoreboard by points, then wins (caller must hold lock)
+func (g *Game) standingsLocked() []Standing {
+	standings := make([]Standing, 0, len(g.Scoreboard))
+	for _, s := range g.Scoreboard {
+		standing := *s
+		if p, exists := g.Players[s.PlayerID]; exists {
+			standing.Name = p.Name // Follow renames
+		}
+		standings = append(standings, standing)
+	}
+	sort.Slice(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		return a.Name < b.Name
+	})
+	return standings
+}
+
+// finishOrderLocked ranks the seats of an ended game: the winner, then everyone
+// still playing by how far their pieces got, then those who left (caller must hold lock)
+func (g *Game) finishOrderLocked() []*Player {
+	order := make([]*Player, 0, len(g.Players))
+	progress := make(map[string]int, len(g.Players))
+	for _, p := range g.Players {
+		order = append(order, p)
+		progress[p.ID] = g.playerProgressLocked(p)
+	}
+	sort.Slice(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if (a.ID == g.Winner) != (b.ID == g.Winner) {
+			return a.ID == g.Winner
+		}
+		if a.HasLeft != b.HasLeft {
+			return !a.HasLeft
+		}
+		if progress[a.ID] != progress[b.ID] {
+			return progress[a.ID] > progress[b.ID]
+		}
+		return a.Order < b.Order
+	})
+	return order
+}
+
+// recordResultLocked adds a game that just ended to the standings and the archive
+// (caller must hold lock)
+func (g *Game) recordResultLocked() {
+	if g.Scoreboard == nil {
+		g.Scoreboard = make(map[string]*Standing)
+	}
+	order := g.finishOrderLocked()
+	for place, p := range order {
+		s, exists := g.Scoreboard[p.ID]
+		if !exists {
+			s = &Standing{PlayerID: p.ID}
+			g.Scoreboard[p.ID] = s
+		}
+		s.Name = p.Name
+		s.Played++
+		s.Points += len(order) - 1 - place
+		if p.ID == g.Winner {
+			s.Wins++
+		}
+	}
+	g.SeriesGames++
+
+	if g.archive != nil {
+		summary := g.archiveLocked()
+		g.ReplayID = summary.ID
+		g.archive.Add(summary)
+		if g.stats != nil {
+			g.applyRatingsLocked(g.stats.Record(summary))
+		}
+		g.archive.keep(summary, func() ([]byte, error) {
+			return g.archiveRecordLocked(summary)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package models
+
+import "testing"
+
+// endTestGame ends a game with the given winner, as the last move would
+func endTestGame(game *Game, winner string) {
+	game.mu.Lock()
+	defer game.unlock()
+	game.State = Ended
+	game.Winner = winner
+	game.recordResultLocked()
+}
+
+func TestStandingsAcrossRematches(t *testing.T) {
+	game := newPauseTestGame(t)
+	if len(game.Standings()) != 0 {
+		t.Fatal("Expected no standings before a game has finished")
+	}
+
+	// p3 is furthest along of the losers in the first game
+	game.Players["p3"].Pieces[0].IsHome = false
+	game.Players["p3"].Pieces[0].Position = GetStartPosition(game.Players["p3"].Color, game.MaxPlayers)
+	endTestGame(game, "p1")
+
+	if err := game.Rematch("p1"); err != nil {
+		t.Fatalf("Rematch failed: %v", err)
+	}
+	endTestGame(game, "p2")
+
+	standings := game.Standings()
+	if len(standings) != 3 || game.SeriesGames != 2 {
+		t.Fatalf("Expected 3 standings after 2 games, got %d after %d", len(standings), game.SeriesGames)
+	}
+	points := map[string]int{}
+	for _, s := range standings {
+		if s.Played != 2 {
+			t.Errorf("Expected %s to have played 2, got %d", s.PlayerID, s.Played)
+		}
+		points[s.PlayerID] = s.Points
+	}
+	// Game 1: p1 2, p3 1, p2 0. Game 2: p2 2, then p1 and p3 level on progress, split by turn order.
+	if points["p1"]+points["p2"]+points["p3"] != 6 {
+		t.Errorf("Expected 6 points handed out, got %v", points)
+	}
+	if points["p1"] < 2 || points["p2"] != 2 {
+		t.Errorf("Unexpected points %v", points)
+	}
+	if standings[0].Points < standings[1].Points || standings[1].Points < standings[2].Points {
+		t.Errorf("Standings not sorted: %+v", standings)
+	}
+
+	game.Players["p2"].Name = "Renamed"
+	for _, s := range game.Standings() {
+		if s.PlayerID == "p2" && s.Name != "Renamed" {
+			t.Errorf("Expected standings to follow renames, got %q", s.Name)
+		}
+	}
+}
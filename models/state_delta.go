@@ -0,0 +1,88 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Clients following a game over WebSocket can be sent only what changed in its
+// state. Changes are JSON merge patches (RFC 7396) between two versions of the
+// state: changed fields carry their new value, removed and nulled fields carry
+// null, and objects such as players are patched field by field.
+
+// StateSnapshot returns the state as last published, in its JSON form, with its
+// version. Unlike GetGameState it leaves out the time left on the turn, which
+// changes without the state changing.
+func (g *Game) StateSnapshot() (uint64, map[string]interface{}, error) {
+	snapshot := g.state.Load()
+	if snapshot == nil {
+		// Nothing has changed since the game was created or loaded
+		g.mu.RLock()
+		state := g.gameStateLocked()
+		g.mu.RUnlock()
+		snapshot = &state
+	}
+
+	version, _ := (*snapshot)["state_version"].(uint64)
+	state, err := NormalizeState(*snapshot)
+	return version, state, err
+}
+
+// NormalizeState converts a state to the plain maps, slices and values it has
+// once encoded as JSON, so two states can be compared field by field
+func NormalizeState(state map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	var normalized map[string]interface{}
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
+
+// DiffStates returns the merge patch turning one normalized state into another;
+// empty when they are the same
+func DiffStates(from, to map[string]interface{}) map[string]interface{} {
+	patch := make(map[string]interface{})
+	for key, value := range to {
+		old, exists := from[key]
+		if !exists {
+			patch[key] = value
+			continue
+		}
+		oldObject, oldIsObject := old.(map[string]interface{})
+		newObject, newIsObject := value.(map[string]interface{})
+		if oldIsObject && newIsObject {
+			if changes := DiffStates(oldObject, newObject); len(changes) > 0 {
+				patch[key] = changes
+			}
+			continue
+		}
+		if !reflect.DeepEqual(old, value) {
+			patch[key] = value
+		}
+	}
+	for key := range from {
+		if _, exists := to[key]; !exists {
+			patch[key] = nil
+		}
+	}
+	return patch
+}
+
+// ApplyStatePatch applies a merge patch to a normalized state in place
+func ApplyStatePatch(state, patch map[string]interface{}) {
+	for key, value := range patch {
+		if value == nil {
+			delete(state, key)
+			continue
+		}
+		changes, isObject := value.(map[string]interface{})
+		target, targetIsObject := state[key].(map[string]interface{})
+		if isObject && targetIsObject {
+			ApplyStatePatch(target, changes)
+			continue
+		}
+		state[key] = value
+	}
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStateSnapshotVersions(t *testing.T) {
+	game := newEngineTestGame(t)
+
+	version, state, err := game.StateSnapshot()
+	if err != nil {
+		t.Fatalf("Failed to snapshot: %v", err)
+	}
+	if version == 0 || state["state_version"] != float64(version) {
+		t.Errorf("Expected the state to carry its version %d, got %v", version, state["state_version"])
+	}
+	if _, ok := state["turn_remaining_ms"]; ok {
+		t.Error("Snapshots shouldn't carry the time left on the turn")
+	}
+
+	game.SendChatMessage("host1", "hello")
+	next, changed, _ := game.StateSnapshot()
+	if next <= version {
+		t.Errorf("Expected a new version after a change, got %d then %d", version, next)
+	}
+
+	// Reading doesn't bump the version
+	if again, _, _ := game.StateSnapshot(); again != next {
+		t.Errorf("Expected version %d to hold, got %d", next, again)
+	}
+
+	patch := DiffStates(state, changed)
+	if _, ok := patch["players"]; ok {
+		t.Errorf("Expected unchanged players left out of the patch, got %v", patch["players"])
+	}
+	ApplyStatePatch(state, patch)
+	if !reflect.DeepEqual(state, changed) {
+		t.Errorf("Applying the patch should give the new state\ngot  %v\nwant %v", state, changed)
+	}
+}
+
+func TestDiffStates(t *testing.T) {
+	from := map[string]interface{}{
+		"turn":    "p1",
+		"dice":    float64(3),
+		"paused":  "p2",
+		"players": map[string]interface{}{"p1": map[string]interface{}{"pieces": []interface{}{float64(-1), float64(4)}, "name": "A"}},
+	}
+	to := map[string]interface{}{
+		"turn":    "p2",
+		"dice":    float64(3),
+		"winner":  "",
+		"players": map[string]interface{}{"p1": map[string]interface{}{"pieces": []interface{}{float64(-1), float64(9)}, "name": "A"}},
+	}
+
+	patch := DiffStates(from, to)
+	want := map[string]interface{}{
+		"turn":    "p2",
+		"winner":  "",
+		"paused":  nil,
+		"players": map[string]interface{}{"p1": map[string]interface{}{"pieces": []interface{}{float64(-1), float64(9)}}},
+	}
+	if !reflect.DeepEqual(patch, want) {
+		t.Errorf("Unexpected patch\ngot  %v\nwant %v", patch, want)
+	}
+
+	ApplyStatePatch(from, patch)
+	if !reflect.DeepEqual(from, to) {
+		t.Errorf("Applying the patch should give the new state\ngot  %v\nwant %v", from, to)
+	}
+	if len(DiffStates(to, to)) != 0 {
+		t.Error("Expected an empty patch between equal states")
+	}
+}
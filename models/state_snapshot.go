@@ -0,0 +1,128 @@
+package models
+
+import "time"
+
+// The game's state as clients see it is published as an immutable snapshot each
+// time the write lock is released, so GetGameState, called by every broadcast,
+// the bot loop and the timeout checker, never waits on or holds up gameplay.
+
+// unlock publishes a fresh state snapshot and queues the game to be saved to
+// storage, then releases the write lock. Every mutation must release the lock
+// through it.
+func (g *Game) unlock() {
+	g.publishStateLocked()
+	if g.persister != nil {
+		g.persister.markDirty(g)
+	}
+	g.mu.Unlock()
+}
+
+// publishStateLocked swaps in a snapshot of the current state (caller must hold lock)
+func (g *Game) publishStateLocked() {
+	g.stateVersion++
+	state := g.gameStateLocked()
+	g.state.Store(&state)
+}
+
+// GetGameState returns the current game state. The map is the caller's to modify,
+// but the values in it are shared and must not be.
+func (g *Game) GetGameState() map[string]interface{} {
+	var state map[string]interface{}
+	if snapshot := g.state.Load(); snapshot != nil {
+		state = make(map[string]interface{}, len(*snapshot)+1)
+		for k, v := range *snapshot {
+			state[k] = v
+		}
+	} else {
+		// Nothing has changed since the game was created or loaded
+		g.mu.RLock()
+		state = g.gameStateLocked()
+		g.mu.RUnlock()
+	}
+
+	// The snapshot is only published on changes, so the time left is worked out
+	// on each read; null while no timed turn is running
+	state["turn_remaining_ms"] = nil
+	if deadline, ok := state["turn_deadline"].(Timestamp); ok && !deadline.IsZero() {
+		state["turn_remaining_ms"] = max(time.Until(deadline.Time).Milliseconds(), 0)
+	}
+	return state
+}
+
+// gameStateLocked builds the state clients see from copies, so it stays valid
+// after the lock is released (caller must hold lock)
+func (g *Game) gameStateLocked() map[string]interface{} {
+	spectators := make(map[string]*Spectator, len(g.Spectators))
+	for id, s := range g.Spectators {
+		spectator := *s
+		spectators[id] = &spectator
+	}
+	var pauseVote *PauseVote
+	if g.PauseVote != nil {
+		vote := *g.PauseVote
+		vote.Votes = make(map[string]bool, len(g.PauseVote.Votes))
+		for id, approve := range g.PauseVote.Votes {
+			vote.Votes[id] = approve
+		}
+		pauseVote = &vote
+	}
+	var rolling *PendingRoll
+	if g.pendingRoll != nil {
+		rolling = &PendingRoll{RollID: g.pendingRoll.RollID, PlayerID: g.pendingRoll.PlayerID, RevealAt: g.pendingRoll.RevealAt}
+	}
+
+	palette, seatColors := g.seatColorsLocked("")
+	clock, _ := g.turnClockLocked()
+	return map[string]interface{}{
+		"code":                    g.Code,
+		"state_version":           g.stateVersion, // Bumped on every change
+		"players":                 clonePlayers(g.Players),
+		"spectators":              spectators,
+		"takeover_requests":       g.takeoverRequestsLocked(), // Players asking the host for a bot's seat
+		"state":                   g.State,
+		"current_turn":            g.CurrentTurn,
+		"max_players":             g.MaxPlayers,
+		"last_dice_roll":          g.LastDiceRoll,
+		"has_rolled":              g.HasRolled,
+		"winner":                  g.Winner,
+		"turn_start_time":         g.TurnStartTime,
+		"turn_deadline":           clock.Deadline, // null while no turn is running
+		"last_activity":           g.LastActivity,
+		"consecutive_sixes":       g.ConsecutiveSixes,
+		"host_id":                 g.HostID,
+		"paused_by":               g.PausedBy,
+		"pause_deadline":          g.PauseDeadline,
+		"pause_vote":              pauseVote,
+		"pause_budget":            g.PauseBudget,
+		"max_pause_seconds":       int(g.MaxPauseLength / time.Second),
+		"reconnect_grace_seconds": int(g.ReconnectGrace / time.Second),
+		"bot_takeover_seconds":    int(g.BotTakeoverDelay / time.Second),
+		"resume_quorum":           g.ResumeQuorum,
+		"preset":                  g.Preset,
+		"auto_roll":               g.AutoRoll,
+		"auto_skip":               g.AutoSkip,
+		"auto_move":               g.AutoMove,
+		"dice_reveal_ms":          g.DiceRevealDelay.Milliseconds(),
+		"rolling":                 rolling, // Roll being animated, if any
+		"standings":               g.standingsLocked(),
+		"series_games":            g.SeriesGames,
+		"experiment":              g.Experiment,
+		"experiment_arm":          g.ExperimentArm,
+		"replay_id":               g.ReplayID,
+		"capture_grants_turn":     g.CaptureGrantsTurn,
+		"rules":                   g.rulesLocked(),
+		"ranked":                  g.Ranked,
+		"blockades":               g.blockadesLocked(), // Empty unless the rules have blockades
+		"timeout_action":          g.TimeoutAction,
+		"bot_chat":                g.BotChat,
+		"departure_policy":        g.DeparturePolicy,
+		"afk_limit":               g.AFKLimit,
+		"afk_action":              g.AFKAction,
+		"has_password":            g.passwordHash != nil,
+		"missed_turns":            g.missedTurnsLocked(),
+		"unread_chat":             g.unreadChatLocked(),
+		"chat_muted_until":        g.chatMutesLocked(), // By participant, while muted
+		"palette":                 palette,
+		"seat_colors":             seatColors,
+	}
+}
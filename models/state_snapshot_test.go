@@ -0,0 +1,90 @@
+package models
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGameStateSnapshot(t *testing.T) {
+	game := newPauseTestGame(t)
+	before := game.GetGameState()
+	before["connected"] = map[string]int{"p1": 1}
+
+	mover := game.CurrentTurn
+	game.mu.Lock()
+	game.LastDiceRoll = 6
+	game.HasRolled = true
+	game.unlock()
+	if err := game.MovePiece(mover, 0); err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+
+	after := game.GetGameState()
+	if _, leaked := after["connected"]; leaked {
+		t.Error("Expected callers' changes to stay out of the snapshot")
+	}
+	if after["has_rolled"] != false {
+		t.Error("Expected the move to be published, using up the roll")
+	}
+	if piece := after["players"].(map[string]*Player)[mover].Pieces[0]; piece.IsHome {
+		t.Error("Expected the moved piece out of home in the new state")
+	}
+	if piece := before["players"].(map[string]*Player)[mover].Pieces[0]; !piece.IsHome {
+		t.Error("Expected the earlier state to be unaffected by the move")
+	}
+}
+
+// lockedGameState is how game state was read before snapshots: built under the read lock
+func lockedGameState(g *Game) map[string]interface{} {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.gameStateLocked()
+}
+
+func newBenchmarkGame(b *testing.B) *Game {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "p1", "Player 1", 4)
+	for _, id := range []string{"p2", "p3", "p4"} {
+		gm.JoinGame(context.Background(), game.Code, id, "Player", "")
+	}
+	for id := range game.Players {
+		game.SetPlayerReady(id, true)
+	}
+	if err := game.StartGame("p1"); err != nil {
+		b.Fatalf("Failed to start game: %v", err)
+	}
+	return game
+}
+
+// benchmarkReadsUnderWrites reads state from every CPU while a writer keeps
+// taking the game's write lock, as gameplay does
+func benchmarkReadsUnderWrites(b *testing.B, read func(*Game) map[string]interface{}) {
+	game := newBenchmarkGame(b)
+	var stop atomic.Bool
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for !stop.Load() {
+			game.UpdateActivity()
+		}
+	}()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			read(game)
+		}
+	})
+	b.StopTimer()
+	stop.Store(true)
+	<-done
+}
+
+func BenchmarkGameStateLocked(b *testing.B) {
+	benchmarkReadsUnderWrites(b, lockedGameState)
+}
+
+func BenchmarkGameStateSnapshot(b *testing.B) {
+	benchmarkReadsUnderWrites(b, (*Game).GetGameState)
+}
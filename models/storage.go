@@ -0,0 +1,404 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StorageSaveTimeout bounds each background save
+const StorageSaveTimeout = 10 * time.Second
+
+var ErrUnknownStorage = errors.New("unknown storage driver")
+
+var ErrInvalidNamespace = errors.New("storage namespace must be letters, digits, - or _")
+
+// Storage keeps serialized games outside the process so players can resume
+// after a crash or deploy. Load returns ErrGameNotFound for unknown codes.
+// Implementations must be safe for concurrent use.
+type Storage interface {
+	Save(ctx context.Context, code string, data []byte) error
+	Load(ctx context.Context, code string) ([]byte, error)
+	Delete(ctx context.Context, code string) error
+	List(ctx context.Context) ([]string, error)
+}
+
+// NamespacedStorage can keep other records beside games, such as player stats,
+// each kind in a storage of its own that doesn't show up in the games' List
+type NamespacedStorage interface {
+	Storage
+	Namespace(name string) (Storage, error)
+}
+
+// validNamespace reports whether a name is safe to use as a namespace in any
+// storage, including as a directory name
+func validNamespace(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// StorageDriver opens a storage from the part of a storage spec after the colon
+type StorageDriver func(arg string) (Storage, error)
+
+var (
+	storageDrivers   = make(map[string]StorageDriver)
+	storageDriversMu sync.RWMutex
+)
+
+func init() {
+	RegisterStorageDriver("memory", func(string) (Storage, error) { return NewMemoryStorage(), nil })
+	RegisterStorageDriver("file", func(dir string) (Storage, error) { return NewFileStorage(dir) })
+	RegisterStorageDriver("bolt", func(path string) (Storage, error) { return NewBoltStorage(path) })
+}
+
+// RegisterStorageDriver makes a storage driver available to OpenStorage by name
+func RegisterStorageDriver(name string, driver StorageDriver) {
+	storageDriversMu.Lock()
+	defer storageDriversMu.Unlock()
+	storageDrivers[name] = driver
+}
+
+// StorageDrivers lists the registered driver names
+func StorageDrivers() []string {
+	storageDriversMu.RLock()
+	defer storageDriversMu.RUnlock()
+
+	names := make([]string, 0, len(storageDrivers))
+	for name := range storageDrivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OpenStorage opens a storage from a spec of the form driver[:arg], e.g.
+// "memory", "file:/var/lib/ludo/games" or "bolt:/var/lib/ludo/games.db"
+func OpenStorage(spec string) (Storage, error) {
+	name, arg, _ := strings.Cut(spec, ":")
+	storageDriversMu.RLock()
+	driver, ok := storageDrivers[name]
+	storageDriversMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w %q (have %s)", ErrUnknownStorage, name, strings.Join(StorageDrivers(), ", "))
+	}
+	return driver(arg)
+}
+
+// MemoryStorage keeps games in process memory. Nothing survives a restart, so it
+// suits tests and servers that don't need persistence.
+type MemoryStorage struct {
+	games      map[string][]byte
+	namespaces map[string]*MemoryStorage
+	mu         sync.RWMutex
+}
+
+// NewMemoryStorage creates an empty in-memory storage
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{games: make(map[string][]byte)}
+}
+
+// Namespace returns the in-memory storage for another kind of record
+func (s *MemoryStorage) Namespace(name string) (Storage, error) {
+	if !validNamespace(name) {
+		return nil, ErrInvalidNamespace
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.namespaces == nil {
+		s.namespaces = make(map[string]*MemoryStorage)
+	}
+	ns, exists := s.namespaces[name]
+	if !exists {
+		ns = NewMemoryStorage()
+		s.namespaces[name] = ns
+	}
+	return ns, nil
+}
+
+// Save stores a game
+func (s *MemoryStorage) Save(ctx context.Context, code string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.games[code] = append([]byte(nil), data...)
+	return nil
+}
+
+// Load returns a stored game
+func (s *MemoryStorage) Load(ctx context.Context, code string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	data, ok := s.games[code]
+	if !ok {
+		return nil, ErrGameNotFound
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// Delete removes a stored game; deleting an unknown code is not an error
+func (s *MemoryStorage) Delete(ctx context.Context, code string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.games, code)
+	return nil
+}
+
+// List returns the codes of every stored game
+func (s *MemoryStorage) List(ctx context.Context) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	codes := make([]string, 0, len(s.games))
+	for code := range s.games {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes, nil
+}
+
+// StorageErrorHook is called when a game can't be saved to or deleted from storage
+type StorageErrorHook func(code string, err error)
+
+// persister writes changed games to storage in the background. Games mark
+// themselves dirty as they release their write lock; a burst of changes to one
+// game is written once.
+type persister struct {
+	gm      *GameManager
+	storage Storage
+	onError StorageErrorHook
+	dirty   map[string]*Game
+	wake    chan struct{}
+	mu      sync.Mutex
+	io      sync.Mutex // Held while flushing or deleting, so they don't interleave
+}
+
+// markDirty queues a game to be saved. Never blocks; called with the game's lock held.
+func (p *persister) markDirty(g *Game) {
+	p.mu.Lock()
+	p.dirty[g.Code] = g
+	p.mu.Unlock()
+
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run saves dirty games until the process exits
+func (p *persister) run() {
+	for range p.wake {
+		p.flush(context.Background())
+	}
+}
+
+// flush saves every dirty game, returning the first error. Returns once any flush
+// already running has finished too.
+func (p *persister) flush(ctx context.Context) error {
+	p.io.Lock()
+	defer p.io.Unlock()
+
+	p.mu.Lock()
+	dirty := p.dirty
+	p.dirty = make(map[string]*Game)
+	p.mu.Unlock()
+
+	var first error
+	for code, game := range dirty {
+		if err := p.save(ctx, code, game); err != nil {
+			if first == nil {
+				first = err
+			}
+			if p.onError != nil {
+				p.onError(code, err)
+			}
+		}
+	}
+	return first
+}
+
+// save writes one game, unless it has been removed from the manager since it
+// changed (caller must hold p.io)
+func (p *persister) save(ctx context.Context, code string, game *Game) error {
+	p.gm.mu.RLock()
+	current := p.gm.games[code]
+	p.gm.mu.RUnlock()
+	if current != game {
+		return nil
+	}
+
+	data, err := game.marshalSnapshot()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, StorageSaveTimeout)
+	defer cancel()
+	return p.storage.Save(ctx, code, data)
+}
+
+// remove deletes games from storage once they are gone from the manager
+func (p *persister) remove(codes []string) {
+	p.io.Lock()
+	defer p.io.Unlock()
+
+	for _, code := range codes {
+		p.mu.Lock()
+		delete(p.dirty, code)
+		p.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), StorageSaveTimeout)
+		err := p.storage.Delete(ctx, code)
+		cancel()
+		if err != nil && p.onError != nil {
+			p.onError(code, err)
+		}
+	}
+}
+
+// SetStorage persists every game to storage from now on: each change is saved in
+// the background, and games are deleted from storage when purged or removed.
+// Call it at startup, before LoadFromStorage.
+func (gm *GameManager) SetStorage(storage Storage) {
+	p := &persister{
+		gm:      gm,
+		storage: storage,
+		dirty:   make(map[string]*Game),
+		wake:    make(chan struct{}, 1),
+	}
+
+	gm.mu.Lock()
+	p.onError = gm.storageErrorHook
+	gm.persister = p
+	games := make([]*Game, 0, len(gm.games))
+	for _, game := range gm.games {
+		games = append(games, game)
+	}
+	gm.mu.Unlock()
+
+	for _, game := range games {
+		game.mu.Lock()
+		game.persister = p
+		game.unlock()
+	}
+	go p.run()
+
+	// Player stats go beside the games when the storage can keep them apart
+	if ns, ok := storage.(NamespacedStorage); ok {
+		players, err := ns.Namespace(StatsNamespace)
+		if err != nil {
+			if p.onError != nil {
+				p.onError(StatsNamespace, err)
+			}
+			return
+		}
+		gm.stats.setStorage(players, p.onError)
+
+		for _, saver := range []*recordSaver{gm.profiles.saver, gm.friends.saver} {
+			records, err := ns.Namespace(saver.namespace)
+			if err != nil {
+				if p.onError != nil {
+					p.onError(saver.namespace, err)
+				}
+				continue
+			}
+			saver.setStorage(records, p.onError)
+		}
+	}
+}
+
+// OnStorageError registers a hook called when a game can't be saved or deleted.
+// Call it before SetStorage.
+func (gm *GameManager) OnStorageError(hook StorageErrorHook) {
+	gm.mu.Lock()
+	defer gm.mu.Unlock()
+	gm.storageErrorHook = hook
+}
+
+// FlushStorage saves every game and player's stats, profile and friend list
+// changed since their last save and waits for finished games still being
+// archived, returning the first error
+func (gm *GameManager) FlushStorage(ctx context.Context) error {
+	gm.mu.RLock()
+	p := gm.persister
+	gm.mu.RUnlock()
+	err := gm.archive.Flush(ctx)
+	if p == nil {
+		return err
+	}
+	if flushErr := p.flush(ctx); err == nil {
+		err = flushErr
+	}
+	if statsErr := gm.stats.flush(ctx); err == nil {
+		err = statsErr
+	}
+	if profileErr := gm.profiles.saver.flush(ctx); err == nil {
+		err = profileErr
+	}
+	if friendsErr := gm.friends.saver.flush(ctx); err == nil {
+		err = friendsErr
+	}
+	return err
+}
+
+// LoadFromStorage restores every stored game whose code isn't already in use,
+// and players' stats, profiles and friend lists. Turns in progress restart their clock, since the server
+// may have been down for a while. Returns the codes loaded; games that can't be
+// read are skipped and reported in the error.
+func (gm *GameManager) LoadFromStorage(ctx context.Context) ([]string, error) {
+	gm.mu.RLock()
+	p := gm.persister
+	gm.mu.RUnlock()
+	if p == nil {
+		return nil, nil
+	}
+
+	codes, err := p.storage.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var loaded []string
+	var errs []error
+	if err := gm.stats.load(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if err := gm.profiles.load(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if err := gm.friends.load(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	for _, code := range codes {
+		if err := ctx.Err(); err != nil {
+			return loaded, err
+		}
+		data, err := p.storage.Load(ctx, code)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", code, err))
+			continue
+		}
+
+		gm.mu.Lock()
+		game, err := gm.unmarshalSnapshotLocked(data)
+		if game != nil {
+			if game.State == Playing {
+				game.TurnStartTime = Now()
+			}
+			gm.games[game.Code] = game
+			loaded = append(loaded, game.Code)
+		}
+		gm.mu.Unlock()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", code, err))
+		}
+	}
+	return loaded, errors.Join(errs...)
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// boltGamesBucket holds one key per game code
+var boltGamesBucket = []byte("games")
+
+// BoltStorage keeps games in a single BoltDB file. Every save is its own
+// transaction, synced before it returns.
+type BoltStorage struct {
+	db     *bolt.DB
+	bucket []byte
+}
+
+// NewBoltStorage opens a BoltDB file, creating it if needed. Only one process
+// can hold the file open at a time.
+func NewBoltStorage(path string) (*BoltStorage, error) {
+	if path == "" {
+		return nil, ErrInvalidStorageDir
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltGamesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltStorage{db: db, bucket: boltGamesBucket}, nil
+}
+
+// Namespace returns a storage for another kind of record, kept in a bucket of
+// its own in the same file
+func (s *BoltStorage) Namespace(name string) (Storage, error) {
+	if !validNamespace(name) {
+		return nil, ErrInvalidNamespace
+	}
+	bucket := []byte("ns:" + name)
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucket)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &BoltStorage{db: s.db, bucket: bucket}, nil
+}
+
+// Close releases the database file, for every namespace too
+func (s *BoltStorage) Close() error {
+	return s.db.Close()
+}
+
+// Save stores a game
+func (s *BoltStorage) Save(ctx context.Context, code string, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).Put([]byte(code), data)
+	})
+}
+
+// Load returns a stored game
+func (s *BoltStorage) Load(ctx context.Context, code string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var data []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(s.bucket).Get([]byte(code))
+		if value == nil {
+			return ErrGameNotFound
+		}
+		data = append([]byte(nil), value...) // Only valid during the transaction
+		return nil
+	})
+	return data, err
+}
+
+// Delete removes a stored game; deleting an unknown code is not an error
+func (s *BoltStorage) Delete(ctx context.Context, code string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).Delete([]byte(code))
+	})
+}
+
+// List returns the codes of every stored game, in order
+func (s *BoltStorage) List(ctx context.Context) ([]string, error) {
+	var codes []string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).ForEach(func(k, _ []byte) error {
+			codes = append(codes, string(k))
+			return nil
+		})
+	})
+	return codes, err
+}
@@ -0,0 +1,130 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// fileStorageExt is the extension of each game's file
+const fileStorageExt = ".json"
+
+var ErrInvalidStorageDir = errors.New("storage needs a path, e.g. file:/var/lib/ludo/games or bolt:/var/lib/ludo/games.db")
+
+// FileStorage keeps each game in its own JSON file in a directory. Files are
+// written to a temporary name, synced and renamed into place, so a crash leaves
+// either the old or the new version of a game, never a torn one.
+type FileStorage struct {
+	dir string
+}
+
+// NewFileStorage opens a file storage, creating the directory if needed
+func NewFileStorage(dir string) (*FileStorage, error) {
+	if dir == "" {
+		return nil, ErrInvalidStorageDir
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileStorage{dir: dir}, nil
+}
+
+// path returns the file a game is kept in. Codes are generated by the server,
+// but are checked anyway so none can point outside the directory.
+func (s *FileStorage) path(code string) (string, error) {
+	if code == "" || strings.ContainsAny(code, `/\.`) {
+		return "", ErrGameNotFound
+	}
+	return filepath.Join(s.dir, code+fileStorageExt), nil
+}
+
+// Namespace returns a file storage in a subdirectory, which List skips
+func (s *FileStorage) Namespace(name string) (Storage, error) {
+	if !validNamespace(name) {
+		return nil, ErrInvalidNamespace
+	}
+	return NewFileStorage(filepath.Join(s.dir, name))
+}
+
+// Save writes a game atomically
+func (s *FileStorage) Save(ctx context.Context, code string, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	path, err := s.path(code)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, code+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // Fails harmlessly once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Load reads a stored game
+func (s *FileStorage) Load(ctx context.Context, code string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	path, err := s.path(code)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrGameNotFound
+	}
+	return data, err
+}
+
+// Delete removes a stored game; deleting an unknown code is not an error
+func (s *FileStorage) Delete(ctx context.Context, code string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	path, err := s.path(code)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// List returns the codes of every stored game
+func (s *FileStorage) List(ctx context.Context) ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var codes []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, fileStorageExt) {
+			continue
+		}
+		codes = append(codes, strings.TrimSuffix(name, fileStorageExt))
+	}
+	sort.Strings(codes)
+	return codes, nil
+}
@@ -0,0 +1,156 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestStoragePersistsAndRestoresGames(t *testing.T) {
+	storage := NewMemoryStorage()
+	gm := NewGameManager()
+	gm.SetStorage(storage)
+
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+	gm.JoinGame(context.Background(), game.Code, "player2", "Player 2", "")
+	game.SetPlayerReady("host1", true)
+	game.SetPlayerReady("player2", true)
+	if err := game.StartGame("host1"); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+	if err := gm.FlushStorage(context.Background()); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if codes, _ := storage.List(context.Background()); len(codes) != 1 || codes[0] != game.Code {
+		t.Fatalf("Expected the game in storage, got %v", codes)
+	}
+
+	restored := NewGameManager()
+	restored.SetStorage(storage)
+	loaded, err := restored.LoadFromStorage(context.Background())
+	if err != nil || len(loaded) != 1 {
+		t.Fatalf("Expected 1 game loaded, got %v (%v)", loaded, err)
+	}
+	got, err := restored.GetGame(context.Background(), game.Code)
+	if err != nil {
+		t.Fatalf("Restored game missing: %v", err)
+	}
+	if got.State != Playing || got.CurrentTurn != game.CurrentTurn || len(got.Players) != 2 {
+		t.Errorf("Restored game differs: state %s, turn %s, %d players", got.State, got.CurrentTurn, len(got.Players))
+	}
+	if got.SessionSecret("host1") != game.SessionSecret("host1") {
+		t.Error("Session secrets should survive a crash")
+	}
+
+	// Changes to a restored game keep being saved
+	got.SendChatMessage("host1", "still here")
+	restored.FlushStorage(context.Background())
+	data, _ := storage.Load(context.Background(), game.Code)
+	again := NewGameManager()
+	again.mu.Lock()
+	reloaded, err := again.unmarshalSnapshotLocked(data)
+	again.mu.Unlock()
+	if err != nil || len(reloaded.ChatMessages) != 1 {
+		t.Errorf("Expected the chat message to be saved, got %v", err)
+	}
+
+	// Loading twice doesn't clobber live games
+	if twice, _ := restored.LoadFromStorage(context.Background()); len(twice) != 0 {
+		t.Errorf("Expected existing games to be skipped, got %v", twice)
+	}
+}
+
+func TestStorageForgetsRemovedGames(t *testing.T) {
+	storage := NewMemoryStorage()
+	gm := NewGameManager()
+	gm.SetStorage(storage)
+
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+	gm.FlushStorage(context.Background())
+	gm.RemoveGame(game.Code)
+	gm.FlushStorage(context.Background())
+
+	if _, err := storage.Load(context.Background(), game.Code); err != ErrGameNotFound {
+		t.Errorf("Expected the removed game to be deleted from storage, got %v", err)
+	}
+}
+
+func TestFileStorage(t *testing.T) {
+	storage, err := NewFileStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to open storage: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := storage.Save(ctx, "ABC123", []byte(`{"code":"ABC123"}`)); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	storage.Save(ctx, "XYZ789", []byte(`{}`))
+	if data, err := storage.Load(ctx, "ABC123"); err != nil || string(data) != `{"code":"ABC123"}` {
+		t.Errorf("Expected the saved game back, got %s (%v)", data, err)
+	}
+	if codes, _ := storage.List(ctx); len(codes) != 2 || codes[0] != "ABC123" {
+		t.Errorf("Expected both codes listed, got %v", codes)
+	}
+
+	if err := storage.Delete(ctx, "ABC123"); err != nil {
+		t.Errorf("Delete failed: %v", err)
+	}
+	if err := storage.Delete(ctx, "ABC123"); err != nil {
+		t.Errorf("Deleting twice should be harmless, got %v", err)
+	}
+	if _, err := storage.Load(ctx, "ABC123"); err != ErrGameNotFound {
+		t.Errorf("Expected ErrGameNotFound, got %v", err)
+	}
+	if _, err := storage.Load(ctx, "../etc"); err != ErrGameNotFound {
+		t.Errorf("Codes must not escape the directory, got %v", err)
+	}
+}
+
+func TestBoltStorage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.db")
+	storage, err := NewBoltStorage(path)
+	if err != nil {
+		t.Fatalf("Failed to open storage: %v", err)
+	}
+	ctx := context.Background()
+
+	storage.Save(ctx, "XYZ789", []byte(`{}`))
+	if err := storage.Save(ctx, "ABC123", []byte(`{"code":"ABC123"}`)); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	storage.Delete(ctx, "XYZ789")
+	storage.Close()
+
+	// Games survive reopening the file
+	storage, err = NewBoltStorage(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen storage: %v", err)
+	}
+	defer storage.Close()
+	if data, err := storage.Load(ctx, "ABC123"); err != nil || string(data) != `{"code":"ABC123"}` {
+		t.Errorf("Expected the saved game back, got %s (%v)", data, err)
+	}
+	if codes, _ := storage.List(ctx); len(codes) != 1 || codes[0] != "ABC123" {
+		t.Errorf("Expected only the remaining code listed, got %v", codes)
+	}
+	if _, err := storage.Load(ctx, "XYZ789"); err != ErrGameNotFound {
+		t.Errorf("Expected ErrGameNotFound, got %v", err)
+	}
+}
+
+func TestOpenStorage(t *testing.T) {
+	if _, err := OpenStorage("memory"); err != nil {
+		t.Errorf("Expected the memory driver, got %v", err)
+	}
+	if _, err := OpenStorage("file:" + t.TempDir()); err != nil {
+		t.Errorf("Expected the file driver, got %v", err)
+	}
+	if _, err := OpenStorage("file"); err != ErrInvalidStorageDir {
+		t.Errorf("Expected ErrInvalidStorageDir, got %v", err)
+	}
+	if _, err := OpenStorage("sqlite:/tmp/games.db"); !errors.Is(err, ErrUnknownStorage) {
+		t.Errorf("Expected ErrUnknownStorage, got %v", err)
+	}
+}
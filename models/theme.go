@@ -0,0 +1,98 @@
+package models
+
+import "errors"
+
+// ThemeKind separates board themes from piece skins
+type ThemeKind string
+
+const (
+	ThemeBoard ThemeKind = "board"      // Colors and textures for the board
+	ThemePiece ThemeKind = "piece_skin" // Look of the pieces
+)
+
+// Default selections for players without a saved preference
+const (
+	DefaultBoardTheme = "classic"
+	DefaultPieceSkin  = "piece-classic"
+)
+
+var (
+	ErrUnknownTheme   = errors.New("unknown theme")
+	ErrWrongThemeKind = errors.New("theme is not of the requested kind")
+)
+
+// UnlockCondition describes what a player must achieve to use a theme.
+// Clients show these to players; the server does not track the counters yet.
+type UnlockCondition struct {
+	GamesPlayed int `json:"games_played,omitempty"`
+	Wins        int `json:"wins,omitempty"`
+}
+
+// Theme is one entry of the asset manifest
+type Theme struct {
+	ID         string           `json:"id"`
+	Name       string           `json:"name"`
+	Kind       ThemeKind        `json:"kind"`
+	PreviewURL string           `json:"preview_url"`
+	Unlock     *UnlockCondition `json:"unlock,omitempty"`
+}
+
+// ThemeManifest lists every theme and skin clients can offer
+type ThemeManifest struct {
+	Version      int     `json:"version"`
+	DefaultBoard string  `json:"default_board_theme"`
+	DefaultPiece string  `json:"default_piece_skin"`
+	BoardThemes  []Theme `json:"board_themes"`
+	PieceSkins   []Theme `json:"piece_skins"`
+}
+
+// themeAssetPath is where preview images are served from
+const themeAssetPath = "/assets/themes/"
+
+// Themes is the built-in theme catalogue
+var Themes = []Theme{
+	{ID: "classic", Name: "Classic", Kind: ThemeBoard},
+	{ID: "high-contrast", Name: "High Contrast", Kind: ThemeBoard},
+	{ID: "midnight", Name: "Midnight", Kind: ThemeBoard, Unlock: &UnlockCondition{GamesPlayed: 10}},
+	{ID: "wood", Name: "Wooden Table", Kind: ThemeBoard, Unlock: &UnlockCondition{Wins: 5}},
+	{ID: "piece-classic", Name: "Classic", Kind: ThemePiece},
+	{ID: "piece-glossy", Name: "Glossy", Kind: ThemePiece},
+	{ID: "piece-gem", Name: "Gem", Kind: ThemePiece, Unlock: &UnlockCondition{GamesPlayed: 25}},
+	{ID: "piece-crown", Name: "Crown", Kind: ThemePiece, Unlock: &UnlockCondition{Wins: 20}},
+}
+
+// ThemeManifestVersion changes whenever the catalogue changes so clients can cache it
+const ThemeManifestVersion = 1
+
+// GetThemeManifest returns the manifest of available themes and skins
+func GetThemeManifest() *ThemeManifest {
+	manifest := &ThemeManifest{
+		Version:      ThemeManifestVersion,
+		DefaultBoard: DefaultBoardTheme,
+		DefaultPiece: DefaultPieceSkin,
+		BoardThemes:  []Theme{},
+		PieceSkins:   []Theme{},
+	}
+	for _, theme := range Themes {
+		theme.PreviewURL = themeAssetPath + theme.ID + ".svg"
+		if theme.Kind == ThemeBoard {
+			manifest.BoardThemes = append(manifest.BoardThemes, theme)
+		} else {
+			manifest.PieceSkins = append(manifest.PieceSkins, theme)
+		}
+	}
+	return manifest
+}
+
+// validateTheme checks that a theme ID exists and is of the expected kind
+func validateTheme(id string, kind ThemeKind) error {
+	for _, theme := range Themes {
+		if theme.ID == id {
+			if theme.Kind != kind {
+				return ErrWrongThemeKind
+			}
+			return nil
+		}
+	}
+	return ErrUnknownTheme
+}
@@ -0,0 +1,50 @@
+package models
+
+import "testing"
+
+func TestThemeManifest(t *testing.T) {
+	manifest := GetThemeManifest()
+	if len(manifest.BoardThemes) == 0 || len(manifest.PieceSkins) == 0 {
+		t.Fatal("Manifest should list board themes and piece skins")
+	}
+	for _, theme := range append(manifest.BoardThemes, manifest.PieceSkins...) {
+		if theme.PreviewURL == "" {
+			t.Errorf("Theme %s has no preview URL", theme.ID)
+		}
+	}
+	if err := validateTheme(manifest.DefaultBoard, ThemeBoard); err != nil {
+		t.Errorf("Default board theme invalid: %v", err)
+	}
+	if err := validateTheme(manifest.DefaultPiece, ThemePiece); err != nil {
+		t.Errorf("Default piece skin invalid: %v", err)
+	}
+}
+
+func TestProfileThemeSelection(t *testing.T) {
+	profiles := NewGameManager().Profiles()
+
+	if p := profiles.Get("player1"); p.BoardTheme != DefaultBoardTheme || p.PieceSkin != DefaultPieceSkin {
+		t.Errorf("Expected default themes, got %+v", p)
+	}
+
+	if _, err := profiles.SetTheme("player1", "neon", ""); err != ErrUnknownTheme {
+		t.Errorf("Expected ErrUnknownTheme, got %v", err)
+	}
+	if _, err := profiles.SetTheme("player1", "piece-gem", ""); err != ErrWrongThemeKind {
+		t.Errorf("Expected ErrWrongThemeKind, got %v", err)
+	}
+
+	if _, err := profiles.SetTheme("player1", "midnight", ""); err != nil {
+		t.Fatalf("Failed to set theme: %v", err)
+	}
+	p, err := profiles.SetTheme("player1", "", "piece-glossy")
+	if err != nil {
+		t.Fatalf("Failed to set skin: %v", err)
+	}
+	if p.BoardTheme != "midnight" || p.PieceSkin != "piece-glossy" {
+		t.Errorf("Expected saved selection, got %+v", p)
+	}
+	if got := profiles.Get("player1"); got.BoardTheme != "midnight" {
+		t.Errorf("Selection not persisted: %+v", got)
+	}
+}
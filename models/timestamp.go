@@ -0,0 +1,49 @@
+package models
+
+import (
+	"bytes"
+	"time"
+)
+
+// TimestampFormat is how every timestamp goes over the wire: RFC3339 in UTC with
+// exactly three fractional digits, so clients can parse them the same way everywhere
+const TimestampFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// Timestamp is a time.Time that encodes as TimestampFormat, or as null when zero.
+// Decoding accepts any RFC3339 time, so older snapshots still load.
+type Timestamp struct {
+	time.Time
+}
+
+// At wraps a time as a Timestamp
+func At(t time.Time) Timestamp {
+	return Timestamp{t}
+}
+
+// Now returns the current time as a Timestamp, cut to the millisecond so a time a
+// client echoes back (such as a chat read marker) compares equal to the original
+func Now() Timestamp {
+	return Timestamp{time.Now().Truncate(time.Millisecond)}
+}
+
+// String formats the timestamp as it appears on the wire
+func (t Timestamp) String() string {
+	return t.UTC().Format(TimestampFormat)
+}
+
+// MarshalJSON encodes the timestamp as an RFC3339 UTC string with milliseconds
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(`"` + t.String() + `"`), nil
+}
+
+// UnmarshalJSON decodes an RFC3339 string; null leaves the zero time
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		t.Time = time.Time{}
+		return nil
+	}
+	return t.Time.UnmarshalJSON(data)
+}
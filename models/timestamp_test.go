@@ -0,0 +1,40 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimestampJSON(t *testing.T) {
+	local := time.FixedZone("UTC+2", 2*60*60)
+	ts := At(time.Date(2024, 3, 1, 14, 5, 6, 7_891_000, local))
+
+	data, err := json.Marshal(ts)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	if string(data) != `"2024-03-01T12:05:06.007Z"` {
+		t.Errorf("Expected UTC with milliseconds, got %s", data)
+	}
+
+	if data, _ := json.Marshal(Timestamp{}); string(data) != "null" {
+		t.Errorf("Expected zero timestamp to encode as null, got %s", data)
+	}
+
+	var decoded struct{ A, B, C Timestamp }
+	in := `{"A": "2024-03-01T12:05:06.007Z", "B": null, "C": "0001-01-01T00:00:00Z"}`
+	if err := json.Unmarshal([]byte(in), &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if !decoded.A.Equal(ts.Truncate(time.Millisecond)) {
+		t.Errorf("Expected %v, got %v", ts, decoded.A)
+	}
+	if !decoded.B.IsZero() || !decoded.C.IsZero() {
+		t.Error("Expected null and old zero values to decode as zero")
+	}
+
+	if now := Now(); now.Nanosecond()%int(time.Millisecond) != 0 {
+		t.Errorf("Now should be cut to the millisecond, got %v", now.Time)
+	}
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"context"
+	"strings"
+)
+
+// Translator translates chat text into another language. Implementations may call
+// out to an external service, so they are never invoked while a game lock is held.
+type Translator interface {
+	Translate(ctx context.Context, text, targetLocale string) (string, error)
+}
+
+// NormalizeLocale reduces a locale tag such as "ar-MA" or "en_US" to its
+// lower-case language code, so readers of the same language share translations
+func NormalizeLocale(locale string) string {
+	locale = strings.TrimSpace(locale)
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		locale = locale[:i]
+	}
+	return strings.ToLower(locale)
+}
+
+// TranslateChat translates a chat message into each locale and caches the results.
+// Failed or unchanged translations are skipped, and no further locales are tried
+// once ctx is done. Returns how many were stored.
+func (g *Game) TranslateChat(ctx context.Context, id int, t Translator, locales []string) int {
+	if t == nil {
+		return 0
+	}
+
+	g.mu.RLock()
+	text := ""
+	for _, msg := range g.ChatMessages {
+		if msg.ID == id {
+			text = msg.Message
+			break
+		}
+	}
+	g.mu.RUnlock()
+	if text == "" {
+		return 0
+	}
+
+	translated := make(map[string]string)
+	for _, locale := range locales {
+		if ctx.Err() != nil {
+			break
+		}
+		locale = NormalizeLocale(locale)
+		if locale == "" {
+			continue
+		}
+		if _, done := translated[locale]; done {
+			continue
+		}
+		out, err := t.Translate(ctx, text, locale)
+		if err != nil || out == "" || out == text {
+			continue
+		}
+		translated[locale] = out
+	}
+	if len(translated) == 0 {
+		return 0
+	}
+
+	g.mu.Lock()
+	defer g.unlock()
+	for i := range g.ChatMessages {
+		if g.ChatMessages[i].ID != id {
+			continue
+		}
+		msg := &g.ChatMessages[i]
+		if msg.translations == nil {
+			msg.translations = make(map[string]string)
+		}
+		for locale, out := range translated {
+			msg.translations[locale] = out
+		}
+		return len(translated)
+	}
+	return 0
+}
+
+// GetRecentChatFor returns the most recent chat messages with the translated
+// field filled in for the reader's locale where a translation exists
+func (g *Game) GetRecentChatFor(limit int, locale string) []ChatMessage {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	messages := g.ChatMessages
+	if limit > 0 && limit < len(messages) {
+		messages = messages[len(messages)-limit:]
+	}
+
+	locale = NormalizeLocale(locale)
+	out := make([]ChatMessage, len(messages))
+	for i, msg := range messages {
+		msg.Translated = msg.translations[locale]
+		msg.translations = nil
+		out[i] = msg
+	}
+	return out
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// fakeTranslator tags text with the target locale, failing for "xx"
+type fakeTranslator struct {
+	calls int
+}
+
+func (f *fakeTranslator) Translate(ctx context.Context, text, targetLocale string) (string, error) {
+	f.calls++
+	if targetLocale == "xx" {
+		return "", errors.New("unsupported locale")
+	}
+	return "[" + targetLocale + "] " + text, nil
+}
+
+func TestNormalizeLocale(t *testing.T) {
+	cases := map[string]string{"ar-MA": "ar", "en_US": "en", " FR ": "fr", "": ""}
+	for in, want := range cases {
+		if got := NormalizeLocale(in); got != want {
+			t.Errorf("NormalizeLocale(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTranslateChat(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+	gm.JoinGame(context.Background(), game.Code, "player2", "Player 2", "")
+
+	first, err := game.SendChatMessage("host1", "hello")
+	if err != nil {
+		t.Fatalf("Failed to send chat: %v", err)
+	}
+	second, _ := game.SendChatMessage("player2", "salam")
+	if first.ID != 1 || second.ID != 2 {
+		t.Fatalf("Expected sequential chat IDs, got %d and %d", first.ID, second.ID)
+	}
+
+	tr := &fakeTranslator{}
+	if n := game.TranslateChat(context.Background(), first.ID, tr, []string{"ar-MA", "ar", "xx", ""}); n != 1 {
+		t.Errorf("Expected 1 translation stored, got %d", n)
+	}
+	if tr.calls != 2 {
+		t.Errorf("Expected duplicate locales to be translated once, got %d calls", tr.calls)
+	}
+
+	chat := game.GetRecentChatFor(10, "ar-EG")
+	if chat[0].Translated != "[ar] hello" {
+		t.Errorf("Expected Arabic translation, got %q", chat[0].Translated)
+	}
+	if chat[1].Translated != "" {
+		t.Errorf("Untranslated message should have no translation, got %q", chat[1].Translated)
+	}
+	if chat := game.GetRecentChatFor(10, "en"); chat[0].Translated != "" {
+		t.Errorf("English reader should get no translation, got %q", chat[0].Translated)
+	}
+	if game.TranslateChat(context.Background(), 99, tr, []string{"ar"}) != 0 {
+		t.Error("Unknown message IDs should not be translated")
+	}
+}
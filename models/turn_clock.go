@@ -0,0 +1,112 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrInvalidTurnTimeout = errors.New("turn timeout must be 0 (unlimited) or between 5 and 600 seconds")
+
+// TurnClock is whose turn it is and when it times out. Clients count down to the
+// absolute deadline, so everyone shows the same timer whatever their latency.
+type TurnClock struct {
+	Turn     string    `json:"turn"`
+	Deadline Timestamp `json:"turn_deadline"`
+}
+
+// TurnClock returns the running turn's clock; false while no turn is running,
+// such as before the start, while paused and after the game ends, and while an
+// untimed turn has no deadline
+func (g *Game) TurnClock() (TurnClock, bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.turnClockLocked()
+}
+
+// turnClockLocked backs TurnClock (caller must hold lock)
+func (g *Game) turnClockLocked() (TurnClock, bool) {
+	if g.State != Playing || g.CurrentTurn == "" || g.TurnStartTime.IsZero() {
+		return TurnClock{}, false
+	}
+	deadline := g.turnDeadlineLocked()
+	if deadline.IsZero() {
+		return TurnClock{}, false
+	}
+	return TurnClock{
+		Turn:     g.CurrentTurn,
+		Deadline: At(deadline.Truncate(time.Millisecond)),
+	}, true
+}
+
+// turnTimedOutLocked reports whether the running turn is past its deadline.
+// Untimed turns never are. (caller must hold lock)
+func (g *Game) turnTimedOutLocked() bool {
+	if g.TurnStartTime.IsZero() {
+		return false
+	}
+	deadline := g.turnDeadlineLocked()
+	return !deadline.IsZero() && time.Now().After(deadline)
+}
+
+// validTurnTimeout reports whether a turn length can be played: unlimited, or
+// between MinRuleTurnTimeout and MaxRuleTurnTimeout
+func validTurnTimeout(timeout time.Duration) bool {
+	return timeout == 0 || (timeout >= MinRuleTurnTimeout && timeout <= MaxRuleTurnTimeout)
+}
+
+// SetTurnTimeout sets the time allowed per turn; zero leaves turns untimed (host
+// only, before the game starts)
+func (g *Game) SetTurnTimeout(hostID string, timeout time.Duration) error {
+	if !validTurnTimeout(timeout) {
+		return ErrInvalidTurnTimeout
+	}
+
+	g.mu.Lock()
+	defer g.unlock()
+
+	if g.HostID != hostID {
+		return ErrNotHost
+	}
+	if g.State != Waiting {
+		return ErrGameStarted
+	}
+	g.TurnTimeout = timeout
+	g.LastActivity = Now()
+	return nil
+}
+
+// TakeTurnWarning returns the running turn's clock once it is within
+// TurnTimeoutWarning of its deadline, so the player can be warned. Each turn
+// is only returned once, and bots' turns never are.
+func (g *Game) TakeTurnWarning() (TurnClock, bool) {
+	// Checked under the read lock first, as this runs for every game every second
+	g.mu.RLock()
+	_, due := g.turnWarningDueLocked()
+	g.mu.RUnlock()
+	if !due {
+		return TurnClock{}, false
+	}
+
+	g.mu.Lock()
+	defer g.unlock()
+
+	clock, due := g.turnWarningDueLocked()
+	if due {
+		g.turnWarned = g.TurnStartTime.Time
+	}
+	return clock, due
+}
+
+// turnWarningDueLocked returns the running turn's clock if its player should be
+// warned now (caller must hold lock)
+func (g *Game) turnWarningDueLocked() (TurnClock, bool) {
+	clock, ok := g.turnClockLocked()
+	if !ok || g.turnWarned.Equal(g.TurnStartTime.Time) {
+		return TurnClock{}, false
+	}
+	if player := g.Players[clock.Turn]; player == nil || player.botPlays() {
+		return TurnClock{}, false
+	}
+	left := time.Until(clock.Deadline.Time)
+	return clock, left > 0 && left <= TurnTimeoutWarning
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTurnClock(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "player1", "Player 1", 4)
+	gm.JoinGame(context.Background(), game.Code, "player2", "Player 2", "")
+
+	if _, ok := game.TurnClock(); ok {
+		t.Error("No turn clock should run before the game starts")
+	}
+	if state := game.GetGameState(); !state["turn_deadline"].(Timestamp).IsZero() {
+		t.Error("Expected no turn deadline before the game starts")
+	}
+
+	game.SetPlayerReady("player1", true)
+	game.SetPlayerReady("player2", true)
+	if err := game.StartGame("player1"); err != nil {
+		t.Fatalf("Failed to start game: %v", err)
+	}
+
+	clock, ok := game.TurnClock()
+	if !ok || clock.Turn != game.CurrentTurn {
+		t.Fatalf("Expected a clock for %s, got %+v", game.CurrentTurn, clock)
+	}
+	if want := game.TurnStartTime.Add(game.TurnTimeout); clock.Deadline.Sub(want).Abs() > time.Millisecond {
+		t.Errorf("Expected deadline %v, got %v", want, clock.Deadline)
+	}
+
+	if err := game.PauseGame(game.CurrentTurn); err != nil {
+		t.Fatalf("Failed to pause: %v", err)
+	}
+	if _, ok := game.TurnClock(); ok {
+		t.Error("Turn clock should stop while paused")
+	}
+}
+
+func TestUntimedTurns(t *testing.T) {
+	gm := NewGameManager()
+	game, _ := gm.CreateGame(context.Background(), "player1", "Player 1", 4)
+	gm.JoinGame(context.Background(), game.Code, "player2", "Player 2", "")
+
+	if err := game.SetTurnTimeout("player2", 0); err != ErrNotHost {
+		t.Errorf("Expected ErrNotHost, got %v", err)
+	}
+	for _, timeout := range []time.Duration{-time.Second, time.Second, time.Hour} {
+		if err := game.SetTurnTimeout("player1", timeout); err != ErrInvalidTurnTimeout {
+			t.Errorf("Expected ErrInvalidTurnTimeout for %v, got %v", timeout, err)
+		}
+	}
+	if err := game.SetTurnTimeout("player1", 0); err != nil {
+		t.Fatalf("Failed to make turns untimed: %v", err)
+	}
+	if listing, _ := game.LobbyListing(); listing.TurnTimeoutSeconds != 0 {
+		t.Errorf("Expected the lobby to show untimed turns, got %d", listing.TurnTimeoutSeconds)
+	}
+
+	game.SetPlayerReady("player1", true)
+	game.SetPlayerReady("player2", true)
+	game.StartGame("player1")
+	if err := game.SetTurnTimeout("player1", time.Minute); err != ErrGameStarted {
+		t.Errorf("Expected ErrGameStarted, got %v", err)
+	}
+
+	game.TurnStartTime = At(time.Now().Add(-time.Hour))
+	if game.IsTurnTimedOut() {
+		t.Error("Untimed turns shouldn't time out")
+	}
+	if player, _ := game.HandleTurnTimeout(); player != "" {
+		t.Errorf("Expected no timeout to handle, got one for %s", player)
+	}
+	if _, ok := game.TurnClock(); ok {
+		t.Error("Untimed turns shouldn't have a clock")
+	}
+	if state := game.GetGameState(); state["turn_remaining_ms"] != nil {
+		t.Errorf("Expected no time remaining for an untimed turn, got %v", state["turn_remaining_ms"])
+	}
+	if rules := game.GetRules(); rules.TurnTimeoutSeconds != 0 || rules.Validate() != nil {
+		t.Errorf("Expected valid rules with untimed turns, got %+v", rules)
+	}
+
+	// A disconnected player's untimed turn still ends after the reconnect grace
+	game.SetConnected(game.CurrentTurn, false)
+	game.Players[game.CurrentTurn].DisconnectedAt = At(time.Now().Add(-time.Hour))
+	if !game.IsTurnTimedOut() {
+		t.Error("Expected the reconnect grace to time out the turn")
+	}
+	game.SetConnected(game.CurrentTurn, true)
+
+	// Restarts keep turns untimed
+	var buf bytes.Buffer
+	if _, err := gm.WriteSnapshot(context.Background(), &buf); err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	fresh := NewGameManager()
+	fresh.LoadSnapshot(context.Background(), &buf)
+	if restored, err := fresh.GetGame(context.Background(), game.Code); err != nil || restored.TurnTimeout != 0 {
+		t.Errorf("Expected untimed turns restored, got %v (%v)", restored.TurnTimeout, err)
+	}
+}
+
+func TestTurnWarning(t *testing.T) {
+	game := newPauseTestGame(t)
+
+	if _, ok := game.TakeTurnWarning(); ok {
+		t.Error("Expected no warning at the start of a turn")
+	}
+	if remaining, ok := game.GetGameState()["turn_remaining_ms"].(int64); !ok || remaining <= 0 || remaining > game.TurnTimeout.Milliseconds() {
+		t.Errorf("Expected up to %v remaining, got %v", game.TurnTimeout, game.GetGameState()["turn_remaining_ms"])
+	}
+
+	game.TurnStartTime = At(time.Now().Add(TurnTimeoutWarning/2 - game.TurnTimeout))
+	clock, ok := game.TakeTurnWarning()
+	if !ok || clock.Turn != game.CurrentTurn {
+		t.Fatalf("Expected a warning for %s, got %+v", game.CurrentTurn, clock)
+	}
+	if _, ok := game.TakeTurnWarning(); ok {
+		t.Error("Expected one warning per turn")
+	}
+
+	// The next turn is warned again
+	game.SkipTurn(game.CurrentTurn)
+	game.TurnStartTime = At(time.Now().Add(TurnTimeoutWarning/2 - game.TurnTimeout))
+	if clock, ok := game.TakeTurnWarning(); !ok || clock.Turn != game.CurrentTurn {
+		t.Errorf("Expected a warning for %s's turn, got %+v", game.CurrentTurn, clock)
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aminearbi/ludo-nadwa-server/handlers"
+	"github.com/aminearbi/ludo-nadwa-server/models"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// newRouter registers every HTTP route. Methods are routed declaratively, so a
+// request with the wrong method gets 405 with an Allow header listing the right ones.
+func newRouter(handler *handlers.Handler, wsHandler *handlers.WebSocketHandler, gameManager *models.GameManager, webRoot fs.FS, lobby *handlers.Lobby, requestTimeout time.Duration) http.Handler {
+	r := chi.NewRouter()
+	r.Use(handlers.CORS)
+	r.Use(middleware.GetHead)
+
+	// API routes get a deadline so handlers stop working for clients that have gone
+	// away or waited too long. The WebSocket and web client are long-lived and don't.
+	// They are served under /api/v1, and under /api as deprecated aliases.
+	openapi := handler.OpenAPI(r)
+	api := func(r chi.Router) {
+		r.Use(middleware.Timeout(requestTimeout))
+
+		// Game lifecycle and play
+		r.Route("/game", func(r chi.Router) {
+			r.Post("/create", handler.CreateGame)
+			r.Post("/join", handler.JoinGame)
+			r.Post("/spectate", handler.JoinAsSpectator)
+			r.Get("/state", handler.GetGameState)
+			r.Get("/preview-move", handler.PreviewMove)
+			r.Post("/reconnect", handler.Reconnect)
+			r.Get("/events", handler.GetGameEvents)
+			r.Get("/history", handler.GetMoveHistory)
+			r.Get("/chat/history", handler.GetChat)
+			r.Get("/commentary", handler.GetCommentary)
+			r.Get("/replay", handler.GetGameReplay)
+			r.Get("/replay/verify", handler.VerifyReplay)
+			r.Get("/export", handler.ExportGame)
+			r.Get("/webhooks/deliveries", handler.GetWebhookDeliveries)
+			r.Post("/bot/release", handler.ReleaseBot)
+			r.Post("/bot/act", handler.BotAction)
+
+			// Mutating actions signed with the acting player's session secret
+			r.Group(func(r chi.Router) {
+				r.Use(handler.RequireSignature)
+				r.Post("/start", handler.StartGame)
+				r.Post("/roll/ack", handler.AckRoll)
+				r.Post("/ready", handler.SetReady)
+				r.Post("/color", handler.SetColor)
+				r.Post("/leave", handler.LeaveGame)
+				r.Post("/pause", handler.PauseGame)
+				r.Post("/resume", handler.ResumeGame)
+				r.Post("/pause/vote", handler.VotePause)
+				r.Post("/chat/read", handler.MarkChatRead)
+				r.Post("/emote", handler.SendEmote)
+				r.Post("/bot/takeover", handler.TakeOverBot)
+				r.Post("/palette", handler.SetPalette)
+				r.Post("/auto-roll", handler.SetAutoRoll)
+				r.Post("/auto-move", handler.SetAutoMove)
+				r.Post("/premove", handler.QueueMove)
+			})
+
+			// Host actions, signed with the session secret of the host named by host_id
+			r.Group(func(r chi.Router) {
+				r.Use(handler.RequireHostSignature)
+				r.Post("/restore", handler.RestoreGame)
+				r.Post("/kick", handler.KickPlayer)
+				r.Post("/transfer-host", handler.TransferHost)
+				r.Post("/chat/delete", handler.DeleteChat)
+				r.Post("/mute", handler.MuteChat)
+				r.Post("/rematch", handler.Rematch)
+				r.Get("/webhooks", handler.ListGameWebhooks)
+				r.With(handler.RequireAPIKey(handlers.ScopeWebhooksWrite)).Post("/webhooks", handler.RegisterGameWebhook)
+				r.Post("/webhooks/delete", handler.DeleteGameWebhook)
+				r.Post("/bot/add", handler.AddBot)
+				r.Post("/bot/fill", handler.FillBots)
+				r.Post("/bot/remove", handler.RemoveBot)
+				r.Post("/bot/claim", handler.ClaimBot)
+				r.Post("/bot/stand-in", handler.BotStandIn)
+				r.Post("/bot/takeover/approve", handler.ApproveTakeover)
+				r.Post("/bot/takeover/decline", handler.DeclineTakeover)
+				r.Post("/bot/chat", handler.SetBotChat)
+				r.Post("/bot/fast-forward", handler.FastForward)
+				r.Post("/visibility", handler.SetVisibility)
+				r.Post("/password", handler.SetPassword)
+				r.Post("/turn-timeout", handler.SetTurnTimeout)
+			})
+
+			// Turn and chat actions, which clients retry on flaky networks. Retries are
+			// answered from the idempotency cache before their reused nonce is checked.
+			r.Group(func(r chi.Router) {
+				r.Use(handler.Idempotent, handler.RequireSignature)
+				r.Post("/roll", handler.RollDice)
+				r.Post("/move", handler.MovePiece)
+				r.Post("/skip", handler.SkipTurn)
+				r.Post("/chat", handler.SendChat)
+			})
+		})
+
+		// Read-only game resources addressed by path
+		r.Route("/games/{code}", func(r chi.Router) {
+			r.Get("/", handler.GetGameState)
+			r.Get("/moves", handler.GetMoveHistory)
+			r.Get("/events", handler.GetGameEvents)
+			r.Get("/chat", handler.GetChat)
+			r.Get("/commentary", handler.GetCommentary)
+			r.Get("/replay", handler.GetGameReplay)
+			r.Get("/replay/verify", handler.VerifyReplay)
+			r.Get("/export", handler.ExportGame)
+		})
+
+		// Profile changes, friends and invites, signed with the session secret of a game the player is in
+		r.Group(func(r chi.Router) {
+			r.Use(handler.RequireSignature)
+			r.Post("/profile", handler.UpdateProfile)
+			r.Post("/profile/theme", handler.SetProfileTheme)
+			r.Post("/player/name", handler.RenamePlayer)
+			r.Get("/friends", handler.GetFriends)
+			r.Post("/friends", handler.AddFriend)
+			r.Post("/friends/remove", handler.RemoveFriend)
+			r.Get("/friends/code", handler.GetFriendCode)
+			r.Get("/invites", handler.GetInvites)
+			r.Post("/invites", handler.SendInvite)
+			r.Post("/invites/accept", handler.AcceptInvite)
+			r.Post("/invites/decline", handler.DeclineInvite)
+		})
+
+		// Board geometry, themes and server info
+		r.Get("/board", handler.GetBoard)
+		r.Get("/version", handler.GetVersion)
+		r.Get("/openapi.json", openapi)
+		r.Get("/time", handler.GetTime)
+		r.Get("/themes", handler.GetThemes)
+		r.Get("/palettes", handler.GetPalettes)
+		r.Get("/emotes", handler.GetEmotes)
+		r.Get("/profile", handler.GetProfile)
+		r.Get("/profile/theme", handler.GetProfileTheme)
+		r.Get("/lobby", handler.GetLobby)
+		r.Get("/announcements", handler.GetAnnouncements)
+		r.Get("/maintenance", handler.GetMaintenance)
+		r.Get("/stats", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(gameManager.GetGameStats())
+		})
+		r.Get("/stats/meta", handler.GetMetaStats)
+		r.Get("/players/{id}/stats", handler.GetPlayerStats)
+		r.Get("/archive/{code}", handler.GetArchivedGame)
+		r.Get("/leaderboard", handler.GetLeaderboard)
+
+		// Admin routes, by minimum role
+		r.Route("/admin", func(r chi.Router) {
+			viewer := r.With(handler.RequireRole(handlers.RoleViewer))
+			viewer.Get("/whoami", handler.AdminWhoAmI)
+			viewer.Get("/games", handler.AdminListGames)
+			viewer.Get("/game", handler.AdminGetGame)
+			viewer.Get("/metrics", handler.GetGameMetrics)
+			viewer.Get("/analytics", handler.GetAnalytics)
+			viewer.Get("/experiments", handler.ListExperiments)
+			viewer.Get("/webhooks/dead-letters", handler.GetWebhookDeadLetters)
+
+			moderator := r.With(handler.RequireRole(handlers.RoleModerator))
+			moderator.Get("/chat", handler.AdminGetChat)
+			moderator.Post("/game/kick", handler.AdminKickPlayer)
+			moderator.Post("/announce", handler.Announce)
+			moderator.Get("/announce/scheduled", handler.GetScheduledAnnouncements)
+			moderator.Post("/announce/cancel", handler.CancelAnnouncement)
+
+			operator := r.With(handler.RequireRole(handlers.RoleOperator))
+			operator.Post("/maintenance", handler.SetMaintenance)
+			operator.Post("/restart", handler.Restart)
+			operator.Get("/keys", handler.ListAPIKeys)
+			operator.Post("/keys", handler.IssueAPIKey)
+			operator.Post("/keys/revoke", handler.RevokeAPIKey)
+			operator.Post("/game/delete", handler.AdminDeleteGame)
+			operator.Post("/game/restore", handler.AdminRestoreGame)
+			operator.Post("/game/skip", handler.AdminSkipTurn)
+			operator.Post("/game/end", handler.AdminEndGame)
+			operator.Post("/experiments", handler.StartExperiment)
+			operator.Post("/experiments/stop", handler.StopExperiment)
+		})
+
+		// Debug routes for tests and bug reproductions (debug mode only)
+		r.Route("/debug", func(r chi.Router) {
+			r.Use(handler.RequireDebug)
+			r.Post("/fixture", handler.CreateFixtureGame)
+			r.Get("/fixture", handler.GetFixture)
+		})
+
+		// Integration routes (API key with the matching scope)
+		r.Route("/integrations", func(r chi.Router) {
+			r.With(handler.RequireAPIKey(handlers.ScopeGamesCreate)).Post("/games", handler.IntegrationCreateGames)
+			r.With(handler.RequireAPIKey(handlers.ScopeGamesRead)).Get("/games/state", handler.IntegrationGameState)
+			r.With(handler.RequireAPIKey(handlers.ScopeWebhooksWrite)).Post("/webhooks", handler.IntegrationRegisterWebhook)
+		})
+	}
+	r.Route(handlers.APIPrefix, api)
+	r.With(deprecatedAlias).Route("/api", api)
+
+	// WebSocket endpoints
+	r.Get("/ws", wsHandler.HandleWebSocket)
+	r.Get("/ws/lobby", lobby.HandleWebSocket)
+	r.Get("/ws/replay", wsHandler.HandleReplay)
+
+	// Shareable replays: the viewer page for browsers, the replay itself otherwise
+	static := handlers.NewStaticHandler(webRoot)
+	r.Get("/replay/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if handlers.WantsHTML(r) {
+			static.ServeFile(w, r, "replay.html")
+			return
+		}
+		handler.GetReplay(w, r)
+	})
+
+	// Health check endpoint
+	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	})
+
+	// Anything else is the web client. It is served as the not-found handler rather
+	// than a catch-all route so 405 responses only list the methods of real routes.
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		static.ServeHTTP(w, r)
+	})
+
+	return r
+}
+
+// deprecatedAlias marks responses from the unversioned /api routes as deprecated
+// and points clients at the same route under /api/v1
+func deprecatedAlias(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		successor := handlers.APIPrefix + strings.TrimPrefix(r.URL.Path, "/api")
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", "<"+successor+">; rel=\"successor-version\"")
+		next.ServeHTTP(w, r)
+	})
+}
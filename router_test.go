@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/aminearbi/ludo-nadwa-server/handlers"
+	"github.com/aminearbi/ludo-nadwa-server/models"
+)
+
+// hostRoutes are the routes only the host may call
+var hostRoutes = []string{
+	"/restore", "/kick", "/transfer-host", "/chat/delete", "/mute", "/rematch",
+	"/webhooks/delete", "/bot/add", "/bot/fill", "/bot/remove", "/bot/claim",
+	"/bot/stand-in", "/bot/takeover/approve", "/bot/takeover/decline",
+	"/bot/chat", "/bot/fast-forward", "/visibility", "/password", "/turn-timeout",
+}
+
+// newTestRouter returns a strict-signing router and its game manager
+func newTestRouter(t *testing.T) (http.Handler, *models.GameManager) {
+	t.Helper()
+	gm := models.NewGameManager()
+	handler := handlers.NewHandler(gm)
+	handler.SetStrictSigning(true)
+	hub := handlers.NewHub()
+	wsHandler := handlers.NewWebSocketHandler(hub, gm)
+	lobby := handlers.NewLobby(gm, hub)
+	return newRouter(handler, wsHandler, gm, fstest.MapFS{}, lobby, 5*time.Second), gm
+}
+
+// nonceSeq keeps nonces made in the same millisecond apart
+var nonceSeq int
+
+// testNonce returns a fresh nonce, as clients make them
+func testNonce() string {
+	nonceSeq++
+	return fmt.Sprintf("%d-%d", time.Now().UnixMilli(), nonceSeq)
+}
+
+// signedPost sends body to path, signed with secret as clients sign it
+func signedPost(router http.Handler, path, code, secret string, body map[string]string) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(string(payload)))
+	req.Header.Set("Content-Type", "application/json")
+	nonce := testNonce()
+	req.Header.Set(handlers.NonceHeader, nonce)
+	req.Header.Set(handlers.SignatureHeader, models.SignAction(secret, code, path, nonce))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// errorCodeOf returns the error code of a JSON error response
+func errorCodeOf(rec *httptest.ResponseRecorder) string {
+	var resp handlers.ErrorResponse
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	return resp.Code
+}
+
+func TestHostRoutesRejectPlayerSignature(t *testing.T) {
+	for _, route := range hostRoutes {
+		router, gm := newTestRouter(t)
+		game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+		if _, err := gm.JoinGame(context.Background(), game.Code, "p2", "Player 2", ""); err != nil {
+			t.Fatalf("Failed to join: %v", err)
+		}
+
+		path := "/api/v1/game" + route
+		body := map[string]string{"code": game.Code, "host_id": "host1", "player_id": "p2"}
+		rec := signedPost(router, path, game.Code, game.SessionSecret("p2"), body)
+		if rec.Code != http.StatusUnauthorized || errorCodeOf(rec) != "INVALID_SIGNATURE" {
+			t.Errorf("%s: expected 401 INVALID_SIGNATURE for a player's signature, got %d %s", route, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestHostRoutesAcceptHostSignature(t *testing.T) {
+	for _, route := range hostRoutes {
+		router, gm := newTestRouter(t)
+		game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+		gm.JoinGame(context.Background(), game.Code, "p2", "Player 2", "")
+
+		path := "/api/v1/game" + route
+		body := map[string]string{"code": game.Code, "host_id": "host1", "player_id": "p2"}
+		rec := signedPost(router, path, game.Code, game.SessionSecret("host1"), body)
+		switch errorCodeOf(rec) {
+		case "MISSING_SIGNATURE", "INVALID_SIGNATURE", "NONCE_REUSED", "STALE_NONCE":
+			t.Errorf("%s: expected the host's signature to be accepted, got %d %s", route, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestPlayerRoutesRejectAnotherPlayersSignature(t *testing.T) {
+	router, gm := newTestRouter(t)
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+	gm.JoinGame(context.Background(), game.Code, "p2", "Player 2", "")
+
+	path := "/api/v1/game/ready"
+	body := map[string]string{"code": game.Code, "player_id": "host1"}
+	rec := signedPost(router, path, game.Code, game.SessionSecret("p2"), body)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a signature by another player, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = signedPost(router, path, game.Code, game.SessionSecret("host1"), body)
+	if rec.Code == http.StatusUnauthorized {
+		t.Errorf("Expected the player's own signature to be accepted, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+// profileRoutes change a player's profile, signed with a game they are in
+var profileRoutes = []string{"/profile", "/profile/theme", "/player/name"}
+
+func TestProfileRoutesRejectAnotherPlayersSignature(t *testing.T) {
+	for _, route := range profileRoutes {
+		router, gm := newTestRouter(t)
+		game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+		gm.JoinGame(context.Background(), game.Code, "p2", "Player 2", "")
+
+		path := "/api/v1" + route
+		body := map[string]string{"code": game.Code, "player_id": "host1", "board_theme": "classic", "name": "Renamed"}
+		if rec := signedPost(router, path, game.Code, game.SessionSecret("p2"), body); rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: expected 401 for another player's signature, got %d %s", route, rec.Code, rec.Body.String())
+		}
+		if rec := signedPost(router, path, game.Code, game.SessionSecret("host1"), body); rec.Code == http.StatusUnauthorized {
+			t.Errorf("%s: expected the player's own signature to be accepted, got %d %s", route, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+// signedGet sends a GET for path with query, signed with secret as clients sign it
+func signedGet(router http.Handler, path, query, code, secret string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path+"?"+query, nil)
+	if secret != "" {
+		nonce := testNonce()
+		req.Header.Set(handlers.NonceHeader, nonce)
+		req.Header.Set(handlers.SignatureHeader, models.SignAction(secret, code, path, nonce))
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestFriendAndInviteListsRequireSignature(t *testing.T) {
+	router, gm := newTestRouter(t)
+	game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+	gm.JoinGame(context.Background(), game.Code, "p2", "Player 2", "")
+
+	for _, path := range []string{"/api/v1/friends", "/api/v1/friends/code", "/api/v1/invites"} {
+		query := "code=" + game.Code + "&player_id=host1"
+		if rec := signedGet(router, path, query, game.Code, ""); rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: expected 401 without a signature, got %d %s", path, rec.Code, rec.Body.String())
+		}
+		if rec := signedGet(router, path, query, game.Code, game.SessionSecret("p2")); rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: expected 401 for another player's signature, got %d %s", path, rec.Code, rec.Body.String())
+		}
+		if rec := signedGet(router, path, query, game.Code, game.SessionSecret("host1")); rec.Code != http.StatusOK {
+			t.Errorf("%s: expected the player's own signature to be accepted, got %d %s", path, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestInviteAnswersRejectAnotherPlayersSignature(t *testing.T) {
+	for _, path := range []string{"/api/v1/invites/accept", "/api/v1/invites/decline"} {
+		router, gm := newTestRouter(t)
+		game, _ := gm.CreateGame(context.Background(), "host1", "Host", 4)
+		gm.JoinGame(context.Background(), game.Code, "p2", "Player 2", "")
+
+		body := map[string]string{"code": game.Code, "player_id": "host1", "invite_id": "inv_1"}
+		if rec := signedPost(router, path, game.Code, game.SessionSecret("p2"), body); rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: expected 401 for another player's signature, got %d %s", path, rec.Code, rec.Body.String())
+		}
+	}
+}
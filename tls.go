@@ -0,0 +1,81 @@
+package main
+
+import (
+	"crypto/tls"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// defaultCertCache is where certificates from Let's Encrypt are kept across restarts
+const defaultCertCache = "ludo-certs"
+
+// serverTLS returns the TLS config to serve HTTPS with, or nil to serve plain
+// HTTP, and the handler for plain HTTP requests that redirects them to HTTPS.
+// HTTP/2 is offered to clients that support it; WebSockets upgrade over HTTP/1.1
+// on the same port and certificate.
+func serverTLS(cfg Config, port string) (*tls.Config, http.Handler, error) {
+	domains := splitList(cfg.TLSDomains)
+	if len(domains) > 0 && (cfg.TLSCert != "" || cfg.TLSKey != "") {
+		return nil, nil, errors.New("use either a certificate and key or domains for Let's Encrypt, not both")
+	}
+	if (cfg.TLSCert == "") != (cfg.TLSKey == "") {
+		return nil, nil, errors.New("a certificate needs its key and a key its certificate")
+	}
+	redirect := redirectToHTTPS(port)
+
+	if len(domains) > 0 {
+		cacheDir := cfg.TLSCacheDir
+		if cacheDir == "" {
+			cacheDir = defaultCertCache
+		}
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(domains...),
+			Cache:      autocert.DirCache(cacheDir),
+			Email:      cfg.TLSEmail,
+		}
+		config := manager.TLSConfig()
+		config.MinVersion = tls.VersionTLS12
+		// Answers HTTP-01 challenges as well as redirecting
+		return config, manager.HTTPHandler(redirect), nil
+	}
+
+	if cfg.TLSCert != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.TLSCert, cfg.TLSKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		config := &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		}
+		return config, redirect, nil
+	}
+	return nil, nil, nil
+}
+
+// redirectToHTTPS sends plain HTTP requests to the same URL over HTTPS on port
+func redirectToHTTPS(port string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+}
+
+// serveRedirects redirects plain HTTP on port to HTTPS until the process exits
+func serveRedirects(port string, handler http.Handler) {
+	server := &http.Server{Addr: "0.0.0.0:" + port, Handler: handler}
+	if err := server.ListenAndServe(); err != nil {
+		log.Printf("Could not redirect HTTP on port %s: %v", port, err)
+	}
+}
@@ -3,6 +3,9 @@
 const API_BASE = `${window.location.protocol}//${window.location.host}`;
 const WS_BASE = API_BASE.replace('http', 'ws');
 
+// Protocol version this client speaks; must fall within the server's supported range
+const CLIENT_PROTOCOL_VERSION = 1;
+
 // ==================== Game State ====================
 let gameState = {
     code: null,
@@ -16,10 +19,14 @@ let gameState = {
     validMoves: [],
     myColor: null,
     state: 'waiting',
+    sessionSecret: null,
     ws: null
 };
 
 // Timer and timeout tracking
+// Chat is translated into this language when the server has a translator configured
+const CHAT_LOCALE = (navigator.language || 'en').split('-')[0];
+
 const TURN_TIME_LIMIT = 15000; // 15 seconds per action
 const MAX_TIMEOUTS = 3;
 let turnTimer = null;
@@ -45,6 +52,8 @@ const elements = {
     createName: document.getElementById('create-name'),
     joinName: document.getElementById('join-name'),
     gameCode: document.getElementById('game-code'),
+    createPassword: document.getElementById('create-password'),
+    joinPassword: document.getElementById('join-password'),
     createBtn: document.getElementById('create-btn'),
     joinBtn: document.getElementById('join-btn'),
     
@@ -67,6 +76,7 @@ const elements = {
     skipBtn: document.getElementById('skip-btn'),
     gamePlayersList: document.getElementById('game-players-list'),
     chatMessages: document.getElementById('chat-messages'),
+    chatUnread: document.getElementById('chat-unread'),
     chatInput: document.getElementById('chat-input'),
     sendChat: document.getElementById('send-chat'),
     
@@ -395,6 +405,11 @@ async function apiCall(endpoint, method = 'GET', body = null) {
         headers: { 'Content-Type': 'application/json' }
     };
     if (body) options.body = JSON.stringify(body);
+    if (method === 'POST' && body && body.code && gameState.sessionSecret) {
+        const nonce = `${Date.now()}-${Math.random().toString(36).slice(2)}`;
+        options.headers['X-Signature-Nonce'] = nonce;
+        options.headers['X-Signature'] = await signAction(body.code, endpoint, nonce);
+    }
     
     const response = await fetch(`${API_BASE}${endpoint}`, options);
     const data = await response.json();
@@ -406,6 +421,17 @@ async function apiCall(endpoint, method = 'GET', body = null) {
     return data;
 }
 
+// Sign (code, action, nonce) with the session secret for servers running in strict mode
+async function signAction(code, action, nonce) {
+    const encoder = new TextEncoder();
+    const key = await crypto.subtle.importKey(
+        'raw', encoder.encode(gameState.sessionSecret),
+        { name: 'HMAC', hash: 'SHA-256' }, false, ['sign']
+    );
+    const signature = await crypto.subtle.sign('HMAC', key, encoder.encode(`${code}|${action}|${nonce}`));
+    return Array.from(new Uint8Array(signature)).map(b => b.toString(16).padStart(2, '0')).join('');
+}
+
 async function createGame() {
     const name = elements.createName.value.trim();
     if (!name) {
@@ -418,14 +444,16 @@ async function createGame() {
     gameState.playerName = name;
     
     try {
-        const response = await apiCall('/api/game/create', 'POST', {
+        const response = await apiCall('/api/v1/game/create', 'POST', {
             player_id: gameState.playerId,
             player_name: name,
-            max_players: maxPlayers
+            max_players: maxPlayers,
+            password: elements.createPassword.value
         });
         
         gameState.code = response.code;
         gameState.isHost = true;
+        gameState.sessionSecret = response.session_secret;
         
         connectWebSocket();
         showWaitingRoom();
@@ -453,12 +481,14 @@ async function joinGame() {
     gameState.code = code;
     
     try {
-        const response = await apiCall('/api/game/join', 'POST', {
+        const response = await apiCall('/api/v1/game/join', 'POST', {
             code: code,
             player_id: gameState.playerId,
-            player_name: name
+            player_name: name,
+            password: elements.joinPassword.value
         });
         
+        gameState.sessionSecret = response.session_secret;
         connectWebSocket();
         showWaitingRoom();
         showToast('Joined game!', 'success');
@@ -469,7 +499,7 @@ async function joinGame() {
 
 async function setReady(ready) {
     try {
-        await apiCall('/api/game/ready', 'POST', {
+        await apiCall('/api/v1/game/ready', 'POST', {
             code: gameState.code,
             player_id: gameState.playerId,
             ready: ready
@@ -481,7 +511,7 @@ async function setReady(ready) {
 
 async function startGame() {
     try {
-        await apiCall('/api/game/start', 'POST', {
+        await apiCall('/api/v1/game/start', 'POST', {
             code: gameState.code,
             player_id: gameState.playerId
         });
@@ -507,7 +537,7 @@ async function rollDice() {
         };
         animate();
         
-        const response = await apiCall('/api/game/roll', 'POST', {
+        const response = await apiCall('/api/v1/game/roll', 'POST', {
             code: gameState.code,
             player_id: gameState.playerId
         });
@@ -543,7 +573,7 @@ async function rollDice() {
 async function movePiece(pieceId) {
     try {
         playSound('moveSound');
-        const response = await apiCall('/api/game/move', 'POST', {
+        const response = await apiCall('/api/v1/game/move', 'POST', {
             code: gameState.code,
             player_id: gameState.playerId,
             piece_id: pieceId
@@ -565,7 +595,7 @@ async function movePiece(pieceId) {
 
 async function skipTurn() {
     try {
-        await apiCall('/api/game/skip', 'POST', {
+        await apiCall('/api/v1/game/skip', 'POST', {
             code: gameState.code,
             player_id: gameState.playerId
         });
@@ -589,7 +619,7 @@ async function sendChat() {
     if (!message) return;
     
     try {
-        await apiCall('/api/game/chat', 'POST', {
+        await apiCall('/api/v1/game/chat', 'POST', {
             code: gameState.code,
             player_id: gameState.playerId,
             message: message
@@ -602,7 +632,7 @@ async function sendChat() {
 
 async function requestRematch() {
     try {
-        await apiCall('/api/game/rematch', 'POST', {
+        await apiCall('/api/v1/game/rematch', 'POST', {
             code: gameState.code,
             host_id: gameState.playerId
         });
@@ -614,7 +644,7 @@ async function requestRematch() {
 
 async function leaveGame() {
     try {
-        await apiCall('/api/game/leave', 'POST', {
+        await apiCall('/api/v1/game/leave', 'POST', {
             code: gameState.code,
             player_id: gameState.playerId
         });
@@ -642,7 +672,7 @@ function connectWebSocket() {
         return; // Already connected
     }
     
-    const wsUrl = `${WS_BASE}/ws?code=${gameState.code}&player_id=${gameState.playerId}`;
+    const wsUrl = `${WS_BASE}/ws?code=${gameState.code}&player_id=${gameState.playerId}&locale=${CHAT_LOCALE}&commentary=true`;
     gameState.ws = new WebSocket(wsUrl);
     
     gameState.ws.onopen = () => {
@@ -652,10 +682,17 @@ function connectWebSocket() {
             clearInterval(pollInterval);
             pollInterval = null;
         }
+        // Catch up on anything that happened while we were away
+        if (gameState.lastSeq !== undefined) {
+            resumeGame();
+        }
     };
     
     gameState.ws.onmessage = (event) => {
         const message = JSON.parse(event.data);
+        if (message.seq !== undefined) {
+            gameState.lastSeq = message.seq;
+        }
         handleWebSocketMessage(message);
     };
     
@@ -678,6 +715,32 @@ function connectWebSocket() {
     };
 }
 
+// Replay the events missed while disconnected, then refresh from the full state
+async function resumeGame() {
+    try {
+        const response = await apiCall('/api/v1/game/reconnect', 'POST', {
+            code: gameState.code,
+            player_id: gameState.playerId,
+            since: gameState.lastSeq
+        });
+        gameState.lastSeq = response.seq;
+        let chatMissed = false;
+        response.events.forEach(event => {
+            if (event.type !== 'refresh') {
+                handleWebSocketMessage(event);
+            } else if (event.hint === 'chat_message') {
+                chatMissed = true;
+            }
+        });
+        if (chatMissed) {
+            fetchChat();
+        }
+        await fetchGameState();
+    } catch (error) {
+        console.error('Error resuming game:', error);
+    }
+}
+
 function startPolling() {
     if (pollInterval) return; // Already polling
     console.log('Starting polling fallback');
@@ -701,6 +764,16 @@ async function handleWebSocketMessage(message) {
     
     if (message.type === 'refresh') {
         await fetchGameState(message.hint);
+    } else if (message.type === 'announcement') {
+        showAnnouncement(message);
+    } else if (message.type === 'maintenance') {
+        showMaintenance(message);
+    } else if (message.type === 'commentary') {
+        addSystemMessage(`🎙️ ${escapeHtml(message.text)}`);
+    } else if (message.type === 'server_restarting') {
+        showToast('Server restarting - your game is saved, reconnecting…', 'warning');
+    } else if (message.type === 'chat_unread') {
+        updateChatUnread(message.unread_chat);
     } else if (message.type === 'pong') {
         // Heartbeat response, ignore
     }
@@ -709,7 +782,7 @@ async function handleWebSocketMessage(message) {
 // Fetch game state from server (source of truth)
 async function fetchGameState(hint) {
     try {
-        const response = await fetch(`${API_BASE}/api/game/state?code=${gameState.code}`);
+        const response = await fetch(`${API_BASE}/api/v1/game/state?code=${gameState.code}`);
         if (!response.ok) {
             throw new Error('Failed to fetch game state');
         }
@@ -762,13 +835,15 @@ async function fetchGameState(hint) {
 
 async function fetchChat() {
     try {
-        const response = await fetch(`${API_BASE}/api/game/chat?code=${gameState.code}`);
+        const response = await fetch(`${API_BASE}/api/v1/game/chat/history?code=${gameState.code}&locale=${CHAT_LOCALE}`);
         if (response.ok) {
             const data = await response.json();
             // Update chat display with new messages
             if (data.chat_messages && data.chat_messages.length > 0) {
                 const lastMsg = data.chat_messages[data.chat_messages.length - 1];
-                addChatMessage(lastMsg.player_name, lastMsg.message, lastMsg.player_id);
+                addChatMessage(lastMsg.player_name, lastMsg.message, lastMsg.player_id, lastMsg.translated);
+                lastChatTimestamp = lastMsg.timestamp;
+                markChatRead();
             }
         }
     } catch (error) {
@@ -776,6 +851,25 @@ async function fetchChat() {
     }
 }
 
+// ==================== Chat Read Markers ====================
+let lastChatTimestamp = null;
+
+// Tell the server chat has been read up to the newest message while the page is visible
+function markChatRead() {
+    if (!lastChatTimestamp || document.visibilityState !== 'visible') return;
+    if (gameState.ws && gameState.ws.readyState === WebSocket.OPEN) {
+        gameState.ws.send(JSON.stringify({ type: 'chat_read', read_at: lastChatTimestamp }));
+    }
+}
+
+function updateChatUnread(count) {
+    if (!elements.chatUnread) return;
+    elements.chatUnread.textContent = count > 99 ? '99+' : String(count);
+    elements.chatUnread.hidden = count === 0;
+}
+
+document.addEventListener('visibilitychange', markChatRead);
+
 function getPlayerName(playerId) {
     const player = gameState.players[playerId];
     return player ? player.name : 'Unknown';
@@ -789,6 +883,9 @@ function updateFromGameState(game) {
     const turnChanged = gameState.currentTurn !== game.current_turn;
     
     gameState.players = game.players || {};
+    if (game.unread_chat) {
+        updateChatUnread(game.unread_chat[gameState.playerId] || 0);
+    }
     gameState.currentTurn = game.current_turn;
     gameState.state = game.state;
     gameState.lastDiceRoll = game.last_dice_roll;
@@ -1081,7 +1178,7 @@ function createConfetti() {
     }
 }
 
-function addChatMessage(sender, text, playerId) {
+function addChatMessage(sender, text, playerId, translated) {
     const player = gameState.players[playerId];
     const colorClass = player ? player.color : '';
     
@@ -1090,6 +1187,7 @@ function addChatMessage(sender, text, playerId) {
     messageDiv.innerHTML = `
         <span class="sender ${colorClass}">${sender}:</span>
         <span class="text">${escapeHtml(text)}</span>
+        ${translated ? `<div class="translation">${escapeHtml(translated)}</div>` : ''}
     `;
     
     elements.chatMessages.appendChild(messageDiv);
@@ -1196,7 +1294,7 @@ async function autoRollAndMove() {
     try {
         elements.rollBtn.disabled = true;
         
-        const response = await apiCall('/api/game/roll', 'POST', {
+        const response = await apiCall('/api/v1/game/roll', 'POST', {
             code: gameState.code,
             player_id: gameState.playerId
         });
@@ -1777,7 +1875,7 @@ function drawAllPieces() {
     const piecesByPosition = new Map();
     
     Object.values(gameState.players).forEach(player => {
-        if (!player.pieces) return;
+        if (!player.pieces || player.pieces_removed) return;
         
         player.pieces.forEach((piece, idx) => {
             const posKey = getPiecePositionKey(player.color, piece, idx);
@@ -2065,6 +2163,8 @@ function resetGameState() {
     
     // Reset timeout tracking
     playerTimeouts = {};
+    lastChatTimestamp = null;
+    updateChatUnread(0);
     
     gameState = {
         code: null,
@@ -2078,6 +2178,7 @@ function resetGameState() {
         validMoves: [],
         myColor: null,
         state: 'waiting',
+        sessionSecret: null,
         ws: null
     };
     
@@ -2333,5 +2434,99 @@ function updateAnimations(currentTime) {
     }
 }
 
+// Warn if this page is too old or too new for the server it talks to
+async function checkServerCompatibility() {
+    try {
+        const info = await apiCall('/api/v1/version');
+        if (CLIENT_PROTOCOL_VERSION < info.min_client_protocol) {
+            showToast('A new version is available - please refresh the page', 'error');
+        } else if (CLIENT_PROTOCOL_VERSION > info.protocol_version) {
+            showToast('The server is older than this page - some features may not work', 'warning');
+        }
+        console.log(`Server ${info.version} (protocol ${info.protocol_version})`);
+    } catch (error) {
+        console.warn('Could not check server version:', error);
+    }
+}
+
+// ==================== Announcements ====================
+let announcementTimer = null;
+const dismissedAnnouncements = new Set();
+
+// Show a server-wide announcement as a banner until it expires or is dismissed
+function showAnnouncement(announcement) {
+    if (dismissedAnnouncements.has(announcement.id)) return;
+    const banner = document.getElementById('announcement-banner');
+    const remaining = new Date(announcement.expires_at) - Date.now();
+    if (!banner || remaining <= 0) return;
+
+    document.getElementById('announcement-text').textContent = announcement.message;
+    banner.className = `announcement-banner ${announcement.level}`;
+    banner.dataset.id = announcement.id;
+    banner.hidden = false;
+
+    clearTimeout(announcementTimer);
+    announcementTimer = setTimeout(() => { banner.hidden = true; }, remaining);
+}
+
+document.getElementById('announcement-close').addEventListener('click', () => {
+    const banner = document.getElementById('announcement-banner');
+    dismissedAnnouncements.add(banner.dataset.id);
+    banner.hidden = true;
+});
+
+// Players outside a game have no WebSocket, so announcements are also polled
+async function fetchAnnouncements() {
+    try {
+        const data = await apiCall('/api/v1/announcements');
+        const latest = data.announcements[data.announcements.length - 1];
+        if (latest) showAnnouncement(latest);
+        showMaintenance(await apiCall('/api/v1/maintenance'));
+    } catch (error) {
+        console.warn('Could not fetch announcements:', error);
+    }
+}
+
+// ==================== Maintenance ====================
+let maintenanceInterval = null;
+
+// Show a maintenance warning with a live countdown to the deadline
+function showMaintenance(status) {
+    const banner = document.getElementById('announcement-banner');
+    clearInterval(maintenanceInterval);
+    maintenanceInterval = null;
+
+    if (!status.enabled) {
+        if ((banner.dataset.id || '').startsWith('maintenance')) banner.hidden = true;
+        return;
+    }
+    const id = `maintenance-${status.started_at}`;
+    if (dismissedAnnouncements.has(id)) return;
+
+    const deadline = status.deadline ? new Date(status.deadline) : null;
+    const render = () => {
+        let text = status.message || 'Server maintenance - new games are disabled';
+        const remaining = deadline ? deadline - Date.now() : 0;
+        if (remaining > 0) {
+            const mins = Math.floor(remaining / 60000);
+            const secs = Math.floor((remaining % 60000) / 1000);
+            text += ` (${status.pause_games ? 'games pause' : 'starts'} in ${mins}:${String(secs).padStart(2, '0')})`;
+        }
+        document.getElementById('announcement-text').textContent = text;
+    };
+
+    clearTimeout(announcementTimer);
+    banner.className = 'announcement-banner warning';
+    banner.dataset.id = id;
+    banner.hidden = false;
+    render();
+    if (deadline && deadline > Date.now()) {
+        maintenanceInterval = setInterval(render, 1000);
+    }
+}
+
 // Initialize
 console.log('🎲 Ludo Nadwa loaded!');
+checkServerCompatibility();
+fetchAnnouncements();
+setInterval(fetchAnnouncements, 60000);